@@ -18,7 +18,10 @@ import (
 	"context"
 	"fmt"
 	"github.com/echovault/echovault/internal"
+	"os"
+	"runtime"
 	"slices"
+	"strconv"
 	"strings"
 )
 
@@ -203,6 +206,273 @@ func (server *EchoVault) RewriteAOF() (string, error) {
 	return internal.ParseStringResponse(b)
 }
 
+// ConfigGet returns the values of the configuration parameters that match the glob patterns.
+//
+// Parameters:
+//
+// `patterns` - ...string - The glob patterns of the parameter names. The parameters are named after their command
+// line flags, e.g. max-memory or eviction-policy.
+//
+// Returns: A map of the matching parameter names to their values.
+func (server *EchoVault) ConfigGet(patterns ...string) (map[string]string, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(append([]string{"CONFIG", "GET"}, patterns...)), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	arr, err := internal.ParseStringArrayResponse(b)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string]string, len(arr)/2)
+	for i := 0; i+1 < len(arr); i += 2 {
+		res[arr[i]] = arr[i+1]
+	}
+	return res, nil
+}
+
+// ConfigSet changes the value of the configuration parameter while the server is running.
+// The engines that depend on the parameter are reconfigured in place.
+//
+// Parameters:
+//
+// `parameter` - string - The parameter name. The mutable parameters are aof-sync-strategy, eviction-policy,
+// eviction-sample, latency-monitor-threshold, lfu-decay-time, lfu-log-factor, max-memory, notify-keyspace-events,
+// password, require-pass, slowlog-log-slower-than, slowlog-max-len, snapshot-interval and snapshot-threshold.
+//
+// `value` - string - The new value of the parameter.
+//
+// Returns: true when the parameter is changed.
+//
+// Errors:
+//
+// "unsupported CONFIG parameter <parameter>" - when the parameter does not exist.
+//
+// "CONFIG parameter <parameter> can't be changed while the server is running" - when the parameter is immutable.
+func (server *EchoVault) ConfigSet(parameter string, value string) (bool, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"CONFIG", "SET", parameter, value}), nil, false, true)
+	if err != nil {
+		return false, err
+	}
+	res, err := internal.ParseStringResponse(b)
+	return strings.EqualFold(res, "ok"), err
+}
+
+// ConfigRewrite writes the values of the mutable configuration parameters to the JSON or YAML config file
+// in the config's ConfigFile. The other entries of the file are kept as they are.
+//
+// Errors:
+//
+// "the server is running without a config file" - when the config has no ConfigFile.
+func (server *EchoVault) ConfigRewrite() error {
+	_, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"CONFIG", "REWRITE"}), nil, false, true)
+	return err
+}
+
+// ConfigResetStat resets the statistics reported by Info and CommandStats: the calls, rejected calls and failed
+// calls of each command, the errors by prefix, the number of commands processed and the keyspace hits and misses.
+func (server *EchoVault) ConfigResetStat() error {
+	_, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"CONFIG", "RESETSTAT"}), nil, false, true)
+	return err
+}
+
+// CommandStats returns the statistics of each command that has been called since the server started or since
+// the statistics were last reset. Subcommands are reported separately as "command|subcommand".
+//
+// Calls counts the executed calls, including the FailedCalls that returned an error. RejectedCalls counts
+// the calls refused before execution by the ACL layer or because of invalid arguments.
+func (server *EchoVault) CommandStats() map[string]internal.CommandStats {
+	return server.getCommandStats()
+}
+
+// GetExpiryInfo returns the statistics of the deletion of expired keys, including the keys deleted by the
+// active expiry cycles that run every eviction interval.
+func (server *EchoVault) GetExpiryInfo() internal.ExpiryInfo {
+	server.keysWithExpiry.rwMutex.RLock()
+	volatileKeys := 0
+	for _, index := range server.keysWithExpiry.keys {
+		volatileKeys += index.Len()
+	}
+	server.keysWithExpiry.rwMutex.RUnlock()
+
+	server.expiryStats.mutex.Lock()
+	defer server.expiryStats.mutex.Unlock()
+	return internal.ExpiryInfo{
+		VolatileKeys:     volatileKeys,
+		ExpiredKeys:      server.expiryStats.expiredKeys,
+		Cycles:           server.expiryStats.cycles,
+		LastCycleExpired: server.expiryStats.lastCycleExpired,
+		TimeCapReached:   server.expiryStats.timeCapReached,
+		CycleTime:        server.expiryStats.cycleTime,
+	}
+}
+
+// Info returns the sections reported by the INFO command: information about the server, its clients, memory,
+// persistence, statistics, replication, CPU usage, cluster membership, the statistics of each command and error,
+// and the number of keys in each database.
+func (server *EchoVault) Info() internal.Info {
+	memory := server.GetMemoryStats()
+	return internal.Info{
+		Server: internal.ServerSection{
+			ServerInfo: server.GetServerInfo(),
+			ProcessID:  os.Getpid(),
+			TCPPort:    int(server.config.Port),
+			Uptime:     server.clock.Now().Sub(server.stats.startTime),
+			GoVersion:  runtime.Version(),
+			OS:         runtime.GOOS + " " + runtime.GOARCH,
+		},
+		Clients:     server.getClientsInfo(),
+		Memory:      memory,
+		Persistence: server.getPersistenceInfo(),
+		Stats: internal.StatsInfo{
+			TotalConnections: server.connId.Load(),
+			TotalCommands:    server.stats.totalCommands.Load(),
+			TotalErrors:      server.stats.totalErrors.Load(),
+			KeyspaceHits:     server.stats.keyspaceHits.Load(),
+			KeyspaceMisses:   server.stats.keyspaceMisses.Load(),
+			EvictedKeys:      memory.EvictedKeys,
+			ExpiryInfo:       server.GetExpiryInfo(),
+		},
+		Replication:  server.GetReplicationInfo(),
+		CPU:          getCPUInfo(),
+		Cluster:      server.GetClusterInfo(),
+		CommandStats: server.getCommandStats(),
+		ErrorStats:   server.getErrorStats(),
+		Keyspace:     server.getKeyspaceInfo(),
+	}
+}
+
+// MonitorEntry is a command processed by the server.
+//
+// Timestamp - time.Time - the time the command was processed.
+//
+// Database - int - the database the command was executed against.
+//
+// Source - string - one of "embedded", "tcp", "aof", "replication" or "raft".
+//
+// ClientAddr - string - the remote address of the TCP client. Empty for other sources.
+//
+// ClientName - string - the name of the client connection, if set.
+//
+// Command - []string - the command and its arguments, with passwords redacted.
+//
+// Dropped - uint64 - the number of entries dropped before this one because the subscriber fell behind.
+type MonitorEntry = internal.MonitorEntry
+
+// Monitor returns a channel that receives every command processed by the server from now on, like MONITOR.
+// The channel buffers up to MonitorBufferSize entries. Entries are dropped while the buffer is full,
+// and the number of dropped entries is reported in the next entry received.
+//
+// The channel is closed when the context is cancelled.
+func (server *EchoVault) Monitor(ctx context.Context) <-chan MonitorEntry {
+	return server.monitor.Subscribe(ctx)
+}
+
+// SlowlogGet returns the latest count entries of the slow log, newest first. A negative count returns every entry.
+// Commands that take at least the SlowlogThreshold to execute are logged, up to SlowlogMaxLen entries.
+func (server *EchoVault) SlowlogGet(count int) []internal.SlowlogEntry {
+	return server.slowlog.Get(count)
+}
+
+// SlowlogLen returns the number of entries in the slow log.
+func (server *EchoVault) SlowlogLen() int {
+	return server.slowlog.Len()
+}
+
+// SlowlogReset removes every entry from the slow log.
+func (server *EchoVault) SlowlogReset() {
+	server.slowlog.Reset()
+}
+
+// LatencyLatest returns the latest and highest latency of each event recorded by the latency monitor.
+// Events are only recorded when their latency reaches the LatencyThreshold.
+func (server *EchoVault) LatencyLatest() []internal.LatencyEvent {
+	return server.latency.Latest()
+}
+
+// LatencyHistory returns the latencies recorded for the event, oldest first.
+func (server *EchoVault) LatencyHistory(event string) []internal.LatencySample {
+	return server.latency.History(strings.ToLower(event))
+}
+
+// LatencyReset removes the latencies recorded for the given events, or for every event when none is given.
+// It returns the number of events reset.
+func (server *EchoVault) LatencyReset(events ...string) int {
+	for i, event := range events {
+		events[i] = strings.ToLower(event)
+	}
+	return server.latency.Reset(events...)
+}
+
+// LatencyHistogram returns the distribution of the execution time of the given commands,
+// or of every command that has been called when none is given.
+func (server *EchoVault) LatencyHistogram(commands ...string) map[string]internal.LatencyHistogram {
+	return server.getLatencyHistogram(commands)
+}
+
+// MemoryUsage returns the estimated number of bytes used by the key and its value.
+//
+// Parameters:
+//
+// `key` - string - The key to estimate.
+//
+// `samples` - ...int - The number of elements sampled from hashes and lists. Defaults to 5. 0 samples every element.
+//
+// Returns: The estimated size in bytes, or -1 when the key does not exist.
+func (server *EchoVault) MemoryUsage(key string, samples ...int) (int, error) {
+	cmd := []string{"MEMORY", "USAGE", key}
+	if len(samples) > 0 {
+		cmd = append(cmd, "SAMPLES", strconv.Itoa(samples[0]))
+	}
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return 0, err
+	}
+	if isNil, _ := internal.ParseNilResponse(b); isNil {
+		return -1, nil
+	}
+	return internal.ParseIntegerResponse(b)
+}
+
+// PinKeys pins the keys that match the glob patterns, so that they're never evicted when max memory is reached.
+// Pinned keys can still expire and be deleted.
+//
+// Parameters:
+//
+// `patterns` - ...string - The glob patterns of the keys to pin.
+//
+// Returns: The number of patterns that were not pinned before.
+func (server *EchoVault) PinKeys(patterns ...string) (int, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(append([]string{"EVICTION", "PIN"}, patterns...)), nil, false, true)
+	if err != nil {
+		return 0, err
+	}
+	return internal.ParseIntegerResponse(b)
+}
+
+// UnpinKeys removes glob patterns of pinned keys, so that the keys that match them can be evicted again.
+//
+// Parameters:
+//
+// `patterns` - ...string - The glob patterns passed to PinKeys or the NoEvictKeys config.
+//
+// Returns: The number of patterns that were removed.
+func (server *EchoVault) UnpinKeys(patterns ...string) (int, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(append([]string{"EVICTION", "UNPIN"}, patterns...)), nil, false, true)
+	if err != nil {
+		return 0, err
+	}
+	return internal.ParseIntegerResponse(b)
+}
+
+// PinnedKeys returns the glob patterns of the keys that are never evicted.
+func (server *EchoVault) PinnedKeys() ([]string, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"EVICTION", "PINNED"}), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	return internal.ParseStringArrayResponse(b)
+}
+
 // AddCommand adds a new command to EchoVault. The added command can be executed using the ExecuteCommand method.
 //
 // Parameters:
@@ -16,17 +16,24 @@ package echovault
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"github.com/echovault/echovault/internal"
 	"github.com/echovault/echovault/internal/clock"
+	"github.com/echovault/echovault/internal/config"
 	"github.com/echovault/echovault/internal/constants"
+	"github.com/echovault/echovault/internal/latency"
+	"github.com/echovault/echovault/internal/logger"
 	"github.com/tidwall/resp"
+	"log/slog"
 	"os"
 	"path"
 	"reflect"
 	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -567,3 +574,410 @@ func TestEchoVault_LastSave(t *testing.T) {
 		})
 	}
 }
+
+func TestEchoVault_Info(t *testing.T) {
+	server := createEchoVault()
+
+	if _, _, err := server.Set("InfoKey1", "value1", SetOptions{}); err != nil {
+		t.Error(err)
+		return
+	}
+	if _, _, err := server.Set("InfoKey2", "value2", SetOptions{EX: 100}); err != nil {
+		t.Error(err)
+		return
+	}
+	if _, err := server.Get("InfoKey1"); err != nil {
+		t.Error(err)
+		return
+	}
+	if _, err := server.Get("InfoKey3"); err != nil {
+		t.Error(err)
+		return
+	}
+	if _, err := server.Incr("InfoKey1"); err == nil {
+		t.Error("expected INCR on a non-integer value to return an error")
+		return
+	}
+
+	info := server.Info()
+
+	if info.Server.Server != "echovault" || info.Server.Mode != "standalone" || info.Server.ProcessID == 0 {
+		t.Errorf("unexpected server section %+v", info.Server)
+	}
+	if info.Replication.Role != "master" || info.Cluster.Enabled {
+		t.Errorf("expected a standalone master, got role %s, cluster enabled %v",
+			info.Replication.Role, info.Cluster.Enabled)
+	}
+	if keyspace := info.Keyspace[0]; keyspace.Keys != 2 || keyspace.Expires != 1 {
+		t.Errorf("expected database 0 to hold 2 keys with 1 expiry, got %+v", keyspace)
+	}
+	if info.Memory.Keys != 2 || info.Memory.Dataset <= 0 {
+		t.Errorf("expected the memory section to count 2 keys, got %+v", info.Memory)
+	}
+	if info.Stats.KeyspaceHits < 1 || info.Stats.KeyspaceMisses < 1 {
+		t.Errorf("expected keyspace hits and misses, got %d hits and %d misses",
+			info.Stats.KeyspaceHits, info.Stats.KeyspaceMisses)
+	}
+	if info.CommandStats["set"].Calls != 2 || info.CommandStats["get"].Calls != 2 {
+		t.Errorf("expected 2 SET and 2 GET calls, got %+v", info.CommandStats)
+	}
+	if info.Stats.TotalCommands < 5 {
+		t.Errorf("expected at least 5 commands processed, got %d", info.Stats.TotalCommands)
+	}
+	if info.ErrorStats["ERR"] != 1 || info.Stats.TotalErrors != 1 {
+		t.Errorf("expected 1 ERR error, got %+v (total %d)", info.ErrorStats, info.Stats.TotalErrors)
+	}
+	if info.CPU.Goroutines <= 0 {
+		t.Errorf("expected a positive number of goroutines, got %d", info.CPU.Goroutines)
+	}
+}
+
+func TestEchoVault_CommandStats(t *testing.T) {
+	server := createEchoVault()
+
+	if _, _, err := server.Set("CommandStatsKey1", "value1", SetOptions{}); err != nil {
+		t.Error(err)
+		return
+	}
+	if _, err := server.Incr("CommandStatsKey1"); err == nil {
+		t.Error("expected INCR on a non-integer value to return an error")
+		return
+	}
+	if _, err := server.ExecuteCommand("GET"); err == nil {
+		t.Error("expected GET without a key to return an error")
+		return
+	}
+	if _, err := server.ConfigGet("port"); err != nil {
+		t.Error(err)
+		return
+	}
+	if _, err := server.ExecuteCommand("CONFIG", "UNKNOWN"); err == nil {
+		t.Error("expected an unknown CONFIG subcommand to return an error")
+		return
+	}
+
+	stats := server.CommandStats()
+	tests := map[string]internal.CommandStats{
+		"set":        {Calls: 1},
+		"incr":       {Calls: 1, FailedCalls: 1},
+		"get":        {RejectedCalls: 1},
+		"config|get": {Calls: 1},
+		"config":     {RejectedCalls: 1},
+	}
+	for name, want := range tests {
+		got, ok := stats[name]
+		if !ok {
+			t.Errorf("expected statistics for %s, got %+v", name, stats)
+			continue
+		}
+		if got.Calls != want.Calls || got.RejectedCalls != want.RejectedCalls || got.FailedCalls != want.FailedCalls {
+			t.Errorf("expected %s to have %d calls, %d rejected and %d failed, got %d, %d and %d", name,
+				want.Calls, want.RejectedCalls, want.FailedCalls, got.Calls, got.RejectedCalls, got.FailedCalls)
+		}
+	}
+
+	if err := server.ConfigResetStat(); err != nil {
+		t.Error(err)
+		return
+	}
+
+	// Only the CONFIG RESETSTAT call itself is recorded after the reset.
+	if stats = server.CommandStats(); len(stats) != 1 || stats["config|resetstat"].Calls != 1 {
+		t.Errorf("expected only the config|resetstat call after reset, got %+v", stats)
+	}
+	if info := server.Info(); info.Stats.TotalErrors != 0 || len(info.ErrorStats) != 0 {
+		t.Errorf("expected no errors after reset, got %+v (total %d)", info.ErrorStats, info.Stats.TotalErrors)
+	}
+}
+
+func TestEchoVault_Slowlog(t *testing.T) {
+	server := createEchoVaultWithConfig(config.Config{
+		DataDir:        "",
+		EvictionPolicy: constants.NoEviction,
+		SlowlogMaxLen:  3,
+	})
+
+	for i := 0; i < 4; i++ {
+		if _, _, err := server.Set(fmt.Sprintf("SlowlogKey%d", i), "value", SetOptions{}); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+
+	if got := server.SlowlogLen(); got != 3 {
+		t.Errorf("expected slowlog length 3, got %d", got)
+	}
+
+	entries := server.SlowlogGet(-1)
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 slowlog entries, got %d", len(entries))
+	}
+	if want := []string{"SET", "SlowlogKey3", "value"}; !reflect.DeepEqual(entries[0].Command, want) {
+		t.Errorf("expected newest slowlog entry %v, got %v", want, entries[0].Command)
+	}
+	if entries[0].ID != 3 || entries[2].ID != 1 {
+		t.Errorf("expected slowlog IDs 3 to 1, got %d to %d", entries[0].ID, entries[2].ID)
+	}
+	if got := server.SlowlogGet(1); len(got) != 1 || got[0].ID != 3 {
+		t.Errorf("expected only the newest slowlog entry, got %+v", got)
+	}
+
+	server.SlowlogReset()
+	if got := server.SlowlogLen(); got != 0 {
+		t.Errorf("expected empty slowlog after reset, got length %d", got)
+	}
+}
+
+func TestEchoVault_Latency(t *testing.T) {
+	server := createEchoVaultWithConfig(config.Config{
+		DataDir:          "",
+		EvictionPolicy:   constants.NoEviction,
+		LatencyThreshold: 10,
+	})
+
+	now := server.clock.Now()
+	server.latency.Observe(latency.EventAOFFsync, now, 5*time.Millisecond)
+	server.latency.Observe(latency.EventAOFFsync, now, 20*time.Millisecond)
+	server.latency.Observe(latency.EventSnapshot, now.Add(time.Second), 30*time.Millisecond)
+
+	events := server.LatencyLatest()
+	if len(events) != 2 || events[0].Name != latency.EventAOFFsync || events[1].Name != latency.EventSnapshot {
+		t.Fatalf("expected aof-fsync and snapshot events, got %+v", events)
+	}
+	if events[0].Latest != 20*time.Millisecond || events[0].Max != 20*time.Millisecond {
+		t.Errorf("expected aof-fsync latency of 20ms, got %+v", events[0])
+	}
+
+	if samples := server.LatencyHistory("SNAPSHOT"); len(samples) != 1 || samples[0].Latency != 30*time.Millisecond {
+		t.Errorf("expected 1 snapshot sample of 30ms, got %+v", samples)
+	}
+
+	if got := server.LatencyReset("aof-fsync", "unknown"); got != 1 {
+		t.Errorf("expected 1 event reset, got %d", got)
+	}
+	if events = server.LatencyLatest(); len(events) != 1 {
+		t.Errorf("expected 1 event after reset, got %+v", events)
+	}
+
+	if _, _, err := server.Set("LatencyKey", "value", SetOptions{}); err != nil {
+		t.Error(err)
+		return
+	}
+	histograms := server.LatencyHistogram("SET", "GET")
+	histogram, ok := histograms["set"]
+	if len(histograms) != 1 || !ok {
+		t.Fatalf("expected a histogram for set only, got %+v", histograms)
+	}
+	if histogram.Calls != 1 || len(histogram.Buckets) == 0 || histogram.Buckets[len(histogram.Buckets)-1].Count != 1 {
+		t.Errorf("expected 1 set call in the histogram, got %+v", histogram)
+	}
+}
+
+func TestEchoVault_Monitor(t *testing.T) {
+	server := createEchoVault()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	entries := server.Monitor(ctx)
+
+	if _, _, err := server.Set("MonitorKey", "value", SetOptions{}); err != nil {
+		t.Error(err)
+		return
+	}
+
+	select {
+	case entry := <-entries:
+		if entry.Source != "embedded" || entry.Database != 0 || entry.ClientAddr != "" {
+			t.Errorf("expected an embedded entry for database 0, got %+v", entry)
+		}
+		if want := []string{"SET", "MonitorKey", "value"}; !reflect.DeepEqual(entry.Command, want) {
+			t.Errorf("expected command %v, got %v", want, entry.Command)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the monitor entry")
+	}
+
+	cancel()
+	select {
+	case _, ok := <-entries:
+		if ok {
+			t.Error("expected the channel to be closed without further entries")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the channel to close")
+	}
+}
+
+func TestEchoVault_Config(t *testing.T) {
+	configFile := path.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configFile, []byte("Port: 7480\nMaxMemory: 0\n"), 0644); err != nil {
+		t.Error(err)
+		return
+	}
+
+	server := createEchoVaultWithConfig(config.Config{
+		DataDir:        "",
+		EvictionPolicy: constants.NoEviction,
+		ConfigFile:     configFile,
+	})
+
+	tests := []struct {
+		name      string
+		parameter string
+		value     string
+		wantErr   string
+	}{
+		{name: "1. Set max memory", parameter: "max-memory", value: "10mb"},
+		{name: "2. Set the eviction policy", parameter: "eviction-policy", value: "allkeys-lru"},
+		{name: "3. Set the AOF sync strategy", parameter: "aof-sync-strategy", value: "always"},
+		{name: "4. Set the slowlog max length", parameter: "slowlog-max-len", value: "10"},
+		{name: "5. Set the snapshot interval", parameter: "snapshot-interval", value: "1m"},
+		{name: "6. Reject an immutable parameter", parameter: "port", value: "7000", wantErr: "can't be changed"},
+		{name: "7. Reject an unknown parameter", parameter: "unknown", value: "1", wantErr: "unsupported CONFIG parameter"},
+		{name: "8. Reject an invalid value", parameter: "eviction-policy", value: "lru", wantErr: "not a valid policy"},
+		{name: "9. Reject require pass without a password", parameter: "require-pass", value: "yes", wantErr: "password cannot be empty"},
+	}
+
+	for _, test := range tests {
+		ok, err := server.ConfigSet(test.parameter, test.value)
+		if test.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("%s: expected error containing \"%s\", got %v", test.name, test.wantErr, err)
+			}
+			continue
+		}
+		if err != nil || !ok {
+			t.Errorf("%s: expected the parameter to be set, got %v", test.name, err)
+		}
+	}
+
+	values, err := server.ConfigGet("max-memory", "eviction-*", "port")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	expected := map[string]string{
+		"max-memory":        "10485760",
+		"eviction-policy":   "allkeys-lru",
+		"eviction-sample":   "0",
+		"eviction-interval": "0s",
+		"port":              "0",
+	}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("expected config %v, got %v", expected, values)
+	}
+
+	// The slow log picks up the new max length.
+	if _, _, err = server.Set("ConfigKey", "value", SetOptions{}); err != nil {
+		t.Error(err)
+		return
+	}
+	if server.SlowlogLen() == 0 {
+		t.Error("expected the slow log to record commands after slowlog-max-len is set")
+	}
+
+	if err = server.ConfigRewrite(); err != nil {
+		t.Error(err)
+		return
+	}
+	b, err := os.ReadFile(configFile)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for _, entry := range []string{"Port: 7480", "MaxMemory: 10485760", "EvictionPolicy: allkeys-lru", "AOFSyncStrategy: always"} {
+		if !strings.Contains(string(b), entry) {
+			t.Errorf("expected the rewritten config file to contain \"%s\", got:\n%s", entry, string(b))
+		}
+	}
+
+	if err = createEchoVault().ConfigRewrite(); err == nil || !strings.Contains(err.Error(), "without a config file") {
+		t.Errorf("expected an error when rewriting without a config file, got %v", err)
+	}
+}
+
+// syncBuffer is a bytes.Buffer that's safe to write to from the goroutines of the server.
+type syncBuffer struct {
+	mutex sync.Mutex
+	buf   bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buf.String()
+}
+
+func TestEchoVault_Logger(t *testing.T) {
+	out := new(syncBuffer)
+	// The custom logger writes every level, the LogLevel config decides which entries reach it.
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelDebug)
+	l, err := logger.New(out, logger.FormatJSON, level)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	server, err := NewEchoVault(
+		WithConfig(config.Config{
+			DataDir:        "",
+			EvictionPolicy: constants.NoEviction,
+			LogLevel:       "info",
+		}),
+		WithLogger(l),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	for _, key := range []string{"LoggerKey1", "LoggerKey2"} {
+		if _, _, err = server.Set(key, "value", SetOptions{}); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+
+	if _, err = server.Del("LoggerKey1"); err != nil {
+		t.Error(err)
+		return
+	}
+	if strings.Contains(out.String(), "deleted key") {
+		t.Errorf("expected the deletion not to be logged at the info level, got %s", out.String())
+	}
+
+	if _, err = server.ConfigSet("log-level", "DEBUG"); err != nil {
+		t.Error(err)
+		return
+	}
+	values, err := server.ConfigGet("log-level")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if values["log-level"] != "debug" {
+		t.Errorf("expected log-level \"debug\", got \"%s\"", values["log-level"])
+	}
+
+	if _, err = server.Del("LoggerKey2"); err != nil {
+		t.Error(err)
+		return
+	}
+	if !strings.Contains(out.String(), `"msg":"deleted key","database":0,"key":"LoggerKey2"`) {
+		t.Errorf("expected the deletion to be logged at the debug level, got %s", out.String())
+	}
+
+	if _, err = server.ConfigSet("log-level", "verbose"); err == nil {
+		t.Error("expected an unknown log level to be rejected")
+	}
+	if _, err = server.ConfigSet("log-format", "json"); err == nil {
+		t.Error("expected log-format not to be changeable while the server is running")
+	}
+}
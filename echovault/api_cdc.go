@@ -0,0 +1,63 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package echovault
+
+import (
+	"context"
+	"github.com/echovault/echovault/internal"
+)
+
+// MutationEvent is a change data capture event describing a single change to the keyspace.
+//
+// Offset - uint64 - the position of the event in the feed. Offsets start at 1 and increase by 1 per event.
+//
+// Type - string - one of "set", "del", "expire", "evict" or "flush". A "set" event is published when a key is
+// created, or when its value or expiry time changes.
+//
+// Database - int - the database of the key. -1 when every database is flushed.
+//
+// Key - string - the key that changed. Empty for flush events.
+//
+// Command - []string - the command that caused the change.
+//
+// Timestamp - time.Time - the time of the change. Changes replicated from another cluster keep the original time.
+//
+// Origin - string - the source ID of the cluster that made the change. Empty for local changes.
+type MutationEvent = internal.MutationEvent
+
+// SubscribeMutations returns a channel that receives every change to the keyspace from now on, in the order the
+// changes are applied. In cluster mode, every node emits the changes committed through raft.
+//
+// The channel is closed when the context is cancelled, or when the subscriber falls further behind than the
+// CDC backlog size. In that case, resume with SubscribeMutationsFrom and the offset after the last event received.
+func (server *EchoVault) SubscribeMutations(ctx context.Context) <-chan MutationEvent {
+	events, _ := server.cdc.Subscribe(ctx, 0)
+	return events
+}
+
+// SubscribeMutationsFrom returns a channel that receives the changes to the keyspace starting at the given offset,
+// followed by every new change.
+//
+// Errors:
+//
+// "offset <offset> is out of range" - when the offset is no longer in the CDC backlog, or is ahead of the feed.
+func (server *EchoVault) SubscribeMutationsFrom(ctx context.Context, offset uint64) (<-chan MutationEvent, error) {
+	return server.cdc.Subscribe(ctx, offset)
+}
+
+// MutationOffset returns the offset of the latest change data capture event.
+func (server *EchoVault) MutationOffset() uint64 {
+	return server.cdc.Offset()
+}
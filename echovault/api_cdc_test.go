@@ -0,0 +1,161 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package echovault
+
+import (
+	"context"
+	"fmt"
+	"github.com/echovault/echovault/internal"
+	"github.com/echovault/echovault/internal/clock"
+	"slices"
+	"testing"
+	"time"
+)
+
+// nextMutation returns the next event from the channel, failing the test if none arrives in time.
+func nextMutation(t *testing.T, events <-chan MutationEvent) MutationEvent {
+	select {
+	case event, ok := <-events:
+		if !ok {
+			t.Fatal("expected mutation event, channel is closed")
+		}
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for mutation event")
+	}
+	return MutationEvent{}
+}
+
+func TestEchoVault_SubscribeMutations(t *testing.T) {
+	server := createEchoVault()
+	t.Cleanup(server.ShutDown)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := server.SubscribeMutations(ctx)
+
+	if _, _, err := server.Set("MutationKey1", "value1", SetOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := server.Expire("MutationKey1", 10, ExpireOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := server.Del("MutationKey1"); err != nil {
+		t.Fatal(err)
+	}
+	presetKeyData(server, context.Background(), "MutationKey2", internal.KeyData{
+		Value:    "value2",
+		ExpireAt: clock.NewClock().Now().Add(-1 * time.Second),
+	})
+	if _, err := server.Get("MutationKey2"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		eventType string
+		key       string
+		command   []string
+	}{
+		{eventType: "set", key: "MutationKey1", command: []string{"SET", "MutationKey1", "value1"}},
+		{eventType: "set", key: "MutationKey1", command: []string{"EXPIRE", "MutationKey1", "10"}},
+		{eventType: "del", key: "MutationKey1", command: []string{"DEL", "MutationKey1"}},
+		// The value and the expiry time are set separately.
+		{eventType: "set", key: "MutationKey2", command: nil},
+		{eventType: "set", key: "MutationKey2", command: nil},
+		{eventType: "expire", key: "MutationKey2", command: []string{"DEL", "MutationKey2"}},
+	}
+	var first uint64
+	for i, e := range expected {
+		event := nextMutation(t, events)
+		if i == 0 {
+			first = event.Offset
+		}
+		if event.Offset != first+uint64(i) {
+			t.Errorf("expected event %d to have offset %d, got %d", i, first+uint64(i), event.Offset)
+		}
+		if event.Type != e.eventType || event.Key != e.key || !slices.Equal(event.Command, e.command) {
+			t.Errorf("expected event %d to be %s %s %v, got %s %s %v",
+				i, e.eventType, e.key, e.command, event.Type, event.Key, event.Command)
+		}
+	}
+
+	if offset := server.MutationOffset(); offset != first+uint64(len(expected))-1 {
+		t.Errorf("expected mutation offset %d, got %d", first+uint64(len(expected))-1, offset)
+	}
+
+	t.Run("Test resume from offset", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		events, err := server.SubscribeMutationsFrom(ctx, first+2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if event := nextMutation(t, events); event.Offset != first+2 || event.Type != "del" {
+			t.Errorf("expected del event at offset %d, got %+v", first+2, event)
+		}
+		cancel()
+		// The channel is closed once the context is cancelled.
+		for range events {
+		}
+	})
+
+	t.Run("Test resume from an offset ahead of the feed", func(t *testing.T) {
+		if _, err := server.SubscribeMutationsFrom(context.Background(), first+100); err == nil {
+			t.Error("expected error when the offset is ahead of the feed")
+		}
+	})
+}
+
+func TestEchoVault_ClusterSubscribeMutations(t *testing.T) {
+	leaderConf := clusterConfig(t, "CDC-0", true, "")
+	leader, err := NewEchoVault(WithContext(context.Background()), WithConfig(leaderConf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	go leader.Start()
+	t.Cleanup(leader.ShutDown)
+	if !eventually(t, 10*time.Second, leader.raft.IsRaftLeader) {
+		t.Fatal("expected node to become the raft leader")
+	}
+
+	followerConf := clusterConfig(t, "CDC-1", false,
+		fmt.Sprintf("%s/%s:%d", leaderConf.ServerID, leaderConf.BindAddr, leaderConf.DiscoveryPort))
+	follower, err := NewEchoVault(WithContext(context.Background()), WithConfig(followerConf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	go follower.Start()
+	t.Cleanup(follower.ShutDown)
+	if !eventually(t, 10*time.Second, follower.raft.HasJoinedCluster) {
+		t.Fatal("expected follower to join the cluster")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	leaderEvents := leader.SubscribeMutations(ctx)
+	followerEvents := follower.SubscribeMutations(ctx)
+
+	if _, _, err = leader.Set("ClusterMutationKey", "value", SetOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Every node emits the mutations applied through the raft FSM.
+	for name, events := range map[string]<-chan MutationEvent{"leader": leaderEvents, "follower": followerEvents} {
+		event := nextMutation(t, events)
+		if event.Type != "set" || event.Key != "ClusterMutationKey" ||
+			!slices.Equal(event.Command, []string{"SET", "ClusterMutationKey", "value"}) {
+			t.Errorf("expected %s to emit set event for ClusterMutationKey, got %+v", name, event)
+		}
+	}
+}
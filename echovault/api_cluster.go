@@ -0,0 +1,143 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package echovault
+
+import (
+	"errors"
+	"github.com/echovault/echovault/internal"
+	"github.com/echovault/echovault/internal/memberlist"
+)
+
+// InstallGossipKey adds a base64 encoded key to the gossip keyring of every node in the cluster.
+// The key is accepted for incoming messages but is not used to encrypt outgoing messages until UseGossipKey is
+// called. To rotate keys, install the new key, use it, and then remove the old key with RemoveGossipKey.
+//
+// Errors:
+//
+// "gossip keyring is only available in cluster mode" - when the instance is running in standalone mode.
+//
+// "gossip encryption is not enabled" - when the cluster was started without gossip keys.
+func (server *EchoVault) InstallGossipKey(key string) error {
+	if !server.isInCluster() {
+		return errors.New("gossip keyring is only available in cluster mode")
+	}
+	return server.memberList.InstallKey(key)
+}
+
+// UseGossipKey makes a base64 encoded key the key used to encrypt outgoing gossip on every node in the cluster.
+// The key must be installed with InstallGossipKey first.
+func (server *EchoVault) UseGossipKey(key string) error {
+	if !server.isInCluster() {
+		return errors.New("gossip keyring is only available in cluster mode")
+	}
+	return server.memberList.UseKey(key)
+}
+
+// RemoveGossipKey removes a base64 encoded key from the gossip keyring of every node in the cluster.
+// The key that's currently used to encrypt outgoing gossip cannot be removed.
+func (server *EchoVault) RemoveGossipKey(key string) error {
+	if !server.isInCluster() {
+		return errors.New("gossip keyring is only available in cluster mode")
+	}
+	return server.memberList.RemoveKey(key)
+}
+
+// ListGossipKeys returns the base64 encoded keys in the local gossip keyring, starting with the key used to
+// encrypt outgoing gossip.
+func (server *EchoVault) ListGossipKeys() ([]string, error) {
+	if !server.isInCluster() {
+		return nil, errors.New("gossip keyring is only available in cluster mode")
+	}
+	return server.memberList.ListKeys()
+}
+
+// GetClusterInfo returns the cluster membership state of the instance, including the state of its attempts to
+// join the cluster through the configured seeds.
+func (server *EchoVault) GetClusterInfo() internal.ClusterInfo {
+	if !server.isInCluster() {
+		return internal.ClusterInfo{Enabled: false, JoinState: memberlist.JoinStateNone, Ready: true}
+	}
+
+	joinState, attempts, err := server.memberList.JoinState()
+	info := internal.ClusterInfo{
+		Enabled:      true,
+		JoinState:    joinState,
+		JoinAttempts: attempts,
+		Seeds:        memberlist.Seeds(server.getConfig()),
+		Members:      server.memberList.NumMembers(),
+		RaftState:    server.raft.State(),
+		Leader:       server.raft.LeaderID(),
+	}
+	if err != nil {
+		info.JoinError = err.Error()
+	}
+	if voters, err := server.raft.Voters(); err == nil {
+		for _, voter := range voters {
+			if voter != info.Leader {
+				info.Followers = append(info.Followers, voter)
+			}
+		}
+	}
+	info.Ready = joinState != memberlist.JoinStateJoining &&
+		(server.raft.IsRaftLeader() || server.raft.HasJoinedCluster())
+
+	return info
+}
+
+// IsReady returns true when the instance is ready to serve requests. A standalone instance is always ready.
+// A cluster node is ready once it has joined the cluster and is either the raft leader or a follower of a leader.
+func (server *EchoVault) IsReady() bool {
+	return server.GetClusterInfo().Ready
+}
+
+// ForgetNode removes another node from the cluster's raft configuration.
+// This command must be executed on the cluster leader.
+//
+// Parameters:
+//
+// `serverId` - string - the server ID of the node to remove.
+//
+// Returns: "OK" when the node is removed.
+//
+// Errors:
+//
+// "cluster support disabled" - when the instance is running in standalone mode.
+//
+// "removing server <serverId> would leave <n> live voters, but a quorum of <q> is required" - when removing the
+// node would leave the cluster without a quorum.
+func (server *EchoVault) ForgetNode(serverId string) (string, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"CLUSTER", "FORGET", serverId}), nil, false, true)
+	if err != nil {
+		return "", err
+	}
+	return internal.ParseStringResponse(b)
+}
+
+// Decommission drains the current node out of the cluster. The node hands leadership over, waits to be removed
+// from the raft configuration, and leaves the memberlist. The node is only removed when the remaining voters can
+// still form a quorum.
+//
+// Returns: "OK" when the node has left the cluster.
+//
+// Errors:
+//
+// "cluster support disabled" - when the instance is running in standalone mode.
+func (server *EchoVault) Decommission() (string, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"CLUSTER", "DECOMMISSION"}), nil, false, true)
+	if err != nil {
+		return "", err
+	}
+	return internal.ParseStringResponse(b)
+}
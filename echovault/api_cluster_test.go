@@ -0,0 +1,358 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package echovault
+
+import (
+	"context"
+	"fmt"
+	"github.com/echovault/echovault/internal"
+	"github.com/echovault/echovault/internal/config"
+	"github.com/echovault/echovault/internal/constants"
+	hraft "github.com/hashicorp/raft"
+	"path"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func clusterConfig(t *testing.T, serverId string, bootstrapCluster bool, joinAddr string) config.Config {
+	port, err := internal.GetFreePort()
+	if err != nil {
+		t.Fatal(err)
+	}
+	discoveryPort, err := internal.GetFreePort()
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf := DefaultConfig()
+	conf.DataDir = ""
+	conf.ServerID = serverId
+	conf.BindAddr = getBindAddr().String()
+	conf.Port = uint16(port)
+	conf.DiscoveryPort = uint16(discoveryPort)
+	conf.BootstrapCluster = bootstrapCluster
+	conf.JoinAddr = joinAddr
+	conf.EvictionPolicy = constants.NoEviction
+	return conf
+}
+
+func secureClusterConfig(t *testing.T, serverId string, bootstrapCluster bool, joinAddr string) config.Config {
+	conf := clusterConfig(t, serverId, bootstrapCluster, joinAddr)
+	conf.GossipKeys = []string{"T3aM3Bd1WsCqjzfcdW3NxQVrpqtsRjJUu8EKw6Eu8hk="}
+	conf.RaftTLS = true
+	conf.CertKeyPairs = [][]string{{
+		path.Join("..", "openssl", "server", "server1.crt"),
+		path.Join("..", "openssl", "server", "server1.key"),
+	}}
+	conf.ClientCAs = []string{path.Join("..", "openssl", "server", "rootCA.crt")}
+	return conf
+}
+
+func TestEchoVault_SecureCluster(t *testing.T) {
+	leaderConf := secureClusterConfig(t, "SECURE-0", true, "")
+	leader, err := NewEchoVault(WithContext(context.Background()), WithConfig(leaderConf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	go leader.Start()
+	if !eventually(t, 10*time.Second, leader.raft.IsRaftLeader) {
+		t.Fatal("expected node to become the raft leader")
+	}
+
+	followerConf := secureClusterConfig(t, "SECURE-1", false,
+		fmt.Sprintf("%s/%s:%d", leaderConf.ServerID, leaderConf.BindAddr, leaderConf.DiscoveryPort))
+	follower, err := NewEchoVault(WithContext(context.Background()), WithConfig(followerConf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	go follower.Start()
+
+	t.Cleanup(func() {
+		follower.ShutDown()
+		leader.ShutDown()
+	})
+
+	if !eventually(t, 10*time.Second, follower.raft.HasJoinedCluster) {
+		t.Fatal("expected follower to join the cluster over the encrypted transports")
+	}
+
+	t.Run("Test writes are replicated over the TLS raft transport", func(t *testing.T) {
+		if _, _, err := leader.Set("SecureKey1", "value1", SetOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		if !eventually(t, 5*time.Second, func() bool {
+			value, _ := follower.Get("SecureKey1")
+			return value == "value1"
+		}) {
+			t.Error("expected SecureKey1 to be replicated to the follower")
+		}
+	})
+
+	t.Run("Test gossip key rotation", func(t *testing.T) {
+		oldKey := leaderConf.GossipKeys[0]
+		newKey := "HhV0NkFpS6E3rVbLn4a9bVZ7VnQm8C3bL6u2vJ4pG0k="
+
+		if err := leader.InstallGossipKey(newKey); err != nil {
+			t.Fatal(err)
+		}
+		if !eventually(t, 5*time.Second, func() bool {
+			keys, _ := follower.ListGossipKeys()
+			return slices.Contains(keys, newKey)
+		}) {
+			t.Fatal("expected new key to be installed on the follower")
+		}
+
+		if err := leader.UseGossipKey(newKey); err != nil {
+			t.Fatal(err)
+		}
+		if !eventually(t, 5*time.Second, func() bool {
+			keys, _ := follower.ListGossipKeys()
+			return len(keys) > 0 && keys[0] == newKey
+		}) {
+			t.Fatal("expected new key to be the primary key on the follower")
+		}
+
+		if err := leader.RemoveGossipKey(oldKey); err != nil {
+			t.Fatal(err)
+		}
+		if !eventually(t, 5*time.Second, func() bool {
+			keys, _ := follower.ListGossipKeys()
+			return slices.Equal(keys, []string{newKey})
+		}) {
+			keys, _ := follower.ListGossipKeys()
+			t.Fatalf("expected follower keyring to be [%s], got %v", newKey, keys)
+		}
+		if keys, _ := leader.ListGossipKeys(); !slices.Equal(keys, []string{newKey}) {
+			t.Errorf("expected leader keyring to be [%s], got %v", newKey, keys)
+		}
+
+		// The cluster keeps working after the rotation.
+		if _, _, err := leader.Set("SecureKey2", "value2", SetOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		if !eventually(t, 5*time.Second, func() bool {
+			value, _ := follower.Get("SecureKey2")
+			return value == "value2"
+		}) {
+			t.Error("expected SecureKey2 to be replicated to the follower")
+		}
+	})
+
+	t.Run("Test invalid gossip key is rejected", func(t *testing.T) {
+		conf := secureClusterConfig(t, "SECURE-2", false, followerConf.JoinAddr)
+		conf.GossipKeys = []string{"invalid"}
+		if _, err := NewEchoVault(WithContext(context.Background()), WithConfig(conf)); err == nil {
+			t.Error("expected error when the gossip key is invalid")
+		}
+	})
+
+	t.Run("Test gossip keyring is not available in standalone mode", func(t *testing.T) {
+		server := createEchoVault()
+		if err := server.InstallGossipKey(leaderConf.GossipKeys[0]); err == nil {
+			t.Error("expected error when installing a gossip key in standalone mode")
+		}
+	})
+}
+
+func TestEchoVault_ClusterJoinRetry(t *testing.T) {
+	leaderConf := clusterConfig(t, "SEED-0", true, "")
+
+	// The first seed does not exist, and the leader is not started yet, so the follower keeps retrying
+	// in the background instead of exiting.
+	deadPort, err := internal.GetFreePort()
+	if err != nil {
+		t.Fatal(err)
+	}
+	followerConf := clusterConfig(t, "SEED-1", false, "")
+	followerConf.Seeds = []string{
+		fmt.Sprintf("SEED-X/%s:%d", getBindAddr().String(), deadPort),
+		fmt.Sprintf("%s/%s:%d", leaderConf.ServerID, leaderConf.BindAddr, leaderConf.DiscoveryPort),
+	}
+	follower, err := NewEchoVault(WithContext(context.Background()), WithConfig(followerConf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	go follower.Start()
+	t.Cleanup(follower.ShutDown)
+
+	if !eventually(t, 5*time.Second, func() bool {
+		return follower.GetClusterInfo().JoinAttempts > 0
+	}) {
+		t.Fatal("expected the follower to retry joining the cluster")
+	}
+	info := follower.GetClusterInfo()
+	if info.JoinState != "joining" {
+		t.Errorf("expected join state \"joining\", got \"%s\"", info.JoinState)
+	}
+	if info.JoinError == "" {
+		t.Error("expected the latest join error to be reported")
+	}
+	if follower.IsReady() {
+		t.Error("expected the follower not to be ready before joining the cluster")
+	}
+
+	leader, err := NewEchoVault(WithContext(context.Background()), WithConfig(leaderConf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	go leader.Start()
+	t.Cleanup(leader.ShutDown)
+
+	if !eventually(t, 30*time.Second, follower.IsReady) {
+		t.Fatalf("expected the follower to join the cluster, got %+v", follower.GetClusterInfo())
+	}
+	info = follower.GetClusterInfo()
+	if info.JoinState != "joined" {
+		t.Errorf("expected join state \"joined\", got \"%s\"", info.JoinState)
+	}
+	if info.Leader != leaderConf.ServerID {
+		t.Errorf("expected leader %s, got %s", leaderConf.ServerID, info.Leader)
+	}
+	if !leader.IsReady() {
+		t.Error("expected the leader to be ready")
+	}
+
+	b, err := follower.handleCommand(follower.context, internal.EncodeCommand([]string{"INFO", "cluster"}), nil, false, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"cluster_enabled:1", "cluster_join_state:joined", "cluster_ready:1"} {
+		if !strings.Contains(string(b), expected) {
+			t.Errorf("expected INFO cluster to contain \"%s\", got \"%s\"", expected, string(b))
+		}
+	}
+}
+
+func TestEchoVault_ClusterLeave(t *testing.T) {
+	leaderConf := clusterConfig(t, "LEAVE-0", true, "")
+	leader, err := NewEchoVault(WithContext(context.Background()), WithConfig(leaderConf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	go leader.Start()
+	t.Cleanup(leader.ShutDown)
+	if !eventually(t, 10*time.Second, leader.raft.IsRaftLeader) {
+		t.Fatal("expected node to become the raft leader")
+	}
+
+	joinAddr := fmt.Sprintf("%s/%s:%d", leaderConf.ServerID, leaderConf.BindAddr, leaderConf.DiscoveryPort)
+	followers := make([]*EchoVault, 3)
+	for i := range followers {
+		conf := clusterConfig(t, fmt.Sprintf("LEAVE-%d", i+1), false, joinAddr)
+		followers[i], err = NewEchoVault(WithContext(context.Background()), WithConfig(conf))
+		if err != nil {
+			t.Fatal(err)
+		}
+		go followers[i].Start()
+		t.Cleanup(followers[i].ShutDown)
+		if !eventually(t, 10*time.Second, followers[i].raft.HasJoinedCluster) {
+			t.Fatalf("expected %s to join the cluster", conf.ServerID)
+		}
+	}
+	if !eventually(t, 10*time.Second, func() bool {
+		voters, _ := leader.raft.Voters()
+		return len(voters) == 4
+	}) {
+		t.Fatal("expected 4 voters in the cluster")
+	}
+
+	t.Run("Test CLUSTER DECOMMISSION removes the node from the raft configuration", func(t *testing.T) {
+		res, err := followers[0].Decommission()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res != "OK" {
+			t.Errorf("expected response \"OK\", got \"%s\"", res)
+		}
+		voters, _ := leader.raft.Voters()
+		if slices.Contains(voters, "LEAVE-1") {
+			t.Errorf("expected LEAVE-1 to be removed from the voters, got %v", voters)
+		}
+		if _, err = followers[0].Decommission(); err == nil {
+			t.Error("expected error when decommissioning a node that has left the cluster")
+		}
+		if _, _, err = followers[0].Set("key1", "value1", SetOptions{}); err == nil {
+			t.Error("expected error when writing to a node that has left the cluster")
+		}
+	})
+
+	t.Run("Test CLUSTER FORGET validation", func(t *testing.T) {
+		if _, err := followers[2].ForgetNode("LEAVE-2"); err == nil {
+			t.Error("expected error when CLUSTER FORGET is not sent to the leader")
+		}
+		if _, err := leader.ForgetNode("LEAVE-0"); err == nil {
+			t.Error("expected error when forgetting the current node")
+		}
+		if _, err := leader.ForgetNode("LEAVE-X"); err == nil {
+			t.Error("expected error when forgetting a node that is not in the cluster")
+		}
+	})
+
+	t.Run("Test CLUSTER FORGET refuses to break the quorum and removes dead nodes", func(t *testing.T) {
+		// Add two voters that are not running. LEAVE-0, LEAVE-2 and LEAVE-3 are the only live voters out of 5.
+		for _, id := range []string{"LEAVE-8", "LEAVE-9"} {
+			deadPort, err := internal.GetFreePort()
+			if err != nil {
+				t.Fatal(err)
+			}
+			address := hraft.ServerAddress(fmt.Sprintf("%s:%d", getBindAddr().String(), deadPort))
+			if err = leader.raft.AddVoter(hraft.ServerID(id), address, 0, 5*time.Second); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// Removing LEAVE-3 would leave 2 live voters out of 4, which is not a quorum.
+		if _, err := leader.ForgetNode("LEAVE-3"); err == nil {
+			t.Error("expected error when forgetting the node breaks the quorum")
+		}
+
+		for _, id := range []string{"LEAVE-9", "LEAVE-8"} {
+			res, err := leader.ForgetNode(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res != "OK" {
+				t.Errorf("expected response \"OK\", got \"%s\"", res)
+			}
+		}
+		voters, _ := leader.raft.Voters()
+		if !slices.Equal(voters, []string{"LEAVE-0", "LEAVE-2", "LEAVE-3"}) {
+			t.Errorf("expected voters [LEAVE-0 LEAVE-2 LEAVE-3], got %v", voters)
+		}
+	})
+
+	t.Run("Test graceful shutdown removes the node from the raft configuration", func(t *testing.T) {
+		followers[1].ShutDown()
+		followers[2].ShutDown()
+		voters, _ := leader.raft.Voters()
+		if !slices.Equal(voters, []string{"LEAVE-0"}) {
+			t.Errorf("expected only LEAVE-0 to be left in the voters, got %v", voters)
+		}
+		if _, err := leader.Decommission(); err == nil {
+			t.Error("expected error when decommissioning the last voter")
+		}
+	})
+
+	t.Run("Test cluster commands are disabled in standalone mode", func(t *testing.T) {
+		server := createEchoVault()
+		if _, err := server.ForgetNode("LEAVE-1"); err == nil {
+			t.Error("expected error when forgetting a node in standalone mode")
+		}
+		if _, err := server.Decommission(); err == nil {
+			t.Error("expected error when decommissioning in standalone mode")
+		}
+	})
+}
@@ -16,7 +16,9 @@ package echovault
 
 import (
 	"errors"
+	"github.com/echovault/echovault/internal"
 	"slices"
+	"time"
 )
 
 // SetProtocol sets the RESP protocol that's expected from responses to embedded API calls.
@@ -68,3 +70,30 @@ func (server *EchoVault) SelectDB(database int) error {
 
 	return nil
 }
+
+// ClientInfo holds the information of a TCP connection returned by ClientList.
+type ClientInfo = internal.ConnectionInfo
+
+// ClientList returns the information of the TCP connections, sorted by id.
+// Use ClientInfo.Type and ClientInfo.Flags to get the type and flags reported by CLIENT LIST.
+func (server *EchoVault) ClientList() []ClientInfo {
+	return server.listConnections()
+}
+
+// ClientKill closes the TCP connections with the given ids.
+//
+// Returns: The number of connections closed.
+func (server *EchoVault) ClientKill(ids ...uint64) int {
+	return server.killConnections(nil, ids)
+}
+
+// ClientPause suspends the commands of the clients, including embedded API calls, for the timeout.
+// When writeOnly is true, only the write commands are suspended. The CLIENT commands are never suspended.
+func (server *EchoVault) ClientPause(timeout time.Duration, writeOnly bool) {
+	server.pauseClients(timeout, writeOnly)
+}
+
+// ClientUnpause resumes the commands suspended by ClientPause or CLIENT PAUSE.
+func (server *EchoVault) ClientUnpause() {
+	server.unpauseClients()
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/tidwall/resp"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestEchoVault_Hello(t *testing.T) {
@@ -283,3 +284,47 @@ func TestEchoVault_SetProtocol(t *testing.T) {
 		})
 	}
 }
+
+func TestEchoVault_ClientPause(t *testing.T) {
+	server := createEchoVault()
+
+	if clients := server.ClientList(); len(clients) != 0 {
+		t.Errorf("ClientList() expected no TCP connections, got %d", len(clients))
+	}
+
+	// Writes are suspended by a write pause, reads are not.
+	server.ClientPause(10*time.Second, true)
+	done := make(chan error)
+	go func() {
+		_, _, err := server.Set("key", "value", SetOptions{})
+		done <- err
+	}()
+	if _, err := server.Get("key"); err != nil {
+		t.Errorf("Get() error = %v", err)
+	}
+	select {
+	case <-done:
+		t.Error("Set() expected to be suspended while paused")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	server.ClientUnpause()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Set() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("Set() expected to complete after ClientUnpause()")
+	}
+
+	// The pause ends by itself after the timeout.
+	server.ClientPause(50*time.Millisecond, false)
+	start := time.Now()
+	if _, err := server.Get("key"); err != nil {
+		t.Errorf("Get() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("Get() expected to be suspended until the pause ends, returned after %v", elapsed)
+	}
+}
@@ -79,6 +79,8 @@ type PExpireAtOptions ExpireOptions
 //
 // `options` - SetOptions.
 //
+// `writeOptions` - WriteOption - optional, e.g. WithDurability to wait for the write to be acknowledged.
+//
 // Returns: true if the set is successful, If the "Get" flag in SetOptions is set to true, the previous value is returned.
 //
 // Errors:
@@ -86,7 +88,7 @@ type PExpireAtOptions ExpireOptions
 // "key <key> does not exist"" - when the XX flag is set to true and the key does not exist.
 //
 // "key <key> does already exists" - when the NX flag is set to true and the key already exists.
-func (server *EchoVault) Set(key, value string, options SetOptions) (string, bool, error) {
+func (server *EchoVault) Set(key, value string, options SetOptions, writeOptions ...WriteOption) (string, bool, error) {
 	cmd := []string{"SET", key, value}
 
 	switch {
@@ -124,6 +126,10 @@ func (server *EchoVault) Set(key, value string, options SetOptions) (string, boo
 		previousValue = ""
 	}
 
+	if err = server.awaitDurability(writeOptions); err != nil {
+		return previousValue, true, err
+	}
+
 	return previousValue, true, nil
 }
 
@@ -134,12 +140,14 @@ func (server *EchoVault) Set(key, value string, options SetOptions) (string, boo
 //
 // `kvPairs` - map[string]string - a map representing all the keys and values to be set.
 //
+// `writeOptions` - WriteOption - optional, e.g. WithDurability to wait for the write to be acknowledged.
+//
 // Returns: true if the set is successful.
 //
 // Errors:
 //
 // "key <key> already exists" - when the NX flag is set to true and the key already exists.
-func (server *EchoVault) MSet(kvPairs map[string]string) (bool, error) {
+func (server *EchoVault) MSet(kvPairs map[string]string, writeOptions ...WriteOption) (bool, error) {
 	cmd := []string{"MSET"}
 
 	for k, v := range kvPairs {
@@ -156,6 +164,10 @@ func (server *EchoVault) MSet(kvPairs map[string]string) (bool, error) {
 		return false, err
 	}
 
+	if err = server.awaitDurability(writeOptions); err != nil {
+		return strings.EqualFold(s, "ok"), err
+	}
+
 	return strings.EqualFold(s, "ok"), nil
 }
 
@@ -578,3 +590,49 @@ func (server *EchoVault) GetDel(key string) (string, error) {
 	}
 	return internal.ParseStringResponse(b)
 }
+
+// ObjectFreq returns the logarithmic access frequency counter of the key.
+// The counter is only tracked when the eviction policy is allkeys-lfu or volatile-lfu.
+//
+// Parameters:
+//
+// `key` - string - the key whose access frequency should be returned.
+//
+// Returns: The access frequency counter of the key, or -1 when the key does not exist.
+//
+// Errors:
+//
+// "an lfu eviction policy is not selected, access frequency not tracked" - when the eviction policy is not an lfu policy.
+func (server *EchoVault) ObjectFreq(key string) (int, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"OBJECT", "FREQ", key}), nil, false, true)
+	if err != nil {
+		return 0, err
+	}
+	if isNil, _ := internal.ParseNilResponse(b); isNil {
+		return -1, nil
+	}
+	return internal.ParseIntegerResponse(b)
+}
+
+// ObjectIdleTime returns the number of seconds since the key was last read or written.
+// The idle time is not tracked when the eviction policy is allkeys-lfu or volatile-lfu.
+//
+// Parameters:
+//
+// `key` - string - the key whose idle time should be returned.
+//
+// Returns: The idle time of the key in seconds, or -1 when the key does not exist.
+//
+// Errors:
+//
+// "an lfu eviction policy is selected, idle time not tracked" - when the eviction policy is an lfu policy.
+func (server *EchoVault) ObjectIdleTime(key string) (int, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"OBJECT", "IDLETIME", key}), nil, false, true)
+	if err != nil {
+		return 0, err
+	}
+	if isNil, _ := internal.ParseNilResponse(b); isNil {
+		return -1, nil
+	}
+	return internal.ParseIntegerResponse(b)
+}
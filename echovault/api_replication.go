@@ -0,0 +1,199 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package echovault
+
+import (
+	"errors"
+	"fmt"
+	"github.com/echovault/echovault/internal"
+	"strconv"
+	"time"
+)
+
+// ReplicaOf makes this instance an asynchronous replica of the primary listening at host:port.
+// The instance keeps serving reads from its own dataset while it synchronizes with the primary, and rejects writes
+// until it's promoted with ReplicaOfNoOne. Only supported in standalone mode.
+//
+// Returns: "OK" when the replication link has been configured.
+func (server *EchoVault) ReplicaOf(host string, port int) (string, error) {
+	b, err := server.handleCommand(
+		server.context,
+		internal.EncodeCommand([]string{"REPLICAOF", host, strconv.Itoa(port)}),
+		nil,
+		false,
+		true,
+	)
+	if err != nil {
+		return "", err
+	}
+	return internal.ParseStringResponse(b)
+}
+
+// ReplicaOfNoOne stops replicating from the primary and promotes this instance to a primary.
+// The dataset is kept as is.
+//
+// Returns: "OK" when the instance has been promoted.
+func (server *EchoVault) ReplicaOfNoOne() (string, error) {
+	b, err := server.handleCommand(
+		server.context,
+		internal.EncodeCommand([]string{"REPLICAOF", "NO", "ONE"}),
+		nil,
+		false,
+		true,
+	)
+	if err != nil {
+		return "", err
+	}
+	return internal.ParseStringResponse(b)
+}
+
+// GetReplicationInfo returns the replication role of the instance, its replication ID and offset, the state of
+// its link to the primary, and the replicas attached to it.
+func (server *EchoVault) GetReplicationInfo() internal.ReplicationInfo {
+	return server.replication.Info()
+}
+
+// Wait blocks until the writes issued before it are acknowledged by at least numReplicas replicas or the timeout
+// elapses. A timeout of 0 blocks indefinitely. In cluster mode, the writes are acknowledged once they're committed
+// by the raft quorum.
+//
+// Returns: The number of replicas that acknowledged the writes.
+//
+// Errors:
+//
+// "WAIT cannot be used with replica instances" - when the instance is a replica or a raft follower.
+func (server *EchoVault) Wait(numReplicas int, timeout time.Duration) (int, error) {
+	b, err := server.handleCommand(
+		server.context,
+		internal.EncodeCommand([]string{
+			"WAIT", strconv.Itoa(numReplicas), strconv.FormatInt(timeout.Milliseconds(), 10),
+		}),
+		nil,
+		false,
+		true,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return internal.ParseIntegerResponse(b)
+}
+
+// WaitAOF blocks until the writes issued before it are fsynced to the local append-only log (when numLocal is 1)
+// and by at least numReplicas replicas, or the timeout elapses. A timeout of 0 blocks indefinitely.
+//
+// Returns: The number of local fsyncs (0 or 1) and the number of replicas that fsynced the writes.
+//
+// Errors:
+//
+// "WAITAOF cannot be used when numlocal is set but appendonly is disabled" - when numLocal is 1 and there's no
+// data directory configured.
+func (server *EchoVault) WaitAOF(numLocal int, numReplicas int, timeout time.Duration) (int, int, error) {
+	b, err := server.handleCommand(
+		server.context,
+		internal.EncodeCommand([]string{
+			"WAITAOF", strconv.Itoa(numLocal), strconv.Itoa(numReplicas),
+			strconv.FormatInt(timeout.Milliseconds(), 10),
+		}),
+		nil,
+		false,
+		true,
+	)
+	if err != nil {
+		return 0, 0, err
+	}
+	res, err := internal.ParseIntegerArrayResponse(b)
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(res) != 2 {
+		return 0, 0, fmt.Errorf("unexpected WAITAOF response %v", res)
+	}
+	return res[0], res[1], nil
+}
+
+// Durability describes the acknowledgements that a write must receive before it's reported as successful.
+//
+// AOF - Wait for the write to be fsynced to the append-only log, locally and by the replicas.
+//
+// Replicas - The number of replicas that must acknowledge the write.
+//
+// Timeout - How long to wait for the acknowledgements. A timeout of 0 blocks indefinitely.
+type Durability struct {
+	AOF      bool
+	Replicas int
+	Timeout  time.Duration
+}
+
+// WriteOption modifies how a write issued through the embedded API is performed.
+type WriteOption func(options *writeOptions)
+
+type writeOptions struct {
+	durability *Durability
+}
+
+// WithDurability makes the write wait until it reaches the given durability. The write is applied either way,
+// but an error is returned when the durability is not reached before the timeout.
+func WithDurability(durability Durability) WriteOption {
+	return func(options *writeOptions) {
+		options.durability = &durability
+	}
+}
+
+// awaitDurability waits for the durability requested in the write options, if any.
+func (server *EchoVault) awaitDurability(options []WriteOption) error {
+	opts := &writeOptions{}
+	for _, option := range options {
+		option(opts)
+	}
+	if opts.durability == nil {
+		return nil
+	}
+	return server.WaitForDurability(*opts.durability)
+}
+
+// WaitForDurability blocks until all the writes issued so far reach the given durability. Use it after the
+// write APIs that don't accept a WriteOption, e.g. Del, HSet or LPush, to wait for their acknowledgements.
+//
+// Parameters:
+//
+// `d` - Durability - the acknowledgements to wait for.
+//
+// Errors:
+//
+// "durability not reached: ..." - when the acknowledgements are not received before the timeout.
+func (server *EchoVault) WaitForDurability(d Durability) error {
+	if !d.AOF {
+		replicas, err := server.Wait(d.Replicas, d.Timeout)
+		if err != nil {
+			return err
+		}
+		if replicas < d.Replicas {
+			return fmt.Errorf("durability not reached: %d of %d replicas acknowledged the write", replicas, d.Replicas)
+		}
+		return nil
+	}
+
+	local, replicas, err := server.WaitAOF(1, d.Replicas, d.Timeout)
+	if err != nil {
+		return err
+	}
+	if local < 1 {
+		return errors.New("durability not reached: the write was not fsynced to the local append-only log")
+	}
+	if replicas < d.Replicas {
+		return fmt.Errorf("durability not reached: %d of %d replicas fsynced the write", replicas, d.Replicas)
+	}
+	return nil
+}
@@ -0,0 +1,297 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package echovault
+
+import (
+	"github.com/echovault/echovault/internal"
+	"github.com/echovault/echovault/internal/config"
+	"github.com/echovault/echovault/internal/constants"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func setupReplicationServer(t *testing.T, dataDir string) (*EchoVault, int) {
+	port, err := internal.GetFreePort()
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := NewEchoVault(
+		WithConfig(config.Config{
+			BindAddr:        "localhost",
+			Port:            uint16(port),
+			DataDir:         dataDir,
+			AOFSyncStrategy: "everysec",
+			EvictionPolicy:  constants.NoEviction,
+			ReplBacklogSize: 1024 * 1024,
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		server.Start()
+	}()
+	t.Cleanup(func() {
+		server.ShutDown()
+	})
+	return server, port
+}
+
+// eventually retries the condition until it's true or the timeout elapses.
+func eventually(t *testing.T, timeout time.Duration, condition func() bool) bool {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if condition() {
+			return true
+		}
+		<-time.After(10 * time.Millisecond)
+	}
+	return condition()
+}
+
+func TestEchoVault_Replication(t *testing.T) {
+	primary, primaryPort := setupReplicationServer(t, "")
+	replica, _ := setupReplicationServer(t, "")
+
+	// Preset values of each type on the primary before the replica connects.
+	if _, _, err := primary.Set("ReplicationKey1", "value1", SetOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := primary.HSet("ReplicationHash1", map[string]string{"field1": "value1", "field2": "2"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := primary.RPush("ReplicationList1", "one", "two", "three"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := primary.SAdd("ReplicationSet1", "one", "two"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := primary.ZAdd("ReplicationZSet1", map[string]float64{"one": 1.5, "two": 2}, ZAddOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := primary.Incr("ReplicationCounter1"); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Test full resync", func(t *testing.T) {
+		if _, err := replica.ReplicaOf("localhost", primaryPort); err != nil {
+			t.Fatal(err)
+		}
+		if !eventually(t, 5*time.Second, func() bool {
+			return replica.GetReplicationInfo().LinkStatus == "connected"
+		}) {
+			t.Fatalf("expected replica link to be connected, got %s", replica.GetReplicationInfo().LinkStatus)
+		}
+
+		if value, _ := replica.Get("ReplicationKey1"); value != "value1" {
+			t.Errorf("expected ReplicationKey1 to be \"value1\", got \"%s\"", value)
+		}
+		if res, _ := replica.HGetAll("ReplicationHash1"); len(res) != 4 {
+			t.Errorf("expected ReplicationHash1 to have 2 fields, got %v", res)
+		}
+		if res, _ := replica.LRange("ReplicationList1", 0, -1); !slices.Equal(res, []string{"one", "two", "three"}) {
+			t.Errorf("expected ReplicationList1 to be [one two three], got %v", res)
+		}
+		if res, _ := replica.SMembers("ReplicationSet1"); len(res) != 2 {
+			t.Errorf("expected ReplicationSet1 to have 2 members, got %v", res)
+		}
+		if res, _ := replica.ZScore("ReplicationZSet1", "one"); res != 1.5 {
+			t.Errorf("expected score of member one to be 1.5, got %v", res)
+		}
+		// Integers must keep their type so that the replica can apply INCR from the stream.
+		if _, err := primary.Incr("ReplicationCounter1"); err != nil {
+			t.Fatal(err)
+		}
+		if !eventually(t, 5*time.Second, func() bool {
+			value, _ := replica.Get("ReplicationCounter1")
+			return value == "2"
+		}) {
+			value, _ := replica.Get("ReplicationCounter1")
+			t.Errorf("expected ReplicationCounter1 to be \"2\", got \"%s\"", value)
+		}
+	})
+
+	t.Run("Test writes are streamed to the replica", func(t *testing.T) {
+		if _, _, err := primary.Set("ReplicationKey2", "value2", SetOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		if err := primary.SelectDB(1); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := primary.Set("ReplicationKey3", "value3", SetOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		if err := primary.SelectDB(0); err != nil {
+			t.Fatal(err)
+		}
+
+		if !eventually(t, 5*time.Second, func() bool {
+			return replica.GetReplicationInfo().Offset == primary.GetReplicationInfo().Offset
+		}) {
+			t.Errorf("expected replica offset %d to reach primary offset %d",
+				replica.GetReplicationInfo().Offset, primary.GetReplicationInfo().Offset)
+		}
+
+		if value, _ := replica.Get("ReplicationKey2"); value != "value2" {
+			t.Errorf("expected ReplicationKey2 to be \"value2\", got \"%s\"", value)
+		}
+		if value, _ := replica.Get("ReplicationKey3"); value != "" {
+			t.Errorf("expected ReplicationKey3 to not exist in database 0, got \"%s\"", value)
+		}
+		if err := replica.SelectDB(1); err != nil {
+			t.Fatal(err)
+		}
+		if value, _ := replica.Get("ReplicationKey3"); value != "value3" {
+			t.Errorf("expected ReplicationKey3 to be \"value3\" in database 1, got \"%s\"", value)
+		}
+		if err := replica.SelectDB(0); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Test replica rejects writes", func(t *testing.T) {
+		_, _, err := replica.Set("ReplicationKey4", "value4", SetOptions{})
+		if err == nil || !strings.Contains(err.Error(), "READONLY") {
+			t.Errorf("expected READONLY error, got %v", err)
+		}
+	})
+
+	t.Run("Test replication info", func(t *testing.T) {
+		primaryInfo := primary.GetReplicationInfo()
+		replicaInfo := replica.GetReplicationInfo()
+		if primaryInfo.Role != "master" {
+			t.Errorf("expected primary role to be master, got %s", primaryInfo.Role)
+		}
+		if len(primaryInfo.Replicas) != 1 {
+			t.Fatalf("expected 1 replica attached to the primary, got %d", len(primaryInfo.Replicas))
+		}
+		if replicaInfo.Role != "slave" {
+			t.Errorf("expected replica role to be slave, got %s", replicaInfo.Role)
+		}
+		if replicaInfo.ReplID != primaryInfo.ReplID {
+			t.Errorf("expected replica replication ID %s, got %s", primaryInfo.ReplID, replicaInfo.ReplID)
+		}
+		if replicaInfo.PrimaryPort != primaryPort {
+			t.Errorf("expected replica primary port %d, got %d", primaryPort, replicaInfo.PrimaryPort)
+		}
+		if primaryInfo.SyncFull != 1 {
+			t.Errorf("expected 1 full resync, got %d", primaryInfo.SyncFull)
+		}
+	})
+
+	t.Run("Test REPLICAOF NO ONE promotes the replica", func(t *testing.T) {
+		if _, err := replica.ReplicaOfNoOne(); err != nil {
+			t.Fatal(err)
+		}
+		info := replica.GetReplicationInfo()
+		if info.Role != "master" {
+			t.Errorf("expected promoted replica role to be master, got %s", info.Role)
+		}
+		if _, _, err := replica.Set("ReplicationKey4", "value4", SetOptions{}); err != nil {
+			t.Errorf("expected promoted replica to accept writes, got %v", err)
+		}
+		if value, _ := replica.Get("ReplicationKey1"); value != "value1" {
+			t.Errorf("expected promoted replica to keep its dataset, got \"%s\" for ReplicationKey1", value)
+		}
+	})
+}
+
+func TestEchoVault_WaitDurability(t *testing.T) {
+	primary, primaryPort := setupReplicationServer(t, t.TempDir())
+	replica, _ := setupReplicationServer(t, t.TempDir())
+
+	t.Run("Test WAIT without replicas", func(t *testing.T) {
+		if _, _, err := primary.Set("WaitKey1", "value1", SetOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		replicas, err := primary.Wait(1, 100*time.Millisecond)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if replicas != 0 {
+			t.Errorf("expected 0 replicas to acknowledge the write, got %d", replicas)
+		}
+		// The write is applied but the durability is not reached.
+		_, _, err = primary.Set("WaitKey2", "value2", SetOptions{},
+			WithDurability(Durability{Replicas: 1, Timeout: 100 * time.Millisecond}))
+		if err == nil || !strings.Contains(err.Error(), "durability not reached") {
+			t.Errorf("expected durability error, got %v", err)
+		}
+		if value, _ := primary.Get("WaitKey2"); value != "value2" {
+			t.Errorf("expected WaitKey2 to be \"value2\", got \"%s\"", value)
+		}
+	})
+
+	t.Run("Test WAITAOF fsyncs the local append-only log", func(t *testing.T) {
+		if _, _, err := primary.Set("WaitKey3", "value3", SetOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		local, replicas, err := primary.WaitAOF(1, 0, 5*time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if local != 1 || replicas != 0 {
+			t.Errorf("expected WAITAOF response [1 0], got [%d %d]", local, replicas)
+		}
+	})
+
+	if _, err := replica.ReplicaOf("localhost", primaryPort); err != nil {
+		t.Fatal(err)
+	}
+	if !eventually(t, 5*time.Second, func() bool {
+		return replica.GetReplicationInfo().LinkStatus == "connected"
+	}) {
+		t.Fatalf("expected replica link to be connected, got %s", replica.GetReplicationInfo().LinkStatus)
+	}
+
+	t.Run("Test WAIT with a replica", func(t *testing.T) {
+		if _, _, err := primary.Set("WaitKey4", "value4", SetOptions{},
+			WithDurability(Durability{Replicas: 1, Timeout: 5 * time.Second})); err != nil {
+			t.Fatal(err)
+		}
+		// The write is visible on the replica once it's acknowledged.
+		if value, _ := replica.Get("WaitKey4"); value != "value4" {
+			t.Errorf("expected WaitKey4 to be \"value4\" on the replica, got \"%s\"", value)
+		}
+		if _, err := replica.Wait(0, 0); err == nil {
+			t.Error("expected WAIT to return error on a replica")
+		}
+	})
+
+	t.Run("Test WaitForDurability after a write without write options", func(t *testing.T) {
+		if _, err := primary.Del("WaitKey4"); err != nil {
+			t.Fatal(err)
+		}
+		if err := primary.WaitForDurability(Durability{Replicas: 1, Timeout: 5 * time.Second}); err != nil {
+			t.Fatal(err)
+		}
+		if value, _ := replica.Get("WaitKey4"); value != "" {
+			t.Errorf("expected WaitKey4 to be deleted on the replica, got \"%s\"", value)
+		}
+	})
+
+	t.Run("Test WAITAOF with a replica", func(t *testing.T) {
+		if _, err := primary.MSet(map[string]string{"WaitKey5": "value5", "WaitKey6": "value6"},
+			WithDurability(Durability{AOF: true, Replicas: 1, Timeout: 10 * time.Second})); err != nil {
+			t.Fatal(err)
+		}
+		if value, _ := replica.Get("WaitKey5"); value != "value5" {
+			t.Errorf("expected WaitKey5 to be \"value5\" on the replica, got \"%s\"", value)
+		}
+	})
+}
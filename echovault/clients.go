@@ -0,0 +1,201 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package echovault
+
+import (
+	"cmp"
+	"context"
+	"net"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/echovault/echovault/internal"
+	"github.com/echovault/echovault/internal/logger"
+)
+
+// connHandle holds the handles of a TCP connection and the statistics updated by each of its commands.
+// The statistics are atomics so that a command does not lock the map of connections to update them.
+type connHandle struct {
+	cancel     context.CancelFunc // Cancels the context of the connection, which closes it.
+	writeMutex sync.Mutex         // Keeps the messages pushed to the connection from interleaving with the replies.
+
+	database        atomic.Int64           // The index of the database selected by the connection.
+	lastInteraction atomic.Int64           // The time of the last command in unix nanoseconds.
+	lastCommand     atomic.Pointer[string] // The name of the last command, e.g. "get" or "client|list".
+	queryBuffer     atomic.Int64           // The size in bytes of the last command.
+	outputBuffer    atomic.Int64           // The size in bytes of the last reply.
+}
+
+// recordCommand records the command in the statistics of the connection.
+func (handle *connHandle) recordCommand(now time.Time, name string, size int) {
+	handle.lastInteraction.Store(now.UnixNano())
+	handle.lastCommand.Store(&name)
+	handle.queryBuffer.Store(int64(size))
+}
+
+// fill copies the statistics of the connection into its information.
+func (handle *connHandle) fill(info *internal.ConnectionInfo) {
+	info.Database = int(handle.database.Load())
+	info.LastInteraction = time.Unix(0, handle.lastInteraction.Load())
+	if name := handle.lastCommand.Load(); name != nil {
+		info.LastCommand = *name
+	}
+	info.QueryBuffer = int(handle.queryBuffer.Load())
+	info.OutputBuffer = int(handle.outputBuffer.Load())
+}
+
+// connectionInfo returns the information of the TCP connection.
+func (server *EchoVault) connectionInfo(conn *net.Conn) internal.ConnectionInfo {
+	server.connInfo.mut.RLock()
+	defer server.connInfo.mut.RUnlock()
+	info := server.connInfo.tcpClients[conn]
+	if handle, ok := server.connInfo.handles[conn]; ok {
+		handle.fill(&info)
+	}
+	return info
+}
+
+// updateConnectionInfo applies the update to the information of the TCP connection.
+func (server *EchoVault) updateConnectionInfo(conn *net.Conn, update func(info *internal.ConnectionInfo)) {
+	server.connInfo.mut.Lock()
+	defer server.connInfo.mut.Unlock()
+	info, ok := server.connInfo.tcpClients[conn]
+	if !ok {
+		return
+	}
+	update(&info)
+	server.connInfo.tcpClients[conn] = info
+}
+
+// listConnections returns the information of the TCP connections, sorted by id.
+// The authenticated user, the subscriptions and whether the connection is a replica are read from the modules
+// that own them.
+func (server *EchoVault) listConnections() []internal.ConnectionInfo {
+	server.connInfo.mut.RLock()
+	conns := make([]*net.Conn, 0, len(server.connInfo.tcpClients))
+	infos := make([]internal.ConnectionInfo, 0, len(server.connInfo.tcpClients))
+	for conn, info := range server.connInfo.tcpClients {
+		if handle, ok := server.connInfo.handles[conn]; ok {
+			handle.fill(&info)
+		}
+		conns = append(conns, conn)
+		infos = append(infos, info)
+	}
+	server.connInfo.mut.RUnlock()
+
+	for i, conn := range conns {
+		if server.acl != nil {
+			infos[i].User = server.acl.ConnectionUser(conn)
+		}
+		infos[i].Subscriptions, infos[i].PatternSubscriptions = server.pubSub.Subscriptions(conn)
+		infos[i].Replica = server.replication.IsReplicaConnection(conn)
+		infos[i].Tracking = server.tracking.Enabled(infos[i].Id)
+	}
+
+	slices.SortFunc(infos, func(a, b internal.ConnectionInfo) int {
+		return cmp.Compare(a.Id, b.Id)
+	})
+	return infos
+}
+
+// killConnections closes the TCP connections with the given ids and returns the number of connections closed.
+// The connection that sent the command is closed after its reply is written.
+func (server *EchoVault) killConnections(current *net.Conn, ids []uint64) int {
+	server.connInfo.mut.RLock()
+	defer server.connInfo.mut.RUnlock()
+
+	var killed int
+	for conn, info := range server.connInfo.tcpClients {
+		if !slices.Contains(ids, info.Id) {
+			continue
+		}
+		killed += 1
+		server.connInfo.handles[conn].cancel()
+		if conn == current {
+			continue
+		}
+		if err := (*conn).Close(); err != nil {
+			server.logger.Debug("close killed connection", logger.ConnIDKey, info.Id, logger.ErrorKey, err)
+		}
+	}
+	return killed
+}
+
+// pauseClients suspends the commands of the clients for the timeout. When writeOnly is true, only the write
+// commands are suspended. When a pause is already in effect, the pause ends at the later of the two times and
+// suspends the commands suspended by either of them.
+func (server *EchoVault) pauseClients(timeout time.Duration, writeOnly bool) {
+	server.clientPause.mutex.Lock()
+	defer server.clientPause.mutex.Unlock()
+
+	until := time.Now().Add(timeout)
+	if time.Now().Before(server.clientPause.until) {
+		if until.Before(server.clientPause.until) {
+			until = server.clientPause.until
+		}
+		writeOnly = writeOnly && server.clientPause.writeOnly
+	}
+	if server.clientPause.unpaused != nil {
+		// Wake up the suspended commands so that they wait for the new pause.
+		close(server.clientPause.unpaused)
+	}
+	server.clientPause.until = until
+	server.clientPause.writeOnly = writeOnly
+	server.clientPause.unpaused = make(chan struct{})
+}
+
+// unpauseClients resumes the commands suspended by pauseClients.
+func (server *EchoVault) unpauseClients() {
+	server.clientPause.mutex.Lock()
+	defer server.clientPause.mutex.Unlock()
+
+	if server.clientPause.unpaused != nil {
+		close(server.clientPause.unpaused)
+		server.clientPause.unpaused = nil
+	}
+	server.clientPause.until = time.Time{}
+}
+
+// clientsPaused returns true when a client pause is in effect.
+func (server *EchoVault) clientsPaused() bool {
+	server.clientPause.mutex.Lock()
+	defer server.clientPause.mutex.Unlock()
+	return time.Now().Before(server.clientPause.until)
+}
+
+// waitForUnpause blocks the command until the client pause ends or the pause does not suspend the command.
+// It returns an error when the context is cancelled first, e.g. when the connection is killed.
+func (server *EchoVault) waitForUnpause(ctx context.Context, write bool) error {
+	for {
+		server.clientPause.mutex.Lock()
+		remaining := time.Until(server.clientPause.until)
+		suspended := remaining > 0 && (write || !server.clientPause.writeOnly)
+		unpaused := server.clientPause.unpaused
+		server.clientPause.mutex.Unlock()
+
+		if !suspended {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-unpaused:
+		case <-time.After(remaining):
+		}
+	}
+}
@@ -16,20 +16,29 @@ package echovault
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/echovault/echovault/internal"
+	"github.com/echovault/echovault/internal/logger"
 	"time"
 )
 
 func (server *EchoVault) isInCluster() bool {
-	return server.config.BootstrapCluster || server.config.JoinAddr != ""
+	return server.config.BootstrapCluster || server.config.JoinAddr != "" ||
+		len(server.config.Seeds) > 0 || server.config.DiscoveryDNS != ""
+}
+
+// hasLeftCluster returns true once the node was decommissioned or has left the cluster on shutdown.
+// Raft and the memberlist are stopped by then, so the node can't apply or forward changes to the keyspace anymore.
+func (server *EchoVault) hasLeftCluster() bool {
+	return server.isInCluster() && server.leftCluster.Load()
 }
 
 func (server *EchoVault) raftApplyDeleteKey(ctx context.Context, key string) error {
 	serverId, _ := ctx.Value(internal.ContextServerID("ServerID")).(string)
 	protocol, _ := ctx.Value("Protocol").(int)
 	database, _ := ctx.Value("Database").(int)
+	reason, _ := ctx.Value(internal.ContextDeleteReason("Reason")).(string)
 
 	deleteKeyRequest := internal.ApplyRequest{
 		Type:         "delete-key",
@@ -38,30 +47,11 @@ func (server *EchoVault) raftApplyDeleteKey(ctx context.Context, key string) err
 		Protocol:     protocol,
 		Database:     database,
 		Key:          key,
+		Reason:       reason,
 	}
 
-	b, err := json.Marshal(deleteKeyRequest)
-	if err != nil {
-		return fmt.Errorf("could not parse delete key request for key: %s", key)
-	}
-
-	applyFuture := server.raft.Apply(b, 500*time.Millisecond)
-
-	if err = applyFuture.Error(); err != nil {
-		return err
-	}
-
-	r, ok := applyFuture.Response().(internal.ApplyResponse)
-
-	if !ok {
-		return fmt.Errorf("unprocessable entity %v", r)
-	}
-
-	if r.Error != nil {
-		return r.Error
-	}
-
-	return nil
+	_, err := server.raft.Apply(deleteKeyRequest, 500*time.Millisecond)
+	return err
 }
 
 func (server *EchoVault) raftApplyCommand(ctx context.Context, cmd []string) ([]byte, error) {
@@ -79,26 +69,123 @@ func (server *EchoVault) raftApplyCommand(ctx context.Context, cmd []string) ([]
 		CMD:          cmd,
 	}
 
-	b, err := json.Marshal(applyRequest)
+	return server.raft.Apply(applyRequest, 500*time.Millisecond)
+}
+
+// raftApplyACLState replicates the current node's ACL users to every node in the raft cluster.
+func (server *EchoVault) raftApplyACLState(ctx context.Context) error {
+	serverId, _ := ctx.Value(internal.ContextServerID("ServerID")).(string)
+
+	state, err := server.acl.GetState()
 	if err != nil {
-		return nil, fmt.Errorf("could not parse command request for commad: %+v", cmd)
+		return err
 	}
 
-	applyFuture := server.raft.Apply(b, 500*time.Millisecond)
+	aclStateRequest := internal.ApplyRequest{
+		Type:         "acl-state",
+		ServerID:     serverId,
+		ConnectionID: "nil",
+		ACLState:     state,
+	}
+
+	_, err = server.raft.Apply(aclStateRequest, 500*time.Millisecond)
+	return err
+}
+
+// clusterLeaveTimeout is how long a node waits for each step of leaving the cluster when shutting down.
+const clusterLeaveTimeout = 5 * time.Second
 
-	if err = applyFuture.Error(); err != nil {
-		return nil, err
+// leaveCluster gracefully removes the node from the cluster. The node hands leadership over to another voter,
+// waits until the leader removes it from the raft configuration, broadcasts its intent to leave the memberlist,
+// and then stops raft.
+func (server *EchoVault) leaveCluster(timeout time.Duration) error {
+	if !server.leftCluster.CompareAndSwap(false, true) {
+		return errors.New("node has already left the cluster")
 	}
 
-	r, ok := applyFuture.Response().(internal.ApplyResponse)
+	serverId := server.config.ServerID
 
-	if !ok {
-		return nil, fmt.Errorf("unprocessable entity %v", r)
+	// Hand leadership over so that the cluster keeps accepting writes while the node leaves.
+	if err := server.raft.TransferLeadership(); err != nil {
+		server.logger.Warn("transfer leadership", logger.ErrorKey, err)
 	}
 
-	if r.Error != nil {
-		return nil, r.Error
+	voters, err := server.raft.Voters()
+	if err != nil {
+		server.logger.Error("leave cluster", logger.ErrorKey, err)
+	}
+	// The last voter can't be removed, the cluster simply stops with it.
+	if len(voters) > 1 && server.raft.HasServer(serverId) {
+		if err = server.awaitRaftRemoval(serverId, timeout); err != nil {
+			server.logger.Error("leave cluster", logger.ErrorKey, err)
+		}
 	}
 
-	return r.Response, nil
+	server.memberList.MemberListShutdown(timeout)
+
+	if err = server.raft.Shutdown(); err != nil {
+		return err
+	}
+
+	server.logger.Info("left the cluster")
+	return nil
+}
+
+// awaitRaftRemoval asks the leader to remove the node from the raft configuration until it's removed or the
+// timeout elapses.
+func (server *EchoVault) awaitRaftRemoval(serverId string, timeout time.Duration) error {
+	deadline := server.clock.After(timeout)
+	ticker := time.NewTicker(200 * time.Millisecond)
+	defer ticker.Stop()
+
+	for server.raft.HasServer(serverId) {
+		if server.raft.IsRaftLeader() {
+			// Leadership could not be transferred, so the node removes itself and steps down once it's committed.
+			if err := server.raft.RemoveServerByID(serverId); err != nil {
+				return err
+			}
+			continue
+		}
+		server.memberList.RequestRaftLeave()
+		select {
+		case <-ticker.C:
+		case <-deadline:
+			return fmt.Errorf("timed out waiting for server %s to be removed from the raft configuration", serverId)
+		}
+	}
+
+	return nil
+}
+
+// decommission drains the current node out of the cluster. The node is only removed when the remaining voters
+// can still form a quorum.
+func (server *EchoVault) decommission() error {
+	if !server.isInCluster() {
+		return errors.New("cluster support disabled")
+	}
+	if server.leftCluster.Load() {
+		return errors.New("node has already left the cluster")
+	}
+	if err := server.raft.CheckQuorumAfterRemoval(server.config.ServerID, server.memberList.IsAlive); err != nil {
+		return err
+	}
+	return server.leaveCluster(clusterLeaveTimeout)
+}
+
+// forgetNode removes another node from the raft configuration. It must be called on the leader, and the node is
+// only removed when the remaining voters can still form a quorum.
+func (server *EchoVault) forgetNode(serverId string) error {
+	if !server.isInCluster() {
+		return errors.New("cluster support disabled")
+	}
+	if serverId == server.config.ServerID {
+		return errors.New("can't forget the current node, use CLUSTER DECOMMISSION instead")
+	}
+	if !server.raft.IsRaftLeader() {
+		return fmt.Errorf("CLUSTER FORGET must be sent to the cluster leader %s", server.raft.LeaderID())
+	}
+	if err := server.raft.CheckQuorumAfterRemoval(serverId, server.memberList.IsAlive); err != nil {
+		return err
+	}
+	return server.raft.RemoveServerByID(serverId)
 }
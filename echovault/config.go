@@ -122,6 +122,24 @@ func WithJoinAddr(joinAddr string) func(echovault *EchoVault) {
 	}
 }
 
+// WithSeeds is an option to the NewEchoVault function that allows you to pass the
+// addresses ([server-id/]host:port) of the cluster members to join.
+// If not specified, EchoVault will use the default configuration from config.DefaultConfig().
+func WithSeeds(seeds []string) func(echovault *EchoVault) {
+	return func(echovault *EchoVault) {
+		echovault.config.Seeds = seeds
+	}
+}
+
+// WithDiscoveryDNS is an option to the NewEchoVault function that allows you to pass a
+// DNS name used to discover the cluster members to join.
+// If not specified, EchoVault will use the default configuration from config.DefaultConfig().
+func WithDiscoveryDNS(name string) func(echovault *EchoVault) {
+	return func(echovault *EchoVault) {
+		echovault.config.DiscoveryDNS = name
+	}
+}
+
 // WithBindAddr is an option to the NewEchoVault function that allows you to pass a
 // custom BindAddr to EchoVault.
 // If not specified, EchoVault will use the default configuration from config.DefaultConfig().
@@ -268,6 +286,15 @@ func WithEvictionPolicy(evictionPolicy string) func(echovault *EchoVault) {
 	}
 }
 
+// WithNoEvictKeys is an option to the NewEchoVault function that allows you to pass glob patterns of keys that
+// are never evicted when max memory is reached. More patterns can be added while the server is running with PinKeys.
+// If not specified, every key can be evicted.
+func WithNoEvictKeys(patterns []string) func(echovault *EchoVault) {
+	return func(echovault *EchoVault) {
+		echovault.config.NoEvictKeys = patterns
+	}
+}
+
 // WithEvictionSample is an option to the NewEchoVault function that allows you to pass a
 // custom EvictionSample to EchoVault.
 // If not specified, EchoVault will use the default configuration from config.DefaultConfig().
@@ -277,6 +304,69 @@ func WithEvictionSample(evictionSample uint) func(echovault *EchoVault) {
 	}
 }
 
+// WithMetricsPort is an option to the NewEchoVault function that allows you to pass a
+// custom MetricsPort to EchoVault. The HTTP listener serving Prometheus metrics is only started when it's not 0.
+// If not specified, EchoVault will use the default configuration from config.DefaultConfig().
+func WithMetricsPort(port uint16) func(echovault *EchoVault) {
+	return func(echovault *EchoVault) {
+		echovault.config.MetricsPort = port
+	}
+}
+
+// WithSlowlogThreshold is an option to the NewEchoVault function that allows you to pass a
+// custom SlowlogThreshold in microseconds to EchoVault. 0 logs every command and a negative value disables the slow log.
+// If not specified, EchoVault will use the default configuration from config.DefaultConfig().
+func WithSlowlogThreshold(threshold int64) func(echovault *EchoVault) {
+	return func(echovault *EchoVault) {
+		echovault.config.SlowlogThreshold = threshold
+	}
+}
+
+// WithSlowlogMaxLen is an option to the NewEchoVault function that allows you to pass a
+// custom SlowlogMaxLen to EchoVault.
+// If not specified, EchoVault will use the default configuration from config.DefaultConfig().
+func WithSlowlogMaxLen(maxLen uint) func(echovault *EchoVault) {
+	return func(echovault *EchoVault) {
+		echovault.config.SlowlogMaxLen = maxLen
+	}
+}
+
+// WithLatencyThreshold is an option to the NewEchoVault function that allows you to pass a
+// custom LatencyThreshold in milliseconds to EchoVault. 0 disables the latency monitor.
+// If not specified, EchoVault will use the default configuration from config.DefaultConfig().
+func WithLatencyThreshold(threshold uint64) func(echovault *EchoVault) {
+	return func(echovault *EchoVault) {
+		echovault.config.LatencyThreshold = threshold
+	}
+}
+
+// WithMonitorBufferSize is an option to the NewEchoVault function that allows you to pass a
+// custom MonitorBufferSize to EchoVault. Commands are dropped for monitors that fall further behind.
+// If not specified, EchoVault will use the default configuration from config.DefaultConfig().
+func WithMonitorBufferSize(size uint) func(echovault *EchoVault) {
+	return func(echovault *EchoVault) {
+		echovault.config.MonitorBufferSize = size
+	}
+}
+
+// WithLFULogFactor is an option to the NewEchoVault function that allows you to pass a
+// custom LFULogFactor to EchoVault.
+// If not specified, EchoVault will use the default configuration from config.DefaultConfig().
+func WithLFULogFactor(lfuLogFactor uint) func(echovault *EchoVault) {
+	return func(echovault *EchoVault) {
+		echovault.config.LFULogFactor = lfuLogFactor
+	}
+}
+
+// WithLFUDecayTime is an option to the NewEchoVault function that allows you to pass a
+// custom LFUDecayTime in minutes to EchoVault.
+// If not specified, EchoVault will use the default configuration from config.DefaultConfig().
+func WithLFUDecayTime(lfuDecayTime uint) func(echovault *EchoVault) {
+	return func(echovault *EchoVault) {
+		echovault.config.LFUDecayTime = lfuDecayTime
+	}
+}
+
 // WithEvictionInterval is an option to the NewEchoVault function that allows you to pass a
 // custom EvictionInterval to EchoVault.
 // If not specified, EchoVault will use the default configuration from config.DefaultConfig().
@@ -321,3 +411,152 @@ func WithRaftBindPort(raftBindPort uint16) func(echovault *EchoVault) {
 		echovault.config.RaftBindPort = raftBindPort
 	}
 }
+
+// WithReplicaOf is an option to the NewEchoVault function that allows you to pass a
+// custom ReplicaOf address (host:port) to EchoVault.
+// If not specified, EchoVault will use the default configuration from config.DefaultConfig().
+func WithReplicaOf(replicaOf string) func(echovault *EchoVault) {
+	return func(echovault *EchoVault) {
+		echovault.config.ReplicaOf = replicaOf
+	}
+}
+
+// WithReplBacklogSize is an option to the NewEchoVault function that allows you to pass a
+// custom ReplBacklogSize to EchoVault.
+// If not specified, EchoVault will use the default configuration from config.DefaultConfig().
+func WithReplBacklogSize(replBacklogSize uint64) func(echovault *EchoVault) {
+	return func(echovault *EchoVault) {
+		echovault.config.ReplBacklogSize = replBacklogSize
+	}
+}
+
+// WithCDCBacklogSize is an option to the NewEchoVault function that allows you to pass a
+// custom CDCBacklogSize to EchoVault. It's the number of change data capture events kept for
+// subscribers that resume from an offset.
+// If not specified, EchoVault will use the default configuration from config.DefaultConfig().
+func WithCDCBacklogSize(cdcBacklogSize uint) func(echovault *EchoVault) {
+	return func(echovault *EchoVault) {
+		echovault.config.CDCBacklogSize = cdcBacklogSize
+	}
+}
+
+// WithGossipKeys is an option to the NewEchoVault function that allows you to pass the
+// base64 encoded keys used to encrypt cluster gossip. The first key encrypts outgoing messages,
+// and all the keys are accepted for incoming messages.
+// If not specified, EchoVault will use the default configuration from config.DefaultConfig().
+func WithGossipKeys(keys []string) func(echovault *EchoVault) {
+	return func(echovault *EchoVault) {
+		echovault.config.GossipKeys = keys
+	}
+}
+
+// WithRaftTLS is an option to the NewEchoVault function that allows you to secure the
+// raft transport with mTLS using the configured CertKeyPairs and ClientCAs.
+// If not specified, EchoVault will use the default configuration from config.DefaultConfig().
+func WithRaftTLS(b ...bool) func(echovault *EchoVault) {
+	return func(echovault *EchoVault) {
+		if len(b) > 0 {
+			echovault.config.RaftTLS = b[0]
+		} else {
+			echovault.config.RaftTLS = true
+		}
+	}
+}
+
+// WithXRepl is an option to the NewEchoVault function that enables cross-cluster replication.
+// The time of the latest write of every key is kept so that writes replicated from other clusters
+// are resolved with last-writer-wins. Setting targets with WithXReplTargets also enables it.
+// If not specified, EchoVault will use the default configuration from config.DefaultConfig().
+func WithXRepl(b ...bool) func(echovault *EchoVault) {
+	return func(echovault *EchoVault) {
+		if len(b) > 0 {
+			echovault.config.XRepl = b[0]
+		} else {
+			echovault.config.XRepl = true
+		}
+	}
+}
+
+// WithXReplSourceID is an option to the NewEchoVault function that allows you to pass the ID of this
+// cluster on the clusters it replicates to. Every node of the cluster must use the same ID.
+// If not specified, the server ID is used.
+func WithXReplSourceID(sourceID string) func(echovault *EchoVault) {
+	return func(echovault *EchoVault) {
+		echovault.config.XReplSourceID = sourceID
+	}
+}
+
+// WithXReplTargets is an option to the NewEchoVault function that allows you to pass the addresses (host:port)
+// of the nodes of the remote cluster to replicate to. The agent connects to the first reachable node.
+// If not specified, EchoVault will use the default configuration from config.DefaultConfig().
+func WithXReplTargets(targets []string) func(echovault *EchoVault) {
+	return func(echovault *EchoVault) {
+		echovault.config.XReplTargets = targets
+	}
+}
+
+// WithXReplAuth is an option to the NewEchoVault function that allows you to pass the credentials used to
+// authenticate with the remote cluster. Leave the username empty to authenticate as the default user.
+// If not specified, EchoVault will use the default configuration from config.DefaultConfig().
+func WithXReplAuth(username string, password string) func(echovault *EchoVault) {
+	return func(echovault *EchoVault) {
+		echovault.config.XReplUsername = username
+		echovault.config.XReplPassword = password
+	}
+}
+
+// WithXReplDatabases is an option to the NewEchoVault function that limits cross-cluster replication
+// to the given databases.
+// If not specified, all databases are replicated.
+func WithXReplDatabases(databases []int) func(echovault *EchoVault) {
+	return func(echovault *EchoVault) {
+		echovault.config.XReplDatabases = databases
+	}
+}
+
+// WithXReplKeyPatterns is an option to the NewEchoVault function that limits cross-cluster replication
+// to the keys that match one of the glob patterns.
+// If not specified, all keys are replicated.
+func WithXReplKeyPatterns(patterns []string) func(echovault *EchoVault) {
+	return func(echovault *EchoVault) {
+		echovault.config.XReplKeyPatterns = patterns
+	}
+}
+
+// WithKeyspaceEvents is an option to the NewEchoVault function that allows you to pass the classes of keyspace
+// events published to the __keyspace@<db>__:<key> and __keyevent@<db>__:<event> channels, using the flags of
+// the Redis notify-keyspace-events parameter (e.g. "KEA").
+// If not specified, keyspace notifications are disabled.
+func WithKeyspaceEvents(events string) func(echovault *EchoVault) {
+	return func(echovault *EchoVault) {
+		echovault.config.KeyspaceEvents = events
+	}
+}
+
+// WithLogLevel is an option to the NewEchoVault function that allows you to pass the minimum level
+// of the log entries ("debug", "info", "warn" or "error"). It can be changed with CONFIG SET log-level.
+// If not specified, EchoVault will use the default configuration from config.DefaultConfig().
+func WithLogLevel(level string) func(echovault *EchoVault) {
+	return func(echovault *EchoVault) {
+		echovault.config.LogLevel = level
+	}
+}
+
+// WithLogFormat is an option to the NewEchoVault function that allows you to pass the format
+// of the log entries ("text" or "json"). It's ignored when a logger is passed with WithLogger.
+// If not specified, EchoVault will use the default configuration from config.DefaultConfig().
+func WithLogFormat(format string) func(echovault *EchoVault) {
+	return func(echovault *EchoVault) {
+		echovault.config.LogFormat = format
+	}
+}
+
+// WithLogOutput is an option to the NewEchoVault function that allows you to pass where the log
+// entries are written to: "stderr", "stdout" or the path of a file the entries are appended to.
+// It's ignored when a logger is passed with WithLogger.
+// If not specified, EchoVault will use the default configuration from config.DefaultConfig().
+func WithLogOutput(output string) func(echovault *EchoVault) {
+	return func(echovault *EchoVault) {
+		echovault.config.LogOutput = output
+	}
+}
@@ -0,0 +1,27 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !unix
+
+package echovault
+
+import (
+	"github.com/echovault/echovault/internal"
+	"runtime"
+)
+
+// getCPUInfo returns the number of goroutines. The CPU time is not reported on this platform.
+func getCPUInfo() internal.CPUInfo {
+	return internal.CPUInfo{Goroutines: runtime.NumGoroutine()}
+}
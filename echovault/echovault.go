@@ -24,25 +24,35 @@ import (
 	"github.com/echovault/echovault/internal/aof"
 	"github.com/echovault/echovault/internal/clock"
 	"github.com/echovault/echovault/internal/config"
-	"github.com/echovault/echovault/internal/constants"
 	"github.com/echovault/echovault/internal/eviction"
+	"github.com/echovault/echovault/internal/latency"
+	"github.com/echovault/echovault/internal/logger"
 	"github.com/echovault/echovault/internal/memberlist"
 	"github.com/echovault/echovault/internal/modules/acl"
 	"github.com/echovault/echovault/internal/modules/admin"
+	"github.com/echovault/echovault/internal/modules/cdc"
 	"github.com/echovault/echovault/internal/modules/connection"
 	"github.com/echovault/echovault/internal/modules/generic"
 	"github.com/echovault/echovault/internal/modules/hash"
 	"github.com/echovault/echovault/internal/modules/list"
 	"github.com/echovault/echovault/internal/modules/pubsub"
+	"github.com/echovault/echovault/internal/modules/replication"
 	"github.com/echovault/echovault/internal/modules/set"
 	"github.com/echovault/echovault/internal/modules/sorted_set"
 	str "github.com/echovault/echovault/internal/modules/string"
+	"github.com/echovault/echovault/internal/modules/xrepl"
+	"github.com/echovault/echovault/internal/monitor"
 	"github.com/echovault/echovault/internal/raft"
+	"github.com/echovault/echovault/internal/slowlog"
 	"github.com/echovault/echovault/internal/snapshot"
+	"github.com/echovault/echovault/internal/tracking"
+	"github.com/gobwas/glob"
 	"io"
-	"log"
+	"log/slog"
 	"net"
+	"net/http"
 	"os"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -52,8 +62,17 @@ type EchoVault struct {
 	// clock is an implementation of a time interface that allows mocking of time functions during testing.
 	clock clock.Clock
 
+	// logger writes the leveled, structured log entries of the server and its engines.
+	// Entries below logLevel are discarded. The level is changed by CONFIG SET log-level.
+	logger   logger.Logger
+	logLevel *slog.LevelVar
+	logFile  io.Closer // The file the log entries are appended to when LogOutput is a file path.
+
 	// config holds the echovault configuration variables.
-	config config.Config
+	// The mutable parameters are changed by CONFIG SET while the server is running, so they must be read with
+	// getConfig, which holds configMut.
+	config    config.Config
+	configMut sync.RWMutex
 
 	// The current index for the latest connection id.
 	// This number is incremented everytime there's a new connection and
@@ -65,8 +84,16 @@ type EchoVault struct {
 	connInfo struct {
 		mut        *sync.RWMutex                         // RWMutex for the connInfo object.
 		tcpClients map[*net.Conn]internal.ConnectionInfo // Map that holds connection information for each TCP client.
+		handles    map[*net.Conn]*connHandle             // Map that holds the handles used to close and write to each TCP client.
 		embedded   internal.ConnectionInfo               // Information for the embedded connection.
 	}
+	// clientPause suspends the commands of the clients while CLIENT PAUSE is in effect.
+	clientPause struct {
+		mutex     sync.Mutex
+		until     time.Time     // The time the pause ends.
+		writeOnly bool          // Whether only the write commands are suspended.
+		unpaused  chan struct{} // Closed when the pause ends, either by CLIENT UNPAUSE or by a new pause.
+	}
 
 	// Global read-write mutex for entire store.
 	storeLock *sync.RWMutex
@@ -77,46 +104,89 @@ type EchoVault struct {
 
 	// Holds all the keys that are currently associated with an expiry.
 	keysWithExpiry struct {
-		// Mutex as only one process should be able to update this list at a time.
+		// Mutex as only one process should be able to update the index at a time.
 		rwMutex sync.RWMutex
-		// A map holding a string slice of the volatile keys for each database.
-		keys map[int][]string
+		// A map holding the index of the volatile keys, ordered by expiry time, for each database.
+		keys map[int]*eviction.ExpiryIndex
 	}
-	// LFU cache used when eviction policy is allkeys-lfu or volatile-lfu.
-	lfuCache struct {
-		// Mutex as only one goroutine can edit the LFU cache at a time.
-		mutex sync.Mutex
-		// LFU cache for each database represented by a min heap.
-		cache map[int]*eviction.CacheLFU
+	// Memory usage of the dataset, maintained as keys are written and deleted while the store lock is held.
+	memory struct {
+		// The estimated number of bytes used by every key.
+		used int64
+		// The highest number of bytes used by every key.
+		peak int64
+		// The estimated number of bytes used by the keys of each database.
+		databases map[int]int64
+		// The number of keys evicted because the dataset reached max memory.
+		evictedKeys uint64
+		// The best candidates for eviction found by sampling the keyspace with the lru, lfu and ttl eviction policies.
+		pool *eviction.Pool
 	}
-	// LRU cache used when eviction policy is allkeys-lru or volatile-lru.
-	lruCache struct {
-		// Mutex as only one goroutine can edit the LRU at a time.
+	// Keys that are never evicted, matched by glob patterns from the NoEvictKeys config and PinKeys.
+	noEvict struct {
+		mutex    sync.RWMutex
+		patterns map[string]glob.Glob
+	}
+	// Statistics of the deletion of expired keys.
+	expiryStats struct {
+		// Mutex as the stats are updated by the active expiry cycle of each database.
 		mutex sync.Mutex
-		// LRU cache represented by a max heap.
-		cache map[int]*eviction.CacheLRU
+		// The number of keys deleted because they expired, either by the active expiry cycle or when accessed.
+		expiredKeys uint64
+		// The number of active expiry cycles.
+		cycles uint64
+		// The number of keys deleted by the latest active expiry cycle.
+		lastCycleExpired uint64
+		// The number of active expiry cycles that stopped because they reached their time limit.
+		timeCapReached uint64
+		// The total time spent in active expiry cycles.
+		cycleTime time.Duration
+	}
+
+	// Statistics of the commands processed by the server, reported by the INFO command.
+	stats struct {
+		startTime      time.Time     // The time the server started.
+		commands       sync.Map      // Map of command names to their *commandStats.
+		errors         sync.Map      // Map of error prefixes to the number of errors returned to clients.
+		totalCommands  atomic.Uint64 // The number of commands processed.
+		totalErrors    atomic.Uint64 // The number of errors returned to clients.
+		keyspaceHits   atomic.Uint64 // The number of successful lookups of keys.
+		keyspaceMisses atomic.Uint64 // The number of failed lookups of keys.
 	}
 
+	slowlog       *slowlog.SlowLog // The commands that took at least the slow log threshold to execute.
+	latency       *latency.Monitor // The latency spikes of events such as AOF fsyncs and snapshots.
+	snapshotStart atomic.Int64     // The unix time in nanoseconds at which the snapshot in progress started.
+	monitor       *monitor.Monitor // Streams the processed commands to the connections running MONITOR.
+
 	// Holds the list of all commands supported by the echovault.
 	commandsRWMut sync.RWMutex
 	commands      []internal.Command
 
-	raft       *raft.Raft             // The raft replication layer for the echovault.
-	memberList *memberlist.MemberList // The memberlist layer for the echovault.
+	raft        *raft.Raft             // The raft replication layer for the echovault.
+	memberList  *memberlist.MemberList // The memberlist layer for the echovault.
+	leftCluster atomic.Bool            // Atomic boolean that's true once the node has left the cluster.
 
 	context context.Context
 
-	acl    *acl.ACL
-	pubSub *pubsub.PubSub
+	acl         *acl.ACL
+	pubSub      *pubsub.PubSub
+	tracking    *tracking.Tracking       // Tracks the keys read by the clients for CLIENT TRACKING.
+	replication *replication.Replication // Primary/replica replication engine for standalone mode.
+	cdc         *cdc.Feed                // Change data capture feed of every mutation of the keyspace.
+	xrepl       *xrepl.XRepl             // Cross-cluster replication engine.
 
 	snapshotInProgress         atomic.Bool      // Atomic boolean that's true when actively taking a snapshot.
 	rewriteAOFInProgress       atomic.Bool      // Atomic boolean that's true when actively rewriting AOF file is in progress.
 	stateCopyInProgress        atomic.Bool      // Atomic boolean that's true when actively copying state for snapshotting or preamble generation.
 	stateMutationInProgress    atomic.Bool      // Atomic boolean that is set to true when state mutation is in progress.
+	applyLock                  sync.RWMutex     // Held by the raft FSM while it applies a batch, and by the read commands in cluster mode.
 	latestSnapshotMilliseconds atomic.Int64     // Unix epoch in milliseconds.
 	snapshotEngine             *snapshot.Engine // Snapshot engine for standalone mode.
 	aofEngine                  *aof.Engine      // AOF engine for standalone mode.
 
+	metricsServer *http.Server // The HTTP server serving Prometheus metrics. Nil when no metrics port is configured.
+
 	listener atomic.Value  // Holds the TCP listener.
 	quit     chan struct{} // Channel that signals the closing of all client connections.
 	stopTTL  chan struct{} // Channel that signals the TTL sampling goroutine to stop execution.
@@ -140,6 +210,19 @@ func WithConfig(config config.Config) func(echovault *EchoVault) {
 	}
 }
 
+// Logger is the interface of the leveled, structured logger used by EchoVault.
+// The args of each method are alternating key/value pairs attached to the entry as fields.
+type Logger = logger.Logger
+
+// WithLogger is an option for the NewEchoVault function that allows you to pass a
+// custom logger to EchoVault. Entries below the LogLevel config are discarded before they reach it.
+// If not specified, EchoVault writes the entries to LogOutput in LogFormat.
+func WithLogger(logger Logger) func(echovault *EchoVault) {
+	return func(echovault *EchoVault) {
+		echovault.logger = logger
+	}
+}
+
 // NewEchoVault creates a new EchoVault instance.
 // This functions accepts the WithContext, WithConfig and WithCommands options.
 func NewEchoVault(options ...func(echovault *EchoVault)) (*EchoVault, error) {
@@ -150,10 +233,12 @@ func NewEchoVault(options ...func(echovault *EchoVault)) (*EchoVault, error) {
 		connInfo: struct {
 			mut        *sync.RWMutex
 			tcpClients map[*net.Conn]internal.ConnectionInfo
+			handles    map[*net.Conn]*connHandle
 			embedded   internal.ConnectionInfo
 		}{
 			mut:        &sync.RWMutex{},
 			tcpClients: make(map[*net.Conn]internal.ConnectionInfo),
+			handles:    make(map[*net.Conn]*connHandle),
 			embedded: internal.ConnectionInfo{
 				Id:       0,
 				Name:     "embedded",
@@ -163,26 +248,39 @@ func NewEchoVault(options ...func(echovault *EchoVault)) (*EchoVault, error) {
 		},
 		storeLock: &sync.RWMutex{},
 		store:     make(map[int]map[string]internal.KeyData),
+		memory: struct {
+			used        int64
+			peak        int64
+			databases   map[int]int64
+			evictedKeys uint64
+			pool        *eviction.Pool
+		}{
+			databases: make(map[int]int64),
+			pool:      eviction.NewPool(),
+		},
 		keysWithExpiry: struct {
 			rwMutex sync.RWMutex
-			keys    map[int][]string
+			keys    map[int]*eviction.ExpiryIndex
 		}{
 			rwMutex: sync.RWMutex{},
-			keys:    make(map[int][]string),
+			keys:    make(map[int]*eviction.ExpiryIndex),
 		},
 		commandsRWMut: sync.RWMutex{},
 		commands: func() []internal.Command {
 			var commands []internal.Command
 			commands = append(commands, acl.Commands()...)
 			commands = append(commands, admin.Commands()...)
+			commands = append(commands, cdc.Commands()...)
 			commands = append(commands, connection.Commands()...)
 			commands = append(commands, generic.Commands()...)
 			commands = append(commands, hash.Commands()...)
 			commands = append(commands, list.Commands()...)
 			commands = append(commands, pubsub.Commands()...)
+			commands = append(commands, replication.Commands()...)
 			commands = append(commands, set.Commands()...)
 			commands = append(commands, sorted_set.Commands()...)
 			commands = append(commands, str.Commands()...)
+			commands = append(commands, xrepl.Commands()...)
 			return commands
 		}(),
 		quit:    make(chan struct{}),
@@ -193,6 +291,25 @@ func NewEchoVault(options ...func(echovault *EchoVault)) (*EchoVault, error) {
 		option(echovault)
 	}
 
+	echovault.stats.startTime = echovault.clock.Now()
+
+	// Set up the logger.
+	if err := echovault.setupLogger(); err != nil {
+		return nil, err
+	}
+
+	// Set up the slow log and the latency monitor.
+	echovault.slowlog = slowlog.NewSlowLog(
+		slowlog.WithThreshold(time.Duration(echovault.config.SlowlogThreshold)*time.Microsecond),
+		slowlog.WithMaxLen(int(echovault.config.SlowlogMaxLen)),
+	)
+	echovault.latency = latency.NewMonitor(
+		latency.WithThreshold(time.Duration(echovault.config.LatencyThreshold) * time.Millisecond),
+	)
+	echovault.monitor = monitor.NewMonitor(
+		monitor.WithBufferSize(int(echovault.config.MonitorBufferSize)),
+	)
+
 	echovault.context = context.WithValue(
 		echovault.context, "ServerID",
 		internal.ContextServerID(echovault.config.ServerID),
@@ -201,28 +318,108 @@ func NewEchoVault(options ...func(echovault *EchoVault)) (*EchoVault, error) {
 	// Load .so modules from config
 	for _, path := range echovault.config.Modules {
 		if err := echovault.LoadModule(path); err != nil {
-			log.Printf("%s %v\n", path, err)
+			echovault.logger.Error("load module", "path", path, logger.ErrorKey, err)
 			continue
 		}
-		log.Printf("loaded plugin %s\n", path)
+		echovault.logger.Info("loaded module", "path", path)
 	}
 
 	// Set up ACL module
-	echovault.acl = acl.NewACL(echovault.config)
+	echovault.acl = acl.NewACL(echovault.config, echovault.logger)
+
+	// Set up client-side caching invalidation
+	echovault.tracking = tracking.NewTracking(tracking.WithSendFunc(echovault.sendToClient))
 
 	// Set up Pub/Sub module
-	echovault.pubSub = pubsub.NewPubSub()
+	echovault.pubSub = pubsub.NewPubSub(echovault.logger)
+	keyspaceEvents, err := pubsub.ParseKeyspaceEvents(echovault.config.KeyspaceEvents)
+	if err != nil {
+		return nil, err
+	}
+	echovault.pubSub.SetKeyspaceEvents(keyspaceEvents)
+	// Normalise the classes so that CONFIG GET returns them in the same form as they're applied.
+	echovault.config.KeyspaceEvents = pubsub.FormatKeyspaceEvents(keyspaceEvents)
+
+	// Pin the keys that are never evicted.
+	echovault.noEvict.patterns = make(map[string]glob.Glob)
+	if _, err = echovault.pinKeys(echovault.config.NoEvictKeys); err != nil {
+		return nil, err
+	}
+
+	// Read the cross-cluster replication state saved when the server was last shut down.
+	xreplState, err := xrepl.ReadState(echovault.config.DataDir)
+	if err != nil {
+		echovault.logger.Warn("read xrepl state", logger.ErrorKey, err)
+	}
+
+	// Set up change data capture feed
+	echovault.cdc = cdc.NewFeed(
+		cdc.WithClock(echovault.clock),
+		cdc.WithBacklogSize(int(echovault.config.CDCBacklogSize)),
+		cdc.WithResume(xreplState.FeedID, xreplState.FeedOffset),
+	)
+
+	// Set up cross-cluster replication module
+	xreplSourceID := echovault.config.XReplSourceID
+	if xreplSourceID == "" {
+		xreplSourceID = echovault.config.ServerID
+	}
+	echovault.xrepl = xrepl.NewXRepl(
+		xrepl.WithLogger(echovault.logger),
+		xrepl.WithEnabled(echovault.config.XRepl),
+		xrepl.WithSourceID(xreplSourceID),
+		xrepl.WithFeed(echovault.cdc),
+		xrepl.WithTargets(echovault.config.XReplTargets),
+		xrepl.WithTargetAuth(echovault.config.XReplUsername, echovault.config.XReplPassword),
+		xrepl.WithDatabases(echovault.config.XReplDatabases),
+		xrepl.WithKeyPatterns(echovault.config.XReplKeyPatterns),
+		xrepl.WithIsActiveFunc(echovault.isXReplActive),
+		xrepl.WithGetKeysFunc(echovault.getXReplKeys),
+		xrepl.WithGetKeyFunc(echovault.getXReplKey),
+		xrepl.WithDirectory(echovault.config.DataDir),
+		xrepl.WithCheckpoints(xreplState.Checkpoints),
+	)
+
+	// Set up replication module
+	echovault.replication = replication.NewReplication(
+		replication.WithClock(echovault.clock),
+		replication.WithLogger(echovault.logger),
+		replication.WithListeningPort(int(echovault.config.Port)),
+		replication.WithBacklogSize(int(echovault.config.ReplBacklogSize)),
+		replication.WithGetSyncPayloadFunc(echovault.getReplicationSyncPayload),
+		replication.WithAOFProgressFunc(func() (uint64, uint64, bool) {
+			if echovault.aofEngine == nil || !echovault.aofEngine.Enabled() {
+				return 0, 0, false
+			}
+			return echovault.aofEngine.Offset(), echovault.aofEngine.SyncedOffset(), true
+		}),
+		replication.WithHandleCommandFunc(func(database int, command []byte) error {
+			ctx := context.WithValue(echovault.context, "Protocol", 2)
+			ctx = context.WithValue(ctx, "Database", database)
+			ctx = context.WithValue(ctx, internal.ContextSource("Source"), monitor.SourceReplication)
+			if _, err := echovault.handleCommand(ctx, command, nil, true, false); err != nil {
+				return err
+			}
+			// Keep the replica's own append-only log up to date so that it can restart from it.
+			echovault.aofEngine.LogCommand(database, command)
+			return nil
+		}),
+	)
 
 	if echovault.isInCluster() {
 		echovault.raft = raft.NewRaft(raft.Opts{
 			Config:                echovault.config,
 			GetCommand:            echovault.getCommand,
-			SetValues:             echovault.setValues,
-			SetExpiry:             echovault.setExpiry,
+			SetState:              echovault.replaceState,
 			StartSnapshot:         echovault.startSnapshot,
 			FinishSnapshot:        echovault.finishSnapshot,
+			StartApply:            echovault.applyLock.Lock,
+			FinishApply:           echovault.applyLock.Unlock,
 			SetLatestSnapshotTime: echovault.setLatestSnapshot,
 			GetHandlerFuncParams:  echovault.getHandlerFuncParams,
+			MonitorCommand: func(ctx context.Context, cmd []string) {
+				echovault.feedMonitor(ctx, monitor.SourceRaft, "", cmd)
+			},
 			DeleteKey: func(ctx context.Context, key string) error {
 				echovault.storeLock.Lock()
 				defer echovault.storeLock.Unlock()
@@ -231,6 +428,7 @@ func NewEchoVault(options ...func(echovault *EchoVault)) (*EchoVault, error) {
 			GetState: func() map[int]map[string]internal.KeyData {
 				state := make(map[int]map[string]internal.KeyData)
 				for database, store := range echovault.getState() {
+					state[database] = make(map[string]internal.KeyData)
 					for k, v := range store {
 						if data, ok := v.(internal.KeyData); ok {
 							state[database][k] = data
@@ -239,20 +437,36 @@ func NewEchoVault(options ...func(echovault *EchoVault)) (*EchoVault, error) {
 				}
 				return state
 			},
+			GetACLState: func() ([]byte, error) {
+				return echovault.acl.GetState()
+			},
+			SetACLState: func(state []byte) error {
+				return echovault.acl.SetState(state)
+			},
+			Logger: echovault.logger,
 		})
 		echovault.memberList = memberlist.NewMemberList(memberlist.Opts{
 			Config:           echovault.config,
 			HasJoinedCluster: echovault.raft.HasJoinedCluster,
 			AddVoter:         echovault.raft.AddVoter,
 			RemoveRaftServer: echovault.raft.RemoveServer,
-			IsRaftLeader:     echovault.raft.IsRaftLeader,
-			ApplyMutate:      echovault.raftApplyCommand,
-			ApplyDeleteKey:   echovault.raftApplyDeleteKey,
+			CheckQuorum: func(id string) error {
+				return echovault.raft.CheckQuorumAfterRemoval(id, echovault.memberList.IsAlive)
+			},
+			IsRaftLeader: echovault.raft.IsRaftLeader,
+			ApplyMutate:  echovault.raftApplyCommand,
+			ApplyDeleteKey: func(ctx context.Context, key string) error {
+				// Followers only forward the deletion of expired keys.
+				ctx = context.WithValue(ctx, internal.ContextDeleteReason("Reason"), cdc.EventExpire)
+				return echovault.raftApplyDeleteKey(ctx, key)
+			},
+			Logger: echovault.logger,
 		})
 	} else {
 		// Set up standalone snapshot engine
 		echovault.snapshotEngine = snapshot.NewSnapshotEngine(
 			snapshot.WithClock(echovault.clock),
+			snapshot.WithLogger(echovault.logger),
 			snapshot.WithDirectory(echovault.config.DataDir),
 			snapshot.WithThreshold(echovault.config.SnapShotThreshold),
 			snapshot.WithInterval(echovault.config.SnapshotInterval),
@@ -275,7 +489,8 @@ func NewEchoVault(options ...func(echovault *EchoVault)) (*EchoVault, error) {
 			snapshot.WithSetKeyDataFunc(func(database int, key string, data internal.KeyData) {
 				ctx := context.WithValue(context.Background(), "Database", database)
 				if err := echovault.setValues(ctx, map[string]interface{}{key: data.Value}); err != nil {
-					log.Println(err)
+					echovault.logger.Error("restore key from snapshot", logger.DatabaseKey, database, "key", key,
+						logger.ErrorKey, err)
 				}
 				echovault.setExpiry(ctx, key, data.ExpireAt, false)
 			}),
@@ -284,10 +499,14 @@ func NewEchoVault(options ...func(echovault *EchoVault)) (*EchoVault, error) {
 		// Set up standalone AOF engine
 		aofEngine, err := aof.NewAOFEngine(
 			aof.WithClock(echovault.clock),
+			aof.WithLogger(echovault.logger),
 			aof.WithDirectory(echovault.config.DataDir),
 			aof.WithStrategy(echovault.config.AOFSyncStrategy),
 			aof.WithStartRewriteFunc(echovault.startRewriteAOF),
 			aof.WithFinishRewriteFunc(echovault.finishRewriteAOF),
+			aof.WithFsyncFunc(func(d time.Duration) {
+				echovault.latency.Observe(latency.EventAOFFsync, echovault.clock.Now(), d)
+			}),
 			aof.WithGetStateFunc(func() map[int]map[string]internal.KeyData {
 				state := make(map[int]map[string]internal.KeyData)
 				for database, data := range echovault.getState() {
@@ -303,16 +522,18 @@ func NewEchoVault(options ...func(echovault *EchoVault)) (*EchoVault, error) {
 			aof.WithSetKeyDataFunc(func(database int, key string, value internal.KeyData) {
 				ctx := context.WithValue(context.Background(), "Database", database)
 				if err := echovault.setValues(ctx, map[string]interface{}{key: value.Value}); err != nil {
-					log.Println(err)
+					echovault.logger.Error("restore key from AOF", logger.DatabaseKey, database, "key", key,
+						logger.ErrorKey, err)
 				}
 				echovault.setExpiry(ctx, key, value.ExpireAt, false)
 			}),
 			aof.WithHandleCommandFunc(func(database int, command []byte) {
 				ctx := context.WithValue(context.Background(), "Protocol", 2)
 				ctx = context.WithValue(ctx, "Database", database)
+				ctx = context.WithValue(ctx, internal.ContextSource("Source"), monitor.SourceAOF)
 				_, err := echovault.handleCommand(ctx, command, nil, true, false)
 				if err != nil {
-					log.Println(err)
+					echovault.logger.Error("replay command from AOF", logger.DatabaseKey, database, logger.ErrorKey, err)
 				}
 			}),
 		)
@@ -322,8 +543,8 @@ func NewEchoVault(options ...func(echovault *EchoVault)) (*EchoVault, error) {
 		echovault.aofEngine = aofEngine
 	}
 
-	// If eviction policy is not noeviction, start a goroutine to evict keys at the configured interval.
-	if echovault.config.EvictionPolicy != constants.NoEviction {
+	// Start a goroutine that deletes expired keys at the configured interval.
+	if echovault.config.EvictionInterval > 0 {
 		go func() {
 			ticker := time.NewTicker(echovault.config.EvictionInterval)
 			defer func() {
@@ -332,21 +553,30 @@ func NewEchoVault(options ...func(echovault *EchoVault)) (*EchoVault, error) {
 			for {
 				select {
 				case <-ticker.C:
-					// Run key eviction for each database that has volatile keys.
+					// Run an active expiry cycle for each database that has volatile keys.
+					echovault.keysWithExpiry.rwMutex.RLock()
+					databases := make([]int, 0, len(echovault.keysWithExpiry.keys))
+					for database, index := range echovault.keysWithExpiry.keys {
+						if index.Len() > 0 {
+							databases = append(databases, database)
+						}
+					}
+					echovault.keysWithExpiry.rwMutex.RUnlock()
 					wg := sync.WaitGroup{}
-					for database, _ := range echovault.keysWithExpiry.keys {
+					for _, database := range databases {
 						wg.Add(1)
 						ctx := context.WithValue(context.Background(), "Database", database)
 						go func(ctx context.Context, wg *sync.WaitGroup) {
 							if err := echovault.evictKeysWithExpiredTTL(ctx); err != nil {
-								log.Printf("evict with ttl: %v\n", err)
+								echovault.logger.Error("delete expired keys",
+									logger.DatabaseKey, ctx.Value("Database"), logger.ErrorKey, err)
 							}
 							wg.Done()
 						}(ctx, &wg)
 					}
 					wg.Wait()
 				case <-echovault.stopTTL:
-					break
+					return
 				}
 			}
 		}()
@@ -356,21 +586,34 @@ func NewEchoVault(options ...func(echovault *EchoVault)) (*EchoVault, error) {
 		return nil, errors.New("must provide certificate and key file paths for TLS mode")
 	}
 
+	if echovault.isInCluster() {
+		// Validate the cluster transport security settings before starting the cluster layers.
+		if _, err := memberlist.NewKeyring(echovault.config.GossipKeys); err != nil {
+			return nil, err
+		}
+		if echovault.config.RaftTLS {
+			if _, err := raft.NewTLSConfig(echovault.config); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	if echovault.isInCluster() {
 		// Initialise raft and memberlist
-		echovault.raft.RaftInit(echovault.context)
-		echovault.memberList.MemberListInit(echovault.context)
-		// Initialise caches
-		echovault.initialiseCaches()
+		if err := echovault.raft.RaftInit(echovault.context); err != nil {
+			return nil, err
+		}
+		if err := echovault.memberList.MemberListInit(echovault.context); err != nil {
+			return nil, err
+		}
 	}
 
 	if !echovault.isInCluster() {
-		echovault.initialiseCaches()
 		// Restore from AOF by default if it's enabled
 		if echovault.config.RestoreAOF {
 			err := echovault.aofEngine.Restore()
 			if err != nil {
-				log.Println(err)
+				echovault.logger.Warn("restore AOF", logger.ErrorKey, err)
 			}
 		}
 
@@ -378,16 +621,36 @@ func NewEchoVault(options ...func(echovault *EchoVault)) (*EchoVault, error) {
 		if echovault.config.RestoreSnapshot && !echovault.config.RestoreAOF {
 			err := echovault.snapshotEngine.Restore()
 			if err != nil {
-				log.Println(err)
+				echovault.logger.Warn("restore snapshot", logger.ErrorKey, err)
+			}
+		}
+
+		// Start replicating from the configured primary.
+		if echovault.config.ReplicaOf != "" {
+			host, port, err := net.SplitHostPort(echovault.config.ReplicaOf)
+			if err != nil {
+				return nil, fmt.Errorf("replicaof: %v", err)
 			}
+			p, err := strconv.Atoi(port)
+			if err != nil {
+				return nil, fmt.Errorf("replicaof: port must be an integer")
+			}
+			echovault.replication.ReplicaOf(host, p)
 		}
 	}
 
+	// Start replicating to the remote cluster once this server is the leader.
+	echovault.xrepl.Start()
+
+	if err = echovault.startMetrics(); err != nil {
+		return nil, err
+	}
+
 	return echovault, nil
 }
 
 func (server *EchoVault) startTCP() {
-	conf := server.config
+	conf := server.getConfig()
 
 	listenConfig := net.ListenConfig{
 		KeepAlive: 200 * time.Millisecond,
@@ -399,28 +662,28 @@ func (server *EchoVault) startTCP() {
 		fmt.Sprintf("%s:%d", conf.BindAddr, conf.Port),
 	)
 	if err != nil {
-		log.Printf("listener error: %v", err)
+		server.logger.Error("listen", logger.ErrorKey, err)
 		return
 	}
 
 	if !conf.TLS {
 		// TCP
-		log.Printf("Starting TCP server at Address %s, Port %d...\n", conf.BindAddr, conf.Port)
+		server.logger.Info("starting TCP server", "addr", conf.BindAddr, "port", conf.Port)
 	}
 
 	if conf.TLS || conf.MTLS {
 		// TLS
 		if conf.MTLS {
-			log.Printf("Starting mTLS server at Address %s, Port %d...\n", conf.BindAddr, conf.Port)
+			server.logger.Info("starting mTLS server", "addr", conf.BindAddr, "port", conf.Port)
 		} else {
-			log.Printf("Starting TLS server at Address %s, Port %d...\n", conf.BindAddr, conf.Port)
+			server.logger.Info("starting TLS server", "addr", conf.BindAddr, "port", conf.Port)
 		}
 
 		var certificates []tls.Certificate
 		for _, certKeyPair := range conf.CertKeyPairs {
 			c, err := tls.LoadX509KeyPair(certKeyPair[0], certKeyPair[1])
 			if err != nil {
-				log.Printf("load cert key pair: %v\n", err)
+				server.logger.Error("load cert key pair", logger.ErrorKey, err)
 				return
 			}
 			certificates = append(certificates, c)
@@ -434,15 +697,15 @@ func (server *EchoVault) startTCP() {
 			for _, c := range conf.ClientCAs {
 				ca, err := os.Open(c)
 				if err != nil {
-					log.Printf("client cert open: %v\n", err)
+					server.logger.Error("open client CA", "file", c, logger.ErrorKey, err)
 					return
 				}
 				certBytes, err := io.ReadAll(ca)
 				if err != nil {
-					log.Printf("client cert read: %v\n", err)
+					server.logger.Error("read client CA", "file", c, logger.ErrorKey, err)
 				}
 				if ok := clientCerts.AppendCertsFromPEM(certBytes); !ok {
-					log.Printf("client cert append: %v\n", err)
+					server.logger.Error("append client CA", "file", c)
 				}
 			}
 		}
@@ -464,7 +727,7 @@ func (server *EchoVault) startTCP() {
 		default:
 			conn, err := listener.Accept()
 			if err != nil {
-				log.Printf("listener error: %v\n", err)
+				server.logger.Error("accept connection", logger.ErrorKey, err)
 				return
 			}
 			// Read loop for connection
@@ -485,35 +748,58 @@ func (server *EchoVault) handleConnection(conn net.Conn) {
 	cid := server.connId.Add(1)
 	ctx := context.WithValue(server.context, internal.ContextConnID("ConnectionID"),
 		fmt.Sprintf("%s-%d", server.context.Value(internal.ContextServerID("ServerID")), cid))
+	// Cancel the context when the connection closes to stop the streams started by the connection.
+	ctx, cancel := context.WithCancel(ctx)
 
 	// Set the default connection information
+	now := server.clock.Now()
 	server.connInfo.mut.Lock()
 	server.connInfo.tcpClients[&conn] = internal.ConnectionInfo{
-		Id:       cid,
-		Name:     "",
-		Protocol: 2,
-		Database: 0,
+		Id:              cid,
+		Name:            "",
+		Protocol:        2,
+		Database:        0,
+		Addr:            conn.RemoteAddr().String(),
+		LocalAddr:       conn.LocalAddr().String(),
+		CreatedAt:       now,
+		LastInteraction: now,
 	}
+	handle := &connHandle{cancel: cancel}
+	handle.lastInteraction.Store(now.UnixNano())
+	connLogger := server.logger.With(logger.ConnIDKey, ctx.Value(internal.ContextConnID("ConnectionID")))
+	server.connInfo.handles[&conn] = handle
 	server.connInfo.mut.Unlock()
 
 	defer func() {
-		log.Printf("closing connection %d...", cid)
+		cancel()
+		server.connInfo.mut.Lock()
+		delete(server.connInfo.tcpClients, &conn)
+		delete(server.connInfo.handles, &conn)
+		server.connInfo.mut.Unlock()
+		server.tracking.Disable(cid)
+		connLogger.Debug("closing connection")
 		if err := conn.Close(); err != nil {
-			log.Println(err)
+			connLogger.Debug("close connection", logger.ErrorKey, err)
 		}
 	}()
 
 	for {
+		// The context is cancelled when CLIENT KILL closes the connection.
+		// A connection that kills itself is closed here, after the reply is written.
+		if ctx.Err() != nil {
+			break
+		}
+
 		message, err := internal.ReadMessage(r)
 
 		if err != nil && errors.Is(err, io.EOF) {
 			// Connection closed
-			log.Println(err)
+			connLogger.Debug("connection closed by client")
 			break
 		}
 
 		if err != nil {
-			log.Println(err)
+			connLogger.Warn("read from connection", logger.ErrorKey, err)
 			break
 		}
 
@@ -523,7 +809,7 @@ func (server *EchoVault) handleConnection(conn net.Conn) {
 		}
 		if err != nil {
 			if _, err = w.Write([]byte(fmt.Sprintf("-Error %s\r\n", err.Error()))); err != nil {
-				log.Println(err)
+				connLogger.Debug("write to connection", logger.ErrorKey, err)
 			}
 			continue
 		}
@@ -535,8 +821,15 @@ func (server *EchoVault) handleConnection(conn net.Conn) {
 			continue
 		}
 
+		handle.outputBuffer.Store(int64(len(res)))
+
+		// Hold the write lock so that the invalidation messages of CLIENT TRACKING are not written
+		// between the chunks of the response.
+		handle.writeMutex.Lock()
+
 		if len(res) <= chunkSize {
 			_, _ = w.Write(res)
+			handle.writeMutex.Unlock()
 			continue
 		}
 
@@ -547,7 +840,7 @@ func (server *EchoVault) handleConnection(conn net.Conn) {
 			if len(res)-1-startIndex < chunkSize {
 				_, err = w.Write(res[startIndex:])
 				if err != nil {
-					log.Println(err)
+					connLogger.Debug("write to connection", logger.ErrorKey, err)
 				}
 				break
 			}
@@ -557,6 +850,7 @@ func (server *EchoVault) handleConnection(conn net.Conn) {
 			}
 			startIndex += chunkSize
 		}
+		handle.writeMutex.Unlock()
 	}
 }
 
@@ -579,13 +873,13 @@ func (server *EchoVault) takeSnapshot() error {
 		if server.isInCluster() {
 			// Handle snapshot in cluster mode
 			if err := server.raft.TakeSnapshot(); err != nil {
-				log.Println(err)
+				server.logger.Error("take snapshot", logger.ErrorKey, err)
 			}
 			return
 		}
 		// Handle snapshot in standalone mode
 		if err := server.snapshotEngine.TakeSnapshot(); err != nil {
-			log.Println(err)
+			server.logger.Error("take snapshot", logger.ErrorKey, err)
 		}
 	}()
 
@@ -594,10 +888,14 @@ func (server *EchoVault) takeSnapshot() error {
 
 func (server *EchoVault) startSnapshot() {
 	server.snapshotInProgress.Store(true)
+	server.snapshotStart.Store(time.Now().UnixNano())
 }
 
 func (server *EchoVault) finishSnapshot() {
 	server.snapshotInProgress.Store(false)
+	if start := server.snapshotStart.Swap(0); start != 0 {
+		server.latency.Observe(latency.EventSnapshot, server.clock.Now(), time.Since(time.Unix(0, start)))
+	}
 }
 
 func (server *EchoVault) setLatestSnapshot(msec int64) {
@@ -631,43 +929,34 @@ func (server *EchoVault) rewriteAOF() error {
 // ShutDown gracefully shuts down the EchoVault instance.
 // This function shuts down the memberlist and raft layers.
 func (server *EchoVault) ShutDown() {
+	server.xrepl.Close()
+	if server.metricsServer != nil {
+		if err := server.metricsServer.Close(); err != nil {
+			server.logger.Error("close metrics server", logger.ErrorKey, err)
+		}
+	}
 	if server.listener.Load() != nil {
 		go func() { server.quit <- struct{}{} }()
 		go func() { server.stopTTL <- struct{}{} }()
-		log.Println("closing tcp listener...")
+		server.logger.Info("closing TCP listener")
 		if err := server.listener.Load().(net.Listener).Close(); err != nil {
-			log.Printf("listener close: %v\n", err)
+			server.logger.Error("close TCP listener", logger.ErrorKey, err)
 		}
 	}
 	if !server.isInCluster() {
+		server.replication.Close()
 		server.aofEngine.Close()
 	}
-	if server.isInCluster() {
-		server.raft.RaftShutdown()
-		server.memberList.MemberListShutdown()
+	if server.isInCluster() && !server.leftCluster.Load() {
+		if err := server.leaveCluster(clusterLeaveTimeout); err != nil {
+			server.logger.Error("leave cluster", logger.ErrorKey, err)
+		}
 	}
-}
-
-func (server *EchoVault) initialiseCaches() {
-	// Set up LFU cache.
-	server.lfuCache = struct {
-		mutex sync.Mutex
-		cache map[int]*eviction.CacheLFU
-	}{
-		mutex: sync.Mutex{},
-		cache: make(map[int]*eviction.CacheLFU),
+	// Save the cross-cluster replication state last, once the keyspace no longer changes.
+	if err := server.xrepl.Save(); err != nil {
+		server.logger.Error("save xrepl state", logger.ErrorKey, err)
 	}
-	// set up LRU cache.
-	server.lruCache = struct {
-		mutex sync.Mutex
-		cache map[int]*eviction.CacheLRU
-	}{
-		mutex: sync.Mutex{},
-		cache: make(map[int]*eviction.CacheLRU),
-	}
-	// Initialise caches for each preloaded database.
-	for database, _ := range server.store {
-		server.lfuCache.cache[database] = eviction.NewCacheLFU()
-		server.lruCache.cache[database] = eviction.NewCacheLRU()
+	if server.logFile != nil {
+		_ = server.logFile.Close()
 	}
 }
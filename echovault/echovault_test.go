@@ -24,13 +24,16 @@ import (
 	"github.com/echovault/echovault/internal/clock"
 	"github.com/echovault/echovault/internal/config"
 	"github.com/echovault/echovault/internal/constants"
+	"github.com/echovault/echovault/internal/eviction"
 	"github.com/go-test/deep"
 	"github.com/tidwall/resp"
 	"io"
 	"math"
 	"net"
+	"net/http"
 	"os"
 	"path"
+	"slices"
 	"strings"
 	"sync"
 	"testing"
@@ -593,6 +596,160 @@ func Test_Cluster(t *testing.T) {
 		}
 	})
 
+	t.Run("Test_ACLReplication", func(t *testing.T) {
+		// Create a new user on the leader.
+		if err := nodes[0].client.WriteArray([]resp.Value{
+			resp.StringValue("ACL"), resp.StringValue("SETUSER"),
+			resp.StringValue("replicated_user"), resp.StringValue("on"), resp.StringValue(">password1"),
+		}); err != nil {
+			t.Error(err)
+			return
+		}
+		rd, _, err := nodes[0].client.ReadValue()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if !strings.EqualFold(rd.String(), "ok") {
+			t.Errorf("expected response to be \"OK\", got %s", rd.String())
+			return
+		}
+
+		// Yield
+		ticker := time.NewTicker(200 * time.Millisecond)
+		defer func() {
+			ticker.Stop()
+		}()
+		<-ticker.C
+
+		hasUser := func(node ClientServerPair) (bool, error) {
+			if err := node.client.WriteArray([]resp.Value{resp.StringValue("ACL"), resp.StringValue("USERS")}); err != nil {
+				return false, err
+			}
+			rd, _, err := node.client.ReadValue()
+			if err != nil {
+				return false, err
+			}
+			for _, user := range rd.Array() {
+				if user.String() == "replicated_user" {
+					return true, nil
+				}
+			}
+			return false, nil
+		}
+
+		// Check that the user has been replicated to every node.
+		for i, node := range nodes {
+			ok, err := hasUser(node)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if !ok {
+				t.Errorf("expected node %d to have user replicated_user", i)
+			}
+		}
+
+		// Add a node after the user has been created and check that it catches up.
+		port, err := internal.GetFreePort()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		discoveryPort, err := internal.GetFreePort()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		node := ClientServerPair{
+			serverId:      fmt.Sprintf("SERVER-%d", len(nodes)),
+			bindAddr:      getBindAddr().String(),
+			port:          port,
+			discoveryPort: discoveryPort,
+			joinAddr:      fmt.Sprintf("%s/%s:%d", nodes[0].serverId, nodes[0].bindAddr, nodes[0].discoveryPort),
+		}
+		errChan := make(chan error, 1)
+		setupNode(&node, false, &errChan)
+		select {
+		case err = <-errChan:
+			t.Error(err)
+			return
+		default:
+		}
+		defer func() {
+			_ = node.raw.Close()
+			node.server.ShutDown()
+		}()
+
+		ticker.Reset(1 * time.Second)
+		<-ticker.C
+
+		ok, err := hasUser(node)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if !ok {
+			t.Error("expected late joining node to have user replicated_user")
+		}
+	})
+
+	t.Run("Test_BatchedApply", func(t *testing.T) {
+		// Concurrent writes are batched into shared log entries. Each caller must receive the response
+		// to its own command.
+		concurrency := 200
+		results := make(chan int, concurrency)
+		var wg sync.WaitGroup
+		for i := 0; i < concurrency; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				res, err := nodes[0].server.Incr("BatchedApplyCounter")
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				results <- res
+			}()
+		}
+		wg.Wait()
+		close(results)
+
+		seen := make(map[int]bool, concurrency)
+		for res := range results {
+			if seen[res] {
+				t.Errorf("expected every INCR response to be unique, got %d more than once", res)
+			}
+			seen[res] = true
+		}
+		for i := 1; i <= concurrency; i++ {
+			if !seen[i] {
+				t.Errorf("expected an INCR response of %d", i)
+			}
+		}
+
+		// The counter is replicated to the followers.
+		expected := fmt.Sprintf("%d", concurrency)
+		for i := 1; i < len(nodes); i++ {
+			ticker := time.NewTicker(50 * time.Millisecond)
+			timeout := time.After(5 * time.Second)
+			for {
+				value, _ := nodes[i].server.Get("BatchedApplyCounter")
+				if value == expected {
+					break
+				}
+				select {
+				case <-ticker.C:
+					continue
+				case <-timeout:
+					t.Errorf("expected BatchedApplyCounter on node %d to be %s, got %s", i, expected, value)
+				}
+				break
+			}
+			ticker.Stop()
+		}
+	})
+
 	t.Run("Test_SnapshotRestore", func(t *testing.T) {
 		// TODO: Test snapshot creation and restoration on the cluster.
 	})
@@ -1170,3 +1327,445 @@ func Test_Standalone(t *testing.T) {
 		}
 	})
 }
+
+func Test_ActiveExpiry(t *testing.T) {
+	server := createEchoVaultWithConfig(config.Config{
+		DataDir:          "",
+		EvictionPolicy:   constants.NoEviction,
+		EvictionSample:   20,
+		EvictionInterval: 10 * time.Millisecond,
+	})
+	t.Cleanup(server.ShutDown)
+
+	ctx := context.WithValue(context.Background(), "Database", 0)
+	now := server.clock.Now()
+
+	// More keys than the sample size expire, so that the cycle samples the index again.
+	for i := 0; i < 100; i++ {
+		presetKeyData(server, ctx, fmt.Sprintf("ExpiredKey%d", i), internal.KeyData{
+			Value: "value", ExpireAt: now.Add(-time.Duration(i+1) * time.Second),
+		})
+	}
+	for i := 0; i < 10; i++ {
+		presetKeyData(server, ctx, fmt.Sprintf("VolatileKey%d", i), internal.KeyData{
+			Value: "value", ExpireAt: now.Add(time.Duration(i+1) * time.Hour),
+		})
+	}
+	// Removing the expiry time of a key takes it out of the expiry index.
+	presetKeyData(server, ctx, "PersistedKey", internal.KeyData{Value: "value", ExpireAt: now.Add(-time.Second)})
+	server.setExpiry(ctx, "PersistedKey", time.Time{}, false)
+
+	if !eventually(t, 5*time.Second, func() bool {
+		return server.GetExpiryInfo().ExpiredKeys == 100
+	}) {
+		t.Fatalf("expected 100 expired keys to be deleted, got %+v", server.GetExpiryInfo())
+	}
+
+	server.storeLock.RLock()
+	remaining := len(server.store[0])
+	server.storeLock.RUnlock()
+	if remaining != 11 {
+		t.Errorf("expected 11 keys to remain, got %d", remaining)
+	}
+
+	info := server.GetExpiryInfo()
+	if info.VolatileKeys != 10 {
+		t.Errorf("expected 10 volatile keys, got %d", info.VolatileKeys)
+	}
+	if info.Cycles == 0 {
+		t.Error("expected active expiry cycles to be counted")
+	}
+}
+
+func Test_MaxMemory(t *testing.T) {
+	value := strings.Repeat("a", 100)
+
+	t.Run("Test keys are evicted until the dataset fits in max memory", func(t *testing.T) {
+		for _, policy := range []string{constants.AllKeysLRU, constants.AllKeysLFU, constants.AllKeysRandom} {
+			server := createEchoVaultWithConfig(config.Config{
+				DataDir:        "",
+				EvictionPolicy: policy,
+				MaxMemory:      4096,
+			})
+			ctx := context.WithValue(context.Background(), "Database", 0)
+			for i := 0; i < 100; i++ {
+				if err := presetValue(server, ctx, fmt.Sprintf("Key%d", i), value); err != nil {
+					t.Fatalf("%s: %v", policy, err)
+				}
+			}
+			if !eventually(t, 5*time.Second, func() bool {
+				stats := server.GetMemoryStats()
+				return stats.Dataset < 4096 && stats.EvictedKeys > 0
+			}) {
+				t.Errorf("%s: expected keys to be evicted below max memory, got %+v", policy, server.GetMemoryStats())
+			}
+			server.ShutDown()
+		}
+	})
+
+	t.Run("Test writes are rejected when max memory is reached without eviction", func(t *testing.T) {
+		server := createEchoVaultWithConfig(config.Config{
+			DataDir:        "",
+			EvictionPolicy: constants.NoEviction,
+			MaxMemory:      1024,
+		})
+		t.Cleanup(server.ShutDown)
+		ctx := context.WithValue(context.Background(), "Database", 0)
+
+		var err error
+		for i := 0; i < 100 && err == nil; i++ {
+			err = presetValue(server, ctx, fmt.Sprintf("Key%d", i), value)
+		}
+		if err == nil || err.Error() != "max memory reached, key value not set" {
+			t.Errorf("expected max memory error, got %v", err)
+		}
+
+		// Deleting keys frees their memory, so writes are accepted again.
+		keys := server.GetMemoryStats().Keys
+		if _, err = server.Del("Key0", "Key1", "Key2"); err != nil {
+			t.Fatal(err)
+		}
+		if stats := server.GetMemoryStats(); stats.Keys != keys-3 || stats.Dataset >= 1024 {
+			t.Errorf("expected memory of deleted keys to be released, got %+v", stats)
+		}
+		if err = presetValue(server, ctx, "Key0", value); err != nil {
+			t.Errorf("expected write to be accepted, got %v", err)
+		}
+	})
+
+	t.Run("Test memory usage of a key", func(t *testing.T) {
+		server := createEchoVault()
+		t.Cleanup(server.ShutDown)
+		if _, _, err := server.Set("UsageKey", value, SetOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		usage, err := server.MemoryUsage("UsageKey")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if usage < len(value) {
+			t.Errorf("expected usage to be at least %d, got %d", len(value), usage)
+		}
+		if stats := server.GetMemoryStats(); stats.Dataset != int64(usage) || stats.Databases[0].Keys != 1 {
+			t.Errorf("expected dataset of %d bytes in one key, got %+v", usage, stats)
+		}
+		if usage, _ = server.MemoryUsage("MissingKey"); usage != -1 {
+			t.Errorf("expected -1 for missing key, got %d", usage)
+		}
+	})
+
+	t.Run("Test memory usage of mutated hashes and lists", func(t *testing.T) {
+		server := createEchoVault()
+		t.Cleanup(server.ShutDown)
+
+		// The elements vary in size so that a sampled estimate would differ from the exact size.
+		fields := make(map[string]string)
+		for i := 0; i < 50; i++ {
+			fields[fmt.Sprintf("field%d", i)] = strings.Repeat("v", i*i)
+		}
+		elements := make([]string, 50)
+		for i := range elements {
+			elements[i] = strings.Repeat("e", i*i)
+		}
+		steps := []func() error{
+			func() error { _, err := server.HSet("HashKey", fields); return err },
+			func() error {
+				_, err := server.HSet("HashKey", map[string]string{"field1": "v", "new": "value"})
+				return err
+			},
+			func() error { _, err := server.HIncrBy("HashKey", "counter", 2); return err },
+			func() error { _, err := server.HDel("HashKey", "field49", "field48", "missing"); return err },
+			func() error { _, err := server.RPush("ListKey", elements...); return err },
+			func() error { _, err := server.LPush("ListKey", "head"); return err },
+			func() error { _, err := server.LSet("ListKey", 1, "short"); return err },
+			func() error { _, err := server.LRem("ListKey", 0, elements[49]); return err },
+			func() error { _, err := server.LPop("ListKey", 3); return err },
+			func() error { _, err := server.LTrim("ListKey", 2, 40); return err },
+		}
+		for i, step := range steps {
+			if err := step(); err != nil {
+				t.Fatalf("step %d: %v", i, err)
+			}
+		}
+
+		// The size maintained by the commands is the exact size that every element is counted for.
+		var total int64
+		for _, key := range []string{"HashKey", "ListKey"} {
+			usage, err := server.MemoryUsage(key, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			total += int64(usage)
+		}
+		if stats := server.GetMemoryStats(); stats.Dataset != total {
+			t.Errorf("expected dataset of %d bytes, got %d", total, stats.Dataset)
+		}
+	})
+}
+
+func Test_ApproximatedEviction(t *testing.T) {
+	value := strings.Repeat("a", 100)
+
+	tests := []struct {
+		name   string
+		policy string
+		// access makes the keys other than Key0 to Key9 more recently or frequently used.
+		access func(server *EchoVault)
+	}{
+		{
+			name:   "1. Evict the least recently used keys",
+			policy: constants.AllKeysLRU,
+			access: func(server *EchoVault) {
+				server.storeLock.Lock()
+				defer server.storeLock.Unlock()
+				for i := 0; i < 10; i++ {
+					key := fmt.Sprintf("Key%d", i)
+					data := server.store[0][key]
+					data.Access = eviction.LRUClock(server.clock.Now().Add(-time.Duration(i+1) * time.Hour))
+					server.store[0][key] = data
+				}
+			},
+		},
+		{
+			name:   "2. Evict the least frequently used keys",
+			policy: constants.AllKeysLFU,
+			access: func(server *EchoVault) {
+				for i := 10; i < 50; i++ {
+					for j := 0; j < 3; j++ {
+						if _, err := server.Get(fmt.Sprintf("Key%d", i)); err != nil {
+							t.Fatal(err)
+						}
+					}
+				}
+			},
+		},
+		{
+			name:   "3. Evict the keys that expire first",
+			policy: constants.VolatileTTL,
+			access: func(server *EchoVault) {
+				ctx := context.WithValue(context.Background(), "Database", 0)
+				for i := 0; i < 50; i++ {
+					server.setExpiry(ctx, fmt.Sprintf("Key%d", i), server.clock.Now().Add(time.Duration(i+1)*time.Hour), false)
+				}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Sample more keys than the keyspace holds, so that the eviction pool holds the best candidates.
+			server := createEchoVaultWithConfig(config.Config{
+				DataDir:        "",
+				EvictionPolicy: test.policy,
+				EvictionSample: 100,
+			})
+			t.Cleanup(server.ShutDown)
+			ctx := context.WithValue(context.Background(), "Database", 0)
+
+			for i := 0; i < 50; i++ {
+				if err := presetValue(server, ctx, fmt.Sprintf("Key%d", i), value); err != nil {
+					t.Fatal(err)
+				}
+			}
+			test.access(server)
+
+			// Lower max memory so that fewer than 10 keys have to be evicted.
+			stats := server.GetMemoryStats()
+			server.config.MaxMemory = uint64(stats.Dataset - 5*stats.Dataset/int64(stats.Keys))
+			if err := server.adjustMemoryUsage(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			stats = server.GetMemoryStats()
+			if stats.EvictedKeys == 0 || stats.Dataset >= int64(server.config.MaxMemory) {
+				t.Errorf("expected keys to be evicted below max memory, got %+v", stats)
+			}
+			for i := 10; i < 50; i++ {
+				if exists := server.keysExist(ctx, []string{fmt.Sprintf("Key%d", i)})[fmt.Sprintf("Key%d", i)]; !exists {
+					t.Errorf("expected Key%d not to be evicted", i)
+				}
+			}
+		})
+	}
+
+	t.Run("4. Return the access frequency and idle time of keys", func(t *testing.T) {
+		lfu := createEchoVaultWithConfig(config.Config{
+			DataDir:        "",
+			EvictionPolicy: constants.AllKeysLFU,
+		})
+		t.Cleanup(lfu.ShutDown)
+		if _, _, err := lfu.Set("FreqKey", value, SetOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		// With a log factor of 0, every access increments the counter.
+		for i := 0; i < 5; i++ {
+			if _, err := lfu.Get("FreqKey"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if freq, err := lfu.ObjectFreq("FreqKey"); err != nil || freq != eviction.LFUInitValue+5 {
+			t.Errorf("expected frequency %d, got %d, %v", eviction.LFUInitValue+5, freq, err)
+		}
+		if _, err := lfu.ObjectIdleTime("FreqKey"); err == nil {
+			t.Error("expected error for idle time with an lfu eviction policy")
+		}
+
+		lru := createEchoVaultWithConfig(config.Config{
+			DataDir:        "",
+			EvictionPolicy: constants.AllKeysLRU,
+		})
+		t.Cleanup(lru.ShutDown)
+		presetKeyData(lru, context.Background(), "IdleKey", internal.KeyData{Value: value})
+		lru.storeLock.Lock()
+		data := lru.store[0]["IdleKey"]
+		data.Access = eviction.LRUClock(lru.clock.Now().Add(-time.Minute))
+		lru.store[0]["IdleKey"] = data
+		lru.storeLock.Unlock()
+		if idle, err := lru.ObjectIdleTime("IdleKey"); err != nil || idle != 60 {
+			t.Errorf("expected idle time 60, got %d, %v", idle, err)
+		}
+		// Reading the key resets its idle time.
+		if _, err := lru.Get("IdleKey"); err != nil {
+			t.Fatal(err)
+		}
+		if idle, _ := lru.ObjectIdleTime("IdleKey"); idle != 0 {
+			t.Errorf("expected idle time 0 after access, got %d", idle)
+		}
+		if idle, _ := lru.ObjectIdleTime("MissingKey"); idle != -1 {
+			t.Errorf("expected -1 for missing key, got %d", idle)
+		}
+	})
+}
+
+func Test_PinnedKeys(t *testing.T) {
+	value := strings.Repeat("a", 100)
+
+	for _, policy := range []string{
+		constants.AllKeysRandom, constants.AllKeysLRU, constants.VolatileRandom, constants.VolatileTTL,
+	} {
+		t.Run(policy, func(t *testing.T) {
+			server := createEchoVaultWithConfig(config.Config{
+				DataDir:        "",
+				EvictionPolicy: policy,
+				NoEvictKeys:    []string{"Config:*"},
+			})
+			t.Cleanup(server.ShutDown)
+			if _, err := server.PinKeys("Session:*", "Config:*"); err != nil {
+				t.Fatal(err)
+			}
+			ctx := context.WithValue(context.Background(), "Database", 0)
+
+			for i := 0; i < 20; i++ {
+				for _, prefix := range []string{"Config", "Session", "Cache"} {
+					key := fmt.Sprintf("%s:%d", prefix, i)
+					presetKeyData(server, ctx, key, internal.KeyData{Value: value, ExpireAt: server.clock.Now().Add(time.Hour)})
+				}
+			}
+
+			// Lower max memory so that every unpinned key has to be evicted.
+			stats := server.GetMemoryStats()
+			server.config.MaxMemory = uint64(stats.Dataset / 2)
+			if err := server.adjustMemoryUsage(ctx); err == nil {
+				t.Error("expected error when there are no keys left to evict")
+			}
+
+			server.storeLock.RLock()
+			defer server.storeLock.RUnlock()
+			for key, _ := range server.store[0] {
+				if strings.HasPrefix(key, "Cache:") {
+					t.Errorf("expected %s to be evicted", key)
+				}
+			}
+			if len(server.store[0]) != 40 {
+				t.Errorf("expected 40 pinned keys to remain, got %d", len(server.store[0]))
+			}
+		})
+	}
+
+	t.Run("Test pin and unpin key patterns", func(t *testing.T) {
+		server := createEchoVaultWithConfig(config.Config{
+			DataDir:        "",
+			EvictionPolicy: constants.AllKeysLRU,
+			NoEvictKeys:    []string{"Config:*"},
+		})
+		t.Cleanup(server.ShutDown)
+
+		if count, err := server.PinKeys("Session:*", "Config:*"); err != nil || count != 1 {
+			t.Errorf("expected 1 new pattern, got %d, %v", count, err)
+		}
+		if patterns, _ := server.PinnedKeys(); !slices.Equal(patterns, []string{"Config:*", "Session:*"}) {
+			t.Errorf("expected pinned patterns [Config:* Session:*], got %v", patterns)
+		}
+		if count, err := server.UnpinKeys("Config:*", "Other:*"); err != nil || count != 1 {
+			t.Errorf("expected 1 removed pattern, got %d, %v", count, err)
+		}
+		if patterns, _ := server.PinnedKeys(); !slices.Equal(patterns, []string{"Session:*"}) {
+			t.Errorf("expected pinned patterns [Session:*], got %v", patterns)
+		}
+		if _, err := server.PinKeys("Invalid[*"); err == nil {
+			t.Error("expected error for invalid pattern")
+		}
+	})
+}
+
+func Test_Metrics(t *testing.T) {
+	port, err := internal.GetFreePort()
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := NewEchoVault(WithConfig(config.Config{
+		BindAddr:       "localhost",
+		DataDir:        "",
+		EvictionPolicy: constants.NoEviction,
+		MetricsPort:    uint16(port),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(server.ShutDown)
+
+	if _, _, err = server.Set("MetricsKey1", "value1", SetOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = server.Set("MetricsKey2", "value2", SetOptions{EX: 100}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = server.Get("MetricsKey1"); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := http.Get(fmt.Sprintf("http://localhost:%d/metrics", port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	if contentType := res.Header.Get("Content-Type"); !strings.HasPrefix(contentType, "text/plain") {
+		t.Errorf("expected a text/plain content type, got %s", contentType)
+	}
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"# TYPE echovault_commands_total counter\n",
+		`echovault_commands_total{command="set"} 2` + "\n",
+		`echovault_commands_total{command="get"} 1` + "\n",
+		"# TYPE echovault_command_duration_seconds histogram\n",
+		`echovault_command_duration_seconds_bucket{command="set",le="+Inf"} 2` + "\n",
+		`echovault_command_duration_seconds_count{command="get"} 1` + "\n",
+		"echovault_connected_clients 0\n",
+		`echovault_keys{db="0"} 2` + "\n",
+		`echovault_expiring_keys{db="0"} 1` + "\n",
+		"echovault_expired_keys_total 0\n",
+		"echovault_evicted_keys_total 0\n",
+		"# TYPE echovault_snapshot_duration_seconds histogram\n",
+		"echovault_pubsub_channels 0\n",
+	} {
+		if !strings.Contains(string(body), expected) {
+			t.Errorf("expected metrics to contain %q, got:\n%s", expected, body)
+		}
+	}
+}
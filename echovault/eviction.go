@@ -0,0 +1,275 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package echovault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/echovault/echovault/internal/constants"
+	"github.com/echovault/echovault/internal/eviction"
+	"github.com/gobwas/glob"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+// isLFUPolicy returns true when the eviction policy evicts the least frequently used keys.
+func (server *EchoVault) isLFUPolicy() bool {
+	return slices.Contains([]string{constants.AllKeysLFU, constants.VolatileLFU}, strings.ToLower(server.getConfig().EvictionPolicy))
+}
+
+// accessKey returns the access data of a key after it's accessed. With an lfu eviction policy, the access counter
+// of the key is incremented, otherwise the LRU clock of the access is recorded. Pass false as exists for new keys.
+func (server *EchoVault) accessKey(access uint32, exists bool) uint32 {
+	now := server.clock.Now()
+	if !server.isLFUPolicy() {
+		return eviction.LRUClock(now)
+	}
+	if !exists {
+		return eviction.NewLFU(now)
+	}
+	conf := server.getConfig()
+	return eviction.TouchLFU(access, now, int(conf.LFULogFactor), int(conf.LFUDecayTime))
+}
+
+// nextEvictionCandidate returns the database and the key that should be evicted next following the eviction policy.
+// Pinned keys are never returned. It returns false when there are no keys left to evict.
+// It must be called while the store lock is held.
+//
+// The lru, lfu and ttl policies approximate the best key to evict like Redis does. Every call samples EvictionSample
+// keys from each database into the eviction pool, which keeps the best candidates across calls, and returns the best
+// candidate in the pool.
+func (server *EchoVault) nextEvictionCandidate() (int, string, bool) {
+	policy := strings.ToLower(server.getConfig().EvictionPolicy)
+	volatile := slices.Contains([]string{
+		constants.VolatileLFU, constants.VolatileLRU, constants.VolatileRandom, constants.VolatileTTL,
+	}, policy)
+
+	server.noEvict.mutex.RLock()
+	defer server.noEvict.mutex.RUnlock()
+
+	switch policy {
+	case constants.AllKeysRandom:
+		// Evict random keys. Map iteration order is random.
+		for database, store := range server.store {
+			for key, _ := range store {
+				if !server.isPinned(key) {
+					return database, key, true
+				}
+			}
+		}
+		return 0, "", false
+	case constants.VolatileRandom:
+		// Evict random keys with an associated expiry time.
+		server.keysWithExpiry.rwMutex.RLock()
+		defer server.keysWithExpiry.rwMutex.RUnlock()
+		for database, index := range server.keysWithExpiry.keys {
+			for i := 0; i < server.evictionSamples() && i < index.Len(); i++ {
+				if key, ok := index.RandomKey(); ok && !server.isPinned(key) {
+					return database, key, true
+				}
+			}
+		}
+		// Every sampled key is pinned, so look for a key that isn't.
+		for database, store := range server.store {
+			for key, data := range store {
+				if !data.ExpireAt.IsZero() && !server.isPinned(key) {
+					return database, key, true
+				}
+			}
+		}
+		return 0, "", false
+	case constants.AllKeysLFU, constants.AllKeysLRU, constants.VolatileLFU, constants.VolatileLRU, constants.VolatileTTL:
+		server.sampleEvictionPool(policy, volatile, server.evictionSamples())
+		if server.memory.pool.Len() == 0 {
+			// Every sampled key is pinned, so look for candidates in the whole keyspace.
+			server.sampleEvictionPool(policy, volatile, 0)
+		}
+		for {
+			entry, ok := server.memory.pool.Pop()
+			if !ok {
+				return 0, "", false
+			}
+			// Skip the keys that were deleted, lost their expiry time, or were pinned since they were sampled.
+			data, ok := server.store[entry.Database][entry.Key]
+			if ok && (!volatile || !data.ExpireAt.IsZero()) && !server.isPinned(entry.Key) {
+				return entry.Database, entry.Key, true
+			}
+		}
+	default:
+		return 0, "", false
+	}
+}
+
+// evictionSamples returns the number of keys sampled from each database when looking for keys to evict.
+func (server *EchoVault) evictionSamples() int {
+	samples := server.getConfig().EvictionSample
+	if samples == 0 {
+		return 5
+	}
+	return int(samples)
+}
+
+// sampleEvictionPool samples keys from every database and inserts the keys that are not pinned in the eviction pool.
+// Keys are scored by their idle time with an lru policy, by the inverse of their access counter with an lfu policy,
+// and by how soon they expire with the volatile-ttl policy. When volatile is true, only keys with an expiry time
+// are sampled. A samples count of 0 inserts every key. It must be called while the store lock and the no-evict lock
+// are held.
+func (server *EchoVault) sampleEvictionPool(policy string, volatile bool, samples int) {
+	now := server.clock.Now()
+	decayTime := int(server.getConfig().LFUDecayTime)
+	insert := func(database int, key string) {
+		data, ok := server.store[database][key]
+		if !ok || (volatile && data.ExpireAt.IsZero()) || server.isPinned(key) {
+			return
+		}
+		switch policy {
+		case constants.AllKeysLFU, constants.VolatileLFU:
+			frequency := eviction.LFUFrequency(data.Access, now, decayTime)
+			server.memory.pool.Insert(database, key, uint64(255-frequency))
+		case constants.VolatileTTL:
+			server.memory.pool.Insert(database, key, uint64(math.MaxInt64-data.ExpireAt.UnixNano()))
+		default:
+			server.memory.pool.Insert(database, key, uint64(eviction.IdleTime(data.Access, now)))
+		}
+	}
+
+	if volatile {
+		server.keysWithExpiry.rwMutex.RLock()
+		defer server.keysWithExpiry.rwMutex.RUnlock()
+	}
+
+	for database, store := range server.store {
+		if volatile && samples > 0 {
+			index := server.keysWithExpiry.keys[database]
+			for i := 0; index != nil && i < samples && i < index.Len(); i++ {
+				if key, ok := index.RandomKey(); ok {
+					insert(database, key)
+				}
+			}
+			continue
+		}
+		// Map iteration starts at a random key, so the first keys are a random sample.
+		sampled := 0
+		for key, _ := range store {
+			if samples > 0 && sampled == samples {
+				break
+			}
+			insert(database, key)
+			sampled += 1
+		}
+	}
+}
+
+// isPinned returns true when the key matches one of the patterns of keys that are never evicted.
+// It must be called while the no-evict lock is held.
+func (server *EchoVault) isPinned(key string) bool {
+	for _, g := range server.noEvict.patterns {
+		if g.Match(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// pinKeys adds the glob patterns to the patterns of keys that are never evicted.
+// It returns the number of patterns that were not pinned before.
+func (server *EchoVault) pinKeys(patterns []string) (int, error) {
+	compiled := make(map[string]glob.Glob, len(patterns))
+	for _, pattern := range patterns {
+		g, err := glob.Compile(pattern)
+		if err != nil {
+			return 0, fmt.Errorf("invalid no-evict pattern %s: %v", pattern, err)
+		}
+		compiled[pattern] = g
+	}
+
+	server.noEvict.mutex.Lock()
+	defer server.noEvict.mutex.Unlock()
+
+	count := 0
+	for pattern, g := range compiled {
+		if _, ok := server.noEvict.patterns[pattern]; !ok {
+			server.noEvict.patterns[pattern] = g
+			count += 1
+		}
+	}
+	return count, nil
+}
+
+// unpinKeys removes the glob patterns from the patterns of keys that are never evicted.
+// It returns the number of patterns that were removed.
+func (server *EchoVault) unpinKeys(patterns []string) int {
+	server.noEvict.mutex.Lock()
+	defer server.noEvict.mutex.Unlock()
+
+	count := 0
+	for _, pattern := range patterns {
+		if _, ok := server.noEvict.patterns[pattern]; ok {
+			delete(server.noEvict.patterns, pattern)
+			count += 1
+		}
+	}
+	return count
+}
+
+// getPinnedKeys returns the sorted glob patterns of keys that are never evicted.
+func (server *EchoVault) getPinnedKeys() []string {
+	server.noEvict.mutex.RLock()
+	defer server.noEvict.mutex.RUnlock()
+
+	patterns := make([]string, 0, len(server.noEvict.patterns))
+	for pattern, _ := range server.noEvict.patterns {
+		patterns = append(patterns, pattern)
+	}
+	slices.Sort(patterns)
+	return patterns
+}
+
+// getObjectFreq returns the logarithmic access counter of the key without recording an access.
+func (server *EchoVault) getObjectFreq(ctx context.Context, key string) (int, bool, error) {
+	if !server.isLFUPolicy() {
+		return 0, false, errors.New("an lfu eviction policy is not selected, access frequency not tracked")
+	}
+
+	server.storeLock.RLock()
+	defer server.storeLock.RUnlock()
+
+	database := ctx.Value("Database").(int)
+	data, ok := server.store[database][key]
+	if !ok || (!data.ExpireAt.IsZero() && data.ExpireAt.Before(server.clock.Now())) {
+		return 0, false, nil
+	}
+	return int(eviction.LFUFrequency(data.Access, server.clock.Now(), int(server.getConfig().LFUDecayTime))), true, nil
+}
+
+// getObjectIdleTime returns the time since the key was last accessed without recording an access.
+func (server *EchoVault) getObjectIdleTime(ctx context.Context, key string) (time.Duration, bool, error) {
+	if server.isLFUPolicy() {
+		return 0, false, errors.New("an lfu eviction policy is selected, idle time not tracked")
+	}
+
+	server.storeLock.RLock()
+	defer server.storeLock.RUnlock()
+
+	database := ctx.Value("Database").(int)
+	data, ok := server.store[database][key]
+	if !ok || (!data.ExpireAt.IsZero() && data.ExpireAt.Before(server.clock.Now())) {
+		return 0, false, nil
+	}
+	return eviction.IdleTime(data.Access, server.clock.Now()), true, nil
+}
@@ -15,19 +15,16 @@
 package echovault
 
 import (
-	"container/heap"
 	"context"
 	"errors"
 	"fmt"
 	"github.com/echovault/echovault/internal"
 	"github.com/echovault/echovault/internal/constants"
 	"github.com/echovault/echovault/internal/eviction"
-	"log"
+	"github.com/echovault/echovault/internal/latency"
+	"github.com/echovault/echovault/internal/logger"
+	"github.com/echovault/echovault/internal/modules/cdc"
 	"math/rand"
-	"runtime"
-	"slices"
-	"strings"
-	"sync"
 	"time"
 )
 
@@ -50,25 +47,15 @@ func (server *EchoVault) SwapDBs(database1, database2 int) {
 	}
 	server.storeLock.Unlock()
 
+	// The values read by the tracking clients may have changed.
+	server.tracking.InvalidateAll()
+
 	// Swap the connections for each database.
-	server.connInfo.mut.Lock()
-	defer server.connInfo.mut.Unlock()
-	for connection, info := range server.connInfo.tcpClients {
-		switch info.Database {
-		case database1:
-			server.connInfo.tcpClients[connection] = internal.ConnectionInfo{
-				Id:       info.Id,
-				Name:     info.Name,
-				Protocol: info.Protocol,
-				Database: database2,
-			}
-		case database2:
-			server.connInfo.tcpClients[connection] = internal.ConnectionInfo{
-				Id:       info.Id,
-				Name:     info.Name,
-				Protocol: info.Protocol,
-				Database: database1,
-			}
+	server.connInfo.mut.RLock()
+	defer server.connInfo.mut.RUnlock()
+	for _, handle := range server.connInfo.handles {
+		if !handle.database.CompareAndSwap(int64(database1), int64(database2)) {
+			handle.database.CompareAndSwap(int64(database2), int64(database1))
 		}
 	}
 }
@@ -82,34 +69,70 @@ func (server *EchoVault) Flush(database int) {
 	server.keysWithExpiry.rwMutex.Lock()
 	defer server.keysWithExpiry.rwMutex.Unlock()
 
-	server.lfuCache.mutex.Lock()
-	defer server.lfuCache.mutex.Unlock()
+	server.memory.pool.Clear(database)
 
-	server.lruCache.mutex.Lock()
-	defer server.lruCache.mutex.Unlock()
+	server.tracking.InvalidateAll()
+
+	if database == -1 {
+		server.publishEvent(internal.MutationEvent{Type: cdc.EventFlush, Database: database, Command: []string{"FLUSHALL"}})
+	} else {
+		server.publishEvent(internal.MutationEvent{Type: cdc.EventFlush, Database: database, Command: []string{"FLUSHDB"}})
+	}
 
 	if database == -1 {
 		for db, _ := range server.store {
 			// Clear db store.
 			clear(server.store[db])
+			server.updateMemoryUsage(db, server.memory.databases[db], 0)
 			// Clear db volatile key tracker.
-			clear(server.keysWithExpiry.keys[db])
-			// Clear db LFU cache.
-			server.lfuCache.cache[db] = eviction.NewCacheLFU()
-			// Clear db LRU cache.
-			server.lruCache.cache[db] = eviction.NewCacheLRU()
+			server.keysWithExpiry.keys[db] = eviction.NewExpiryIndex()
 		}
 		return
 	}
 
 	// Clear db store.
 	clear(server.store[database])
+	server.updateMemoryUsage(database, server.memory.databases[database], 0)
 	// Clear db volatile key tracker.
-	clear(server.keysWithExpiry.keys[database])
-	// Clear db LFU cache.
-	server.lfuCache.cache[database] = eviction.NewCacheLFU()
-	// Clear db LRU cache.
-	server.lruCache.cache[database] = eviction.NewCacheLRU()
+	server.keysWithExpiry.keys[database] = eviction.NewExpiryIndex()
+}
+
+// replaceState replaces every logical database with the given state in one step. It's used to restore raft
+// snapshots, so that clients never read a partially restored keyspace and the keys missing from the snapshot
+// are removed.
+func (server *EchoVault) replaceState(state map[int]map[string]internal.KeyData) {
+	server.storeLock.Lock()
+	defer server.storeLock.Unlock()
+
+	server.keysWithExpiry.rwMutex.Lock()
+	defer server.keysWithExpiry.rwMutex.Unlock()
+
+	server.memory.pool.Clear(-1)
+
+	server.tracking.InvalidateAll()
+
+	server.publishEvent(internal.MutationEvent{Type: cdc.EventFlush, Database: -1, Command: []string{"FLUSHALL"}})
+
+	for database := range server.store {
+		clear(server.store[database])
+		server.updateMemoryUsage(database, server.memory.databases[database], 0)
+		server.keysWithExpiry.keys[database] = eviction.NewExpiryIndex()
+	}
+
+	for database, data := range state {
+		if server.store[database] == nil {
+			server.store[database] = make(map[string]internal.KeyData, len(data))
+			server.keysWithExpiry.keys[database] = eviction.NewExpiryIndex()
+		}
+		for key, keyData := range data {
+			keyData.Size = internal.EstimateKeySize(key, keyData.Value, internal.DefaultMemorySamples)
+			keyData.Access = server.accessKey(0, false)
+			server.store[database][key] = keyData
+			server.updateMemoryUsage(database, 0, keyData.Size)
+			server.keysWithExpiry.keys[database].Set(key, keyData.ExpireAt)
+			server.publishEvent(internal.MutationEvent{Type: cdc.EventSet, Database: database, Key: key})
+		}
+	}
 }
 
 func (server *EchoVault) keysExist(ctx context.Context, keys []string) map[string]bool {
@@ -123,6 +146,11 @@ func (server *EchoVault) keysExist(ctx context.Context, keys []string) map[strin
 	for _, key := range keys {
 		_, ok := server.store[database][key]
 		exists[key] = ok
+		// Handlers check that keys exist before reading them, so this is where most misses are seen.
+		// Hits are counted when the values are read.
+		if !ok {
+			server.stats.keyspaceMisses.Add(1)
+		}
 	}
 
 	return exists
@@ -153,24 +181,30 @@ func (server *EchoVault) getValues(ctx context.Context, keys []string) map[strin
 	for _, key := range keys {
 		entry, ok := server.store[database][key]
 		if !ok {
+			server.stats.keyspaceMisses.Add(1)
 			values[key] = nil
 			continue
 		}
 
 		if entry.ExpireAt != (time.Time{}) && entry.ExpireAt.Before(server.clock.Now()) {
+			server.stats.keyspaceMisses.Add(1)
+			ctx := context.WithValue(ctx, internal.ContextDeleteReason("Reason"), cdc.EventExpire)
 			if !server.isInCluster() {
 				// If in standalone mode, delete the key directly.
 				err := server.deleteKey(ctx, key)
 				if err != nil {
-					log.Printf("keyExists: %+v\n", err)
+					server.logger.Error("delete expired key", logger.DatabaseKey, database, "key", key, logger.ErrorKey, err)
 				}
 			} else if server.isInCluster() && server.raft.IsRaftLeader() {
 				// If we're in a raft cluster, and we're the leader, send command to delete the key in the cluster.
-				err := server.raftApplyDeleteKey(ctx, key)
-				if err != nil {
-					log.Printf("keyExists: %+v\n", err)
-				}
-			} else if server.isInCluster() && !server.raft.IsRaftLeader() {
+				// The deletion is applied asynchronously, as applying it takes the store lock held here and waits
+				// for the read commands that hold the apply lock.
+				go func(ctx context.Context, key string) {
+					if err := server.raftApplyDeleteKey(ctx, key); err != nil {
+						server.logger.Error("delete expired key", logger.DatabaseKey, database, "key", key, logger.ErrorKey, err)
+					}
+				}(ctx, key)
+			} else if server.isInCluster() && !server.raft.IsRaftLeader() && !server.hasLeftCluster() {
 				// Forward message to leader to initiate key deletion.
 				// This is always called regardless of ForwardCommand config value
 				// because we always want to remove expired keys.
@@ -180,27 +214,28 @@ func (server *EchoVault) getValues(ctx context.Context, keys []string) map[strin
 			continue
 		}
 
+		server.stats.keyspaceHits.Add(1)
 		values[key] = entry.Value
-	}
 
-	// Asynchronously update the keys in the cache.
-	go func(ctx context.Context, keys []string) {
-		if err := server.updateKeysInCache(ctx, keys); err != nil {
-			log.Printf("getValues error: %+v\n", err)
-		}
-	}(ctx, keys)
+		// Record the access to the key for the lru and lfu eviction policies.
+		entry.Access = server.accessKey(entry.Access, true)
+		server.store[database][key] = entry
+	}
 
 	return values
 }
 
 func (server *EchoVault) setValues(ctx context.Context, entries map[string]interface{}) error {
+	return server.updateValues(ctx, entries, nil)
+}
+
+// updateValues sets the values of the keys. The size of the keys with a growth changes by their growth, which the
+// hash and list commands compute from the elements they add and remove, so that large hashes and lists are not
+// sampled on every write. The size of the other keys is estimated from their value.
+func (server *EchoVault) updateValues(ctx context.Context, entries map[string]interface{}, growth map[string]int64) error {
 	server.storeLock.Lock()
 	defer server.storeLock.Unlock()
 
-	if internal.IsMaxMemoryExceeded(server.config.MaxMemory) && server.config.EvictionPolicy == constants.NoEviction {
-		return errors.New("max memory reached, key value not set")
-	}
-
 	database := ctx.Value("Database").(int)
 
 	// If database does not exist, create it.
@@ -208,29 +243,55 @@ func (server *EchoVault) setValues(ctx context.Context, entries map[string]inter
 		server.createDatabase(database)
 	}
 
+	sizes := make(map[string]int64, len(entries))
+	total := int64(0)
 	for key, value := range entries {
-		expireAt := time.Time{}
-		if _, ok := server.store[database][key]; ok {
-			expireAt = server.store[database][key].ExpireAt
+		previous, exists := server.store[database][key]
+		switch keyGrowth, ok := growth[key]; {
+		case ok && exists:
+			sizes[key] = previous.Size + keyGrowth
+		case ok:
+			sizes[key] = internal.EstimateKeySize(key, nil, 0) + keyGrowth
+		default:
+			sizes[key] = internal.EstimateKeySize(key, value, internal.DefaultMemorySamples)
+		}
+		total += sizes[key] - previous.Size
+	}
+
+	// Writes that shrink the dataset, like removing members from a set, are allowed when max memory is reached.
+	if total > 0 && server.isMaxMemoryExceeded() && server.getConfig().EvictionPolicy == constants.NoEviction {
+		return errors.New("max memory reached, key value not set")
+	}
+
+	for key, value := range entries {
+		if !server.acceptsWrite(ctx, database, key) {
+			continue
 		}
-		server.store[database][key] = internal.KeyData{
+		previous, exists := server.store[database][key]
+		data := internal.KeyData{
 			Value:    value,
-			ExpireAt: expireAt,
+			ExpireAt: previous.ExpireAt,
+			Size:     sizes[key],
+			Access:   server.accessKey(previous.Access, exists),
 		}
+		server.store[database][key] = data
+		server.updateMemoryUsage(database, previous.Size, data.Size)
 		if !server.isInCluster() {
 			server.snapshotEngine.IncrementChangeCount()
 		}
+		server.publishMutation(ctx, cdc.EventSet, key)
+		server.notifyWrite(ctx, database, key, value)
+		server.invalidateKey(ctx, key)
 	}
 
-	// Asynchronously update the keys in the cache.
-	go func(ctx context.Context, entries map[string]interface{}) {
-		for key, _ := range entries {
-			err := server.updateKeysInCache(ctx, []string{key})
-			if err != nil {
-				log.Printf("setValues error: %+v\n", err)
+	// Asynchronously evict keys when the write took the dataset over max memory.
+	if server.isMaxMemoryExceeded() && server.getConfig().EvictionPolicy != constants.NoEviction {
+		go func(ctx context.Context) {
+			if err := server.adjustMemoryUsage(ctx); err != nil {
+				server.logger.Error("evict keys", logger.ErrorKey, err)
 			}
-		}
-	}(ctx, entries)
+		}(ctx)
+	}
 
 	return nil
 }
@@ -241,55 +302,95 @@ func (server *EchoVault) setExpiry(ctx context.Context, key string, expireAt tim
 
 	database := ctx.Value("Database").(int)
 
-	server.store[database][key] = internal.KeyData{
-		Value:    server.store[database][key].Value,
-		ExpireAt: expireAt,
+	if !server.acceptsWrite(ctx, database, key) {
+		return
 	}
 
-	// If the slice of keys associated with expiry time does not contain the current key, add the key.
-	server.keysWithExpiry.rwMutex.Lock()
-	if !slices.Contains(server.keysWithExpiry.keys[database], key) {
-		server.keysWithExpiry.keys[database] = append(server.keysWithExpiry.keys[database], key)
+	if data, ok := server.store[database][key]; ok && !data.ExpireAt.Equal(expireAt) {
+		server.publishMutation(ctx, cdc.EventSet, key)
+		server.notifyExpiry(ctx, database, key, expireAt)
+		server.invalidateKey(ctx, key)
 	}
-	server.keysWithExpiry.rwMutex.Unlock()
 
-	// If touch is true, update the keys status in the cache.
+	data, exists := server.store[database][key]
+	data.ExpireAt = expireAt
+	// If touch is true, record the access to the key.
 	if touch {
-		go func(ctx context.Context, key string) {
-			err := server.updateKeysInCache(ctx, []string{key})
-			if err != nil {
-				log.Printf("setExpiry error: %+v\n", err)
-			}
-		}(ctx, key)
+		data.Access = server.accessKey(data.Access, exists)
 	}
+	server.store[database][key] = data
+
+	// Add the key to the index of keys associated with an expiry time, or remove it if the expiry time is cleared.
+	server.keysWithExpiry.rwMutex.Lock()
+	server.keysWithExpiry.keys[database].Set(key, expireAt)
+	server.keysWithExpiry.rwMutex.Unlock()
 }
 
 func (server *EchoVault) deleteKey(ctx context.Context, key string) error {
 	database := ctx.Value("Database").(int)
 
+	if !server.acceptsWrite(ctx, database, key) {
+		return nil
+	}
+
+	if data, ok := server.store[database][key]; ok {
+		server.updateMemoryUsage(database, data.Size, 0)
+		reason, _ := ctx.Value(internal.ContextDeleteReason("Reason")).(string)
+		if reason != "" {
+			// Expired and evicted keys are not deleted by the command in the context.
+			server.publishEvent(internal.MutationEvent{
+				Type: reason, Database: database, Key: key, Command: []string{"DEL", key},
+			})
+		} else {
+			server.publishMutation(ctx, cdc.EventDel, key)
+		}
+		server.notifyDelete(ctx, database, key, reason)
+		server.invalidateKey(ctx, key)
+		switch reason {
+		case cdc.EventExpire:
+			server.expiryStats.mutex.Lock()
+			server.expiryStats.expiredKeys += 1
+			server.expiryStats.mutex.Unlock()
+		case cdc.EventEvict:
+			server.memory.evictedKeys += 1
+		}
+	}
+
 	// Delete the key from keyLocks and store.
 	delete(server.store[database], key)
 
-	// Remove key from slice of keys associated with expiry.
+	// Remove key from the index of keys associated with expiry.
 	server.keysWithExpiry.rwMutex.Lock()
 	defer server.keysWithExpiry.rwMutex.Unlock()
-	server.keysWithExpiry.keys[database] = slices.DeleteFunc(server.keysWithExpiry.keys[database], func(k string) bool {
-		return k == key
-	})
-
-	// Remove the key from the cache associated with the database.
-	switch {
-	case slices.Contains([]string{constants.AllKeysLFU, constants.VolatileLFU}, server.config.EvictionPolicy):
-		server.lfuCache.cache[database].Delete(key)
-	case slices.Contains([]string{constants.AllKeysLRU, constants.VolatileLRU}, server.config.EvictionPolicy):
-		server.lruCache.cache[database].Delete(key)
+	if index, ok := server.keysWithExpiry.keys[database]; ok {
+		index.Delete(key)
 	}
 
-	log.Printf("deleted key %s\n", key)
+	server.logger.Debug("deleted key", logger.DatabaseKey, database, "key", key)
 
 	return nil
 }
 
+// publishMutation records the change to the key in the change data capture feed, along with the command
+// that's being executed. It must be called while the store lock is held, so that the events are published
+// in the same order as the changes are applied.
+func (server *EchoVault) publishMutation(ctx context.Context, eventType string, key string) {
+	database, _ := ctx.Value("Database").(int)
+	command, _ := ctx.Value(internal.ContextCommand("Command")).([]string)
+	event := internal.MutationEvent{Type: eventType, Database: database, Key: key, Command: command}
+	// Writes replicated from another cluster keep their origin, so that they're not replicated back.
+	if write, ok := ctx.Value(internal.ContextWriteOrigin("WriteOrigin")).(internal.WriteOrigin); ok {
+		event.Origin, event.Timestamp = write.Source, write.Timestamp
+	}
+	server.publishEvent(event)
+}
+
+// publishEvent publishes the event to the change data capture feed and records it as the latest write of the key
+// for cross-cluster conflict resolution. It must be called while the store lock is held.
+func (server *EchoVault) publishEvent(event internal.MutationEvent) {
+	server.xrepl.Record(server.cdc.Publish(event))
+}
+
 func (server *EchoVault) createDatabase(database int) {
 	// Create database store.
 	server.store[database] = make(map[string]internal.KeyData)
@@ -297,17 +398,7 @@ func (server *EchoVault) createDatabase(database int) {
 	// Set volatile keys tracker for database.
 	server.keysWithExpiry.rwMutex.Lock()
 	defer server.keysWithExpiry.rwMutex.Unlock()
-	server.keysWithExpiry.keys[database] = make([]string, 0)
-
-	// Create database LFU cache.
-	server.lfuCache.mutex.Lock()
-	defer server.lfuCache.mutex.Unlock()
-	server.lfuCache.cache[database] = eviction.NewCacheLFU()
-
-	// Create database LRU cache.
-	server.lruCache.mutex.Lock()
-	defer server.lruCache.mutex.Unlock()
-	server.lruCache.cache[database] = eviction.NewCacheLRU()
+	server.keysWithExpiry.keys[database] = eviction.NewExpiryIndex()
 }
 
 func (server *EchoVault) getState() map[int]map[string]interface{} {
@@ -329,312 +420,145 @@ func (server *EchoVault) getState() map[int]map[string]interface{} {
 	return data
 }
 
-// updateKeysInCache updates either the key access count or the most recent access time in the cache
-// depending on whether an LFU or LRU strategy was used.
-func (server *EchoVault) updateKeysInCache(ctx context.Context, keys []string) error {
-	database := ctx.Value("Database").(int)
-
-	for _, key := range keys {
-		// Only update cache when in standalone mode or when raft leader.
-		if server.isInCluster() || (server.isInCluster() && !server.raft.IsRaftLeader()) {
-			return nil
-		}
-		// If max memory is 0, there's no max so no need to update caches.
-		if server.config.MaxMemory == 0 {
-			return nil
-		}
-		switch strings.ToLower(server.config.EvictionPolicy) {
-		case constants.AllKeysLFU:
-			server.lfuCache.mutex.Lock()
-			server.lfuCache.cache[database].Update(key)
-			server.lfuCache.mutex.Unlock()
-		case constants.AllKeysLRU:
-			server.lruCache.mutex.Lock()
-			server.lruCache.cache[database].Update(key)
-			server.lruCache.mutex.Unlock()
-		case constants.VolatileLFU:
-			server.lfuCache.mutex.Lock()
-			if server.store[database][key].ExpireAt != (time.Time{}) {
-				server.lfuCache.cache[database].Update(key)
-			}
-			server.lfuCache.mutex.Unlock()
-		case constants.VolatileLRU:
-			server.lruCache.mutex.Lock()
-			if server.store[database][key].ExpireAt != (time.Time{}) {
-				server.lruCache.cache[database].Update(key)
-			}
-			server.lruCache.mutex.Unlock()
-		}
-	}
-
-	server.storeLock.Lock()
-	defer server.storeLock.Unlock()
-
-	wg := sync.WaitGroup{}
-	errChan := make(chan error)
-	doneChan := make(chan struct{})
-
-	for db, _ := range server.store {
-		wg.Add(1)
-		ctx := context.WithValue(ctx, "Database", db)
-		go func(ctx context.Context, database int, wg *sync.WaitGroup, errChan *chan error) {
-			if err := server.adjustMemoryUsage(ctx); err != nil {
-				*errChan <- fmt.Errorf("adjustMemoryUsade database %d", database)
-			}
-			wg.Done()
-		}(ctx, db, &wg, &errChan)
-	}
-
-	go func() {
-		wg.Wait()
-		doneChan <- struct{}{}
-	}()
-
-	select {
-	case err := <-errChan:
-		return fmt.Errorf("adjustMemoryUsage error: %+v", err)
-	case <-doneChan:
-	}
-
-	return nil
-}
-
-// adjustMemoryUsage should only be called from standalone echovault or from raft cluster leader.
+// adjustMemoryUsage evicts keys, following the eviction policy, until the estimated size of the dataset is below
+// max memory or there are no keys left to evict.
+// adjustMemoryUsage only evicts keys from standalone echovault or from the raft cluster leader.
 func (server *EchoVault) adjustMemoryUsage(ctx context.Context) error {
-	// If max memory is 0, there's no need to adjust memory usage.
-	if server.config.MaxMemory == 0 {
+	if server.isInCluster() && !server.raft.IsRaftLeader() {
 		return nil
 	}
 
-	database := ctx.Value("Database").(int)
+	ctx = context.WithValue(ctx, internal.ContextDeleteReason("Reason"), cdc.EventEvict)
 
-	// Check if memory usage is above max-memory.
-	// If it is, pop items from the cache until we get under the limit.
-	var memStats runtime.MemStats
-	runtime.ReadMemStats(&memStats)
-	// If we're using less memory than the max-memory, there's no need to evict.
-	if memStats.HeapInuse < server.config.MaxMemory {
-		return nil
-	}
-	// Force a garbage collection first before we start evicting keys.
-	runtime.GC()
-	runtime.ReadMemStats(&memStats)
-	if memStats.HeapInuse < server.config.MaxMemory {
-		return nil
-	}
-	// We've done a GC, but we're still at or above the max memory limit.
-	// Start a loop that evicts keys until either the heap is empty or
-	// we're below the max memory limit.
-	switch {
-	case slices.Contains([]string{constants.AllKeysLFU, constants.VolatileLFU}, strings.ToLower(server.config.EvictionPolicy)):
-		// Remove keys from LFU cache until we're below the max memory limit or
-		// until the LFU cache is empty.
-		server.lfuCache.mutex.Lock()
-		defer server.lfuCache.mutex.Unlock()
-		for {
-			// Return if cache is empty
-			if server.lfuCache.cache[database].Len() == 0 {
-				return fmt.Errorf("adjustMemoryUsage -> LFU cache empty")
-			}
+	start := time.Now()
+	defer func() {
+		server.latency.Observe(latency.EventEvictionCycle, server.clock.Now(), time.Since(start))
+	}()
 
-			key := heap.Pop(server.lfuCache.cache[database]).(string)
-			if !server.isInCluster() {
-				// If in standalone mode, directly delete the key
-				if err := server.deleteKey(ctx, key); err != nil {
-					return fmt.Errorf("adjustMemoryUsage -> LFU cache eviction: %+v", err)
-				}
-			} else if server.isInCluster() && server.raft.IsRaftLeader() {
-				// If in raft cluster, send command to delete key from cluster
-				if err := server.raftApplyDeleteKey(ctx, key); err != nil {
-					return fmt.Errorf("adjustMemoryUsage -> LFU cache eviction: %+v", err)
-				}
-			}
-			// Run garbage collection
-			runtime.GC()
-			// Return if we're below max memory
-			runtime.ReadMemStats(&memStats)
-			if memStats.HeapInuse < server.config.MaxMemory {
-				return nil
-			}
+	for {
+		server.storeLock.Lock()
+		// If we're using less memory than the max-memory, there's no need to evict.
+		if !server.isMaxMemoryExceeded() {
+			server.storeLock.Unlock()
+			return nil
 		}
-	case slices.Contains([]string{constants.AllKeysLRU, constants.VolatileLRU}, strings.ToLower(server.config.EvictionPolicy)):
-		// Remove keys from th LRU cache until we're below the max memory limit or
-		// until the LRU cache is empty.
-		server.lruCache.mutex.Lock()
-		defer server.lruCache.mutex.Unlock()
-		for {
-			// Return if cache is empty
-			if server.lruCache.cache[database].Len() == 0 {
-				return fmt.Errorf("adjustMemoryUsage -> LRU cache empty")
-			}
-
-			key := heap.Pop(server.lruCache.cache[database]).(string)
-			if !server.isInCluster() {
-				// If in standalone mode, directly delete the key.
-				if err := server.deleteKey(ctx, key); err != nil {
-					return fmt.Errorf("adjustMemoryUsage -> LRU cache eviction: %+v", err)
-				}
-			} else if server.isInCluster() && server.raft.IsRaftLeader() {
-				// If in cluster mode and the node is a cluster leader,
-				// send command to delete the key from the cluster.
-				if err := server.raftApplyDeleteKey(ctx, key); err != nil {
-					return fmt.Errorf("adjustMemoryUsage -> LRU cache eviction: %+v", err)
-				}
-			}
-
-			// Run garbage collection
-			runtime.GC()
-			// Return if we're below max memory
-			runtime.ReadMemStats(&memStats)
-			if memStats.HeapInuse < server.config.MaxMemory {
-				return nil
-			}
+		database, key, ok := server.nextEvictionCandidate()
+		if !ok {
+			server.storeLock.Unlock()
+			return errors.New("adjustMemoryUsage error: no keys left to evict")
 		}
-	case slices.Contains([]string{constants.AllKeysRandom}, strings.ToLower(server.config.EvictionPolicy)):
-		// Remove random keys until we're below the max memory limit
-		// or there are no more keys remaining.
-		for {
-			// If there are no keys, return error
-			if len(server.store) == 0 {
-				err := errors.New("no keys to evict")
-				return fmt.Errorf("adjustMemoryUsage -> all keys random: %+v", err)
-			}
-			// Get random key in the database
-			idx := rand.Intn(len(server.store))
-			for db, data := range server.store {
-				if db == database {
-					for key, _ := range data {
-						if idx == 0 {
-							if !server.isInCluster() {
-								// If in standalone mode, directly delete the key
-								if err := server.deleteKey(ctx, key); err != nil {
-									return fmt.Errorf("adjustMemoryUsage -> all keys random: %+v", err)
-								}
-							} else if server.isInCluster() && server.raft.IsRaftLeader() {
-								if err := server.raftApplyDeleteKey(ctx, key); err != nil {
-									return fmt.Errorf("adjustMemoryUsage -> all keys random: %+v", err)
-								}
-							}
-							// Run garbage collection
-							runtime.GC()
-							// Return if we're below max memory
-							runtime.ReadMemStats(&memStats)
-							if memStats.HeapInuse < server.config.MaxMemory {
-								return nil
-							}
-						}
-						idx--
-					}
-				}
+		ctx := context.WithValue(ctx, "Database", database)
+		if !server.isInCluster() {
+			// If in standalone mode, directly delete the key.
+			err := server.deleteKey(ctx, key)
+			server.storeLock.Unlock()
+			if err != nil {
+				return fmt.Errorf("adjustMemoryUsage -> %s eviction: %+v", server.getConfig().EvictionPolicy, err)
 			}
+			continue
 		}
-	case slices.Contains([]string{constants.VolatileRandom}, strings.ToLower(server.config.EvictionPolicy)):
-		// Remove random keys with an associated expiry time until we're below the max memory limit
-		// or there are no more keys with expiry time.
-		for {
-			// Get random volatile key
-			server.keysWithExpiry.rwMutex.RLock()
-			idx := rand.Intn(len(server.keysWithExpiry.keys))
-			key := server.keysWithExpiry.keys[database][idx]
-			server.keysWithExpiry.rwMutex.RUnlock()
-
-			if !server.isInCluster() {
-				// If in standalone mode, directly delete the key
-				if err := server.deleteKey(ctx, key); err != nil {
-					return fmt.Errorf("adjustMemoryUsage -> volatile keys random: %+v", err)
-				}
-			} else if server.isInCluster() && server.raft.IsRaftLeader() {
-				if err := server.raftApplyDeleteKey(ctx, key); err != nil {
-					return fmt.Errorf("adjustMemoryUsage -> volatile keys randome: %+v", err)
-				}
-			}
-
-			// Run garbage collection
-			runtime.GC()
-			// Return if we're below max memory
-			runtime.ReadMemStats(&memStats)
-			if memStats.HeapInuse < server.config.MaxMemory {
-				return nil
-			}
+		server.storeLock.Unlock()
+		// If in cluster mode, send command to delete the key from the cluster.
+		// Raft locks the keyspace on every node to apply the deletion.
+		if err := server.raftApplyDeleteKey(ctx, key); err != nil {
+			return fmt.Errorf("adjustMemoryUsage -> %s eviction: %+v", server.getConfig().EvictionPolicy, err)
 		}
-	default:
-		return nil
 	}
 }
 
-// evictKeysWithExpiredTTL is a function that samples keys with an associated TTL
-// and evicts keys that are currently expired.
-// This function will sample 20 keys from the list of keys with an associated TTL,
-// if the key is expired, it will be evicted.
+// evictKeysWithExpiredTTL runs an active expiry cycle that deletes the expired keys of the database.
+//
+// The cycle follows the adaptive algorithm of Redis. It takes a sample of up to EvictionSample keys that have expired
+// from the expiry index, which holds the volatile keys ordered by expiry time, and deletes them. When the whole
+// sample has expired, there are probably more expired keys, so it samples again. The cycle stops when a sample
+// contains fewer expired keys than the sample size, or when it has run for 25% of the eviction interval, so that
+// a large number of keys expiring at the same time does not block the keyspace.
 // This function is only executed in standalone mode or by the raft cluster leader.
 func (server *EchoVault) evictKeysWithExpiredTTL(ctx context.Context) error {
 	// Only execute this if we're in standalone mode, or raft cluster leader.
 	if server.isInCluster() && !server.raft.IsRaftLeader() {
 		return nil
 	}
-
-	server.keysWithExpiry.rwMutex.RLock()
+	// Keep the dataset unchanged while the clients are paused.
+	if server.clientsPaused() {
+		return nil
+	}
 
 	database := ctx.Value("Database").(int)
+	ctx = context.WithValue(ctx, internal.ContextDeleteReason("Reason"), cdc.EventExpire)
 
-	// Sample size should be the configured sample size, or the size of the keys with expiry,
-	// whichever one is smaller.
-	sampleSize := int(server.config.EvictionSample)
-	if len(server.keysWithExpiry.keys[database]) < sampleSize {
-		sampleSize = len(server.keysWithExpiry.keys)
-	}
-	keys := make([]string, sampleSize)
-
-	deletedCount := 0
-	thresholdPercentage := 20
-
-	var idx int
-	var key string
-	for i := 0; i < len(keys); i++ {
-		for {
-			// Retry retrieval of a random key until we find a key that is not already in the list of sampled keys.
-			idx = rand.Intn(len(server.keysWithExpiry.keys))
-			key = server.keysWithExpiry.keys[database][idx]
-			if !slices.Contains(keys, key) {
-				keys[i] = key
-				break
-			}
-		}
+	sampleSize := int(server.getConfig().EvictionSample)
+	if sampleSize <= 0 {
+		sampleSize = 20
 	}
-	server.keysWithExpiry.rwMutex.RUnlock()
+	timeLimit := server.config.EvictionInterval / 4
+
+	start := time.Now()
+	expired := 0
+	timeCapReached := false
+	defer func() {
+		server.expiryStats.mutex.Lock()
+		defer server.expiryStats.mutex.Unlock()
+		server.expiryStats.cycles += 1
+		server.expiryStats.lastCycleExpired = uint64(expired)
+		server.expiryStats.cycleTime += time.Since(start)
+		server.latency.Observe(latency.EventExpireCycle, server.clock.Now(), time.Since(start))
+		if timeCapReached {
+			server.expiryStats.timeCapReached += 1
+		}
+		if expired > 0 {
+			server.logger.Debug("deleted expired keys", logger.DatabaseKey, database, "count", expired,
+				"time_cap_reached", timeCapReached)
+		}
+	}()
 
-	// Loop through the keys and delete them if they're expired
-	server.storeLock.Lock()
-	defer server.storeLock.Unlock()
-	for _, k := range keys {
-		// Delete the expired key
-		deletedCount += 1
+	for {
+		var keys []string
 		if !server.isInCluster() {
-			if err := server.deleteKey(ctx, k); err != nil {
-				return fmt.Errorf("evictKeysWithExpiredTTL -> standalone delete: %+v", err)
+			// In standalone mode, delete the expired keys while the keyspace is locked,
+			// so that a key can't be given a new expiry time between the sample and the deletion.
+			server.storeLock.Lock()
+			server.keysWithExpiry.rwMutex.Lock()
+			keys = server.keysWithExpiry.keys[database].PopExpired(server.clock.Now(), sampleSize)
+			server.keysWithExpiry.rwMutex.Unlock()
+			for _, key := range keys {
+				if err := server.deleteKey(ctx, key); err != nil {
+					server.storeLock.Unlock()
+					return fmt.Errorf("evictKeysWithExpiredTTL -> standalone delete: %+v", err)
+				}
 			}
-		} else if server.isInCluster() && server.raft.IsRaftLeader() {
-			if err := server.raftApplyDeleteKey(ctx, k); err != nil {
-				return fmt.Errorf("evictKeysWithExpiredTTL -> cluster delete: %+v", err)
+			server.storeLock.Unlock()
+		} else {
+			// In cluster mode, the deletion is applied through raft, which locks the keyspace on every node.
+			server.keysWithExpiry.rwMutex.Lock()
+			keys = server.keysWithExpiry.keys[database].PopExpired(server.clock.Now(), sampleSize)
+			server.keysWithExpiry.rwMutex.Unlock()
+			for i, key := range keys {
+				if err := server.raftApplyDeleteKey(ctx, key); err != nil {
+					// Put the keys that were not deleted back in the index so that they're sampled again.
+					for _, k := range keys[i:] {
+						if expireAt := server.getExpiry(ctx, k); !expireAt.IsZero() {
+							server.keysWithExpiry.rwMutex.Lock()
+							server.keysWithExpiry.keys[database].Set(k, expireAt)
+							server.keysWithExpiry.rwMutex.Unlock()
+						}
+					}
+					return fmt.Errorf("evictKeysWithExpiredTTL -> cluster delete: %+v", err)
+				}
 			}
 		}
-	}
-
-	// If sampleSize is 0, there's no need to calculate deleted percentage.
-	if sampleSize == 0 {
-		return nil
-	}
 
-	log.Printf("%d keys sampled, %d keys deleted\n", sampleSize, deletedCount)
+		expired += len(keys)
 
-	// If the deleted percentage is over 20% of the sample size, execute the function again immediately.
-	if (deletedCount/sampleSize)*100 >= thresholdPercentage {
-		log.Printf("deletion ratio (%d percent) reached threshold (%d percent), sampling again\n",
-			(deletedCount/sampleSize)*100, thresholdPercentage)
-		return server.evictKeysWithExpiredTTL(ctx)
+		// If fewer keys than the sample size have expired, there are no expired keys left in the index.
+		if len(keys) < sampleSize {
+			return nil
+		}
+		if timeLimit > 0 && time.Since(start) >= timeLimit {
+			timeCapReached = true
+			return nil
+		}
 	}
-
-	return nil
 }
 
 func (server *EchoVault) randomKey(ctx context.Context) string {
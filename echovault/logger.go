@@ -0,0 +1,68 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package echovault
+
+import (
+	"context"
+	"github.com/echovault/echovault/internal"
+	"github.com/echovault/echovault/internal/logger"
+	"log/slog"
+	"strings"
+)
+
+// setupLogger creates the logger from the LogLevel, LogFormat and LogOutput config. A logger passed with
+// WithLogger is wrapped instead, so that the LogLevel config and CONFIG SET log-level also apply to it.
+func (server *EchoVault) setupLogger() error {
+	server.logLevel = new(slog.LevelVar)
+	if server.config.LogLevel != "" {
+		level, err := logger.ParseLevel(server.config.LogLevel)
+		if err != nil {
+			return err
+		}
+		server.logLevel.Set(level)
+	}
+
+	if server.logger != nil {
+		server.logger = logger.Leveled(server.logger, server.logLevel)
+		return nil
+	}
+
+	w, closer, err := logger.Open(server.config.LogOutput)
+	if err != nil {
+		return err
+	}
+	l, err := logger.New(w, server.config.LogFormat, server.logLevel)
+	if err != nil {
+		if closer != nil {
+			_ = closer.Close()
+		}
+		return err
+	}
+	server.logger, server.logFile = l, closer
+	return nil
+}
+
+// commandLogger returns the logger with the connection ID, database and command attached to every entry.
+// The connection ID is omitted for the commands of the embedded instance.
+func (server *EchoVault) commandLogger(ctx context.Context, cmd []string) logger.Logger {
+	args := []any{logger.DatabaseKey, ctx.Value("Database")}
+	if connID, ok := ctx.Value(internal.ContextConnID("ConnectionID")).(string); ok {
+		args = append(args, logger.ConnIDKey, connID)
+	}
+	if len(cmd) > 0 {
+		args = append(args, logger.CommandKey, strings.ToLower(cmd[0]))
+	}
+	return server.logger.With(args...)
+}
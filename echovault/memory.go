@@ -0,0 +1,87 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package echovault
+
+import (
+	"context"
+	"github.com/echovault/echovault/internal"
+	"runtime"
+)
+
+// updateMemoryUsage replaces the size of a key in the dataset's memory usage with its new size.
+// Pass 0 as the old size for new keys, and 0 as the new size for deleted keys.
+// It must be called while the store lock is held.
+func (server *EchoVault) updateMemoryUsage(database int, oldSize int64, newSize int64) {
+	server.memory.used += newSize - oldSize
+	server.memory.databases[database] += newSize - oldSize
+	if server.memory.used > server.memory.peak {
+		server.memory.peak = server.memory.used
+	}
+}
+
+// isMaxMemoryExceeded returns true when the dataset uses at least the configured max memory.
+// It must be called while the store lock is held.
+func (server *EchoVault) isMaxMemoryExceeded() bool {
+	maxMemory := server.getConfig().MaxMemory
+	return maxMemory > 0 && server.memory.used >= int64(maxMemory)
+}
+
+// getMemoryUsage returns the estimated number of bytes used by the key, sampling the given number of elements
+// of hashes and lists. It returns false when the key does not exist.
+func (server *EchoVault) getMemoryUsage(ctx context.Context, key string, samples int) (int64, bool) {
+	server.storeLock.RLock()
+	defer server.storeLock.RUnlock()
+
+	database := ctx.Value("Database").(int)
+
+	data, ok := server.store[database][key]
+	if !ok || (!data.ExpireAt.IsZero() && data.ExpireAt.Before(server.clock.Now())) {
+		return 0, false
+	}
+	return internal.EstimateKeySize(key, data.Value, samples), true
+}
+
+// GetMemoryStats returns the memory usage of the dataset, estimated from the size of every key.
+// The size of each key is maintained as it's written, so this does not scan the keyspace.
+func (server *EchoVault) GetMemoryStats() internal.MemoryStats {
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+
+	conf := server.getConfig()
+
+	server.storeLock.RLock()
+	defer server.storeLock.RUnlock()
+
+	stats := internal.MemoryStats{
+		PeakDataset:    server.memory.peak,
+		Dataset:        server.memory.used,
+		MaxMemory:      conf.MaxMemory,
+		EvictionPolicy: conf.EvictionPolicy,
+		EvictedKeys:    server.memory.evictedKeys,
+		HeapAlloc:      memStats.HeapAlloc,
+		Databases:      make(map[int]internal.DatabaseMemory),
+	}
+	for database, store := range server.store {
+		if len(store) == 0 {
+			continue
+		}
+		stats.Keys += len(store)
+		stats.Databases[database] = internal.DatabaseMemory{
+			Keys:    len(store),
+			Dataset: server.memory.databases[database],
+		}
+	}
+	return stats
+}
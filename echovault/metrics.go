@@ -0,0 +1,161 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package echovault
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"github.com/echovault/echovault/internal/logger"
+	"github.com/echovault/echovault/internal/metrics"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// startMetrics starts the HTTP listener that serves Prometheus metrics at /metrics when a metrics port is configured.
+func (server *EchoVault) startMetrics() error {
+	if server.config.MetricsPort == 0 {
+		return nil
+	}
+
+	listener, err := net.Listen("tcp", fmt.Sprintf("%s:%d", server.config.BindAddr, server.config.MetricsPort))
+	if err != nil {
+		return fmt.Errorf("metrics listener: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler(server.collectMetrics))
+	server.metricsServer = &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+
+	server.logger.Info("starting metrics server", "addr", server.config.BindAddr, "port", server.config.MetricsPort)
+	go func() {
+		if err := server.metricsServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			server.logger.Error("metrics server", logger.ErrorKey, err)
+		}
+	}()
+
+	return nil
+}
+
+// collectMetrics adds the metrics of the server to the exposition.
+func (server *EchoVault) collectMetrics(e *metrics.Exposition) {
+	info := server.Info()
+
+	e.Gauge("echovault_uptime_seconds", "The number of seconds since the server started.",
+		info.Server.Uptime.Seconds())
+
+	// Clients and commands.
+	e.Gauge("echovault_connected_clients", "The number of open client connections.",
+		float64(info.Clients.ConnectedClients))
+	e.Counter("echovault_connections_received_total", "The number of client connections accepted.",
+		float64(info.Stats.TotalConnections))
+	for _, name := range sortedKeys(info.CommandStats) {
+		s, ok := server.stats.commands.Load(name)
+		if !ok {
+			continue
+		}
+		command := metrics.Label{Name: "command", Value: name}
+		stats := s.(*commandStats)
+		e.Counter("echovault_commands_total", "The number of calls to each command.",
+			float64(stats.calls.Load()), command)
+		e.Counter("echovault_commands_rejected_total", "The number of calls to each command rejected before execution.",
+			float64(stats.rejected.Load()), command)
+		e.Counter("echovault_commands_failed_total", "The number of calls to each command that returned an error.",
+			float64(stats.failed.Load()), command)
+		e.Histogram("echovault_command_duration_seconds", "The latency of each command.",
+			stats.latency.Snapshot(), command)
+	}
+	for _, prefix := range sortedKeys(info.ErrorStats) {
+		e.Counter("echovault_errors_total", "The number of errors returned to clients by error prefix.",
+			float64(info.ErrorStats[prefix]), metrics.Label{Name: "error", Value: prefix})
+	}
+
+	// Keyspace.
+	for _, database := range sortedKeys(info.Keyspace) {
+		keyspace := info.Keyspace[database]
+		db := metrics.Label{Name: "db", Value: strconv.Itoa(database)}
+		e.Gauge("echovault_keys", "The number of keys in each database.", float64(keyspace.Keys), db)
+		e.Gauge("echovault_expiring_keys", "The number of keys with an expiry time in each database.",
+			float64(keyspace.Expires), db)
+	}
+	e.Counter("echovault_keyspace_hits_total", "The number of successful lookups of keys.",
+		float64(info.Stats.KeyspaceHits))
+	e.Counter("echovault_keyspace_misses_total", "The number of failed lookups of keys.",
+		float64(info.Stats.KeyspaceMisses))
+	e.Counter("echovault_expired_keys_total", "The number of keys deleted because they expired.",
+		float64(info.Stats.ExpiredKeys))
+	e.Counter("echovault_evicted_keys_total", "The number of keys evicted because the dataset reached max memory.",
+		float64(info.Stats.EvictedKeys))
+
+	// Memory.
+	e.Gauge("echovault_memory_dataset_bytes", "The estimated number of bytes used by the dataset.",
+		float64(info.Memory.Dataset))
+	e.Gauge("echovault_memory_max_bytes", "The configured maximum number of bytes used by the dataset.",
+		float64(info.Memory.MaxMemory))
+	e.Gauge("echovault_memory_heap_bytes", "The number of bytes of allocated heap objects.",
+		float64(info.Memory.HeapAlloc))
+
+	// Persistence.
+	e.Gauge("echovault_last_snapshot_timestamp_seconds", "The unix time of the latest snapshot.",
+		float64(info.Persistence.LastSnapshot)/1000)
+	if server.snapshotEngine != nil {
+		e.Histogram("echovault_snapshot_duration_seconds", "The time taken by snapshots.",
+			server.snapshotEngine.Duration())
+	}
+	if info.Persistence.AOFEnabled {
+		e.Histogram("echovault_aof_fsync_duration_seconds", "The latency of the fsyncs of the append-only log.",
+			server.aofEngine.FsyncLatency())
+	}
+
+	// Raft.
+	if server.isInCluster() {
+		for _, state := range []string{"follower", "candidate", "leader", "shutdown"} {
+			e.Gauge("echovault_raft_state", "Whether the raft state of the server is the labelled state.",
+				boolToFloat(info.Cluster.RaftState == state), metrics.Label{Name: "state", Value: state})
+		}
+		e.Gauge("echovault_raft_commit_index", "The index of the latest committed raft log entry.",
+			float64(server.raft.CommitIndex()))
+		e.Gauge("echovault_raft_applied_index", "The index of the latest raft log entry applied to the state.",
+			float64(server.raft.AppliedIndex()))
+		e.Histogram("echovault_snapshot_duration_seconds", "The time taken by snapshots.",
+			server.raft.SnapshotDuration())
+	}
+
+	// Pub/Sub.
+	e.Gauge("echovault_pubsub_channels", "The number of channels with at least one subscriber.",
+		float64(server.pubSub.NumChannels()))
+	e.Gauge("echovault_pubsub_patterns", "The number of patterns with at least one subscriber.",
+		float64(server.pubSub.NumPat()))
+}
+
+// sortedKeys returns the keys of the map in ascending order so that the samples of a metric are in a stable order.
+func sortedKeys[K cmp.Ordered, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -21,9 +21,14 @@ import (
 	"github.com/echovault/echovault/internal"
 	"github.com/echovault/echovault/internal/clock"
 	"github.com/echovault/echovault/internal/constants"
+	"github.com/echovault/echovault/internal/latency"
+	"github.com/echovault/echovault/internal/logger"
+	"github.com/echovault/echovault/internal/monitor"
 	"io"
 	"net"
+	"slices"
 	"strings"
+	"time"
 )
 
 func (server *EchoVault) getCommand(cmd string) (internal.Command, error) {
@@ -46,6 +51,7 @@ func (server *EchoVault) getHandlerFuncParams(ctx context.Context, cmd []string,
 		GetExpiry:             server.getExpiry,
 		GetValues:             server.getValues,
 		SetValues:             server.setValues,
+		UpdateValues:          server.updateValues,
 		SetExpiry:             server.setExpiry,
 		TakeSnapshot:          server.takeSnapshot,
 		GetLatestSnapshotTime: server.getLatestSnapshotTime,
@@ -54,23 +60,62 @@ func (server *EchoVault) getHandlerFuncParams(ctx context.Context, cmd []string,
 		UnloadModule:          server.UnloadModule,
 		ListModules:           server.ListModules,
 		GetPubSub:             server.getPubSub,
-		GetACL:                server.getACL,
-		GetAllCommands:        server.getCommands,
-		GetClock:              server.getClock,
-		Flush:                 server.Flush,
-        Randomkey:             server.randomKey,
-		SwapDBs:               server.SwapDBs,
-		GetServerInfo:         server.GetServerInfo,
+		GetTracking:           server.getTracking,
+		GetConfig:             server.getConfigInterface,
+		SetConfig:             server.setConfig,
+		RewriteConfig:         server.rewriteConfig,
+		ResetStats:            server.resetStats,
+		GetReplication:        server.getReplication,
+		GetReplicationInfo:    server.GetReplicationInfo,
+		GetCDC:                server.getCDC,
+		GetXRepl:              server.getXRepl,
+		GetClusterInfo:        server.GetClusterInfo,
+		GetExpiryInfo:         server.GetExpiryInfo,
+		GetInfo:               server.Info,
+		GetSlowlog:            server.slowlog.Get,
+		GetSlowlogLen:         server.slowlog.Len,
+		ResetSlowlog:          server.slowlog.Reset,
+		GetLatestLatency:      server.latency.Latest,
+		GetLatencyHistory:     server.latency.History,
+		ResetLatency: func(events []string) int {
+			return server.latency.Reset(events...)
+		},
+		GetLatencyHistogram: server.getLatencyHistogram,
+		SubscribeMonitor:    server.monitor.Subscribe,
+		GetMemoryUsage:      server.getMemoryUsage,
+		GetMemoryStats:      server.GetMemoryStats,
+		GetObjectFreq:       server.getObjectFreq,
+		GetObjectIdleTime:   server.getObjectIdleTime,
+		PinKeys:             server.pinKeys,
+		UnpinKeys:           server.unpinKeys,
+		GetPinnedKeys:       server.getPinnedKeys,
+		ForgetNode:          server.forgetNode,
+		DecommissionNode:    server.decommission,
+		Wait:                server.wait,
+		WaitAOF:             server.waitAOF,
+		GetACL:              server.getACL,
+		GetAllCommands:      server.getCommands,
+		GetClock:            server.getClock,
+		Flush:               server.Flush,
+		Randomkey:           server.randomKey,
+		SwapDBs:             server.SwapDBs,
+		GetServerInfo:       server.GetServerInfo,
 		DeleteKey: func(ctx context.Context, key string) error {
 			server.storeLock.Lock()
 			defer server.storeLock.Unlock()
 			return server.deleteKey(ctx, key)
 		},
-		GetConnectionInfo: func(conn *net.Conn) internal.ConnectionInfo {
-			server.connInfo.mut.RLock()
-			defer server.connInfo.mut.RUnlock()
-			return server.connInfo.tcpClients[conn]
+		GetLogger: func() logger.Logger {
+			return server.commandLogger(ctx, cmd)
+		},
+		GetConnectionInfo:    server.connectionInfo,
+		UpdateConnectionInfo: server.updateConnectionInfo,
+		ListConnections:      server.listConnections,
+		KillConnections: func(ids []uint64) int {
+			return server.killConnections(conn, ids)
 		},
+		PauseClients:   server.pauseClients,
+		UnpauseClients: server.unpauseClients,
 		SetConnectionInfo: func(conn *net.Conn, clientname string, protocol int, database int) {
 			server.connInfo.mut.Lock()
 			defer server.connInfo.mut.Unlock()
@@ -94,31 +139,80 @@ func (server *EchoVault) getHandlerFuncParams(ctx context.Context, cmd []string,
 
 			// Set database index for the current connection.
 			info.Database = database
+			if handle, ok := server.connInfo.handles[conn]; ok {
+				handle.database.Store(int64(database))
+			}
 
 			server.connInfo.tcpClients[conn] = info
 		},
 	}
 }
 
-func (server *EchoVault) handleCommand(ctx context.Context, message []byte, conn *net.Conn, replay bool, embedded bool) ([]byte, error) {
+func (server *EchoVault) handleCommand(ctx context.Context, message []byte, conn *net.Conn, replay bool, embedded bool) (res []byte, err error) {
+	// Record the call and its outcome in the command and error statistics.
+	start := time.Now()
+	var cmd []string
+	var commandName string
+	var statsName string // The command name, or "command|subcommand" for subcommands.
+	var executed bool
+	var untrack func() // Forgets the keys recorded for CLIENT TRACKING when the command fails.
+	defer func() {
+		if err != nil && untrack != nil {
+			untrack()
+		}
+		if commandName != "" {
+			duration := time.Since(start)
+			// Calls that fail before the handler runs, or because of their arguments, are rejected rather than failed.
+			if err != nil && (!executed || strings.HasPrefix(err.Error(), constants.WrongArgsResponse)) {
+				server.recordRejectedCommand(statsName)
+			} else {
+				server.recordCommand(statsName, duration, err != nil)
+			}
+			server.latency.Observe(latency.EventCommand, server.clock.Now(), duration)
+			// Only prepare the slow log entry, and redact the command, when the command is logged.
+			if server.slowlog.Exceeds(duration) {
+				clientAddr, clientName := "", ""
+				if conn != nil {
+					clientAddr = (*conn).RemoteAddr().String()
+				}
+				if name, ok := ctx.Value("ConnectionName").(string); ok {
+					clientName = name
+				}
+				server.slowlog.Record(server.clock.Now(), duration, monitor.Redact(cmd), clientAddr, clientName)
+			}
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			server.recordError(err)
+		}
+	}()
+
 	// Prepare context before processing the command.
+	var handle *connHandle
 	server.connInfo.mut.RLock()
-	if embedded && !replay {
+	switch {
+	case replay:
+		// Replayed commands (from the AOF or a replication stream) carry their own protocol and database
+		// in the context.
+	case embedded:
 		// The call is triggered via the embedded API.
 		// Add embedded connection info to the context of the request.
 		ctx = context.WithValue(ctx, "ConnectionName", server.connInfo.embedded.Name)
 		ctx = context.WithValue(ctx, "Protocol", server.connInfo.embedded.Protocol)
 		ctx = context.WithValue(ctx, "Database", server.connInfo.embedded.Database)
-	} else {
+	default:
 		// The call is triggered by a TCP connection.
 		// Add TCP connection info to the context of the request.
 		ctx = context.WithValue(ctx, "ConnectionName", server.connInfo.tcpClients[conn].Name)
 		ctx = context.WithValue(ctx, "Protocol", server.connInfo.tcpClients[conn].Protocol)
-		ctx = context.WithValue(ctx, "Database", server.connInfo.tcpClients[conn].Database)
+		database := server.connInfo.tcpClients[conn].Database
+		if handle = server.connInfo.handles[conn]; handle != nil {
+			database = int(handle.database.Load())
+		}
+		ctx = context.WithValue(ctx, "Database", database)
 	}
 	server.connInfo.mut.RUnlock()
 
-	cmd, err := internal.Decode(message)
+	cmd, err = internal.Decode(message)
 	if err != nil {
 		return nil, err
 	}
@@ -127,6 +221,8 @@ func (server *EchoVault) handleCommand(ctx context.Context, message []byte, conn
 		return nil, errors.New("empty command")
 	}
 
+	ctx = context.WithValue(ctx, internal.ContextCommand("Command"), cmd)
+
 	// If quit command is passed, EOF error.
 	if strings.EqualFold(cmd[0], "quit") {
 		return nil, io.EOF
@@ -136,6 +232,25 @@ func (server *EchoVault) handleCommand(ctx context.Context, message []byte, conn
 	if err != nil {
 		return nil, err
 	}
+	commandName = strings.ToLower(command.Command)
+	statsName = commandName
+
+	// Stream the command to the connections running MONITOR.
+	if server.monitor.Active() {
+		source, clientAddr := monitor.SourceTCP, ""
+		switch {
+		case replay:
+			source = monitor.SourceAOF
+			if s, ok := ctx.Value(internal.ContextSource("Source")).(string); ok {
+				source = s
+			}
+		case embedded:
+			source = monitor.SourceEmbedded
+		case conn != nil:
+			clientAddr = (*conn).RemoteAddr().String()
+		}
+		server.feedMonitor(ctx, source, clientAddr, cmd)
+	}
 
 	synchronize := command.Sync
 	handler := command.HandlerFunc
@@ -148,6 +263,12 @@ func (server *EchoVault) handleCommand(ctx context.Context, message []byte, conn
 	if ok {
 		synchronize = subCommand.Sync
 		handler = subCommand.HandlerFunc
+		statsName = commandName + "|" + strings.ToLower(subCommand.Command)
+	}
+
+	if handle != nil {
+		// Record the command in the connection information reported by CLIENT LIST.
+		handle.recordCommand(server.clock.Now(), statsName, len(message))
 	}
 
 	if conn != nil && server.acl != nil && !embedded {
@@ -158,6 +279,33 @@ func (server *EchoVault) handleCommand(ctx context.Context, message []byte, conn
 		}
 	}
 
+	if conn != nil && !replay && !embedded {
+		// Record the keys read by the command for CLIENT TRACKING once it's authorized.
+		if id, keys := server.trackKeys(conn, cmd, command, subCommand); len(keys) > 0 {
+			untrack = func() {
+				server.tracking.Untrack(id, keys)
+			}
+		}
+	}
+
+	// Suspend the command while CLIENT PAUSE is in effect. The CLIENT command is never suspended, so that
+	// the pause can be lifted with CLIENT UNPAUSE, and neither are the acknowledgements sent by the replicas.
+	if !replay && !slices.Contains([]string{"client", "replconf"}, commandName) {
+		if err = server.waitForUnpause(ctx, internal.IsWriteCommand(command, subCommand)); err != nil {
+			return nil, err
+		}
+	}
+
+	// Replicas only accept writes from their primary.
+	if internal.IsWriteCommand(command, subCommand) && !replay && server.replication.IsReplica() {
+		return nil, errors.New("READONLY You can't write against a read only replica.")
+	}
+
+	// A node that left the cluster no longer takes part in raft, so it can't apply the command.
+	if synchronize && server.hasLeftCluster() {
+		return nil, errors.New("node has left the cluster")
+	}
+
 	// If the command is a write command, wait for state copy to finish.
 	if internal.IsWriteCommand(command, subCommand) {
 		for {
@@ -169,15 +317,26 @@ func (server *EchoVault) handleCommand(ctx context.Context, message []byte, conn
 	}
 
 	if !server.isInCluster() || !synchronize {
+		executed = true
+		// In cluster mode, reads wait for the batch being applied from the raft log, so that they never see
+		// the batch half-applied.
+		readLocked := server.isInCluster() && internal.IsReadCommand(command, subCommand)
+		if readLocked {
+			server.applyLock.RLock()
+		}
 		res, err := handler(server.getHandlerFuncParams(ctx, cmd, conn))
+		if readLocked {
+			server.applyLock.RUnlock()
+		}
 		if err != nil {
+			server.stateMutationInProgress.Store(false)
 			return nil, err
 		}
 
 		if internal.IsWriteCommand(command, subCommand) && !replay {
-			server.connInfo.mut.RLock()
-			server.aofEngine.LogCommand(server.connInfo.tcpClients[conn].Database, message)
-			server.connInfo.mut.RUnlock()
+			database := ctx.Value("Database").(int)
+			server.aofEngine.LogCommand(database, message)
+			server.replication.Feed(database, internal.EncodeCommand(cmd))
 		}
 
 		server.stateMutationInProgress.Store(false)
@@ -188,10 +347,18 @@ func (server *EchoVault) handleCommand(ctx context.Context, message []byte, conn
 	// Handle other commands that need to be synced across the cluster
 	if server.raft.IsRaftLeader() {
 		var res []byte
+		executed = true
 		res, err = server.raftApplyCommand(ctx, cmd)
 		if err != nil {
 			return nil, err
 		}
+		// ACL LOAD reads the users from each node's own config file.
+		// Replicate the leader's resulting ACL so that all the nodes converge on the same users.
+		if command.Module == constants.ACLModule && strings.EqualFold(subCommand.Command, "load") {
+			if err = server.raftApplyACLState(ctx); err != nil {
+				return nil, err
+			}
+		}
 		return res, err
 	}
 
@@ -204,6 +371,23 @@ func (server *EchoVault) handleCommand(ctx context.Context, message []byte, conn
 	return nil, errors.New("not cluster leader, cannot carry out command")
 }
 
+// feedMonitor streams the command to the connections running MONITOR.
+func (server *EchoVault) feedMonitor(ctx context.Context, source string, clientAddr string, cmd []string) {
+	if !server.monitor.Active() {
+		return
+	}
+	database, _ := ctx.Value("Database").(int)
+	clientName, _ := ctx.Value("ConnectionName").(string)
+	server.monitor.Feed(internal.MonitorEntry{
+		Timestamp:  server.clock.Now(),
+		Database:   database,
+		Source:     source,
+		ClientAddr: clientAddr,
+		ClientName: clientName,
+		Command:    cmd,
+	})
+}
+
 func (server *EchoVault) getCommands() []internal.Command {
 	return server.commands
 }
@@ -216,6 +400,18 @@ func (server *EchoVault) getPubSub() interface{} {
 	return server.pubSub
 }
 
+func (server *EchoVault) getReplication() interface{} {
+	return server.replication
+}
+
+func (server *EchoVault) getCDC() interface{} {
+	return server.cdc
+}
+
+func (server *EchoVault) getXRepl() interface{} {
+	return server.xrepl
+}
+
 func (server *EchoVault) getClock() clock.Clock {
 	return server.clock
 }
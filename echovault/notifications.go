@@ -0,0 +1,73 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package echovault
+
+import (
+	"context"
+	"github.com/echovault/echovault/internal"
+	"github.com/echovault/echovault/internal/modules/cdc"
+	"github.com/echovault/echovault/internal/modules/pubsub"
+	"github.com/echovault/echovault/internal/modules/set"
+	"github.com/echovault/echovault/internal/modules/sorted_set"
+	"strings"
+	"time"
+)
+
+// notifyWrite publishes the keyspace notification for the command that set the value of the key.
+// The event is the name of the command, and its class is the type of the value.
+func (server *EchoVault) notifyWrite(ctx context.Context, database int, key string, value interface{}) {
+	command, _ := ctx.Value(internal.ContextCommand("Command")).([]string)
+	if len(command) == 0 {
+		return
+	}
+
+	var class int
+	switch value.(type) {
+	case map[string]interface{}:
+		class = pubsub.NotifyHash
+	case []string:
+		class = pubsub.NotifyList
+	case *set.Set:
+		class = pubsub.NotifySet
+	case *sorted_set.SortedSet:
+		class = pubsub.NotifySortedSet
+	default:
+		class = pubsub.NotifyString
+	}
+
+	server.pubSub.NotifyKeyspaceEvent(ctx, class, strings.ToLower(command[0]), database, key)
+}
+
+// notifyExpiry publishes the keyspace notification for a change of the key's expiry time.
+func (server *EchoVault) notifyExpiry(ctx context.Context, database int, key string, expireAt time.Time) {
+	event := "expire"
+	if expireAt.IsZero() {
+		event = "persist"
+	}
+	server.pubSub.NotifyKeyspaceEvent(ctx, pubsub.NotifyGeneric, event, database, key)
+}
+
+// notifyDelete publishes the keyspace notification for the deletion of the key.
+// Keys deleted because they expired or were evicted have their own event classes.
+func (server *EchoVault) notifyDelete(ctx context.Context, database int, key string, reason string) {
+	switch reason {
+	case cdc.EventExpire:
+		server.pubSub.NotifyKeyspaceEvent(ctx, pubsub.NotifyExpired, "expired", database, key)
+	case cdc.EventEvict:
+		server.pubSub.NotifyKeyspaceEvent(ctx, pubsub.NotifyEvicted, "evicted", database, key)
+	default:
+		server.pubSub.NotifyKeyspaceEvent(ctx, pubsub.NotifyGeneric, "del", database, key)
+	}
+}
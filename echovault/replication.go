@@ -0,0 +1,202 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package echovault
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/echovault/echovault/internal"
+	"github.com/echovault/echovault/internal/logger"
+	"github.com/echovault/echovault/internal/modules/set"
+	"github.com/echovault/echovault/internal/modules/sorted_set"
+	"strconv"
+	"time"
+)
+
+// getReplicationSyncPayload serializes the whole keyspace into RESP commands that rebuild it on a replica
+// during a full resync. Writes are blocked while the keyspace is copied, so the returned replication offset
+// matches the payload exactly.
+func (server *EchoVault) getReplicationSyncPayload() ([]byte, int64) {
+	// Wait until there's no state mutation or copy in progress before starting a new copy process.
+	for {
+		if !server.stateCopyInProgress.Load() && !server.stateMutationInProgress.Load() {
+			server.stateCopyInProgress.Store(true)
+			break
+		}
+	}
+	defer server.stateCopyInProgress.Store(false)
+
+	offset := server.replication.SyncPoint()
+
+	server.storeLock.RLock()
+	defer server.storeLock.RUnlock()
+
+	payload := bytes.NewBuffer(internal.EncodeCommand([]string{"FLUSHALL"}))
+
+	for database, store := range server.store {
+		if len(store) == 0 {
+			continue
+		}
+		payload.Write(internal.EncodeCommand([]string{"SELECT", strconv.Itoa(database)}))
+		for key, data := range store {
+			cmd, err := restoreCommand(key, data.Value)
+			if err != nil {
+				server.logger.Error("encode key for replication sync", logger.DatabaseKey, database, "key", key,
+					logger.ErrorKey, err)
+				continue
+			}
+			if cmd == nil {
+				continue
+			}
+			payload.Write(internal.EncodeCommand(cmd))
+			if !data.ExpireAt.IsZero() {
+				payload.Write(internal.EncodeCommand([]string{
+					"PEXPIREAT", key, strconv.FormatInt(data.ExpireAt.UnixMilli(), 10),
+				}))
+			}
+		}
+	}
+
+	return payload.Bytes(), offset
+}
+
+// restoreCommand returns the command that recreates the key with the given value.
+// A nil command is returned for empty collections, as they cannot exist in the keyspace.
+func restoreCommand(key string, value interface{}) ([]string, error) {
+	switch v := value.(type) {
+	case string, int, float64:
+		return []string{"SET", key, formatValue(v)}, nil
+	case map[string]interface{}:
+		if len(v) == 0 {
+			return nil, nil
+		}
+		cmd := []string{"HSET", key}
+		for field, fieldValue := range v {
+			cmd = append(cmd, field, formatValue(fieldValue))
+		}
+		return cmd, nil
+	case []string:
+		if len(v) == 0 {
+			return nil, nil
+		}
+		return append([]string{"RPUSH", key}, v...), nil
+	case *set.Set:
+		if v.Cardinality() == 0 {
+			return nil, nil
+		}
+		return append([]string{"SADD", key}, v.GetAll()...), nil
+	case *sorted_set.SortedSet:
+		if v.Cardinality() == 0 {
+			return nil, nil
+		}
+		cmd := []string{"ZADD", key}
+		for _, member := range v.GetAll() {
+			cmd = append(cmd, strconv.FormatFloat(float64(member.Score), 'f', -1, 64), string(member.Value))
+		}
+		return cmd, nil
+	default:
+		return nil, fmt.Errorf("unsupported value type %T", value)
+	}
+}
+
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// wait blocks until the writes issued before it are acknowledged by numReplicas replicas or the timeout elapses.
+// In cluster mode, the writes are acknowledged once they're committed by the raft quorum.
+func (server *EchoVault) wait(numReplicas int, timeout time.Duration) (int, error) {
+	if server.isInCluster() {
+		if !server.raft.IsRaftLeader() {
+			return 0, errors.New("WAIT cannot be used with replica instances")
+		}
+		return server.raft.Barrier(timeout)
+	}
+
+	if server.replication.IsReplica() {
+		return 0, errors.New("WAIT cannot be used with replica instances")
+	}
+
+	return server.replication.Wait(server.replication.Offset(), numReplicas, timeout, false), nil
+}
+
+// waitAOF blocks until the writes issued before it are fsynced to the local append-only log when numLocal is 1,
+// and by numReplicas replicas, or the timeout elapses. It returns the number of local and replica fsyncs.
+// In cluster mode, the raft log is fsynced on commit, so the writes are fsynced once they're committed.
+func (server *EchoVault) waitAOF(numLocal int, numReplicas int, timeout time.Duration) (int, int, error) {
+	if server.isInCluster() {
+		if !server.raft.IsRaftLeader() {
+			return 0, 0, errors.New("WAITAOF cannot be used with replica instances")
+		}
+		if numLocal > 0 && server.config.DataDir == "" {
+			return 0, 0, errors.New("WAITAOF cannot be used when numlocal is set but appendonly is disabled")
+		}
+		replicas, err := server.raft.Barrier(timeout)
+		if err != nil {
+			return 0, 0, err
+		}
+		if server.config.DataDir == "" {
+			return 0, replicas, nil
+		}
+		return 1, replicas, nil
+	}
+
+	if server.replication.IsReplica() {
+		return 0, 0, errors.New("WAITAOF cannot be used with replica instances")
+	}
+	if numLocal > 0 && !server.aofEngine.Enabled() {
+		return 0, 0, errors.New("WAITAOF cannot be used when numlocal is set but appendonly is disabled")
+	}
+
+	aofOffset := server.aofEngine.Offset()
+	replOffset := server.replication.Offset()
+
+	// Wait for the local fsync and the replica acknowledgements concurrently so that they share the timeout.
+	localDone := make(chan int, 1)
+	go func() {
+		if !server.aofEngine.Enabled() {
+			localDone <- 0
+			return
+		}
+		if numLocal == 0 {
+			// Report the local fsync state without waiting for it.
+			if server.aofEngine.SyncedOffset() >= aofOffset {
+				localDone <- 1
+			} else {
+				localDone <- 0
+			}
+			return
+		}
+		synced, err := server.aofEngine.WaitForSync(aofOffset, timeout)
+		if err != nil {
+			server.logger.Error("wait for AOF sync", logger.ErrorKey, err)
+		}
+		if synced {
+			localDone <- 1
+		} else {
+			localDone <- 0
+		}
+	}()
+
+	replicas := server.replication.Wait(replOffset, numReplicas, timeout, true)
+
+	return <-localDone, replicas, nil
+}
@@ -0,0 +1,208 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package echovault
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/echovault/echovault/internal/config"
+	"github.com/echovault/echovault/internal/constants"
+	"github.com/echovault/echovault/internal/logger"
+	"github.com/echovault/echovault/internal/modules/pubsub"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+// getConfig returns a copy of the configuration. Use it to read the parameters that CONFIG SET can change.
+func (server *EchoVault) getConfig() config.Config {
+	server.configMut.RLock()
+	defer server.configMut.RUnlock()
+	return server.config
+}
+
+// getConfigInterface returns the configuration as the interface{} expected by the handler params.
+func (server *EchoVault) getConfigInterface() interface{} {
+	return server.getConfig()
+}
+
+// setConfig changes the values of the mutable parameters and reconfigures the engines in place.
+// Every value is validated first, so either all the parameters are changed or none of them.
+func (server *EchoVault) setConfig(values map[string]string) error {
+	server.configMut.Lock()
+	defer server.configMut.Unlock()
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	conf := server.config
+	parameters := make([]config.Parameter, 0, len(names))
+	for _, name := range names {
+		parameter, ok := config.GetParameter(name)
+		if !ok {
+			return fmt.Errorf("unsupported CONFIG parameter %s", name)
+		}
+		if !parameter.Mutable {
+			return fmt.Errorf("CONFIG parameter %s can't be changed while the server is running", parameter.Name)
+		}
+		if err := parameter.Set(&conf, values[name]); err != nil {
+			return err
+		}
+		parameters = append(parameters, parameter)
+	}
+	if err := config.Validate(conf); err != nil {
+		return err
+	}
+	keyspaceEvents, err := pubsub.ParseKeyspaceEvents(conf.KeyspaceEvents)
+	if err != nil {
+		return err
+	}
+
+	// Reconfigure the engines that can fail before changing anything else. The previous AOF sync strategy is
+	// restored when the raft snapshot config can't be changed, so that a failed CONFIG SET changes nothing.
+	setStrategy := conf.AOFSyncStrategy != server.config.AOFSyncStrategy && server.aofEngine != nil
+	if setStrategy {
+		if err = server.aofEngine.SetStrategy(conf.AOFSyncStrategy); err != nil {
+			return err
+		}
+	}
+	setSnapshot := conf.SnapShotThreshold != server.config.SnapShotThreshold ||
+		conf.SnapshotInterval != server.config.SnapshotInterval
+	if setSnapshot && server.isInCluster() {
+		if err = server.raft.SetSnapshotConfig(conf.SnapShotThreshold, conf.SnapshotInterval); err != nil {
+			if setStrategy {
+				if rollbackErr := server.aofEngine.SetStrategy(server.config.AOFSyncStrategy); rollbackErr != nil {
+					server.logger.Error("restore AOF sync strategy", logger.ErrorKey, rollbackErr)
+				}
+			}
+			return err
+		}
+	}
+
+	if setSnapshot && !server.isInCluster() && server.snapshotEngine != nil {
+		server.snapshotEngine.SetThreshold(conf.SnapShotThreshold)
+		server.snapshotEngine.SetInterval(conf.SnapshotInterval)
+	}
+
+	evict := conf.MaxMemory != server.config.MaxMemory || conf.EvictionPolicy != server.config.EvictionPolicy
+
+	server.pubSub.SetKeyspaceEvents(keyspaceEvents)
+	if conf.RequirePass != server.config.RequirePass || conf.Password != server.config.Password {
+		server.acl.SetRequirePass(conf.RequirePass, conf.Password)
+	}
+	server.slowlog.SetThreshold(time.Duration(conf.SlowlogThreshold) * time.Microsecond)
+	server.slowlog.SetMaxLen(int(conf.SlowlogMaxLen))
+	server.latency.SetThreshold(time.Duration(conf.LatencyThreshold) * time.Millisecond)
+	if level, err := logger.ParseLevel(conf.LogLevel); err == nil {
+		server.logLevel.Set(level)
+	}
+
+	// Only the parameters that were set are written, so that the immutable parameters can be read without
+	// holding configMut.
+	for _, parameter := range parameters {
+		_ = parameter.Set(&server.config, values[parameter.Name])
+	}
+	server.config.KeyspaceEvents = pubsub.FormatKeyspaceEvents(keyspaceEvents)
+
+	// Evict keys straight away when max memory was lowered below the memory in use.
+	if evict && conf.EvictionPolicy != constants.NoEviction {
+		go func() {
+			if err := server.adjustMemoryUsage(server.context); err != nil {
+				server.logger.Error("evict keys after config change", logger.ErrorKey, err)
+			}
+		}()
+	}
+
+	return nil
+}
+
+// rewriteConfig writes the values of the mutable parameters to the config file the server was started with.
+// The other entries of the file are kept as they are.
+func (server *EchoVault) rewriteConfig() error {
+	server.configMut.Lock()
+	defer server.configMut.Unlock()
+
+	file := server.config.ConfigFile
+	if file == "" {
+		return errors.New("the server is running without a config file")
+	}
+
+	var marshal func(v interface{}) ([]byte, error)
+	var unmarshal func(b []byte, v interface{}) error
+	switch strings.ToLower(path.Ext(file)) {
+	case ".json":
+		marshal = func(v interface{}) ([]byte, error) {
+			return json.MarshalIndent(v, "", "  ")
+		}
+		unmarshal = func(b []byte, v interface{}) error {
+			// Keep the numbers as they are, as large integers don't fit in a float64.
+			decoder := json.NewDecoder(bytes.NewReader(b))
+			decoder.UseNumber()
+			return decoder.Decode(v)
+		}
+	case ".yaml", ".yml":
+		marshal = yaml.Marshal
+		unmarshal = yaml.Unmarshal
+	default:
+		return fmt.Errorf("config file %s is not a JSON or YAML file", file)
+	}
+
+	entries := make(map[string]interface{})
+	b, err := os.ReadFile(file)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("rewrite config: %+v", err)
+	}
+	if len(b) > 0 {
+		if err = unmarshal(b, &entries); err != nil {
+			return fmt.Errorf("rewrite config: %+v", err)
+		}
+	}
+
+	// Encode the configuration in the format of the file to get the encoded value of each parameter.
+	current := make(map[string]interface{})
+	if b, err = marshal(server.config); err != nil {
+		return fmt.Errorf("rewrite config: %+v", err)
+	}
+	if err = unmarshal(b, &current); err != nil {
+		return fmt.Errorf("rewrite config: %+v", err)
+	}
+	for _, parameter := range config.Parameters() {
+		if parameter.Mutable {
+			entries[parameter.Key] = current[parameter.Key]
+		}
+	}
+
+	if b, err = marshal(entries); err != nil {
+		return fmt.Errorf("rewrite config: %+v", err)
+	}
+
+	// Write a temporary file and rename it so that the config file is never left half written.
+	tmp := file + ".tmp"
+	if err = os.WriteFile(tmp, b, 0644); err != nil {
+		return fmt.Errorf("rewrite config: %+v", err)
+	}
+	if err = os.Rename(tmp, file); err != nil {
+		return fmt.Errorf("rewrite config: %+v", err)
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package echovault
+
+import (
+	"github.com/echovault/echovault/internal"
+	"github.com/echovault/echovault/internal/metrics"
+	"math"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// commandStats holds the counters of a command or subcommand.
+// The counters are updated atomically so that recording a call doesn't need a lock.
+type commandStats struct {
+	calls    atomic.Uint64
+	rejected atomic.Uint64      // The number of calls rejected before the command was executed.
+	failed   atomic.Uint64      // The number of calls that were executed and returned an error.
+	time     atomic.Int64       // The total time spent executing the command in nanoseconds.
+	latency  *metrics.Histogram // The latency histogram of the command, exported as a Prometheus metric.
+}
+
+// loadCommandStats returns the counters of the command, creating them on its first call.
+func (server *EchoVault) loadCommandStats(command string) *commandStats {
+	s, ok := server.stats.commands.Load(command)
+	if !ok {
+		s, _ = server.stats.commands.LoadOrStore(command, &commandStats{latency: metrics.NewHistogram()})
+	}
+	return s.(*commandStats)
+}
+
+// recordCommand records a call to the command that took the given time. Failed calls are counted both as calls
+// and as failed calls.
+func (server *EchoVault) recordCommand(command string, duration time.Duration, failed bool) {
+	server.stats.totalCommands.Add(1)
+	stats := server.loadCommandStats(command)
+	stats.calls.Add(1)
+	if failed {
+		stats.failed.Add(1)
+	}
+	stats.time.Add(int64(duration))
+	stats.latency.Observe(duration)
+}
+
+// recordRejectedCommand records a call to the command that was rejected before it was executed,
+// either by the ACL layer or because its arguments were invalid. Rejected calls are not counted as calls.
+func (server *EchoVault) recordRejectedCommand(command string) {
+	server.stats.totalCommands.Add(1)
+	server.loadCommandStats(command).rejected.Add(1)
+}
+
+// recordError records an error returned to a client under the prefix of its message.
+func (server *EchoVault) recordError(err error) {
+	server.stats.totalErrors.Add(1)
+	prefix := errorPrefix(err)
+	c, ok := server.stats.errors.Load(prefix)
+	if !ok {
+		c, _ = server.stats.errors.LoadOrStore(prefix, &atomic.Uint64{})
+	}
+	c.(*atomic.Uint64).Add(1)
+}
+
+// errorPrefix returns the first word of the error message when it's an upper case error code
+// such as READONLY or WRONGTYPE, and ERR otherwise.
+func errorPrefix(err error) string {
+	word, _, _ := strings.Cut(err.Error(), " ")
+	if len(word) < 2 {
+		return "ERR"
+	}
+	for _, r := range word {
+		if r < 'A' || r > 'Z' {
+			return "ERR"
+		}
+	}
+	return word
+}
+
+// getCommandStats returns the statistics of each command that has been called.
+func (server *EchoVault) getCommandStats() map[string]internal.CommandStats {
+	res := make(map[string]internal.CommandStats)
+	server.stats.commands.Range(func(key, value any) bool {
+		stats := value.(*commandStats)
+		res[key.(string)] = internal.CommandStats{
+			Calls:         stats.calls.Load(),
+			RejectedCalls: stats.rejected.Load(),
+			FailedCalls:   stats.failed.Load(),
+			Time:          time.Duration(stats.time.Load()),
+		}
+		return true
+	})
+	return res
+}
+
+// resetStats resets the statistics reported by the commandstats, errorstats and stats sections of INFO,
+// along with the latency histograms of the commands.
+func (server *EchoVault) resetStats() {
+	server.stats.commands.Range(func(key, value any) bool {
+		server.stats.commands.Delete(key)
+		return true
+	})
+	server.stats.errors.Range(func(key, value any) bool {
+		server.stats.errors.Delete(key)
+		return true
+	})
+	server.stats.totalCommands.Store(0)
+	server.stats.totalErrors.Store(0)
+	server.stats.keyspaceHits.Store(0)
+	server.stats.keyspaceMisses.Store(0)
+}
+
+// getLatencyHistogram returns the distribution of the execution time of the given commands,
+// or of every command that has been called when none is given.
+func (server *EchoVault) getLatencyHistogram(commands []string) map[string]internal.LatencyHistogram {
+	res := make(map[string]internal.LatencyHistogram)
+	add := func(command string, stats *commandStats) {
+		snapshot := stats.latency.Snapshot()
+		histogram := internal.LatencyHistogram{Calls: snapshot.Count}
+		var previous uint64
+		for i, upperBound := range snapshot.Buckets {
+			if snapshot.Counts[i] == previous {
+				continue
+			}
+			previous = snapshot.Counts[i]
+			histogram.Buckets = append(histogram.Buckets, internal.LatencyBucket{
+				UpperBound: time.Duration(math.Round(upperBound * float64(time.Second))),
+				Count:      snapshot.Counts[i],
+			})
+		}
+		res[command] = histogram
+	}
+
+	if len(commands) == 0 {
+		server.stats.commands.Range(func(key, value any) bool {
+			add(key.(string), value.(*commandStats))
+			return true
+		})
+		return res
+	}
+	for _, command := range commands {
+		if stats, ok := server.stats.commands.Load(strings.ToLower(command)); ok {
+			add(strings.ToLower(command), stats.(*commandStats))
+		}
+	}
+	return res
+}
+
+// getErrorStats returns the number of errors returned to clients by error prefix.
+func (server *EchoVault) getErrorStats() map[string]uint64 {
+	res := make(map[string]uint64)
+	server.stats.errors.Range(func(key, value any) bool {
+		res[key.(string)] = value.(*atomic.Uint64).Load()
+		return true
+	})
+	return res
+}
+
+// getClientsInfo returns information about the client connections.
+func (server *EchoVault) getClientsInfo() internal.ClientsInfo {
+	server.connInfo.mut.RLock()
+	defer server.connInfo.mut.RUnlock()
+	return internal.ClientsInfo{ConnectedClients: len(server.connInfo.tcpClients)}
+}
+
+// getPersistenceInfo returns the state of the snapshots and the append-only log.
+func (server *EchoVault) getPersistenceInfo() internal.PersistenceInfo {
+	info := internal.PersistenceInfo{
+		SnapshotInProgress:   server.snapshotInProgress.Load(),
+		LastSnapshot:         server.getLatestSnapshotTime(),
+		AOFRewriteInProgress: server.rewriteAOFInProgress.Load(),
+	}
+	if server.snapshotEngine != nil {
+		info.ChangesSinceSnapshot = server.snapshotEngine.ChangeCount()
+	}
+	if server.aofEngine != nil && server.aofEngine.Enabled() {
+		info.AOFEnabled = true
+		info.AOFOffset = server.aofEngine.Offset()
+		info.AOFSyncedOffset = server.aofEngine.SyncedOffset()
+	}
+	return info
+}
+
+// getKeyspaceInfo returns the number of keys and volatile keys in each database that holds keys.
+func (server *EchoVault) getKeyspaceInfo() map[int]internal.KeyspaceInfo {
+	res := make(map[int]internal.KeyspaceInfo)
+
+	server.storeLock.RLock()
+	for database, store := range server.store {
+		if len(store) > 0 {
+			res[database] = internal.KeyspaceInfo{Keys: len(store)}
+		}
+	}
+	server.storeLock.RUnlock()
+
+	server.keysWithExpiry.rwMutex.RLock()
+	defer server.keysWithExpiry.rwMutex.RUnlock()
+	for database, info := range res {
+		if index, ok := server.keysWithExpiry.keys[database]; ok {
+			info.Expires = index.Len()
+			res[database] = info
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,95 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package echovault
+
+import (
+	"context"
+	"net"
+
+	"github.com/echovault/echovault/internal"
+	"github.com/echovault/echovault/internal/logger"
+)
+
+// sendToClient writes the message pushed by CLIENT TRACKING to the TCP connection with the id.
+// It returns false when the connection does not exist.
+func (server *EchoVault) sendToClient(id uint64, message func(protocol int) []byte) bool {
+	var conn *net.Conn
+	var info internal.ConnectionInfo
+	server.connInfo.mut.RLock()
+	for c, i := range server.connInfo.tcpClients {
+		if i.Id == id {
+			conn, info = c, i
+			break
+		}
+	}
+	handle := server.connInfo.handles[conn]
+	server.connInfo.mut.RUnlock()
+
+	if conn == nil || handle == nil {
+		return false
+	}
+	m := message(info.Protocol)
+	if m == nil {
+		return true
+	}
+
+	handle.writeMutex.Lock()
+	defer handle.writeMutex.Unlock()
+	if _, err := (*conn).Write(m); err != nil {
+		server.logger.Debug("write invalidation message", logger.ConnIDKey, info.Id, logger.ErrorKey, err)
+		return false
+	}
+	return true
+}
+
+// trackKeys records the keys read by the command for CLIENT TRACKING. The keys are recorded before the command
+// runs, so that a write that follows the read always invalidates them. It returns the id of the connection and
+// the keys that it was not tracking yet, which are forgotten again if the command fails.
+func (server *EchoVault) trackKeys(conn *net.Conn, cmd []string, command internal.Command, subCommand internal.SubCommand) (uint64, []string) {
+	server.connInfo.mut.RLock()
+	id := server.connInfo.tcpClients[conn].Id
+	server.connInfo.mut.RUnlock()
+	if !server.tracking.Enabled(id) {
+		return id, nil
+	}
+
+	// CLIENT CACHING applies to the command that follows it.
+	if subCommand.Command == "caching" && command.Command == "client" {
+		return id, nil
+	}
+
+	var keys []string
+	if !internal.IsWriteCommand(command, subCommand) {
+		keyExtractionFunc := command.KeyExtractionFunc
+		if subCommand.KeyExtractionFunc != nil {
+			keyExtractionFunc = subCommand.KeyExtractionFunc
+		}
+		if result, err := keyExtractionFunc(cmd); err == nil {
+			keys = result.ReadKeys
+		}
+	}
+	return id, server.tracking.Track(id, keys)
+}
+
+// invalidateKey sends the invalidation of the modified key to the clients tracking it.
+// It's called by every write to the keyspace, including the deletion of expired and evicted keys.
+func (server *EchoVault) invalidateKey(ctx context.Context, key string) {
+	sender, _ := ctx.Value(internal.ContextConnID("ConnectionID")).(string)
+	server.tracking.Invalidate(key, sender)
+}
+
+func (server *EchoVault) getTracking() interface{} {
+	return server.tracking
+}
@@ -0,0 +1,63 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package echovault
+
+import (
+	"context"
+	"github.com/echovault/echovault/internal"
+)
+
+// isXReplActive returns true when this server should replicate the dataset to the remote cluster.
+// In cluster mode only the raft leader replicates, and in standalone mode replicas don't replicate.
+func (server *EchoVault) isXReplActive() bool {
+	if server.isInCluster() {
+		return server.raft.IsRaftLeader()
+	}
+	return !server.replication.IsReplica()
+}
+
+// getXReplKeys returns the keys of every database for a full cross-cluster sync.
+func (server *EchoVault) getXReplKeys() map[int][]string {
+	server.storeLock.RLock()
+	defer server.storeLock.RUnlock()
+	keys := make(map[int][]string, len(server.store))
+	for database, store := range server.store {
+		for key := range store {
+			keys[database] = append(keys[database], key)
+		}
+	}
+	return keys
+}
+
+// getXReplKey returns the current state of the key and its latest write.
+// Both are read while the keyspace is locked, so that the write matches the state.
+func (server *EchoVault) getXReplKey(database int, key string) (internal.KeyData, internal.WriteOrigin, bool) {
+	server.storeLock.RLock()
+	defer server.storeLock.RUnlock()
+	data, exists := server.store[database][key]
+	write, _ := server.xrepl.LastWrite(database, key)
+	return data, write, exists
+}
+
+// acceptsWrite returns true when the write in the context can be applied to the key.
+// Local writes are always accepted, and writes replicated from another cluster are accepted when they're not older
+// than the latest write of the key. It must be called while the store lock is held.
+func (server *EchoVault) acceptsWrite(ctx context.Context, database int, key string) bool {
+	write, ok := ctx.Value(internal.ContextWriteOrigin("WriteOrigin")).(internal.WriteOrigin)
+	if !ok {
+		return true
+	}
+	return server.xrepl.Accepts(database, key, write)
+}
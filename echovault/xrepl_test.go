@@ -0,0 +1,179 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package echovault
+
+import (
+	"context"
+	"fmt"
+	"github.com/echovault/echovault/internal"
+	"github.com/tidwall/resp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_CrossClusterReplication(t *testing.T) {
+	// The remote cluster accepts replicated writes.
+	remoteConf := clusterConfig(t, "XREPL-B0", true, "")
+	remoteConf.XRepl = true
+	remoteConf.XReplSourceID = "region-b"
+	remote, err := NewEchoVault(WithContext(context.Background()), WithConfig(remoteConf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	go remote.Start()
+	t.Cleanup(remote.ShutDown)
+	if !eventually(t, 10*time.Second, remote.raft.IsRaftLeader) {
+		t.Fatal("expected remote node to become the raft leader")
+	}
+
+	// The local cluster replicates database 0 and the keys that match repl:* to the remote cluster.
+	localConf := clusterConfig(t, "XREPL-A0", true, "")
+	localConf.XReplSourceID = "region-a"
+	localConf.XReplTargets = []string{fmt.Sprintf("%s:%d", remoteConf.BindAddr, remoteConf.Port)}
+	localConf.XReplDatabases = []int{0}
+	localConf.XReplKeyPatterns = []string{"repl:*"}
+	local, err := NewEchoVault(WithContext(context.Background()), WithConfig(localConf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	go local.Start()
+	t.Cleanup(local.ShutDown)
+	if !eventually(t, 10*time.Second, local.raft.IsRaftLeader) {
+		t.Fatal("expected local node to become the raft leader")
+	}
+
+	do := func(server *EchoVault, command ...string) resp.Value {
+		b, err := server.handleCommand(server.context, internal.EncodeCommand(command), nil, false, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		value, _, err := resp.NewReader(strings.NewReader(string(b))).ReadValue()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return value
+	}
+
+	if _, _, err = local.Set("repl:string", "value1", SetOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = local.HSet("repl:hash", map[string]string{"field1": "value1", "field2": "value2"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = local.PExpire("repl:hash", 100000, PExpireOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = local.Set("other", "value", SetOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err = local.SelectDB(1); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = local.Set("repl:db1", "value", SetOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err = local.SelectDB(0); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = local.Set("repl:last", "last", SetOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !eventually(t, 10*time.Second, func() bool {
+		value, _ := remote.Get("repl:last")
+		return value == "last"
+	}) {
+		t.Fatalf("expected repl:last to be replicated, status:\n%s", do(local, "XREPL", "STATUS").String())
+	}
+
+	t.Run("Test replicated values", func(t *testing.T) {
+		if value, _ := remote.Get("repl:string"); value != "value1" {
+			t.Errorf("expected repl:string to be value1, got %s", value)
+		}
+		fields, err := remote.HGetAll("repl:hash")
+		if err != nil {
+			t.Fatal(err)
+		}
+		slices.Sort(fields)
+		if !slices.Equal(fields, []string{"field1", "field2", "value1", "value2"}) {
+			t.Errorf("expected repl:hash to have field1 and field2, got %v", fields)
+		}
+		localExpiry, _ := local.PExpireTime("repl:hash")
+		remoteExpiry, _ := remote.PExpireTime("repl:hash")
+		if localExpiry <= 0 || remoteExpiry != localExpiry {
+			t.Errorf("expected repl:hash to expire at %d, got %d", localExpiry, remoteExpiry)
+		}
+	})
+
+	t.Run("Test filtered keys are not replicated", func(t *testing.T) {
+		if value, _ := remote.Get("other"); value != "" {
+			t.Errorf("expected key that does not match the key patterns not to be replicated, got %s", value)
+		}
+		if err := remote.SelectDB(1); err != nil {
+			t.Fatal(err)
+		}
+		value, _ := remote.Get("repl:db1")
+		if err := remote.SelectDB(0); err != nil {
+			t.Fatal(err)
+		}
+		if value != "" {
+			t.Errorf("expected key in database 1 not to be replicated, got %s", value)
+		}
+	})
+
+	t.Run("Test deletions are replicated", func(t *testing.T) {
+		if _, err := local.Del("repl:string"); err != nil {
+			t.Fatal(err)
+		}
+		if !eventually(t, 10*time.Second, func() bool {
+			value, _ := remote.Get("repl:string")
+			return value == ""
+		}) {
+			t.Error("expected deletion of repl:string to be replicated")
+		}
+	})
+
+	t.Run("Test the checkpoint is stored on the remote cluster", func(t *testing.T) {
+		if !eventually(t, 10*time.Second, func() bool {
+			checkpoint := do(remote, "XREPL", "CHECKPOINT", "region-a").Array()
+			return len(checkpoint) == 2 && checkpoint[0].String() == local.cdc.ID() &&
+				uint64(checkpoint[1].Integer()) == local.MutationOffset()
+		}) {
+			t.Errorf("expected checkpoint [%s %d], got %v",
+				local.cdc.ID(), local.MutationOffset(), do(remote, "XREPL", "CHECKPOINT", "region-a"))
+		}
+		status := do(local, "XREPL", "STATUS").String()
+		for _, line := range []string{"state:connected", "source_id:region-a", "full_syncs:1"} {
+			if !strings.Contains(status, line) {
+				t.Errorf("expected status to contain %q, got %q", line, status)
+			}
+		}
+	})
+
+	t.Run("Test replicated writes keep their origin", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		events := remote.SubscribeMutations(ctx)
+		if _, _, err := local.Set("repl:origin", "value", SetOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		event := nextMutation(t, events)
+		if event.Key != "repl:origin" || event.Origin != "region-a" {
+			t.Errorf("expected event for repl:origin from region-a, got %+v", event)
+		}
+	})
+}
@@ -22,8 +22,10 @@ import (
 	logstore "github.com/echovault/echovault/internal/aof/log"
 	"github.com/echovault/echovault/internal/aof/preamble"
 	"github.com/echovault/echovault/internal/clock"
-	"log"
+	"github.com/echovault/echovault/internal/logger"
+	"github.com/echovault/echovault/internal/metrics"
 	"sync"
+	"time"
 )
 
 type Engine struct {
@@ -43,6 +45,8 @@ type Engine struct {
 	getStateFunc      func() map[int]map[string]internal.KeyData
 	setKeyDataFunc    func(database int, key string, data internal.KeyData)
 	handleCommand     func(database int, command []byte)
+	fsyncFunc         func(latency time.Duration)
+	logger            logger.Logger
 }
 
 func WithClock(clock clock.Clock) func(engine *Engine) {
@@ -51,6 +55,12 @@ func WithClock(clock clock.Clock) func(engine *Engine) {
 	}
 }
 
+func WithLogger(logger logger.Logger) func(engine *Engine) {
+	return func(engine *Engine) {
+		engine.logger = logger
+	}
+}
+
 func WithStrategy(strategy string) func(engine *Engine) {
 	return func(engine *Engine) {
 		engine.syncStrategy = strategy
@@ -93,6 +103,12 @@ func WithHandleCommandFunc(f func(database int, command []byte)) func(engine *En
 	}
 }
 
+func WithFsyncFunc(f func(latency time.Duration)) func(engine *Engine) {
+	return func(engine *Engine) {
+		engine.fsyncFunc = f
+	}
+}
+
 func WithPreambleReadWriter(rw preamble.ReadWriter) func(engine *Engine) {
 	return func(engine *Engine) {
 		engine.preambleRW = rw
@@ -117,6 +133,8 @@ func NewAOFEngine(options ...func(engine *Engine)) (*Engine, error) {
 		getStateFunc:      func() map[int]map[string]internal.KeyData { return nil },
 		setKeyDataFunc:    func(database int, key string, data internal.KeyData) {},
 		handleCommand:     func(database int, command []byte) {},
+		fsyncFunc:         func(latency time.Duration) {},
+		logger:            logger.Default(),
 	}
 
 	// Setup AOFEngine options first as these options are used
@@ -145,6 +163,8 @@ func NewAOFEngine(options ...func(engine *Engine)) (*Engine, error) {
 		logstore.WithStrategy(engine.syncStrategy),
 		logstore.WithReadWriter(engine.appendRW),
 		logstore.WithHandleCommandFunc(engine.handleCommand),
+		logstore.WithFsyncFunc(engine.fsyncFunc),
+		logstore.WithLogger(engine.logger),
 	)
 	if err != nil {
 		return nil, err
@@ -156,8 +176,43 @@ func NewAOFEngine(options ...func(engine *Engine)) (*Engine, error) {
 
 func (engine *Engine) LogCommand(database int, command []byte) {
 	if err := engine.appendStore.Write(database, command); err != nil {
-		log.Printf("log command error: %+v\n", err)
+		engine.logger.Error("log command", logger.ErrorKey, err)
+	}
+}
+
+// Enabled returns true when commands are persisted to an append-only log.
+func (engine *Engine) Enabled() bool {
+	return engine.appendStore.Enabled()
+}
+
+// Offset returns the number of bytes written to the append-only log since startup.
+func (engine *Engine) Offset() uint64 {
+	return engine.appendStore.Offset()
+}
+
+// SyncedOffset returns the offset up to which the append-only log is fsynced.
+func (engine *Engine) SyncedOffset() uint64 {
+	return engine.appendStore.SyncedOffset()
+}
+
+// FsyncLatency returns the latency histogram of the fsyncs of the append-only log.
+func (engine *Engine) FsyncLatency() metrics.HistogramSnapshot {
+	return engine.appendStore.FsyncLatency()
+}
+
+// SetStrategy changes the sync strategy of the append only log while the engine is running.
+func (engine *Engine) SetStrategy(strategy string) error {
+	if err := engine.appendStore.SetStrategy(strategy); err != nil {
+		return err
 	}
+	engine.syncStrategy = strategy
+	return nil
+}
+
+// WaitForSync blocks until the append-only log is fsynced up to the given offset or the timeout elapses.
+// A timeout of 0 blocks indefinitely. It returns false if the timeout elapsed first.
+func (engine *Engine) WaitForSync(offset uint64, timeout time.Duration) (bool, error) {
+	return engine.appendStore.WaitForSync(offset, timeout)
 }
 
 func (engine *Engine) RewriteLog() error {
@@ -192,9 +247,9 @@ func (engine *Engine) Restore() error {
 
 func (engine *Engine) Close() {
 	if err := engine.preambleStore.Close(); err != nil {
-		log.Printf("close preamble store error: %+v\n", engine)
+		engine.logger.Error("close preamble store", logger.ErrorKey, err)
 	}
 	if err := engine.appendStore.Close(); err != nil {
-		log.Printf("close append store error: %+v\n", engine)
+		engine.logger.Error("close append store", logger.ErrorKey, err)
 	}
 }
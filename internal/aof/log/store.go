@@ -15,12 +15,14 @@
 package log
 
 import (
+	"errors"
 	"fmt"
 	"github.com/echovault/echovault/internal"
 	"github.com/echovault/echovault/internal/clock"
+	"github.com/echovault/echovault/internal/logger"
+	"github.com/echovault/echovault/internal/metrics"
 	"github.com/tidwall/resp"
 	"io"
-	"log"
 	"os"
 	"path"
 	"strconv"
@@ -50,6 +52,20 @@ type Store struct {
 	directory string
 	// Function to handle command read from AOF log after restore.
 	handleCommand func(database int, command []byte)
+	// The number of bytes written to the log since the store was created.
+	offset uint64
+	// The value of offset at the latest successful fsync.
+	syncedOffset uint64
+	// Closed and replaced on every successful fsync to wake up the goroutines waiting in WaitForSync.
+	syncNotify chan struct{}
+	// The latency of the fsyncs of the log.
+	fsyncLatency *metrics.Histogram
+	// Function called with the latency of every fsync of the log.
+	fsyncFunc func(latency time.Duration)
+	// Closed to stop the goroutine that syncs the log every second when the strategy changes from "everysec".
+	stopSync chan struct{}
+	// The logger the sync errors are written to.
+	logger logger.Logger
 }
 
 func WithClock(clock clock.Clock) func(store *Store) {
@@ -76,6 +92,18 @@ func WithDirectory(directory string) func(store *Store) {
 	}
 }
 
+func WithFsyncFunc(f func(latency time.Duration)) func(store *Store) {
+	return func(store *Store) {
+		store.fsyncFunc = f
+	}
+}
+
+func WithLogger(logger logger.Logger) func(store *Store) {
+	return func(store *Store) {
+		store.logger = logger
+	}
+}
+
 func WithHandleCommandFunc(f func(database int, command []byte)) func(store *Store) {
 	return func(store *Store) {
 		store.handleCommand = f
@@ -91,6 +119,10 @@ func NewAppendStore(options ...func(store *Store)) (*Store, error) {
 		rw:              nil,
 		mut:             sync.Mutex{},
 		handleCommand:   func(database int, command []byte) {},
+		syncNotify:      make(chan struct{}),
+		fsyncLatency:    metrics.NewHistogram(),
+		fsyncFunc:       func(latency time.Duration) {},
+		logger:          logger.Default(),
 	}
 
 	for _, option := range options {
@@ -114,27 +146,60 @@ func NewAppendStore(options ...func(store *Store)) (*Store, error) {
 	// Start another goroutine that takes handles syncing the content to the file system.
 	// No need to start this goroutine if sync strategy is anything other than 'everysec'.
 	if strings.EqualFold(store.strategy, "everysec") {
-		go func() {
-			ticker := time.NewTicker(1 * time.Second)
-			defer func() {
-				ticker.Stop()
-			}()
-			for {
-				store.mut.Lock()
-				if err := store.Sync(); err != nil {
-					store.mut.Unlock()
-					log.Println(fmt.Errorf("new append store error: %+v", err))
-					break
-				}
-				store.mut.Unlock()
-				<-ticker.C
-			}
-		}()
+		store.stopSync = make(chan struct{})
+		go store.syncEverySecond(store.stopSync)
 	}
 
 	return store, nil
 }
 
+// syncEverySecond syncs the log every second until the stop channel is closed.
+func (store *Store) syncEverySecond(stop <-chan struct{}) {
+	ticker := time.NewTicker(1 * time.Second)
+	defer func() {
+		ticker.Stop()
+	}()
+	for {
+		store.mut.Lock()
+		if err := store.Sync(); err != nil {
+			store.mut.Unlock()
+			store.logger.Error("sync append-only log", logger.ErrorKey, err)
+			break
+		}
+		store.mut.Unlock()
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// SetStrategy changes the sync strategy of the log while it's in use.
+// The pending writes are synced when switching away from "everysec".
+func (store *Store) SetStrategy(strategy string) error {
+	store.mut.Lock()
+	defer store.mut.Unlock()
+
+	strategy = strings.ToLower(strategy)
+	if strategy == store.strategy {
+		return nil
+	}
+	if store.stopSync != nil {
+		if err := store.Sync(); err != nil {
+			return fmt.Errorf("set strategy: %+v", err)
+		}
+		close(store.stopSync)
+		store.stopSync = nil
+	}
+	store.strategy = strategy
+	if strategy == "everysec" {
+		store.stopSync = make(chan struct{})
+		go store.syncEverySecond(store.stopSync)
+	}
+	return nil
+}
+
 func (store *Store) Write(database int, command []byte) error {
 	// Skip operation if ReadWriter is not defined.
 	if store.rw == nil {
@@ -148,14 +213,17 @@ func (store *Store) Write(database int, command []byte) error {
 	// log the SELECT command before logging the incoming command.
 	// This allows us to switch databases appropriately when restoring the state on startup.
 	if database != store.currentDatabase {
-		_, err := store.rw.Write([]byte(fmt.Sprintf("*2\r\n$6\r\nSELECT\r\n$1\r\n%s\r\n", strconv.Itoa(database))))
+		n, err := store.rw.Write([]byte(fmt.Sprintf("*2\r\n$6\r\nSELECT\r\n$1\r\n%s\r\n", strconv.Itoa(database))))
+		store.offset += uint64(n)
 		if err != nil {
 			return fmt.Errorf("log select error: %+v", err)
 		}
 		store.currentDatabase = database
 	}
 
-	if _, err := store.rw.Write(command); err != nil {
+	n, err := store.rw.Write(command)
+	store.offset += uint64(n)
+	if err != nil {
 		return fmt.Errorf("log command error: %+v", err)
 	}
 
@@ -168,13 +236,96 @@ func (store *Store) Write(database int, command []byte) error {
 	return nil
 }
 
+// Sync flushes the log to the file system. The caller must hold the store mutex.
 func (store *Store) Sync() error {
 	if store.rw != nil {
-		return store.rw.Sync()
+		start := time.Now()
+		if err := store.rw.Sync(); err != nil {
+			return err
+		}
+		latency := time.Since(start)
+		store.fsyncLatency.Observe(latency)
+		store.fsyncFunc(latency)
+		store.markSynced()
 	}
 	return nil
 }
 
+// markSynced records that everything written so far is on disk. The caller must hold the store mutex.
+func (store *Store) markSynced() {
+	if store.syncedOffset == store.offset {
+		return
+	}
+	store.syncedOffset = store.offset
+	close(store.syncNotify)
+	store.syncNotify = make(chan struct{})
+}
+
+// Enabled returns true when the store persists the log to a ReadWriter.
+func (store *Store) Enabled() bool {
+	store.mut.Lock()
+	defer store.mut.Unlock()
+	return store.rw != nil
+}
+
+// Offset returns the number of bytes written to the log since the store was created.
+func (store *Store) Offset() uint64 {
+	store.mut.Lock()
+	defer store.mut.Unlock()
+	return store.offset
+}
+
+// SyncedOffset returns the offset up to which the log is known to be on disk.
+func (store *Store) SyncedOffset() uint64 {
+	store.mut.Lock()
+	defer store.mut.Unlock()
+	return store.syncedOffset
+}
+
+// FsyncLatency returns the latency histogram of the fsyncs of the log.
+func (store *Store) FsyncLatency() metrics.HistogramSnapshot {
+	return store.fsyncLatency.Snapshot()
+}
+
+// WaitForSync blocks until the log is on disk up to the given offset or the timeout elapses.
+// A timeout of 0 blocks indefinitely. It returns false if the timeout elapsed first.
+// With the "no" strategy, the log is synced on demand as it would otherwise never be synced by the store.
+func (store *Store) WaitForSync(offset uint64, timeout time.Duration) (bool, error) {
+	store.mut.Lock()
+	if store.rw == nil {
+		store.mut.Unlock()
+		return false, errors.New("append only log is disabled")
+	}
+	if store.syncedOffset < offset && store.strategy == "no" {
+		if err := store.Sync(); err != nil {
+			store.mut.Unlock()
+			return false, fmt.Errorf("wait for sync: %+v", err)
+		}
+	}
+	store.mut.Unlock()
+
+	var deadline <-chan time.Time
+	if timeout > 0 {
+		deadline = store.clock.After(timeout)
+	}
+
+	for {
+		store.mut.Lock()
+		if store.syncedOffset >= offset {
+			store.mut.Unlock()
+			return true, nil
+		}
+		notify := store.syncNotify
+		store.mut.Unlock()
+
+		select {
+		case <-notify:
+		case <-deadline:
+			return false, nil
+		}
+	}
+}
+
 func (store *Store) Restore() error {
 	store.mut.Lock()
 	defer store.mut.Unlock()
@@ -243,7 +394,7 @@ func (store *Store) Truncate() error {
 		return fmt.Errorf("truncate: log select error: %+v", err)
 	}
 	// Immediately sync the file.
-	if err = store.rw.Sync(); err != nil {
+	if err = store.Sync(); err != nil {
 		return fmt.Errorf("truncate: sync error: %+v", err)
 	}
 
@@ -147,3 +147,76 @@ func Test_AppendStore(t *testing.T) {
 	}
 
 }
+
+func Test_AppendStoreWaitForSync(t *testing.T) {
+	t.Cleanup(func() {
+		_ = os.RemoveAll(path.Join(".", "testdata"))
+	})
+
+	t.Run("1. WaitForSync returns an error when the log is disabled", func(t *testing.T) {
+		store, err := log.NewAppendStore(log.WithClock(clock.NewClock()), log.WithStrategy("no"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = store.WaitForSync(0, time.Second); err == nil {
+			t.Error("expected error when waiting for sync on a disabled log")
+		}
+	})
+
+	tests := []struct {
+		name      string
+		directory string
+		strategy  string
+	}{
+		{
+			name:      "2. WaitForSync with always strategy returns immediately",
+			directory: "./testdata/wait/with_always_strategy",
+			strategy:  "always",
+		},
+		{
+			name:      "3. WaitForSync with everysec strategy returns after the next sync",
+			directory: "./testdata/wait/with_everysec_strategy",
+			strategy:  "everysec",
+		},
+		{
+			name:      "4. WaitForSync with no strategy syncs on demand",
+			directory: "./testdata/wait/with_no_strategy",
+			strategy:  "no",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store, err := log.NewAppendStore(
+				log.WithClock(clock.NewClock()),
+				log.WithDirectory(test.directory),
+				log.WithStrategy(test.strategy),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() {
+				_ = store.Close()
+			}()
+
+			if err = store.Write(0, marshalRespCommand([]string{"SET", "key1", "value1"})); err != nil {
+				t.Fatal(err)
+			}
+			offset := store.Offset()
+			if offset == 0 {
+				t.Fatal("expected offset to advance after write")
+			}
+
+			synced, err := store.WaitForSync(offset, 3*time.Second)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !synced {
+				t.Errorf("expected log to be synced up to offset %d, synced up to %d", offset, store.SyncedOffset())
+			}
+			if store.SyncedOffset() < offset {
+				t.Errorf("expected synced offset to be at least %d, got %d", offset, store.SyncedOffset())
+			}
+		})
+	}
+}
@@ -21,10 +21,10 @@ import (
 	"fmt"
 	"github.com/echovault/echovault/internal"
 	"github.com/echovault/echovault/internal/constants"
-	"log"
 	"os"
 	"path"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -39,6 +39,8 @@ type Config struct {
 	Port              uint16        `json:"Port" yaml:"Port"`
 	ServerID          string        `json:"ServerId" yaml:"ServerId"`
 	JoinAddr          string        `json:"JoinAddr" yaml:"JoinAddr"`
+	Seeds             []string      `json:"Seeds" yaml:"Seeds"`
+	DiscoveryDNS      string        `json:"DiscoveryDNS" yaml:"DiscoveryDNS"`
 	BindAddr          string        `json:"BindAddr" yaml:"BindAddr"`
 	DataDir           string        `json:"DataDir" yaml:"DataDir"`
 	BootstrapCluster  bool          `json:"BootstrapCluster" yaml:"BootstrapCluster"`
@@ -53,17 +55,48 @@ type Config struct {
 	AOFSyncStrategy   string        `json:"AOFSyncStrategy" yaml:"AOFSyncStrategy"`
 	MaxMemory         uint64        `json:"MaxMemory" yaml:"MaxMemory"`
 	EvictionPolicy    string        `json:"EvictionPolicy" yaml:"EvictionPolicy"`
+	NoEvictKeys       []string      `json:"NoEvictKeys" yaml:"NoEvictKeys"`
 	EvictionSample    uint          `json:"EvictionSample" yaml:"EvictionSample"`
 	EvictionInterval  time.Duration `json:"EvictionInterval" yaml:"EvictionInterval"`
+	LFULogFactor      uint          `json:"LFULogFactor" yaml:"LFULogFactor"`
+	LFUDecayTime      uint          `json:"LFUDecayTime" yaml:"LFUDecayTime"`
 	Modules           []string      `json:"Plugins" yaml:"Plugins"`
 	DiscoveryPort     uint16        `json:"DiscoveryPort" yaml:"DiscoveryPort"`
+	ReplicaOf         string        `json:"ReplicaOf" yaml:"ReplicaOf"`
+	ReplBacklogSize   uint64        `json:"ReplBacklogSize" yaml:"ReplBacklogSize"`
+	CDCBacklogSize    uint          `json:"CDCBacklogSize" yaml:"CDCBacklogSize"`
+	XRepl             bool          `json:"XRepl" yaml:"XRepl"`
+	XReplSourceID     string        `json:"XReplSourceID" yaml:"XReplSourceID"`
+	XReplTargets      []string      `json:"XReplTargets" yaml:"XReplTargets"`
+	XReplUsername     string        `json:"XReplUsername" yaml:"XReplUsername"`
+	XReplPassword     string        `json:"XReplPassword" yaml:"XReplPassword"`
+	XReplDatabases    []int         `json:"XReplDatabases" yaml:"XReplDatabases"`
+	XReplKeyPatterns  []string      `json:"XReplKeyPatterns" yaml:"XReplKeyPatterns"`
+	KeyspaceEvents    string        `json:"KeyspaceEvents" yaml:"KeyspaceEvents"`
+	GossipKeys        []string      `json:"GossipKeys" yaml:"GossipKeys"`
+	RaftTLS           bool          `json:"RaftTLS" yaml:"RaftTLS"`
+	MetricsPort       uint16        `json:"MetricsPort" yaml:"MetricsPort"`
+	SlowlogThreshold  int64         `json:"SlowlogLogSlowerThan" yaml:"SlowlogLogSlowerThan"`
+	SlowlogMaxLen     uint          `json:"SlowlogMaxLen" yaml:"SlowlogMaxLen"`
+	LatencyThreshold  uint64        `json:"LatencyMonitorThreshold" yaml:"LatencyMonitorThreshold"`
+	MonitorBufferSize uint          `json:"MonitorBufferSize" yaml:"MonitorBufferSize"`
+	LogLevel          string        `json:"LogLevel" yaml:"LogLevel"`
+	LogFormat         string        `json:"LogFormat" yaml:"LogFormat"`
+	LogOutput         string        `json:"LogOutput" yaml:"LogOutput"`
 	RaftBindAddr      string
 	RaftBindPort      uint16
+	ConfigFile        string `json:"-" yaml:"-"` // The file the config was loaded from. CONFIG REWRITE persists to it.
 }
 
 func GetConfig() (Config, error) {
 	var certKeyPairs [][]string
 	var clientCAs []string
+	var gossipKeys []string
+	var seeds []string
+	var xreplTargets []string
+	var xreplDatabases []int
+	var xreplKeyPatterns []string
+	var noEvictKeys []string
 
 	flag.Func("cert-key-pair",
 		"A pair of file paths representing the signed certificate and it's corresponding key separated by a comma.",
@@ -84,6 +117,23 @@ func GetConfig() (Config, error) {
 		return nil
 	})
 
+	flag.Func("gossip-key", `Base64 encoded 16, 24 or 32 byte key used to encrypt cluster gossip.
+Pass the flag multiple times to load a keyring. The first key encrypts outgoing messages, and all the keys
+are accepted for incoming messages.`, func(s string) error {
+		gossipKeys = append(gossipKeys, strings.TrimSpace(s))
+		return nil
+	})
+
+	flag.Func("seed", `Address of a cluster member to join ([server-id/]host:port). Pass the flag multiple times
+or separate the addresses with commas to try several members.`, func(s string) error {
+		for _, seed := range strings.Split(s, ",") {
+			if seed = strings.TrimSpace(seed); seed != "" {
+				seeds = append(seeds, seed)
+			}
+		}
+		return nil
+	})
+
 	aofSyncStrategy := "everysec"
 	flag.Func("aof-sync-strategy", `How often to flush the file contents written to append only file.
 The options are 'always' for syncing on each command, 'everysec' to sync every second, and 'no' to leave it up to the os.`,
@@ -98,7 +148,7 @@ The options are 'always' for syncing on each command, 'everysec' to sync every s
 		})
 
 	var maxMemory uint64 = 0
-	flag.Func("max-memory", `Upper memory limit before triggering eviction. 
+	flag.Func("max-memory", `Upper limit of the estimated size of the dataset before triggering eviction. 
 Supported units (kb, mb, gb, tb, pb). When 0 is passed, there will be no memory limit.
 There is no limit by default.`, func(memory string) error {
 		b, err := internal.ParseMemory(memory)
@@ -118,11 +168,12 @@ There is no limit by default.`, func(memory string) error {
 4) volatile-lfu - Evict the least frequently used keys with an expiration.
 5) volatile-lru - Evict the least recently used keys with an expiration.
 6) allkeys-random - Evict random keys until we get under the max-memory limit.
-7) volatile-random - Evict random keys with an expiration.`, func(policy string) error {
+7) volatile-random - Evict random keys with an expiration.
+8) volatile-ttl - Evict the keys with an expiration that expire first.`, func(policy string) error {
 			policies := []string{
 				constants.NoEviction,
 				constants.AllKeysLFU, constants.AllKeysLRU, constants.AllKeysRandom,
-				constants.VolatileLFU, constants.VolatileLRU, constants.VolatileRandom,
+				constants.VolatileLFU, constants.VolatileLRU, constants.VolatileRandom, constants.VolatileTTL,
 			}
 			policyIdx := slices.Index(policies, strings.ToLower(policy))
 			if policyIdx == -1 {
@@ -132,6 +183,63 @@ There is no limit by default.`, func(memory string) error {
 			return nil
 		})
 
+	flag.Func("no-evict", `Glob pattern of keys that are never evicted when max-memory is reached.
+Pass the flag multiple times to pin the keys that match any of the patterns.`, func(s string) error {
+		noEvictKeys = append(noEvictKeys, s)
+		return nil
+	})
+
+	var replBacklogSize uint64 = 1024 * 1024
+	flag.Func("repl-backlog-size", `The size of the replication backlog kept by a primary for partial resynchronization.
+Supported units (kb, mb, gb, tb, pb). Default is 1mb.`, func(size string) error {
+		b, err := internal.ParseMemory(size)
+		if err != nil {
+			return err
+		}
+		replBacklogSize = b
+		return nil
+	})
+
+	cdcBacklogSize := flag.Uint("cdc-backlog-size", 10000,
+		`The number of change data capture events kept for subscribers that resume from an offset.`)
+
+	xrepl := flag.Bool("xrepl", false, `Enable cross-cluster replication. The time of the latest write of every key is kept
+so that writes replicated from other clusters are resolved with last-writer-wins. Setting xrepl-target enables it.`)
+	xreplSourceID := flag.String("xrepl-source-id", "", `The ID of this cluster on the clusters it replicates to.
+Every node of the cluster must use the same ID. Defaults to the server ID.`)
+	flag.Func("xrepl-target", `Address (host:port) of a node of the remote cluster to replicate to.
+Pass the flag multiple times or separate the addresses with commas to try several nodes.`, func(s string) error {
+		for _, target := range strings.Split(s, ",") {
+			if target = strings.TrimSpace(target); target != "" {
+				xreplTargets = append(xreplTargets, target)
+			}
+		}
+		return nil
+	})
+	xreplUsername := flag.String("xrepl-username", "", "The username used to authenticate with the remote cluster.")
+	xreplPassword := flag.String("xrepl-password", "", "The password used to authenticate with the remote cluster.")
+	flag.Func("xrepl-database", `A database to replicate to the remote cluster.
+Pass the flag multiple times to replicate several databases. All databases are replicated by default.`,
+		func(s string) error {
+			database, err := strconv.Atoi(strings.TrimSpace(s))
+			if err != nil || database < 0 {
+				return errors.New("xrepl-database must be a positive integer")
+			}
+			xreplDatabases = append(xreplDatabases, database)
+			return nil
+		})
+	flag.Func("xrepl-key-pattern", `Glob pattern of the keys to replicate to the remote cluster.
+Pass the flag multiple times to replicate the keys that match any of the patterns. All keys are replicated by default.`,
+		func(s string) error {
+			xreplKeyPatterns = append(xreplKeyPatterns, s)
+			return nil
+		})
+
+	notifyKeyspaceEvents := flag.String("notify-keyspace-events", "", `The classes of keyspace events published to
+the __keyspace@<db>__:<key> and __keyevent@<db>__:<event> channels. K and E select the channels, and
+g (generic), $ (string), l (list), s (set), h (hash), z (sorted set), x (expired), e (evicted) or A (all of them)
+select the events. Keyspace notifications are disabled by default.`)
+
 	var modules []string
 	flag.Func(
 		"loadmodule",
@@ -146,10 +254,16 @@ There is no limit by default.`, func(memory string) error {
 
 	tls := flag.Bool("tls", false, "Start the echovault in TLS mode. Default is false.")
 	mtls := flag.Bool("mtls", false, "Use mTLS to verify the client.")
+	raftTLS := flag.Bool("raft-tls", false, "Use mTLS for the raft transport. Requires cert-key-pair and client-ca.")
 	port := flag.Int("port", 7480, "Port to use. Default is 7480")
+	metricsPort := flag.Uint("metrics-port", 0, `Port of the HTTP listener that serves Prometheus metrics at /metrics.
+The listener binds to the bind address. 0 disables the listener.`)
 	serverId := flag.String("server-id", "1", "EchoVault ID in raft cluster. Leave empty for client.")
 	joinAddr := flag.String("join-addr", "", "Address of cluster member in a cluster to you want to join.")
+	replicaOf := flag.String("replicaof", "", "Address (host:port) of the primary to replicate from on startup. Only works in standalone mode.")
 	bindAddr := flag.String("bind-addr", "127.0.0.1", "Address to bind the echovault to.")
+	discoveryDNS := flag.String("discovery-dns", "", `DNS name used to discover cluster members. SRV records are looked up first,
+then A/AAAA records combined with the discovery port.`)
 	discoveryPort := flag.Uint("discovery-port", 7946, "Port to use for memberlist cluster discovery.")
 	dataDir := flag.String("data-dir", ".", "Directory to store snapshots and logs.")
 	bootstrapCluster := flag.Bool("bootstrap-cluster", false, "Whether this instance should bootstrap a new cluster.")
@@ -158,8 +272,25 @@ There is no limit by default.`, func(memory string) error {
 	snapshotInterval := flag.Duration("snapshot-interval", 5*time.Minute, "The time interval between snapshots (in seconds). Default is 5 minutes.")
 	restoreSnapshot := flag.Bool("restore-snapshot", false, "This flag prompts the echovault to restore state from snapshot when set to true. Only works in standalone mode. Higher priority than restoreAOF.")
 	restoreAOF := flag.Bool("restore-aof", false, "This flag prompts the echovault to restore state from append-only logs. Only works in standalone mode. Lower priority than restoreSnapshot.")
-	evictionSample := flag.Uint("eviction-sample", 20, "An integer specifying the number of keys to sample when checking for expired keys.")
+	evictionSample := flag.Uint("eviction-sample", 20, `An integer specifying the number of keys to sample when checking for expired keys,
+and when looking for keys to evict with the lru and lfu eviction policies.`)
 	evictionInterval := flag.Duration("eviction-interval", 100*time.Millisecond, "The interval between each sampling of keys to evict.")
+	lfuLogFactor := flag.Uint("lfu-log-factor", 10, `How slowly the access counter of a key grows with the lfu eviction policies.
+The counter saturates after about a million accesses with the default of 10.`)
+	lfuDecayTime := flag.Uint("lfu-decay-time", 1, `The number of minutes after which the access counter of a key that isn't accessed
+is decremented with the lfu eviction policies. 0 disables the decay.`)
+	slowlogThreshold := flag.Int64("slowlog-log-slower-than", 10000, `The execution time in microseconds from which commands are logged in the slow log.
+0 logs every command and a negative value disables the slow log.`)
+	slowlogMaxLen := flag.Uint("slowlog-max-len", 128, "The maximum number of commands kept in the slow log.")
+	latencyThreshold := flag.Uint64("latency-monitor-threshold", 0, `The latency in milliseconds from which events such as AOF fsyncs, snapshots and
+eviction cycles are recorded by the latency monitor. 0 disables the latency monitor.`)
+	monitorBufferSize := flag.Uint("monitor-buffer-size", 1024, `The number of commands buffered for each MONITOR connection.
+Commands are dropped for monitors that fall further behind.`)
+	logLevel := flag.String("log-level", "info", `The minimum level of the entries written to the log.
+The options are 'debug', 'info', 'warn' and 'error'.`)
+	logFormat := flag.String("log-format", "text", "The format of the log entries. The options are 'text' and 'json'.")
+	logOutput := flag.String("log-output", "stderr", `Where the log entries are written to.
+The options are 'stderr', 'stdout' or the path of a file the entries are appended to.`)
 	forwardCommand := flag.Bool(
 		"forward-commands",
 		false,
@@ -201,6 +332,8 @@ It is a plain text value by default but you can provide a SHA256 hash by adding
 		Port:              uint16(*port),
 		ServerID:          *serverId,
 		JoinAddr:          *joinAddr,
+		Seeds:             seeds,
+		DiscoveryDNS:      *discoveryDNS,
 		BindAddr:          *bindAddr,
 		DataDir:           *dataDir,
 		BootstrapCluster:  *bootstrapCluster,
@@ -215,12 +348,37 @@ It is a plain text value by default but you can provide a SHA256 hash by adding
 		AOFSyncStrategy:   aofSyncStrategy,
 		MaxMemory:         maxMemory,
 		EvictionPolicy:    evictionPolicy,
+		NoEvictKeys:       noEvictKeys,
 		EvictionSample:    *evictionSample,
 		EvictionInterval:  *evictionInterval,
+		LFULogFactor:      *lfuLogFactor,
+		LFUDecayTime:      *lfuDecayTime,
 		Modules:           modules,
 		DiscoveryPort:     uint16(*discoveryPort),
+		ReplicaOf:         *replicaOf,
+		ReplBacklogSize:   replBacklogSize,
+		CDCBacklogSize:    *cdcBacklogSize,
+		XRepl:             *xrepl,
+		XReplSourceID:     *xreplSourceID,
+		XReplTargets:      xreplTargets,
+		XReplUsername:     *xreplUsername,
+		XReplPassword:     *xreplPassword,
+		XReplDatabases:    xreplDatabases,
+		XReplKeyPatterns:  xreplKeyPatterns,
+		KeyspaceEvents:    *notifyKeyspaceEvents,
+		GossipKeys:        gossipKeys,
+		RaftTLS:           *raftTLS,
+		MetricsPort:       uint16(*metricsPort),
+		SlowlogThreshold:  *slowlogThreshold,
+		SlowlogMaxLen:     *slowlogMaxLen,
+		LatencyThreshold:  *latencyThreshold,
+		MonitorBufferSize: *monitorBufferSize,
+		LogLevel:          *logLevel,
+		LogFormat:         *logFormat,
+		LogOutput:         *logOutput,
 		RaftBindAddr:      raftBindAddr,
 		RaftBindPort:      uint16(raftBindPort),
+		ConfigFile:        *config,
 	}
 
 	if len(*config) > 0 {
@@ -229,9 +387,7 @@ It is a plain text value by default but you can provide a SHA256 hash by adding
 			panic(err)
 		} else {
 			defer func() {
-				if err = f.Close(); err != nil {
-					log.Println(err)
-				}
+				_ = f.Close()
 			}()
 
 			ext := path.Ext(f.Name())
@@ -251,11 +407,5 @@ It is a plain text value by default but you can provide a SHA256 hash by adding
 	}
 
 	// If requirePass is set to true, then password must be provided as well.
-	var err error = nil
-
-	if conf.RequirePass && conf.Password == "" {
-		err = errors.New("password cannot be empty if requirePass is true")
-	}
-
-	return conf, err
+	return conf, Validate(conf)
 }
@@ -18,6 +18,8 @@ func DefaultConfig() Config {
 		Port:              7480,
 		ServerID:          "",
 		JoinAddr:          "",
+		Seeds:             make([]string, 0),
+		DiscoveryDNS:      "",
 		BindAddr:          "localhost",
 		RaftBindAddr:      raftBindAddr,
 		RaftBindPort:      uint16(raftBindPort),
@@ -35,8 +37,32 @@ func DefaultConfig() Config {
 		AOFSyncStrategy:   "everysec",
 		MaxMemory:         0,
 		EvictionPolicy:    constants.NoEviction,
+		NoEvictKeys:       make([]string, 0),
 		EvictionSample:    20,
 		EvictionInterval:  100 * time.Millisecond,
+		LFULogFactor:      10,
+		LFUDecayTime:      1,
 		Modules:           make([]string, 0),
+		ReplicaOf:         "",
+		ReplBacklogSize:   1024 * 1024,
+		CDCBacklogSize:    10000,
+		XRepl:             false,
+		XReplSourceID:     "",
+		XReplTargets:      make([]string, 0),
+		XReplUsername:     "",
+		XReplPassword:     "",
+		XReplDatabases:    make([]int, 0),
+		XReplKeyPatterns:  make([]string, 0),
+		KeyspaceEvents:    "",
+		GossipKeys:        make([]string, 0),
+		RaftTLS:           false,
+		MetricsPort:       0,
+		SlowlogThreshold:  10000,
+		SlowlogMaxLen:     128,
+		LatencyThreshold:  0,
+		MonitorBufferSize: 1024,
+		LogLevel:          "info",
+		LogFormat:         "text",
+		LogOutput:         "stderr",
 	}
 }
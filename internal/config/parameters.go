@@ -0,0 +1,331 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"github.com/echovault/echovault/internal"
+	"github.com/echovault/echovault/internal/constants"
+	"github.com/echovault/echovault/internal/logger"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Parameter is a configuration parameter exposed by CONFIG GET. Mutable parameters can be changed
+// with CONFIG SET while the server is running, and are persisted to the config file by CONFIG REWRITE.
+type Parameter struct {
+	Name    string                                 // The name of the parameter, the same as its command line flag.
+	Key     string                                 // The key of the parameter in the JSON and YAML config files.
+	Mutable bool                                   // Whether the parameter can be changed while the server is running.
+	Get     func(conf Config) string               // Returns the value of the parameter.
+	Set     func(conf *Config, value string) error // Validates the value and sets the parameter. Nil when immutable.
+}
+
+var parameters = []Parameter{
+	{
+		Name: "acl-config", Key: "AclConfig",
+		Get: func(conf Config) string { return conf.AclConfig },
+	},
+	{
+		Name: "aof-sync-strategy", Key: "AOFSyncStrategy", Mutable: true,
+		Get: func(conf Config) string { return conf.AOFSyncStrategy },
+		Set: func(conf *Config, value string) error {
+			if !slices.Contains([]string{"always", "everysec", "no"}, strings.ToLower(value)) {
+				return errors.New("aof-sync-strategy must be 'always', 'everysec' or 'no'")
+			}
+			conf.AOFSyncStrategy = strings.ToLower(value)
+			return nil
+		},
+	},
+	{
+		Name: "bind-addr", Key: "BindAddr",
+		Get: func(conf Config) string { return conf.BindAddr },
+	},
+	{
+		Name: "bootstrap-cluster", Key: "BootstrapCluster",
+		Get: func(conf Config) string { return formatBool(conf.BootstrapCluster) },
+	},
+	{
+		Name: "cdc-backlog-size", Key: "CDCBacklogSize",
+		Get: func(conf Config) string { return strconv.FormatUint(uint64(conf.CDCBacklogSize), 10) },
+	},
+	{
+		Name: "data-dir", Key: "DataDir",
+		Get: func(conf Config) string { return conf.DataDir },
+	},
+	{
+		Name: "discovery-port", Key: "DiscoveryPort",
+		Get: func(conf Config) string { return strconv.FormatUint(uint64(conf.DiscoveryPort), 10) },
+	},
+	{
+		Name: "eviction-interval", Key: "EvictionInterval",
+		Get: func(conf Config) string { return conf.EvictionInterval.String() },
+	},
+	{
+		Name: "eviction-policy", Key: "EvictionPolicy", Mutable: true,
+		Get: func(conf Config) string { return conf.EvictionPolicy },
+		Set: func(conf *Config, value string) error {
+			policies := []string{
+				constants.NoEviction,
+				constants.AllKeysLFU, constants.AllKeysLRU, constants.AllKeysRandom,
+				constants.VolatileLFU, constants.VolatileLRU, constants.VolatileRandom, constants.VolatileTTL,
+			}
+			if !slices.Contains(policies, strings.ToLower(value)) {
+				return fmt.Errorf("policy %s is not a valid policy", value)
+			}
+			conf.EvictionPolicy = strings.ToLower(value)
+			return nil
+		},
+	},
+	{
+		Name: "eviction-sample", Key: "EvictionSample", Mutable: true,
+		Get: func(conf Config) string { return strconv.FormatUint(uint64(conf.EvictionSample), 10) },
+		Set: func(conf *Config, value string) error {
+			n, err := parseUint(value, "eviction-sample")
+			if err != nil {
+				return err
+			}
+			conf.EvictionSample = uint(n)
+			return nil
+		},
+	},
+	{
+		Name: "forward-commands", Key: "ForwardCommand",
+		Get: func(conf Config) string { return formatBool(conf.ForwardCommand) },
+	},
+	{
+		Name: "latency-monitor-threshold", Key: "LatencyMonitorThreshold", Mutable: true,
+		Get: func(conf Config) string { return strconv.FormatUint(conf.LatencyThreshold, 10) },
+		Set: func(conf *Config, value string) error {
+			n, err := parseUint(value, "latency-monitor-threshold")
+			if err != nil {
+				return err
+			}
+			conf.LatencyThreshold = n
+			return nil
+		},
+	},
+	{
+		Name: "lfu-decay-time", Key: "LFUDecayTime", Mutable: true,
+		Get: func(conf Config) string { return strconv.FormatUint(uint64(conf.LFUDecayTime), 10) },
+		Set: func(conf *Config, value string) error {
+			n, err := parseUint(value, "lfu-decay-time")
+			if err != nil {
+				return err
+			}
+			conf.LFUDecayTime = uint(n)
+			return nil
+		},
+	},
+	{
+		Name: "lfu-log-factor", Key: "LFULogFactor", Mutable: true,
+		Get: func(conf Config) string { return strconv.FormatUint(uint64(conf.LFULogFactor), 10) },
+		Set: func(conf *Config, value string) error {
+			n, err := parseUint(value, "lfu-log-factor")
+			if err != nil {
+				return err
+			}
+			conf.LFULogFactor = uint(n)
+			return nil
+		},
+	},
+	{
+		Name: "log-format", Key: "LogFormat",
+		Get: func(conf Config) string { return conf.LogFormat },
+	},
+	{
+		Name: "log-level", Key: "LogLevel", Mutable: true,
+		Get: func(conf Config) string { return conf.LogLevel },
+		Set: func(conf *Config, value string) error {
+			if _, err := logger.ParseLevel(value); err != nil {
+				return err
+			}
+			conf.LogLevel = strings.ToLower(value)
+			return nil
+		},
+	},
+	{
+		Name: "log-output", Key: "LogOutput",
+		Get: func(conf Config) string { return conf.LogOutput },
+	},
+	{
+		Name: "max-memory", Key: "MaxMemory", Mutable: true,
+		Get: func(conf Config) string { return strconv.FormatUint(conf.MaxMemory, 10) },
+		Set: func(conf *Config, value string) error {
+			if n, err := strconv.ParseUint(value, 10, 64); err == nil {
+				conf.MaxMemory = n
+				return nil
+			}
+			if len(value) < 3 {
+				return errors.New("max-memory must be a number of bytes, or a number followed by kb, mb, gb, tb or pb")
+			}
+			n, err := internal.ParseMemory(value)
+			if err != nil {
+				return err
+			}
+			conf.MaxMemory = n
+			return nil
+		},
+	},
+	{
+		Name: "metrics-port", Key: "MetricsPort",
+		Get: func(conf Config) string { return strconv.FormatUint(uint64(conf.MetricsPort), 10) },
+	},
+	{
+		Name: "monitor-buffer-size", Key: "MonitorBufferSize",
+		Get: func(conf Config) string { return strconv.FormatUint(uint64(conf.MonitorBufferSize), 10) },
+	},
+	{
+		// The value is validated and normalised by the pubsub module when the parameter is applied.
+		Name: "notify-keyspace-events", Key: "KeyspaceEvents", Mutable: true,
+		Get: func(conf Config) string { return conf.KeyspaceEvents },
+		Set: func(conf *Config, value string) error {
+			conf.KeyspaceEvents = value
+			return nil
+		},
+	},
+	{
+		Name: "password", Key: "Password", Mutable: true,
+		Get: func(conf Config) string { return conf.Password },
+		Set: func(conf *Config, value string) error {
+			conf.Password = value
+			return nil
+		},
+	},
+	{
+		Name: "port", Key: "Port",
+		Get: func(conf Config) string { return strconv.FormatUint(uint64(conf.Port), 10) },
+	},
+	{
+		Name: "repl-backlog-size", Key: "ReplBacklogSize",
+		Get: func(conf Config) string { return strconv.FormatUint(conf.ReplBacklogSize, 10) },
+	},
+	{
+		Name: "replicaof", Key: "ReplicaOf",
+		Get: func(conf Config) string { return conf.ReplicaOf },
+	},
+	{
+		Name: "require-pass", Key: "RequirePass", Mutable: true,
+		Get: func(conf Config) string { return formatBool(conf.RequirePass) },
+		Set: func(conf *Config, value string) error {
+			b, err := parseBool(value, "require-pass")
+			if err != nil {
+				return err
+			}
+			conf.RequirePass = b
+			return nil
+		},
+	},
+	{
+		Name: "server-id", Key: "ServerId",
+		Get: func(conf Config) string { return conf.ServerID },
+	},
+	{
+		Name: "slowlog-log-slower-than", Key: "SlowlogLogSlowerThan", Mutable: true,
+		Get: func(conf Config) string { return strconv.FormatInt(conf.SlowlogThreshold, 10) },
+		Set: func(conf *Config, value string) error {
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return errors.New("slowlog-log-slower-than must be an integer")
+			}
+			conf.SlowlogThreshold = n
+			return nil
+		},
+	},
+	{
+		Name: "slowlog-max-len", Key: "SlowlogMaxLen", Mutable: true,
+		Get: func(conf Config) string { return strconv.FormatUint(uint64(conf.SlowlogMaxLen), 10) },
+		Set: func(conf *Config, value string) error {
+			n, err := parseUint(value, "slowlog-max-len")
+			if err != nil {
+				return err
+			}
+			conf.SlowlogMaxLen = uint(n)
+			return nil
+		},
+	},
+	{
+		Name: "snapshot-interval", Key: "SnapshotInterval", Mutable: true,
+		Get: func(conf Config) string { return conf.SnapshotInterval.String() },
+		Set: func(conf *Config, value string) error {
+			d, err := time.ParseDuration(value)
+			if err != nil || d < 0 {
+				return errors.New("snapshot-interval must be a positive duration such as 30s or 5m")
+			}
+			conf.SnapshotInterval = d
+			return nil
+		},
+	},
+	{
+		Name: "snapshot-threshold", Key: "SnapshotThreshold", Mutable: true,
+		Get: func(conf Config) string { return strconv.FormatUint(conf.SnapShotThreshold, 10) },
+		Set: func(conf *Config, value string) error {
+			n, err := parseUint(value, "snapshot-threshold")
+			if err != nil {
+				return err
+			}
+			conf.SnapShotThreshold = n
+			return nil
+		},
+	},
+	{
+		Name: "tls", Key: "TLS",
+		Get: func(conf Config) string { return formatBool(conf.TLS) },
+	},
+}
+
+// Parameters returns the configuration parameters, sorted by name.
+func Parameters() []Parameter {
+	return slices.Clone(parameters)
+}
+
+// GetParameter returns the configuration parameter with the given name.
+func GetParameter(name string) (Parameter, bool) {
+	i := slices.IndexFunc(parameters, func(p Parameter) bool {
+		return strings.EqualFold(p.Name, name)
+	})
+	if i == -1 {
+		return Parameter{}, false
+	}
+	return parameters[i], true
+}
+
+// Validate checks the constraints between the parameters of the configuration.
+func Validate(conf Config) error {
+	if conf.RequirePass && conf.Password == "" {
+		return errors.New("password cannot be empty if requirePass is true")
+	}
+	if _, err := logger.ParseLevel(conf.LogLevel); conf.LogLevel != "" && err != nil {
+		return err
+	}
+	if !slices.Contains([]string{"", logger.FormatText, logger.FormatJSON}, strings.ToLower(conf.LogFormat)) {
+		return fmt.Errorf("log format must be '%s' or '%s'", logger.FormatText, logger.FormatJSON)
+	}
+	return nil
+}
+
+func formatBool(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+func parseBool(value string, name string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "yes", "true":
+		return true, nil
+	case "no", "false":
+		return false, nil
+	}
+	return false, fmt.Errorf("%s must be 'yes' or 'no'", name)
+}
+
+func parseUint(value string, name string) (uint64, error) {
+	n, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a positive integer", name)
+	}
+	return n, nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_test
+
+import (
+	"github.com/echovault/echovault/internal/config"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func Test_Parameters(t *testing.T) {
+	parameters := config.Parameters()
+	if !slices.IsSortedFunc(parameters, func(a, b config.Parameter) int {
+		return strings.Compare(a.Name, b.Name)
+	}) {
+		t.Error("expected the parameters to be sorted by name")
+	}
+	for _, parameter := range parameters {
+		if parameter.Mutable != (parameter.Set != nil) {
+			t.Errorf("expected parameter %s to have a setter only when mutable", parameter.Name)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		parameter string
+		value     string
+		expected  string
+		wantErr   string
+	}{
+		{name: "1. Set max memory in bytes", parameter: "max-memory", value: "1048576", expected: "1048576"},
+		{name: "2. Set max memory with a unit", parameter: "max-memory", value: "2mb", expected: "2097152"},
+		{name: "3. Reject max memory with an unknown unit", parameter: "max-memory", value: "2xb", wantErr: "not supported"},
+		{name: "4. Set the eviction policy", parameter: "EVICTION-POLICY", value: "ALLKEYS-LRU", expected: "allkeys-lru"},
+		{name: "5. Reject an unknown eviction policy", parameter: "eviction-policy", value: "lru", wantErr: "not a valid policy"},
+		{name: "6. Set the AOF sync strategy", parameter: "aof-sync-strategy", value: "always", expected: "always"},
+		{name: "7. Reject an unknown AOF sync strategy", parameter: "aof-sync-strategy", value: "sometimes", wantErr: "must be"},
+		{name: "8. Set the snapshot interval", parameter: "snapshot-interval", value: "30s", expected: "30s"},
+		{name: "9. Reject a negative snapshot interval", parameter: "snapshot-interval", value: "-1s", wantErr: "positive duration"},
+		{name: "10. Set require pass", parameter: "require-pass", value: "yes", expected: "yes"},
+		{name: "11. Reject an invalid boolean", parameter: "require-pass", value: "maybe", wantErr: "'yes' or 'no'"},
+		{name: "12. Reject a negative slowlog max length", parameter: "slowlog-max-len", value: "-1", wantErr: "positive integer"},
+		{name: "13. Set the log level", parameter: "log-level", value: "DEBUG", expected: "debug"},
+		{name: "14. Reject an unknown log level", parameter: "log-level", value: "verbose", wantErr: "log level must be"},
+	}
+
+	for _, test := range tests {
+		parameter, ok := config.GetParameter(test.parameter)
+		if !ok {
+			t.Errorf("%s: parameter %s not found", test.name, test.parameter)
+			continue
+		}
+		conf := config.DefaultConfig()
+		err := parameter.Set(&conf, test.value)
+		if test.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("%s: expected error containing \"%s\", got %v", test.name, test.wantErr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if got := parameter.Get(conf); got != test.expected {
+			t.Errorf("%s: expected value \"%s\", got \"%s\"", test.name, test.expected, got)
+		}
+	}
+
+	if _, ok := config.GetParameter("unknown"); ok {
+		t.Error("expected unknown parameter not to be found")
+	}
+
+	conf := config.DefaultConfig()
+	conf.RequirePass = true
+	if err := config.Validate(conf); err == nil {
+		t.Error("expected require-pass without a password to be invalid")
+	}
+
+	conf = config.DefaultConfig()
+	conf.LogFormat = "xml"
+	if err := config.Validate(conf); err == nil {
+		t.Error("expected an unknown log format to be invalid")
+	}
+}
@@ -17,16 +17,19 @@ package constants
 const Version = "0.10.1" // Next EchoVault version. Update this before each release.
 
 const (
-	ACLModule        = "acl"
-	AdminModule      = "admin"
-	ConnectionModule = "connection"
-	GenericModule    = "generic"
-	HashModule       = "hash"
-	ListModule       = "list"
-	PubSubModule     = "pubsub"
-	SetModule        = "set"
-	SortedSetModule  = "sortedset"
-	StringModule     = "string"
+	ACLModule         = "acl"
+	AdminModule       = "admin"
+	CDCModule         = "cdc"
+	ConnectionModule  = "connection"
+	GenericModule     = "generic"
+	HashModule        = "hash"
+	ListModule        = "list"
+	PubSubModule      = "pubsub"
+	ReplicationModule = "replication"
+	SetModule         = "set"
+	SortedSetModule   = "sortedset"
+	StringModule      = "string"
+	XReplModule       = "xrepl"
 )
 
 const (
@@ -53,6 +56,39 @@ const (
 	WriteCategory       = "write"
 )
 
+// Command flags reported by COMMAND INFO.
+const (
+	WriteFlag           = "write"
+	ReadOnlyFlag        = "readonly"
+	DenyOOMFlag         = "denyoom"
+	AdminFlag           = "admin"
+	PubSubFlag          = "pubsub"
+	NoScriptFlag        = "noscript"
+	BlockingFlag        = "blocking"
+	LoadingFlag         = "loading"
+	StaleFlag           = "stale"
+	SkipMonitorFlag     = "skip_monitor"
+	SkipSlowlogFlag     = "skip_slowlog"
+	FastFlag            = "fast"
+	NoAuthFlag          = "no_auth"
+	MayReplicateFlag    = "may_replicate"
+	NoMandatoryKeysFlag = "no_mandatory_keys"
+	MovableKeysFlag     = "movablekeys"
+)
+
+// Key spec flags reported by COMMAND INFO.
+const (
+	KeySpecRO         = "RO"         // The command reads the value of the key.
+	KeySpecRW         = "RW"         // The command reads and modifies the value of the key.
+	KeySpecOW         = "OW"         // The command overwrites the value of the key without reading it.
+	KeySpecRM         = "RM"         // The command deletes the key.
+	KeySpecAccess     = "access"     // The command returns or uses the user data stored at the key.
+	KeySpecUpdate     = "update"     // The command updates the existing data at the key.
+	KeySpecInsert     = "insert"     // The command only adds data to the key.
+	KeySpecDelete     = "delete"     // The command deletes data from the key.
+	KeySpecIncomplete = "incomplete" // The key positions of the spec might not include every key of the command.
+)
+
 const (
 	OkResponse        = "+OK\r\n"
 	WrongArgsResponse = "wrong number of arguments"
@@ -66,4 +102,5 @@ const (
 	VolatileLFU    = "volatile-lfu"
 	AllKeysRandom  = "allkeys-random"
 	VolatileRandom = "volatile-random"
+	VolatileTTL    = "volatile-ttl"
 )
@@ -0,0 +1,93 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eviction
+
+import (
+	"math/rand"
+	"time"
+)
+
+// The access data of a key is held in 24 bits, like the lru field of a Redis object.
+//
+// With an LRU policy, the access data is the LRU clock of the last access, in seconds.
+// With an LFU policy, the upper 16 bits hold the time of the last decrement of the access counter in minutes,
+// and the lower 8 bits hold a logarithmic access counter.
+const (
+	LRUClockMax        = 1<<24 - 1   // The maximum value of the LRU clock before it wraps around.
+	LRUClockResolution = time.Second // The resolution of the LRU clock.
+
+	LFUInitValue = 5 // The access counter of new keys, so that they're not evicted before they can be accessed.
+)
+
+// LRUClock returns the LRU clock at the given time.
+func LRUClock(now time.Time) uint32 {
+	return uint32(now.UnixNano()/int64(LRUClockResolution)) & LRUClockMax
+}
+
+// IdleTime returns the time since the access recorded by the LRU clock.
+func IdleTime(clock uint32, now time.Time) time.Duration {
+	current := LRUClock(now)
+	if current >= clock {
+		return time.Duration(current-clock) * LRUClockResolution
+	}
+	// The clock wrapped around since the access.
+	return time.Duration(current+(LRUClockMax+1-clock)) * LRUClockResolution
+}
+
+// NewLFU returns the LFU access data of a new key.
+func NewLFU(now time.Time) uint32 {
+	return lfuMinutes(now)<<8 | LFUInitValue
+}
+
+// TouchLFU returns the LFU access data after an access to the key. The counter is first decayed by one for every
+// decayTime minutes since it was last decremented, then incremented with a probability that gets lower as the
+// counter grows. A higher logFactor makes the counter grow slower, so that it saturates after more accesses.
+func TouchLFU(access uint32, now time.Time, logFactor int, decayTime int) uint32 {
+	counter := LFUFrequency(access, now, decayTime)
+	if counter < 255 {
+		base := float64(counter) - LFUInitValue
+		if base < 0 {
+			base = 0
+		}
+		if rand.Float64() < 1/(base*float64(logFactor)+1) {
+			counter += 1
+		}
+	}
+	return lfuMinutes(now)<<8 | uint32(counter)
+}
+
+// LFUFrequency returns the access counter of the LFU access data, decayed by one for every decayTime minutes
+// since it was last decremented. A decayTime of 0 disables the decay.
+func LFUFrequency(access uint32, now time.Time, decayTime int) uint8 {
+	counter := uint8(access & 0xFF)
+	if decayTime <= 0 {
+		return counter
+	}
+	last, current := access>>8, lfuMinutes(now)
+	elapsed := current - last
+	if current < last {
+		// The minutes wrapped around since the last decrement.
+		elapsed = current + (0x10000 - last)
+	}
+	periods := elapsed / uint32(decayTime)
+	if periods >= uint32(counter) {
+		return 0
+	}
+	return counter - uint8(periods)
+}
+
+func lfuMinutes(now time.Time) uint32 {
+	return uint32(now.Unix()/60) & 0xFFFF
+}
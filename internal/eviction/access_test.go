@@ -0,0 +1,72 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eviction_test
+
+import (
+	"github.com/echovault/echovault/internal/eviction"
+	"testing"
+	"time"
+)
+
+func Test_IdleTime(t *testing.T) {
+	now := time.Now()
+	clock := eviction.LRUClock(now.Add(-90 * time.Second))
+	if idle := eviction.IdleTime(clock, now); idle != 90*time.Second {
+		t.Errorf("expected idle time 90s, got %s", idle)
+	}
+
+	// The clock wraps around after LRUClockMax seconds.
+	wrapped := time.Unix(3*(eviction.LRUClockMax+1)+5, 0)
+	if idle := eviction.IdleTime(eviction.LRUClockMax-10, wrapped); idle != 16*time.Second {
+		t.Errorf("expected idle time 16s across the clock wrap, got %s", idle)
+	}
+}
+
+func Test_LFU(t *testing.T) {
+	now := time.Now()
+
+	access := eviction.NewLFU(now)
+	if freq := eviction.LFUFrequency(access, now, 1); freq != eviction.LFUInitValue {
+		t.Errorf("expected new key to have frequency %d, got %d", eviction.LFUInitValue, freq)
+	}
+
+	// With a log factor of 0, every access increments the counter until it saturates.
+	for i := 0; i < 300; i++ {
+		access = eviction.TouchLFU(access, now, 0, 1)
+	}
+	if freq := eviction.LFUFrequency(access, now, 1); freq != 255 {
+		t.Errorf("expected frequency to saturate at 255, got %d", freq)
+	}
+
+	// With a higher log factor, the counter grows logarithmically.
+	slow := eviction.NewLFU(now)
+	for i := 0; i < 1000; i++ {
+		slow = eviction.TouchLFU(slow, now, 10, 1)
+	}
+	if freq := eviction.LFUFrequency(slow, now, 1); freq <= eviction.LFUInitValue || freq >= 100 {
+		t.Errorf("expected frequency between %d and 100 after 1000 accesses, got %d", eviction.LFUInitValue, freq)
+	}
+
+	// The counter is decremented once every decay time.
+	if freq := eviction.LFUFrequency(access, now.Add(10*time.Minute), 1); freq != 245 {
+		t.Errorf("expected frequency to decay by 10 after 10 minutes, got %d", freq)
+	}
+	if freq := eviction.LFUFrequency(access, now.Add(10*time.Minute), 0); freq != 255 {
+		t.Errorf("expected frequency not to decay with a decay time of 0, got %d", freq)
+	}
+	if freq := eviction.LFUFrequency(eviction.NewLFU(now), now.Add(time.Hour), 1); freq != 0 {
+		t.Errorf("expected frequency to decay to 0, got %d", freq)
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eviction
+
+import (
+	"container/heap"
+	"math/rand"
+	"time"
+)
+
+type EntryExpiry struct {
+	key      string    // The key, matching the key in the store
+	expireAt time.Time // The time when the key expires
+	index    int       // The index of the entry in the heap
+}
+
+// ExpiryIndex holds the keys of a database that have an expiry time, ordered by their expiry time.
+// The key that expires first is at the root of the heap. Adding, updating and removing a key is O(log n).
+type ExpiryIndex struct {
+	keys    map[string]*EntryExpiry
+	entries []*EntryExpiry
+}
+
+func NewExpiryIndex() *ExpiryIndex {
+	index := ExpiryIndex{
+		keys:    make(map[string]*EntryExpiry),
+		entries: make([]*EntryExpiry, 0),
+	}
+	heap.Init(&index)
+	return &index
+}
+
+func (index *ExpiryIndex) Len() int {
+	return len(index.entries)
+}
+
+func (index *ExpiryIndex) Less(i, j int) bool {
+	return index.entries[i].expireAt.Before(index.entries[j].expireAt)
+}
+
+func (index *ExpiryIndex) Swap(i, j int) {
+	index.entries[i], index.entries[j] = index.entries[j], index.entries[i]
+	index.entries[i].index = i
+	index.entries[j].index = j
+}
+
+func (index *ExpiryIndex) Push(entry any) {
+	e := entry.(*EntryExpiry)
+	e.index = len(index.entries)
+	index.entries = append(index.entries, e)
+	index.keys[e.key] = e
+}
+
+func (index *ExpiryIndex) Pop() any {
+	old := index.entries
+	n := len(old)
+	entry := old[n-1]
+	old[n-1] = nil
+	entry.index = -1
+	index.entries = old[0 : n-1]
+	delete(index.keys, entry.key)
+	return entry
+}
+
+// Set adds the key to the index or updates its expiry time. A zero expiry time removes the key.
+func (index *ExpiryIndex) Set(key string, expireAt time.Time) {
+	if expireAt.IsZero() {
+		index.Delete(key)
+		return
+	}
+	if entry, ok := index.keys[key]; ok {
+		entry.expireAt = expireAt
+		heap.Fix(index, entry.index)
+		return
+	}
+	heap.Push(index, &EntryExpiry{key: key, expireAt: expireAt})
+}
+
+// Delete removes the key from the index.
+func (index *ExpiryIndex) Delete(key string) {
+	if entry, ok := index.keys[key]; ok {
+		heap.Remove(index, entry.index)
+	}
+}
+
+// Contains returns true when the key is in the index.
+func (index *ExpiryIndex) Contains(key string) bool {
+	_, ok := index.keys[key]
+	return ok
+}
+
+// Next returns the key that expires first and its expiry time. It returns false when the index is empty.
+func (index *ExpiryIndex) Next() (string, time.Time, bool) {
+	if len(index.entries) == 0 {
+		return "", time.Time{}, false
+	}
+	return index.entries[0].key, index.entries[0].expireAt, true
+}
+
+// PopExpired removes and returns up to limit keys that expired at or before now, in the order they expired.
+func (index *ExpiryIndex) PopExpired(now time.Time, limit int) []string {
+	keys := make([]string, 0, limit)
+	for len(keys) < limit && len(index.entries) > 0 && !index.entries[0].expireAt.After(now) {
+		keys = append(keys, heap.Pop(index).(*EntryExpiry).key)
+	}
+	return keys
+}
+
+// RandomKey returns a random key from the index. It returns false when the index is empty.
+func (index *ExpiryIndex) RandomKey() (string, bool) {
+	if len(index.entries) == 0 {
+		return "", false
+	}
+	return index.entries[rand.Intn(len(index.entries))].key, true
+}
@@ -0,0 +1,69 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eviction_test
+
+import (
+	"github.com/echovault/echovault/internal/eviction"
+	"slices"
+	"testing"
+	"time"
+)
+
+func Test_ExpiryIndex(t *testing.T) {
+	now := time.Now()
+
+	index := eviction.NewExpiryIndex()
+	index.Set("key1", now.Add(3*time.Second))
+	index.Set("key2", now.Add(-1*time.Second))
+	index.Set("key3", now.Add(-3*time.Second))
+	index.Set("key4", now.Add(5*time.Second))
+	index.Set("key5", now.Add(-2*time.Second))
+
+	// Update the expiry time of a key, and remove the expiry time of another.
+	index.Set("key4", now.Add(-4*time.Second))
+	index.Set("key1", time.Time{})
+	if index.Contains("key1") || index.Len() != 4 {
+		t.Errorf("expected key1 to be removed from the index, got length %d", index.Len())
+	}
+
+	if key, expireAt, ok := index.Next(); !ok || key != "key4" || !expireAt.Equal(now.Add(-4*time.Second)) {
+		t.Errorf("expected key4 to expire first, got %s at %v", key, expireAt)
+	}
+
+	index.Delete("key5")
+	if keys := index.PopExpired(now, 10); !slices.Equal(keys, []string{"key4", "key3", "key2"}) {
+		t.Errorf("expected expired keys [key4 key3 key2], got %v", keys)
+	}
+	if index.Len() != 0 {
+		t.Errorf("expected index to be empty, got length %d", index.Len())
+	}
+	if _, ok := index.RandomKey(); ok {
+		t.Error("expected no random key from an empty index")
+	}
+
+	// PopExpired returns at most limit keys and leaves the keys that have not expired.
+	for i, key := range []string{"key1", "key2", "key3", "key4"} {
+		index.Set(key, now.Add(time.Duration(i-2)*time.Second))
+	}
+	if keys := index.PopExpired(now, 2); !slices.Equal(keys, []string{"key1", "key2"}) {
+		t.Errorf("expected expired keys [key1 key2], got %v", keys)
+	}
+	if keys := index.PopExpired(now, 2); !slices.Equal(keys, []string{"key3"}) {
+		t.Errorf("expected expired keys [key3], got %v", keys)
+	}
+	if key, ok := index.RandomKey(); !ok || key != "key4" {
+		t.Errorf("expected random key key4, got %s", key)
+	}
+}
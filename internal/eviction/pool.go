@@ -0,0 +1,87 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eviction
+
+import (
+	"slices"
+)
+
+// PoolSize is the number of eviction candidates kept in the eviction pool.
+const PoolSize = 16
+
+type PoolEntry struct {
+	Database int    // The database of the key
+	Key      string // The key, matching the key in the store
+	Idle     uint64 // The score of the key. Keys with a higher score are evicted first
+}
+
+// Pool holds the best candidates for eviction found while sampling the keyspace, ordered by their score.
+// Candidates are kept across evictions, so that every sample improves the choice of the next key to evict.
+// This approximates evicting the key with the highest score in the keyspace without keeping every key ordered.
+type Pool struct {
+	entries []PoolEntry
+}
+
+func NewPool() *Pool {
+	return &Pool{entries: make([]PoolEntry, 0, PoolSize)}
+}
+
+func (pool *Pool) Len() int {
+	return len(pool.entries)
+}
+
+// Insert adds the key to the pool when there's space left, or when its score is higher than the score of a key
+// in the pool. The key with the lowest score is then dropped from the pool.
+func (pool *Pool) Insert(database int, key string, idle uint64) {
+	if i := slices.IndexFunc(pool.entries, func(entry PoolEntry) bool {
+		return entry.Database == database && entry.Key == key
+	}); i != -1 {
+		pool.entries = slices.Delete(pool.entries, i, i+1)
+	}
+	if len(pool.entries) == PoolSize {
+		if idle <= pool.entries[0].Idle {
+			return
+		}
+		pool.entries = slices.Delete(pool.entries, 0, 1)
+	}
+	i, _ := slices.BinarySearchFunc(pool.entries, idle, func(entry PoolEntry, idle uint64) int {
+		switch {
+		case entry.Idle < idle:
+			return -1
+		case entry.Idle > idle:
+			return 1
+		}
+		return 0
+	})
+	pool.entries = slices.Insert(pool.entries, i, PoolEntry{Database: database, Key: key, Idle: idle})
+}
+
+// Pop removes and returns the key with the highest score. It returns false when the pool is empty.
+// The key might have been deleted since it was sampled, so the caller must check that it still exists.
+func (pool *Pool) Pop() (PoolEntry, bool) {
+	if len(pool.entries) == 0 {
+		return PoolEntry{}, false
+	}
+	entry := pool.entries[len(pool.entries)-1]
+	pool.entries = pool.entries[:len(pool.entries)-1]
+	return entry, true
+}
+
+// Clear removes every key of the database from the pool. A database of -1 removes every key.
+func (pool *Pool) Clear(database int) {
+	pool.entries = slices.DeleteFunc(pool.entries, func(entry PoolEntry) bool {
+		return database == -1 || entry.Database == database
+	})
+}
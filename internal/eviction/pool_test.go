@@ -0,0 +1,53 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eviction_test
+
+import (
+	"fmt"
+	"github.com/echovault/echovault/internal/eviction"
+	"testing"
+)
+
+func Test_Pool(t *testing.T) {
+	pool := eviction.NewPool()
+
+	for i := 0; i < 2*eviction.PoolSize; i++ {
+		pool.Insert(i%2, fmt.Sprintf("key%d", i), uint64(i))
+	}
+	if pool.Len() != eviction.PoolSize {
+		t.Errorf("expected pool to hold %d keys, got %d", eviction.PoolSize, pool.Len())
+	}
+
+	// Inserting a key with a lower score than every key in a full pool does nothing.
+	pool.Insert(0, "low", 0)
+	// Inserting a key that's already in the pool updates its score.
+	pool.Insert(1, "key31", 100)
+	pool.Clear(0)
+
+	expected := []string{"key31", "key29", "key27", "key25"}
+	for _, key := range expected {
+		entry, ok := pool.Pop()
+		if !ok || entry.Key != key || entry.Database != 1 {
+			t.Errorf("expected %s in database 1, got %+v", key, entry)
+		}
+	}
+	if pool.Len() != eviction.PoolSize/2-len(expected) {
+		t.Errorf("expected %d keys left, got %d", eviction.PoolSize/2-len(expected), pool.Len())
+	}
+	pool.Clear(-1)
+	if _, ok := pool.Pop(); ok {
+		t.Error("expected pool to be empty")
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package latency records the latency spikes of internal events such as AOF fsyncs and snapshots.
+package latency
+
+import (
+	"github.com/echovault/echovault/internal"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// HistoryLen is the number of samples kept for each event.
+// Samples are kept at a resolution of one second, so this is the history of the latest spikes.
+const HistoryLen = 160
+
+// The events recorded by the latency monitor.
+const (
+	EventCommand       = "command"        // The execution of a command.
+	EventAOFFsync      = "aof-fsync"      // An fsync of the append-only log.
+	EventSnapshot      = "snapshot"       // A snapshot of the dataset.
+	EventEvictionCycle = "eviction-cycle" // A cycle evicting keys to free memory.
+	EventExpireCycle   = "expire-cycle"   // An active expiry cycle.
+)
+
+type Monitor struct {
+	mutex     sync.Mutex
+	threshold atomic.Int64 // Latencies below the threshold are not recorded. 0 disables the monitor.
+	events    map[string]*event
+}
+
+type event struct {
+	history []internal.LatencySample // The samples, oldest first.
+	max     time.Duration            // The highest latency recorded.
+}
+
+func WithThreshold(threshold time.Duration) func(monitor *Monitor) {
+	return func(monitor *Monitor) {
+		monitor.threshold.Store(int64(threshold))
+	}
+}
+
+func NewMonitor(options ...func(monitor *Monitor)) *Monitor {
+	monitor := &Monitor{
+		events: make(map[string]*event),
+	}
+	for _, option := range options {
+		option(monitor)
+	}
+	return monitor
+}
+
+// SetThreshold changes the latency from which events are recorded. 0 disables the monitor.
+func (monitor *Monitor) SetThreshold(threshold time.Duration) {
+	monitor.threshold.Store(int64(threshold))
+}
+
+// Observe records the latency of the event when it reaches the threshold.
+// Latencies recorded within the same second are merged into a single sample holding the highest latency.
+// The threshold is checked before locking the monitor, as most events are below it.
+func (monitor *Monitor) Observe(name string, timestamp time.Time, latency time.Duration) {
+	if threshold := time.Duration(monitor.threshold.Load()); threshold <= 0 || latency < threshold {
+		return
+	}
+
+	monitor.mutex.Lock()
+	defer monitor.mutex.Unlock()
+
+	e, ok := monitor.events[name]
+	if !ok {
+		e = &event{}
+		monitor.events[name] = e
+	}
+	e.max = max(e.max, latency)
+
+	timestamp = timestamp.Truncate(time.Second)
+	if n := len(e.history); n > 0 && e.history[n-1].Timestamp.Equal(timestamp) {
+		e.history[n-1].Latency = max(e.history[n-1].Latency, latency)
+		return
+	}
+	e.history = append(e.history, internal.LatencySample{Timestamp: timestamp, Latency: latency})
+	if len(e.history) > HistoryLen {
+		e.history = append([]internal.LatencySample(nil), e.history[len(e.history)-HistoryLen:]...)
+	}
+}
+
+// Latest returns the latest and highest latency of each recorded event, ordered by name.
+func (monitor *Monitor) Latest() []internal.LatencyEvent {
+	monitor.mutex.Lock()
+	defer monitor.mutex.Unlock()
+
+	res := make([]internal.LatencyEvent, 0, len(monitor.events))
+	for name, e := range monitor.events {
+		latest := e.history[len(e.history)-1]
+		res = append(res, internal.LatencyEvent{
+			Name:      name,
+			Timestamp: latest.Timestamp,
+			Latest:    latest.Latency,
+			Max:       e.max,
+		})
+	}
+	slices.SortFunc(res, func(a, b internal.LatencyEvent) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return res
+}
+
+// History returns the samples recorded for the event, oldest first.
+func (monitor *Monitor) History(name string) []internal.LatencySample {
+	monitor.mutex.Lock()
+	defer monitor.mutex.Unlock()
+
+	e, ok := monitor.events[name]
+	if !ok {
+		return []internal.LatencySample{}
+	}
+	return slices.Clone(e.history)
+}
+
+// Reset removes the samples of the given events, or of every event when none is given.
+// It returns the number of events reset.
+func (monitor *Monitor) Reset(names ...string) int {
+	monitor.mutex.Lock()
+	defer monitor.mutex.Unlock()
+
+	if len(names) == 0 {
+		count := len(monitor.events)
+		monitor.events = make(map[string]*event)
+		return count
+	}
+
+	count := 0
+	for _, name := range names {
+		if _, ok := monitor.events[name]; ok {
+			delete(monitor.events, name)
+			count++
+		}
+	}
+	return count
+}
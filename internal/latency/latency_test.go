@@ -0,0 +1,72 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package latency_test
+
+import (
+	"github.com/echovault/echovault/internal/latency"
+	"testing"
+	"time"
+)
+
+func Test_Monitor(t *testing.T) {
+	monitor := latency.NewMonitor(latency.WithThreshold(10 * time.Millisecond))
+	now := time.Unix(1000, 0)
+
+	monitor.Observe(latency.EventAOFFsync, now, 5*time.Millisecond)
+	monitor.Observe(latency.EventAOFFsync, now, 20*time.Millisecond)
+	monitor.Observe(latency.EventAOFFsync, now.Add(500*time.Millisecond), 30*time.Millisecond)
+	monitor.Observe(latency.EventAOFFsync, now.Add(2*time.Second), 15*time.Millisecond)
+	monitor.Observe(latency.EventSnapshot, now, 100*time.Millisecond)
+
+	history := monitor.History(latency.EventAOFFsync)
+	if len(history) != 2 {
+		t.Fatalf("expected 2 samples, got %d", len(history))
+	}
+	if history[0].Latency != 30*time.Millisecond || !history[0].Timestamp.Equal(now) {
+		t.Errorf("expected samples in the same second to be merged, got %+v", history[0])
+	}
+
+	events := monitor.Latest()
+	if len(events) != 2 || events[0].Name != latency.EventAOFFsync || events[1].Name != latency.EventSnapshot {
+		t.Fatalf("expected the aof-fsync and snapshot events, got %+v", events)
+	}
+	if events[0].Latest != 15*time.Millisecond || events[0].Max != 30*time.Millisecond ||
+		!events[0].Timestamp.Equal(now.Add(2*time.Second)) {
+		t.Errorf("unexpected latest aof-fsync event %+v", events[0])
+	}
+
+	for i := 0; i < latency.HistoryLen+10; i++ {
+		monitor.Observe(latency.EventExpireCycle, now.Add(time.Duration(i)*time.Second), time.Second)
+	}
+	if n := len(monitor.History(latency.EventExpireCycle)); n != latency.HistoryLen {
+		t.Errorf("expected the history to be capped at %d samples, got %d", latency.HistoryLen, n)
+	}
+
+	if n := monitor.Reset(latency.EventSnapshot, "unknown"); n != 1 {
+		t.Errorf("expected 1 event to be reset, got %d", n)
+	}
+	if n := monitor.Reset(); n != 2 {
+		t.Errorf("expected 2 events to be reset, got %d", n)
+	}
+	if len(monitor.Latest()) != 0 {
+		t.Errorf("expected no events after a reset")
+	}
+
+	disabled := latency.NewMonitor()
+	disabled.Observe(latency.EventSnapshot, now, time.Hour)
+	if len(disabled.Latest()) != 0 {
+		t.Errorf("expected a threshold of 0 to disable the monitor")
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Keys of the structured fields attached to log entries.
+const (
+	ConnIDKey   = "conn_id"
+	DatabaseKey = "database"
+	CommandKey  = "command"
+	ErrorKey    = "error"
+)
+
+// Logger is a leveled, structured logger. The args of each method are alternating key/value pairs
+// attached to the entry as fields.
+type Logger interface {
+	Debug(msg string, args ...any)
+	Info(msg string, args ...any)
+	Warn(msg string, args ...any)
+	Error(msg string, args ...any)
+	// With returns a logger that attaches the given key/value pairs to every entry.
+	With(args ...any) Logger
+}
+
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+// New returns a logger that writes entries in the given format ("text" or "json") to w.
+// Entries below level are discarded. The level can be changed later through the LevelVar.
+func New(w io.Writer, format string, level *slog.LevelVar) (Logger, error) {
+	if level == nil {
+		level = new(slog.LevelVar)
+	}
+	options := &slog.HandlerOptions{Level: level}
+	switch strings.ToLower(format) {
+	case FormatText, "":
+		return &slogLogger{logger: slog.New(slog.NewTextHandler(w, options))}, nil
+	case FormatJSON:
+		return &slogLogger{logger: slog.New(slog.NewJSONHandler(w, options))}, nil
+	}
+	return nil, fmt.Errorf("log format must be '%s' or '%s'", FormatText, FormatJSON)
+}
+
+// Default returns a logger that writes text entries of level info and above to stderr.
+func Default() Logger {
+	l, _ := New(os.Stderr, FormatText, nil)
+	return l
+}
+
+// Discard returns a logger that drops every entry.
+func Discard() Logger {
+	return &slogLogger{logger: slog.New(discardHandler{})}
+}
+
+func (l *slogLogger) Debug(msg string, args ...any) { l.logger.Debug(msg, args...) }
+func (l *slogLogger) Info(msg string, args ...any)  { l.logger.Info(msg, args...) }
+func (l *slogLogger) Warn(msg string, args ...any)  { l.logger.Warn(msg, args...) }
+func (l *slogLogger) Error(msg string, args ...any) { l.logger.Error(msg, args...) }
+
+func (l *slogLogger) With(args ...any) Logger {
+	return &slogLogger{logger: l.logger.With(args...)}
+}
+
+type leveledLogger struct {
+	logger Logger
+	level  *slog.LevelVar
+}
+
+// Leveled wraps a logger so that entries below the current value of level are discarded before
+// they reach it. It is used for loggers provided by the embedding application, so that the level
+// set through the config also applies to them.
+func Leveled(logger Logger, level *slog.LevelVar) Logger {
+	return &leveledLogger{logger: logger, level: level}
+}
+
+func (l *leveledLogger) log(level slog.Level, f func(msg string, args ...any), msg string, args []any) {
+	if level >= l.level.Level() {
+		f(msg, args...)
+	}
+}
+
+func (l *leveledLogger) Debug(msg string, args ...any) {
+	l.log(slog.LevelDebug, l.logger.Debug, msg, args)
+}
+
+func (l *leveledLogger) Info(msg string, args ...any) {
+	l.log(slog.LevelInfo, l.logger.Info, msg, args)
+}
+
+func (l *leveledLogger) Warn(msg string, args ...any) {
+	l.log(slog.LevelWarn, l.logger.Warn, msg, args)
+}
+
+func (l *leveledLogger) Error(msg string, args ...any) {
+	l.log(slog.LevelError, l.logger.Error, msg, args)
+}
+
+func (l *leveledLogger) With(args ...any) Logger {
+	return &leveledLogger{logger: l.logger.With(args...), level: l.level}
+}
+
+// ParseLevel parses one of the levels "debug", "info", "warn" (or "warning") and "error".
+func ParseLevel(s string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return 0, errors.New("log level must be 'debug', 'info', 'warn' or 'error'")
+}
+
+// Open returns the writer for the log output: "stderr", "stdout" or the path of a file that entries
+// are appended to. The returned closer is nil for the standard streams.
+func Open(output string) (io.Writer, io.Closer, error) {
+	switch strings.ToLower(output) {
+	case "stderr", "":
+		return os.Stderr, nil, nil
+	case "stdout":
+		return os.Stdout, nil, nil
+	}
+	f, err := os.OpenFile(output, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, f, nil
+}
+
+// Writer returns a writer that logs each line written to it as an entry of the logger. It bridges the
+// dependencies that write their logs to an io.Writer or a standard library logger, such as raft and memberlist.
+// The level is read from a leading tag like "[WARN]", lines without one are logged at info level.
+func Writer(logger Logger) io.Writer {
+	return &lineWriter{logger: logger}
+}
+
+type lineWriter struct {
+	logger Logger
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	for _, line := range strings.Split(string(p), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		log := w.logger.Info
+		if strings.HasPrefix(line, "[") {
+			if end := strings.Index(line, "]"); end > 0 {
+				switch line[1:end] {
+				case "TRACE", "DEBUG":
+					log = w.logger.Debug
+				case "WARN":
+					log = w.logger.Warn
+				case "ERR", "ERROR":
+					log = w.logger.Error
+				}
+				line = strings.TrimSpace(line[end+1:])
+			}
+		}
+		log(line)
+	}
+	return len(p), nil
+}
+
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
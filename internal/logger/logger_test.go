@@ -0,0 +1,133 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/echovault/echovault/internal/logger"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func Test_Logger(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	l, err := logger.New(&buf, logger.FormatJSON, level)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l.Debug("dropped")
+	l.With(logger.ConnIDKey, "1-1", logger.DatabaseKey, 2).Info("command", logger.CommandKey, "set")
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 entry, got %d: %s", len(lines), buf.String())
+	}
+	var entry map[string]any
+	if err = json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry["level"] != "INFO" || entry["msg"] != "command" || entry[logger.ConnIDKey] != "1-1" ||
+		entry[logger.DatabaseKey] != float64(2) || entry[logger.CommandKey] != "set" {
+		t.Errorf("unexpected entry %v", entry)
+	}
+
+	// Lowering the level applies to the loggers that were already created.
+	buf.Reset()
+	level.Set(slog.LevelDebug)
+	l.Debug("kept")
+	if !strings.Contains(buf.String(), `"msg":"kept"`) {
+		t.Errorf("expected the debug entry to be written, got %s", buf.String())
+	}
+
+	buf.Reset()
+	text, _ := logger.New(&buf, logger.FormatText, nil)
+	text.Warn("text entry", logger.ErrorKey, "failed")
+	if !strings.Contains(buf.String(), `level=WARN msg="text entry" error=failed`) {
+		t.Errorf("unexpected text entry %s", buf.String())
+	}
+
+	if _, err = logger.New(&buf, "xml", nil); err == nil {
+		t.Error("expected an unknown format to be rejected")
+	}
+}
+
+func Test_Leveled(t *testing.T) {
+	var buf bytes.Buffer
+	inner, _ := logger.New(&buf, logger.FormatText, nil)
+	inner = inner.With("custom", true)
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelError)
+	l := logger.Leveled(inner, level).With(logger.DatabaseKey, 0)
+
+	l.Warn("dropped")
+	if buf.Len() != 0 {
+		t.Errorf("expected the warning to be dropped, got %s", buf.String())
+	}
+	level.Set(slog.LevelWarn)
+	l.Warn("kept")
+	if !strings.Contains(buf.String(), `msg=kept custom=true database=0`) {
+		t.Errorf("unexpected entry %s", buf.String())
+	}
+}
+
+func Test_Writer(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelDebug)
+	l, _ := logger.New(&buf, logger.FormatText, level)
+	w := logger.Writer(l.With("component", "raft"))
+
+	if _, err := w.Write([]byte("[WARN]  raft: heartbeat timeout reached\n[DEBUG] memberlist: stream connection\nno tag\n")); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`level=WARN msg="raft: heartbeat timeout reached" component=raft`,
+		`level=DEBUG msg="memberlist: stream connection" component=raft`,
+		`level=INFO msg="no tag" component=raft`,
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected entry %s, got %s", want, buf.String())
+		}
+	}
+}
+
+func Test_ParseLevel(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected slog.Level
+		wantErr  bool
+	}{
+		{value: "debug", expected: slog.LevelDebug},
+		{value: "INFO", expected: slog.LevelInfo},
+		{value: "warning", expected: slog.LevelWarn},
+		{value: "error", expected: slog.LevelError},
+		{value: "verbose", wantErr: true},
+	}
+	for _, test := range tests {
+		level, err := logger.ParseLevel(test.value)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("expected level %s to be rejected", test.value)
+			}
+			continue
+		}
+		if err != nil || level != test.expected {
+			t.Errorf("expected level %s to parse to %v, got %v (%v)", test.value, test.expected, level, err)
+		}
+	}
+}
@@ -17,7 +17,6 @@ package memberlist
 import (
 	"encoding/json"
 	"github.com/hashicorp/memberlist"
-	"log"
 )
 
 type BroadcastMessage struct {
@@ -37,7 +36,7 @@ func (broadcastMessage *BroadcastMessage) Invalidates(other memberlist.Broadcast
 	}
 
 	switch broadcastMessage.Action {
-	case "RaftJoin":
+	case "RaftJoin", "RaftLeave":
 		return broadcastMessage.Action == otherBroadcast.Action &&
 			broadcastMessage.ServerID == otherBroadcast.ServerID
 	case "MutateData":
@@ -50,13 +49,8 @@ func (broadcastMessage *BroadcastMessage) Invalidates(other memberlist.Broadcast
 
 // Message Implements Broadcast interface
 func (broadcastMessage *BroadcastMessage) Message() []byte {
-	msg, err := json.Marshal(broadcastMessage)
-
-	if err != nil {
-		log.Println(err)
-		return []byte{}
-	}
-
+	// The message only holds strings and bytes, so marshalling it can't fail.
+	msg, _ := json.Marshal(broadcastMessage)
 	return msg
 }
 
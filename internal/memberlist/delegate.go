@@ -20,9 +20,9 @@ import (
 	"fmt"
 	"github.com/echovault/echovault/internal"
 	"github.com/echovault/echovault/internal/config"
+	"github.com/echovault/echovault/internal/logger"
 	"github.com/hashicorp/memberlist"
 	"github.com/hashicorp/raft"
-	"log"
 	"time"
 )
 
@@ -37,6 +37,12 @@ type DelegateOpts struct {
 	isRaftLeader   func() bool
 	applyMutate    func(ctx context.Context, cmd []string) ([]byte, error)
 	applyDeleteKey func(ctx context.Context, key string) error
+	removeServer   func(meta NodeMeta) error
+	checkQuorum    func(id string) error
+	isLeaving      func() bool
+	memberLeaving  func(id raft.ServerID) bool
+	keyring        *memberlist.Keyring
+	logger         logger.Logger
 }
 
 func NewDelegate(opts DelegateOpts) *Delegate {
@@ -52,6 +58,7 @@ func (delegate *Delegate) NodeMeta(limit int) []byte {
 		RaftAddr: raft.ServerAddress(
 			fmt.Sprintf("%s:%d", delegate.options.config.RaftBindAddr, delegate.options.config.RaftBindPort)),
 		MemberlistAddr: fmt.Sprintf("%s:%d", delegate.options.config.BindAddr, delegate.options.config.DiscoveryPort),
+		Leaving:        delegate.options.isLeaving(),
 	}
 
 	b, err := json.Marshal(&meta)
@@ -67,7 +74,7 @@ func (delegate *Delegate) NodeMeta(limit int) []byte {
 func (delegate *Delegate) NotifyMsg(msgBytes []byte) {
 	var msg BroadcastMessage
 	if err := json.Unmarshal(msgBytes, &msg); err != nil {
-		log.Printf("notifymsg: %v", err)
+		delegate.options.logger.Error("decode broadcast message", logger.ErrorKey, err)
 		return
 	}
 
@@ -80,7 +87,27 @@ func (delegate *Delegate) NotifyMsg(msgBytes []byte) {
 		}
 		err := delegate.options.addVoter(msg.NodeMeta.ServerID, msg.NodeMeta.RaftAddr, 0, 0)
 		if err != nil {
-			log.Println(err)
+			delegate.options.logger.Error("add raft voter", "server_id", msg.NodeMeta.ServerID, logger.ErrorKey, err)
+		}
+
+	case "RaftLeave":
+		// If the current node is not the cluster leader, re-broadcast the message.
+		if !delegate.options.isRaftLeader() {
+			delegate.options.broadcastQueue.QueueBroadcast(&msg)
+			return
+		}
+		// The request is gossiped, so it's only accepted when the node it names advertises that it's leaving.
+		if !delegate.options.memberLeaving(msg.NodeMeta.ServerID) {
+			delegate.options.logger.Warn("ignore raft leave request of a node that is not leaving",
+				"server_id", msg.NodeMeta.ServerID)
+			return
+		}
+		if err := delegate.options.checkQuorum(string(msg.NodeMeta.ServerID)); err != nil {
+			delegate.options.logger.Error("remove raft server", "server_id", msg.NodeMeta.ServerID, logger.ErrorKey, err)
+			return
+		}
+		if err := delegate.options.removeServer(msg.NodeMeta); err != nil {
+			delegate.options.logger.Error("remove raft server", "server_id", msg.NodeMeta.ServerID, logger.ErrorKey, err)
 		}
 
 	case "DeleteKey":
@@ -97,7 +124,7 @@ func (delegate *Delegate) NotifyMsg(msgBytes []byte) {
 		key := string(msg.Content)
 
 		if err := delegate.options.applyDeleteKey(ctx, key); err != nil {
-			log.Println(err)
+			delegate.options.logger.Error("apply forwarded key deletion", "key", key, logger.ErrorKey, err)
 		}
 
 	case "MutateData":
@@ -113,12 +140,18 @@ func (delegate *Delegate) NotifyMsg(msgBytes []byte) {
 
 		cmd, err := internal.Decode(msg.Content)
 		if err != nil {
-			log.Println(err)
+			delegate.options.logger.Error("decode forwarded command", logger.ErrorKey, err)
 			return
 		}
 
 		if _, err := delegate.options.applyMutate(ctx, cmd); err != nil {
-			log.Println(err)
+			delegate.options.logger.Error("apply forwarded command", logger.ErrorKey, err)
+		}
+
+	case "KeyringInstall", "KeyringUse", "KeyringRemove":
+		// Keyring operations are sent directly to each member, so they're not re-broadcast.
+		if err := applyKeyringOperation(delegate.options.keyring, msg.Action, msg.Content); err != nil {
+			delegate.options.logger.Error("apply keyring operation", "action", msg.Action, logger.ErrorKey, err)
 		}
 	}
 }
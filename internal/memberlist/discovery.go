@@ -0,0 +1,98 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memberlist
+
+import (
+	"context"
+	"fmt"
+	"github.com/echovault/echovault/internal/config"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Resolver is the subset of net.Resolver used for DNS discovery.
+type Resolver interface {
+	LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// Seeds returns the static seed addresses from the config.
+// JoinAddr is kept for backwards compatibility and is tried before the addresses in Seeds.
+func Seeds(conf config.Config) []string {
+	var seeds []string
+	if conf.JoinAddr != "" {
+		seeds = append(seeds, conf.JoinAddr)
+	}
+	for _, seed := range conf.Seeds {
+		if seed != "" && !slices.Contains(seeds, seed) {
+			seeds = append(seeds, seed)
+		}
+	}
+	return seeds
+}
+
+// Discover returns the addresses to join, made up of the static seeds followed by the addresses discovered
+// through DNS. DNS discovery looks up SRV records for conf.DiscoveryDNS first, and falls back to A/AAAA records
+// combined with the discovery port when there are no SRV records. The node's own address is excluded.
+// When the DNS lookup fails, the static seeds are returned with the error so that they can still be joined.
+func Discover(ctx context.Context, resolver Resolver, conf config.Config) ([]string, error) {
+	seeds := Seeds(conf)
+	if conf.DiscoveryDNS == "" {
+		return seeds, nil
+	}
+
+	self := net.JoinHostPort(conf.BindAddr, strconv.Itoa(int(conf.DiscoveryPort)))
+
+	var discovered []string
+	_, records, err := resolver.LookupSRV(ctx, "", "", conf.DiscoveryDNS)
+	if err == nil && len(records) > 0 {
+		for _, record := range records {
+			discovered = append(discovered,
+				net.JoinHostPort(strings.TrimSuffix(record.Target, "."), strconv.Itoa(int(record.Port))))
+		}
+	} else {
+		hosts, err := resolver.LookupHost(ctx, conf.DiscoveryDNS)
+		if err != nil {
+			return seeds, fmt.Errorf("discover %s: %v", conf.DiscoveryDNS, err)
+		}
+		for _, host := range hosts {
+			discovered = append(discovered, net.JoinHostPort(host, strconv.Itoa(int(conf.DiscoveryPort))))
+		}
+	}
+
+	for _, addr := range discovered {
+		if addr != self && !slices.Contains(seeds, addr) {
+			seeds = append(seeds, addr)
+		}
+	}
+
+	return seeds, nil
+}
+
+// requireNodeNames returns true when every address to join carries a node name ("name/host:port").
+// Addresses discovered through DNS do not, so node names can't be required when DNS discovery is enabled.
+func requireNodeNames(conf config.Config) bool {
+	if conf.DiscoveryDNS != "" {
+		return false
+	}
+	for _, seed := range Seeds(conf) {
+		if !strings.Contains(seed, "/") {
+			return false
+		}
+	}
+	return true
+}
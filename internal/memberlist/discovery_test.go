@@ -0,0 +1,138 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memberlist_test
+
+import (
+	"context"
+	"errors"
+	"github.com/echovault/echovault/internal/config"
+	"github.com/echovault/echovault/internal/memberlist"
+	"net"
+	"slices"
+	"testing"
+)
+
+// stubResolver is a local resolver that answers from static records.
+type stubResolver struct {
+	srv   map[string][]*net.SRV
+	hosts map[string][]string
+}
+
+func (r stubResolver) LookupSRV(_ context.Context, _, _, name string) (string, []*net.SRV, error) {
+	records, ok := r.srv[name]
+	if !ok {
+		return "", nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+	return name, records, nil
+}
+
+func (r stubResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	addrs, ok := r.hosts[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	return addrs, nil
+}
+
+func Test_Discover(t *testing.T) {
+	resolver := stubResolver{
+		srv: map[string][]*net.SRV{
+			"_echovault._tcp.cluster.local": {
+				{Target: "node-1.cluster.local.", Port: 7946},
+				{Target: "node-2.cluster.local.", Port: 7947},
+			},
+		},
+		hosts: map[string][]string{
+			"echovault.cluster.local": {"10.0.0.1", "10.0.0.2", "10.0.0.3"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		conf     config.Config
+		expected []string
+		wantErr  error
+	}{
+		{
+			name: "1. Return the static seeds, starting with JoinAddr",
+			conf: config.Config{
+				JoinAddr: "SERVER-0/10.0.0.1:7946",
+				Seeds:    []string{"SERVER-1/10.0.0.2:7946", "SERVER-0/10.0.0.1:7946"},
+			},
+			expected: []string{"SERVER-0/10.0.0.1:7946", "SERVER-1/10.0.0.2:7946"},
+		},
+		{
+			name: "2. Discover members from SRV records",
+			conf: config.Config{
+				BindAddr:      "10.0.0.9",
+				DiscoveryPort: 7946,
+				DiscoveryDNS:  "_echovault._tcp.cluster.local",
+			},
+			expected: []string{"node-1.cluster.local:7946", "node-2.cluster.local:7947"},
+		},
+		{
+			name: "3. Fall back to A records with the discovery port and exclude the node itself",
+			conf: config.Config{
+				BindAddr:      "10.0.0.2",
+				DiscoveryPort: 7946,
+				Seeds:         []string{"10.0.0.5:7946"},
+				DiscoveryDNS:  "echovault.cluster.local",
+			},
+			expected: []string{"10.0.0.5:7946", "10.0.0.1:7946", "10.0.0.3:7946"},
+		},
+		{
+			name: "4. Return the static seeds with an error when the name cannot be resolved",
+			conf: config.Config{
+				Seeds:        []string{"10.0.0.5:7946"},
+				DiscoveryDNS: "unknown.cluster.local",
+			},
+			expected: []string{"10.0.0.5:7946"},
+			wantErr:  errors.New("discover unknown.cluster.local"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			seeds, err := memberlist.Discover(context.Background(), resolver, test.conf)
+			if test.wantErr != nil && err == nil {
+				t.Errorf("expected error \"%s\", got nil", test.wantErr.Error())
+			}
+			if test.wantErr == nil && err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+			if !slices.Equal(seeds, test.expected) {
+				t.Errorf("expected seeds %v, got %v", test.expected, seeds)
+			}
+		})
+	}
+}
+
+func Test_JoinWhenDiscoveryFails(t *testing.T) {
+	_, seedAddr := startMemberList(t, memberlist.Opts{Config: config.Config{ServerID: "SERVER-0"}})
+
+	// The DNS name cannot be resolved, so the node joins through its static seed.
+	m, _ := startMemberList(t, memberlist.Opts{
+		Config: config.Config{
+			ServerID:     "SERVER-1",
+			Seeds:        []string{seedAddr},
+			DiscoveryDNS: "unknown.cluster.local",
+		},
+		Resolver: stubResolver{},
+	})
+	awaitJoined(t, m)
+	if m.NumMembers() != 2 {
+		t.Errorf("expected 2 members, got %d", m.NumMembers())
+	}
+}
@@ -16,8 +16,8 @@ package memberlist
 
 import (
 	"encoding/json"
+	"github.com/echovault/echovault/internal/logger"
 	"github.com/hashicorp/memberlist"
-	"log"
 )
 
 type EventDelegate struct {
@@ -28,6 +28,7 @@ type EventDelegateOpts struct {
 	incrementNodes   func()
 	decrementNodes   func()
 	removeRaftServer func(meta NodeMeta) error
+	logger           logger.Logger
 }
 
 func NewEventDelegate(opts EventDelegateOpts) *EventDelegate {
@@ -50,14 +51,14 @@ func (eventDelegate *EventDelegate) NotifyLeave(node *memberlist.Node) {
 	err := json.Unmarshal(node.Meta, &meta)
 
 	if err != nil {
-		log.Println("Could not get leaving node's metadata.")
+		eventDelegate.options.logger.Error("decode leaving node metadata", "node", node.Name, logger.ErrorKey, err)
 		return
 	}
 
 	err = eventDelegate.options.removeRaftServer(meta)
 
 	if err != nil {
-		log.Println(err)
+		eventDelegate.options.logger.Error("remove raft server", "node", node.Name, logger.ErrorKey, err)
 	}
 }
 
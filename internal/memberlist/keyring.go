@@ -0,0 +1,128 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memberlist
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/memberlist"
+	"strings"
+)
+
+// DecodeGossipKey decodes a base64 encoded gossip encryption key.
+// The key must be 16, 24 or 32 bytes long to select AES-128, AES-192 or AES-256.
+func DecodeGossipKey(key string) ([]byte, error) {
+	b, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, fmt.Errorf("gossip key is not valid base64: %v", err)
+	}
+	if err = memberlist.ValidateKey(b); err != nil {
+		return nil, fmt.Errorf("gossip key: %v", err)
+	}
+	return b, nil
+}
+
+// NewKeyring creates the gossip keyring from base64 encoded keys. The first key is the primary key used to
+// encrypt outgoing messages. It returns nil when no keys are provided, which disables gossip encryption.
+func NewKeyring(keys []string) (*memberlist.Keyring, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	decoded := make([][]byte, len(keys))
+	for i, key := range keys {
+		b, err := DecodeGossipKey(key)
+		if err != nil {
+			return nil, err
+		}
+		decoded[i] = b
+	}
+	return memberlist.NewKeyring(decoded[1:], decoded[0])
+}
+
+// InstallKey adds a key to the keyring of every node in the cluster.
+// The key is accepted for incoming messages but is not used to encrypt outgoing messages until UseKey is called.
+func (m *MemberList) InstallKey(key string) error {
+	return m.keyringOperation("KeyringInstall", key)
+}
+
+// UseKey makes a key the primary key of every node in the cluster. The key must be installed first.
+func (m *MemberList) UseKey(key string) error {
+	return m.keyringOperation("KeyringUse", key)
+}
+
+// RemoveKey removes a key from the keyring of every node in the cluster. The primary key cannot be removed.
+func (m *MemberList) RemoveKey(key string) error {
+	return m.keyringOperation("KeyringRemove", key)
+}
+
+// ListKeys returns the base64 encoded keys in the local keyring, starting with the primary key.
+func (m *MemberList) ListKeys() ([]string, error) {
+	keyring := m.keyring
+	if keyring == nil {
+		return nil, errors.New("gossip encryption is not enabled")
+	}
+	keys := keyring.GetKeys()
+	res := make([]string, len(keys))
+	for i, key := range keys {
+		res[i] = base64.StdEncoding.EncodeToString(key)
+	}
+	return res, nil
+}
+
+// keyringOperation applies the keyring operation locally, and then sends it to the other members over reliable
+// connections. Messages are encrypted with the current primary key, so the keys are never sent in plain text.
+func (m *MemberList) keyringOperation(action string, key string) error {
+	b, err := DecodeGossipKey(key)
+	if err != nil {
+		return err
+	}
+	if err = applyKeyringOperation(m.keyring, action, b); err != nil {
+		return err
+	}
+
+	msg := BroadcastMessage{Action: action, Content: b}
+	var errs []string
+	for _, node := range m.memberList.Members() {
+		if node.Name == m.memberList.LocalNode().Name {
+			continue
+		}
+		if err = m.memberList.SendReliable(node, msg.Message()); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", node.Name, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("keyring operation failed on nodes %s", strings.Join(errs, ", "))
+	}
+
+	return nil
+}
+
+// applyKeyringOperation applies a keyring operation to the local keyring.
+func applyKeyringOperation(keyring *memberlist.Keyring, action string, key []byte) error {
+	if keyring == nil {
+		return errors.New("gossip encryption is not enabled")
+	}
+	switch action {
+	case "KeyringInstall":
+		return keyring.AddKey(key)
+	case "KeyringUse":
+		return keyring.UseKey(key)
+	case "KeyringRemove":
+		return keyring.RemoveKey(key)
+	default:
+		return fmt.Errorf("unknown keyring operation %s", action)
+	}
+}
@@ -17,11 +17,16 @@ package memberlist
 import (
 	"context"
 	"crypto/md5"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/echovault/echovault/internal"
 	"github.com/echovault/echovault/internal/config"
+	"github.com/echovault/echovault/internal/logger"
 	"log"
+	"net"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/hashicorp/memberlist"
@@ -33,6 +38,8 @@ type NodeMeta struct {
 	ServerID       raft.ServerID      `json:"ServerID"`
 	MemberlistAddr string             `json:"MemberlistAddr"`
 	RaftAddr       raft.ServerAddress `json:"RaftAddr"`
+	// Leaving is advertised by the node itself once it asks to be removed from the raft configuration.
+	Leaving bool `json:"Leaving,omitempty"`
 }
 
 type Opts struct {
@@ -40,34 +47,77 @@ type Opts struct {
 	HasJoinedCluster func() bool
 	AddVoter         func(id raft.ServerID, address raft.ServerAddress, prevIndex uint64, timeout time.Duration) error
 	RemoveRaftServer func(meta NodeMeta) error
-	IsRaftLeader     func() bool
-	ApplyMutate      func(ctx context.Context, cmd []string) ([]byte, error)
-	ApplyDeleteKey   func(ctx context.Context, key string) error
+	// CheckQuorum returns an error if removing the server would leave the cluster without a quorum.
+	CheckQuorum    func(id string) error
+	IsRaftLeader   func() bool
+	ApplyMutate    func(ctx context.Context, cmd []string) ([]byte, error)
+	ApplyDeleteKey func(ctx context.Context, key string) error
+	// Resolver used for DNS discovery. net.DefaultResolver is used when it's nil.
+	Resolver Resolver
+	// Logger for the membership events and errors. logger.Default() is used when it's nil.
+	Logger logger.Logger
 }
 
+const (
+	JoinStateNone    = "none"    // The node bootstraps the cluster and has no members to join.
+	JoinStateJoining = "joining" // The node is retrying to join the cluster in the background.
+	JoinStateJoined  = "joined"  // The node has joined the cluster.
+)
+
 type MemberList struct {
 	options        Opts
 	broadcastQueue *memberlist.TransmitLimitedQueue
 	noOfNodesMut   sync.RWMutex
 	noOfNodes      int
 	memberList     *memberlist.Memberlist
+	keyring        *memberlist.Keyring
+
+	leaving atomic.Bool // Whether the node asked to be removed from the raft configuration.
+
+	joinMut      sync.RWMutex
+	joinState    string
+	joinErr      error
+	joinAttempts int
+	stopJoin     chan struct{}
+	stopJoinOnce sync.Once
 }
 
 func NewMemberList(opts Opts) *MemberList {
+	if opts.Logger == nil {
+		opts.Logger = logger.Default()
+	}
 	return &MemberList{
 		options:        opts,
 		broadcastQueue: new(memberlist.TransmitLimitedQueue),
 		noOfNodesMut:   sync.RWMutex{},
 		noOfNodes:      0,
+		joinState:      JoinStateNone,
+		stopJoin:       make(chan struct{}),
 	}
 }
 
-func (m *MemberList) MemberListInit(ctx context.Context) {
+func (m *MemberList) MemberListInit(ctx context.Context) error {
 	cfg := memberlist.DefaultWANConfig()
-	cfg.RequireNodeNames = true
+	// Forward the entries of the memberlist library to our logger.
+	cfg.Logger = log.New(logger.Writer(m.options.Logger), "", 0)
+	cfg.RequireNodeNames = requireNodeNames(m.options.Config)
 	cfg.Name = m.options.Config.ServerID
 	cfg.BindAddr = m.options.Config.BindAddr
 	cfg.BindPort = int(m.options.Config.DiscoveryPort)
+
+	// Encrypt gossip when keys are configured. Messages that are not encrypted with a key in the keyring
+	// are rejected, so nodes with the wrong key cannot join the cluster.
+	keyring, err := NewKeyring(m.options.Config.GossipKeys)
+	if err != nil {
+		return err
+	}
+	m.keyring = keyring
+	if keyring != nil {
+		cfg.Keyring = keyring
+		cfg.GossipVerifyIncoming = true
+		cfg.GossipVerifyOutgoing = true
+	}
+
 	cfg.Delegate = NewDelegate(DelegateOpts{
 		config:         m.options.Config,
 		broadcastQueue: m.broadcastQueue,
@@ -75,6 +125,12 @@ func (m *MemberList) MemberListInit(ctx context.Context) {
 		isRaftLeader:   m.options.IsRaftLeader,
 		applyMutate:    m.options.ApplyMutate,
 		applyDeleteKey: m.options.ApplyDeleteKey,
+		removeServer:   m.options.RemoveRaftServer,
+		checkQuorum:    m.options.CheckQuorum,
+		isLeaving:      m.leaving.Load,
+		memberLeaving:  m.memberLeaving,
+		keyring:        keyring,
+		logger:         m.options.Logger,
 	})
 	cfg.Events = NewEventDelegate(EventDelegateOpts{
 		incrementNodes: func() {
@@ -88,6 +144,7 @@ func (m *MemberList) MemberListInit(ctx context.Context) {
 			m.noOfNodes -= 1
 		},
 		removeRaftServer: m.options.RemoveRaftServer,
+		logger:           m.options.Logger,
 	})
 
 	m.broadcastQueue.RetransmitMult = 1
@@ -99,29 +156,91 @@ func (m *MemberList) MemberListInit(ctx context.Context) {
 	}
 
 	list, err := memberlist.Create(cfg)
+	if err != nil {
+		return fmt.Errorf("create memberlist: %w", err)
+	}
 	m.memberList = list
 
-	if err != nil {
-		log.Fatal(err)
+	if len(Seeds(m.options.Config)) > 0 || m.options.Config.DiscoveryDNS != "" {
+		m.setJoinState(JoinStateJoining, nil)
+		go m.join(ctx)
 	}
 
-	if m.options.Config.JoinAddr != "" {
-		backoffPolicy := internal.RetryBackoff(retry.NewFibonacci(1*time.Second), 5, 200*time.Millisecond, 0, 0)
+	return nil
+}
 
-		err = retry.Do(ctx, backoffPolicy, func(ctx context.Context) error {
-			_, err = list.Join([]string{m.options.Config.JoinAddr})
-			if err != nil {
-				return retry.RetryableError(err)
-			}
-			return nil
-		})
+// join keeps trying to join the cluster through the seeds until it succeeds, the context is cancelled,
+// or the memberlist is shut down.
+func (m *MemberList) join(ctx context.Context) {
+	resolver := m.options.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-m.stopJoin:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	backoffPolicy := internal.RetryBackoff(retry.NewFibonacci(1*time.Second), 0, 200*time.Millisecond, 30*time.Second, 0)
 
+	err := retry.Do(ctx, backoffPolicy, func(ctx context.Context) error {
+		seeds, err := Discover(ctx, resolver, m.options.Config)
+		if err != nil && len(seeds) > 0 {
+			// The seeds collected so far are still joined when DNS discovery fails.
+			m.options.Logger.Warn("memberlist discovery", logger.ErrorKey, err)
+		}
+		switch {
+		case len(seeds) > 0:
+			_, err = m.memberList.Join(seeds)
+		case err == nil:
+			err = errors.New("no cluster members discovered")
+		}
 		if err != nil {
-			log.Fatal(err)
+			m.setJoinState(JoinStateJoining, err)
+			m.options.Logger.Warn("memberlist join", logger.ErrorKey, err)
+			return retry.RetryableError(err)
 		}
+		return nil
+	})
+	if err != nil {
+		// The context was cancelled before the node could join.
+		return
+	}
+
+	m.setJoinState(JoinStateJoined, nil)
+	m.broadcastRaftAddress()
+}
+
+func (m *MemberList) setJoinState(state string, err error) {
+	m.joinMut.Lock()
+	defer m.joinMut.Unlock()
+	m.joinState = state
+	m.joinErr = err
+	if err != nil {
+		m.joinAttempts += 1
+	}
+}
 
-		m.broadcastRaftAddress()
+// JoinState returns the state of the node's attempts to join the cluster, the number of failed attempts,
+// and the error from the latest failed attempt.
+func (m *MemberList) JoinState() (string, int, error) {
+	m.joinMut.RLock()
+	defer m.joinMut.RUnlock()
+	return m.joinState, m.joinAttempts, m.joinErr
+}
+
+// NumMembers returns the number of live members in the cluster, including the current node.
+func (m *MemberList) NumMembers() int {
+	if m.memberList == nil {
+		return 0
 	}
+	return m.memberList.NumMembers()
 }
 
 func (m *MemberList) broadcastRaftAddress() {
@@ -136,6 +255,55 @@ func (m *MemberList) broadcastRaftAddress() {
 	m.broadcastQueue.QueueBroadcast(&msg)
 }
 
+// RequestRaftLeave asks the cluster leader to remove the current node from the raft configuration.
+// The request is gossiped, so callers should check the raft configuration and retry until the node is removed.
+// The node first advertises that it's leaving in its metadata, which the leader checks so that no other node
+// can ask for its removal.
+func (m *MemberList) RequestRaftLeave() {
+	if m.leaving.CompareAndSwap(false, true) {
+		if err := m.memberList.UpdateNode(time.Second); err != nil {
+			m.options.Logger.Warn("advertise leaving node", logger.ErrorKey, err)
+		}
+	}
+	m.broadcastQueue.QueueBroadcast(&BroadcastMessage{
+		Action: "RaftLeave",
+		NodeMeta: NodeMeta{
+			ServerID: raft.ServerID(m.options.Config.ServerID),
+		},
+	})
+}
+
+// memberLeaving returns true if the live member with the given ID advertises that it's leaving.
+func (m *MemberList) memberLeaving(serverId raft.ServerID) bool {
+	if m.memberList == nil {
+		return false
+	}
+	for _, node := range m.memberList.Members() {
+		if node.Name != string(serverId) {
+			continue
+		}
+		var meta NodeMeta
+		if err := json.Unmarshal(node.Meta, &meta); err != nil {
+			return false
+		}
+		return meta.ServerID == serverId && meta.Leaving
+	}
+	return false
+}
+
+// IsAlive returns true if the server with the given ID is a live member of the cluster.
+func (m *MemberList) IsAlive(serverId string) bool {
+	if m.memberList == nil {
+		return false
+	}
+	for _, node := range m.memberList.Members() {
+		if node.Name == serverId {
+			return true
+		}
+	}
+	return false
+}
+
 // The ForwardDeleteKey function is only called by non-leaders.
 // It uses the broadcast queue to forward a key eviction command within the cluster.
 func (m *MemberList) ForwardDeleteKey(ctx context.Context, key string) {
@@ -170,19 +338,25 @@ func (m *MemberList) ForwardDataMutation(ctx context.Context, cmd []byte) {
 	})
 }
 
-func (m *MemberList) MemberListShutdown() {
+// MemberListShutdown broadcasts the node's intent to leave the cluster, waiting up to the timeout for the
+// message to be sent, and then stops the memberlist.
+func (m *MemberList) MemberListShutdown(timeout time.Duration) {
+	m.stopJoinOnce.Do(func() {
+		close(m.stopJoin)
+	})
+
 	// Gracefully leave memberlist cluster
-	err := m.memberList.Leave(500 * time.Millisecond)
+	err := m.memberList.Leave(timeout)
 	if err != nil {
-		log.Printf("memberlist leave: %v\n", err)
+		m.options.Logger.Error("memberlist leave", logger.ErrorKey, err)
 		return
 	}
 
 	err = m.memberList.Shutdown()
 	if err != nil {
-		log.Printf("memberlist shutdown: %v\n", err)
+		m.options.Logger.Error("memberlist shutdown", logger.ErrorKey, err)
 		return
 	}
 
-	log.Println("successfully shutdown memberlist")
+	m.options.Logger.Info("memberlist shut down")
 }
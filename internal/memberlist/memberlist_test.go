@@ -0,0 +1,131 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memberlist_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/echovault/echovault/internal"
+	"github.com/echovault/echovault/internal/config"
+	"github.com/echovault/echovault/internal/logger"
+	"github.com/echovault/echovault/internal/memberlist"
+	"github.com/hashicorp/raft"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// startMemberList starts a memberlist on a free local port and returns it with its address.
+// The raft callbacks that are not set do nothing, and the node is not the raft leader.
+func startMemberList(t *testing.T, opts memberlist.Opts) (*memberlist.MemberList, string) {
+	port, err := internal.GetFreePort()
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts.Config.BindAddr = "127.0.0.1"
+	opts.Config.DiscoveryPort = uint16(port)
+	if opts.IsRaftLeader == nil {
+		opts.IsRaftLeader = func() bool { return false }
+	}
+	if opts.AddVoter == nil {
+		opts.AddVoter = func(raft.ServerID, raft.ServerAddress, uint64, time.Duration) error { return nil }
+	}
+	if opts.RemoveRaftServer == nil {
+		opts.RemoveRaftServer = func(memberlist.NodeMeta) error { return nil }
+	}
+	if opts.CheckQuorum == nil {
+		opts.CheckQuorum = func(string) error { return nil }
+	}
+	opts.Logger = logger.Discard()
+
+	m := memberlist.NewMemberList(opts)
+	if err = m.MemberListInit(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { m.MemberListShutdown(time.Second) })
+	return m, fmt.Sprintf("%s:%d", opts.Config.BindAddr, opts.Config.DiscoveryPort)
+}
+
+// awaitJoined waits for the memberlist to join the cluster.
+func awaitJoined(t *testing.T, m *memberlist.MemberList) {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		state, _, err := m.JoinState()
+		if state == memberlist.JoinStateJoined {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the node to join the cluster, got state %s with error %v", state, err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func Test_RaftLeave(t *testing.T) {
+	var quorumErr atomic.Pointer[error]
+	removed := make(chan raft.ServerID, 1)
+	opts := memberlist.Opts{
+		IsRaftLeader: func() bool { return true },
+		CheckQuorum: func(string) error {
+			if err := quorumErr.Load(); err != nil {
+				return *err
+			}
+			return nil
+		},
+		RemoveRaftServer: func(meta memberlist.NodeMeta) error {
+			select {
+			case removed <- meta.ServerID:
+			default:
+			}
+			return nil
+		},
+	}
+	opts.Config.ServerID = "SERVER-0"
+	_, leaderAddr := startMemberList(t, opts)
+
+	follower, _ := startMemberList(t, memberlist.Opts{
+		Config: config.Config{ServerID: "SERVER-1", Seeds: []string{leaderAddr}},
+	})
+	awaitJoined(t, follower)
+
+	// The leader keeps the node when its removal would leave the cluster without a quorum.
+	err := errors.New("no quorum")
+	quorumErr.Store(&err)
+	for i := 0; i < 5; i++ {
+		follower.RequestRaftLeave()
+		select {
+		case id := <-removed:
+			t.Fatalf("expected %s not to be removed without a quorum", id)
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+
+	quorumErr.Store(nil)
+	deadline := time.After(5 * time.Second)
+	for {
+		follower.RequestRaftLeave()
+		select {
+		case id := <-removed:
+			if id != "SERVER-1" {
+				t.Errorf("expected SERVER-1 to be removed, got %s", id)
+			}
+			return
+		case <-deadline:
+			t.Fatal("expected the leaving node to be removed")
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+// Estimated memory overheads of the keyspace. They approximate the memory used by the Go runtime for the maps,
+// strings and slices that hold the data, on top of the bytes of the strings themselves.
+const (
+	keyOverhead     = 64 // A key's entry in the database map, including its KeyData.
+	stringOverhead  = 16 // A string header.
+	numberOverhead  = 16 // An integer or float boxed in an interface.
+	elementOverhead = 32 // A list element, or a set or sorted set member, including its map entry.
+	fieldOverhead   = 48 // A hash field, including its map entry and interface value.
+)
+
+// DefaultMemorySamples is the number of elements sampled to estimate the size of hashes and lists, both by
+// MEMORY USAGE and when a hash or list is set as a whole. The hash and list commands then maintain the size as
+// they add and remove elements.
+const DefaultMemorySamples = 5
+
+// Sizer is implemented by the data types that keep track of their own estimated size as they're mutated.
+type Sizer interface {
+	Size() int64
+}
+
+// EstimateMemberSize returns the estimated size of a member of a collection.
+// Data types that implement Sizer use it to maintain their size.
+func EstimateMemberSize(member string) int64 {
+	return elementOverhead + int64(len(member))
+}
+
+// EstimateFieldSize returns the estimated size of a hash field and its value.
+// The hash commands use it to maintain the size of the hashes they mutate.
+func EstimateFieldSize(field string, value interface{}) int64 {
+	return fieldOverhead + int64(len(field)) + EstimateValueSize(value, 0)
+}
+
+// EstimateKeySize returns the estimated number of bytes used by the key and its value.
+// The size of hashes and lists is extrapolated from the given number of sampled elements, so that it takes constant
+// time. When samples is 0, every element is counted.
+func EstimateKeySize(key string, value interface{}, samples int) int64 {
+	return keyOverhead + int64(len(key)) + EstimateValueSize(value, samples)
+}
+
+// EstimateValueSize returns the estimated number of bytes used by the value.
+func EstimateValueSize(value interface{}, samples int) int64 {
+	switch v := value.(type) {
+	case nil:
+		return 0
+	case string:
+		return stringOverhead + int64(len(v))
+	case int, int64, float64:
+		return numberOverhead
+	case Sizer:
+		return v.Size()
+	case []string:
+		if len(v) == 0 {
+			return 0
+		}
+		if samples <= 0 || len(v) <= samples {
+			var size int64
+			for _, element := range v {
+				size += EstimateMemberSize(element)
+			}
+			return size
+		}
+		// Sample elements spread evenly across the list.
+		var size int64
+		for i := 0; i < samples; i++ {
+			size += EstimateMemberSize(v[i*len(v)/samples])
+		}
+		return size * int64(len(v)) / int64(samples)
+	case map[string]interface{}:
+		if len(v) == 0 {
+			return 0
+		}
+		var size int64
+		count := 0
+		for field, fieldValue := range v {
+			if samples > 0 && count == samples {
+				break
+			}
+			size += EstimateFieldSize(field, fieldValue)
+			count += 1
+		}
+		return size * int64(len(v)) / int64(count)
+	default:
+		return stringOverhead
+	}
+}
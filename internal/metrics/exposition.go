@@ -0,0 +1,141 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ContentType is the content type of the Prometheus text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Label is a name and value pair that identifies a sample of a metric.
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Exposition builds metrics in the Prometheus text exposition format.
+// Samples of the same metric are grouped under a single HELP and TYPE header, in the order the metrics were added.
+type Exposition struct {
+	families []*family
+	index    map[string]*family
+}
+
+type family struct {
+	name    string
+	help    string
+	kind    string
+	samples []string
+}
+
+// NewExposition creates an empty exposition.
+func NewExposition() *Exposition {
+	return &Exposition{index: make(map[string]*family)}
+}
+
+// Counter adds a sample of a counter.
+func (e *Exposition) Counter(name string, help string, value float64, labels ...Label) {
+	e.family(name, help, "counter").add(name, labels, value)
+}
+
+// Gauge adds a sample of a gauge.
+func (e *Exposition) Gauge(name string, help string, value float64, labels ...Label) {
+	e.family(name, help, "gauge").add(name, labels, value)
+}
+
+// Histogram adds the buckets, sum and count of a histogram.
+func (e *Exposition) Histogram(name string, help string, histogram HistogramSnapshot, labels ...Label) {
+	f := e.family(name, help, "histogram")
+	for i, upperBound := range histogram.Buckets {
+		f.add(name+"_bucket", append(labels, Label{Name: "le", Value: formatValue(upperBound)}), float64(histogram.Counts[i]))
+	}
+	f.add(name+"_bucket", append(labels, Label{Name: "le", Value: "+Inf"}), float64(histogram.Count))
+	f.add(name+"_sum", labels, histogram.Sum.Seconds())
+	f.add(name+"_count", labels, float64(histogram.Count))
+}
+
+// WriteTo writes the exposition to w.
+func (e *Exposition) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+	for _, f := range e.families {
+		b.WriteString(fmt.Sprintf("# HELP %s %s\n", f.name, escape(f.help, false)))
+		b.WriteString(fmt.Sprintf("# TYPE %s %s\n", f.name, f.kind))
+		for _, sample := range f.samples {
+			b.WriteString(sample)
+		}
+	}
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+func (e *Exposition) family(name string, help string, kind string) *family {
+	if f, ok := e.index[name]; ok {
+		return f
+	}
+	f := &family{name: name, help: help, kind: kind}
+	e.families = append(e.families, f)
+	e.index[name] = f
+	return f
+}
+
+func (f *family) add(name string, labels []Label, value float64) {
+	sample := name
+	if len(labels) > 0 {
+		pairs := make([]string, len(labels))
+		for i, label := range labels {
+			pairs[i] = fmt.Sprintf("%s=\"%s\"", label.Name, escape(label.Value, true))
+		}
+		sample += "{" + strings.Join(pairs, ",") + "}"
+	}
+	f.samples = append(f.samples, sample+" "+formatValue(value)+"\n")
+}
+
+// Handler returns an HTTP handler that serves the metrics added to an exposition by collect on every request.
+func Handler(collect func(e *Exposition)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		e := NewExposition()
+		collect(e)
+		w.Header().Set("Content-Type", ContentType)
+		_, _ = e.WriteTo(w)
+	})
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// escape escapes backslashes and line feeds, and double quotes in label values.
+func escape(s string, quotes bool) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	if quotes {
+		s = strings.ReplaceAll(s, `"`, `\"`)
+	}
+	return s
+}
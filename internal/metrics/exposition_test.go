@@ -0,0 +1,75 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics_test
+
+import (
+	"github.com/echovault/echovault/internal/metrics"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_Exposition(t *testing.T) {
+	histogram := metrics.NewHistogram(0.001, 0.01)
+	histogram.Observe(2 * time.Millisecond)
+
+	e := metrics.NewExposition()
+	e.Counter("calls_total", "The number of calls.", 3, metrics.Label{Name: "command", Value: "get"})
+	e.Gauge("clients", "The number of clients.", 2)
+	e.Counter("calls_total", "The number of calls.", 1, metrics.Label{Name: "command", Value: `a"b\c`})
+	e.Histogram("duration_seconds", "The duration.", histogram.Snapshot(), metrics.Label{Name: "command", Value: "get"})
+
+	var b strings.Builder
+	if _, err := e.WriteTo(&b); err != nil {
+		t.Error(err)
+		return
+	}
+
+	expected := `# HELP calls_total The number of calls.
+# TYPE calls_total counter
+calls_total{command="get"} 3
+calls_total{command="a\"b\\c"} 1
+# HELP clients The number of clients.
+# TYPE clients gauge
+clients 2
+# HELP duration_seconds The duration.
+# TYPE duration_seconds histogram
+duration_seconds_bucket{command="get",le="0.001"} 0
+duration_seconds_bucket{command="get",le="0.01"} 1
+duration_seconds_bucket{command="get",le="+Inf"} 1
+duration_seconds_sum{command="get"} 0.002
+duration_seconds_count{command="get"} 1
+`
+	if b.String() != expected {
+		t.Errorf("expected exposition:\n%s\ngot:\n%s", expected, b.String())
+	}
+}
+
+func Test_Handler(t *testing.T) {
+	handler := metrics.Handler(func(e *metrics.Exposition) {
+		e.Gauge("up", "Whether the server is up.", 1)
+	})
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+
+	if contentType := recorder.Header().Get("Content-Type"); contentType != metrics.ContentType {
+		t.Errorf("expected content type %s, got %s", metrics.ContentType, contentType)
+	}
+	if !strings.Contains(recorder.Body.String(), "up 1\n") {
+		t.Errorf("expected body to contain the up gauge, got %s", recorder.Body.String())
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, of the buckets of a latency histogram.
+// They range from 10 microseconds to 10 seconds.
+var DefaultBuckets = []float64{
+	0.00001, 0.000025, 0.00005, 0.0001, 0.00025, 0.0005, 0.001, 0.0025, 0.005,
+	0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10,
+}
+
+// Histogram counts observed durations in buckets.
+// It is safe for concurrent use, and observing a duration doesn't take a lock.
+type Histogram struct {
+	buckets []float64       // The upper bounds of the buckets in seconds, in ascending order.
+	counts  []atomic.Uint64 // The number of observations in each bucket. The last bucket is +Inf.
+	count   atomic.Uint64   // The number of observations.
+	sum     atomic.Int64    // The sum of the observations in nanoseconds.
+}
+
+// HistogramSnapshot holds the state of a histogram at a point in time.
+type HistogramSnapshot struct {
+	Buckets []float64     // The upper bounds of the buckets in seconds, in ascending order.
+	Counts  []uint64      // The cumulative number of observations less than or equal to each upper bound.
+	Count   uint64        // The number of observations.
+	Sum     time.Duration // The sum of the observations.
+}
+
+// NewHistogram creates a histogram with the given bucket upper bounds in seconds, in ascending order.
+// DefaultBuckets are used when no bucket is given.
+func NewHistogram(buckets ...float64) *Histogram {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	return &Histogram{
+		buckets: buckets,
+		counts:  make([]atomic.Uint64, len(buckets)+1),
+	}
+}
+
+// Observe adds a duration to the histogram.
+func (h *Histogram) Observe(d time.Duration) {
+	seconds := d.Seconds()
+	i := 0
+	for i < len(h.buckets) && seconds > h.buckets[i] {
+		i++
+	}
+	h.counts[i].Add(1)
+	h.count.Add(1)
+	h.sum.Add(int64(d))
+}
+
+// Snapshot returns the current state of the histogram.
+// As observations are not synchronised with each other, the count may be ahead of the buckets under load.
+func (h *Histogram) Snapshot() HistogramSnapshot {
+	snapshot := HistogramSnapshot{
+		Buckets: h.buckets,
+		Counts:  make([]uint64, len(h.buckets)),
+		Count:   h.count.Load(),
+		Sum:     time.Duration(h.sum.Load()),
+	}
+	var cumulative uint64
+	for i := range h.buckets {
+		cumulative += h.counts[i].Load()
+		snapshot.Counts[i] = cumulative
+	}
+	return snapshot
+}
@@ -0,0 +1,46 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics_test
+
+import (
+	"github.com/echovault/echovault/internal/metrics"
+	"slices"
+	"testing"
+	"time"
+)
+
+func Test_Histogram(t *testing.T) {
+	histogram := metrics.NewHistogram(0.001, 0.01, 0.1)
+
+	histogram.Observe(500 * time.Microsecond)
+	histogram.Observe(time.Millisecond)
+	histogram.Observe(5 * time.Millisecond)
+	histogram.Observe(time.Second)
+
+	snapshot := histogram.Snapshot()
+	if expected := []uint64{2, 3, 3}; !slices.Equal(snapshot.Counts, expected) {
+		t.Errorf("expected cumulative bucket counts %v, got %v", expected, snapshot.Counts)
+	}
+	if snapshot.Count != 4 {
+		t.Errorf("expected 4 observations, got %d", snapshot.Count)
+	}
+	if expected := 1006500 * time.Microsecond; snapshot.Sum != expected {
+		t.Errorf("expected sum %v, got %v", expected, snapshot.Sum)
+	}
+
+	if buckets := metrics.NewHistogram().Snapshot().Buckets; !slices.Equal(buckets, metrics.DefaultBuckets) {
+		t.Errorf("expected default buckets %v, got %v", metrics.DefaultBuckets, buckets)
+	}
+}
@@ -24,9 +24,9 @@ import (
 	"github.com/echovault/echovault/internal"
 	"github.com/echovault/echovault/internal/config"
 	"github.com/echovault/echovault/internal/constants"
+	"github.com/echovault/echovault/internal/logger"
 	"github.com/gobwas/glob"
 	"gopkg.in/yaml.v3"
-	"log"
 	"net"
 	"os"
 	"path"
@@ -50,25 +50,25 @@ type ACL struct {
 	GlobPatterns map[string]glob.Glob
 }
 
-func loadUsersFromConfigFile(filePath string) []*User {
+func loadUsersFromConfigFile(filePath string, l logger.Logger) []*User {
 	var users []*User
 
 	if filePath != "" {
 		// Create the directory if it does not exist.
 		if err := os.MkdirAll(path.Dir(filePath), os.ModePerm); err != nil {
-			log.Printf("mkdir ACL config: %v\n", err)
+			l.Error("create ACL config directory", "file", filePath, logger.ErrorKey, err)
 			return users
 		}
 		// Open the config file. Create it if it does not exist.
 		f, err := os.OpenFile(filePath, os.O_RDONLY|os.O_CREATE, os.ModePerm)
 		if err != nil {
-			log.Printf("open ACL config: %v\n", err)
+			l.Error("open ACL config", "file", filePath, logger.ErrorKey, err)
 			return users
 		}
 
 		defer func() {
 			if err := f.Close(); err != nil {
-				log.Printf("close ACL config: %v\n", err)
+				l.Error("close ACL config", "file", filePath, logger.ErrorKey, err)
 			}
 		}()
 
@@ -76,14 +76,14 @@ func loadUsersFromConfigFile(filePath string) []*User {
 
 		if strings.ToLower(ext) == ".json" {
 			if err := json.NewDecoder(f).Decode(&users); err != nil {
-				log.Printf("load ACL config: %v\n", err)
+				l.Error("load ACL config", "file", filePath, logger.ErrorKey, err)
 				return users
 			}
 		}
 
 		if slices.Contains([]string{".yaml", ".yml"}, strings.ToLower(ext)) {
 			if err := yaml.NewDecoder(f).Decode(&users); err != nil {
-				log.Printf("load ACL config: %v\n", err)
+				l.Error("load ACL config", "file", filePath, logger.ErrorKey, err)
 				return users
 			}
 		}
@@ -92,7 +92,7 @@ func loadUsersFromConfigFile(filePath string) []*User {
 	return users
 }
 
-func NewACL(config config.Config) *ACL {
+func NewACL(config config.Config, logger logger.Logger) *ACL {
 	var users []*User
 
 	// 1. Initialise default ACL user
@@ -108,7 +108,7 @@ func NewACL(config config.Config) *ACL {
 	}
 
 	// 2. Read and parse the ACL config file
-	users = loadUsersFromConfigFile(config.AclConfig)
+	users = loadUsersFromConfigFile(config.AclConfig, logger)
 
 	// 3. If default user was not loaded from file, add the created one
 	defaultLoaded := false
@@ -155,6 +155,47 @@ func (acl *ACL) RegisterConnection(conn *net.Conn) {
 	}
 }
 
+// ConnectionUser returns the name of the user the connection is associated with.
+func (acl *ACL) ConnectionUser(conn *net.Conn) string {
+	acl.RLockUsers()
+	defer acl.RUnlockUsers()
+	connection, ok := acl.Connections[conn]
+	if !ok || connection.User == nil {
+		return ""
+	}
+	return connection.User.Username
+}
+
+// SetRequirePass changes whether connections must authenticate, and the password of the default user.
+// Connections that are already registered keep their authentication state.
+func (acl *ACL) SetRequirePass(requirePass bool, password string) {
+	acl.LockUsers()
+	defer acl.UnlockUsers()
+
+	acl.Config.RequirePass = requirePass
+	acl.Config.Password = password
+
+	defaultUserIdx := slices.IndexFunc(acl.Users, func(user *User) bool {
+		return user.Username == "default"
+	})
+	if defaultUserIdx == -1 {
+		return
+	}
+	defaultUser := acl.Users[defaultUserIdx]
+	if !requirePass {
+		defaultUser.NoPassword = true
+		defaultUser.Passwords = []Password{}
+		return
+	}
+	defaultUser.NoPassword = false
+	defaultUser.Passwords = []Password{
+		{
+			PasswordType:  GetPasswordType(password),
+			PasswordValue: password,
+		},
+	}
+}
+
 func (acl *ACL) SetUser(cmd []string) error {
 	acl.LockUsers()
 	defer acl.UnlockUsers()
@@ -456,6 +497,53 @@ func (acl *ACL) AuthorizeConnection(conn *net.Conn, cmd []string, command intern
 	return nil
 }
 
+// GetState returns the JSON serialized list of ACL users.
+// This is used to replicate the ACL across a raft cluster and to include it in raft snapshots.
+func (acl *ACL) GetState() ([]byte, error) {
+	acl.RLockUsers()
+	defer acl.RUnlockUsers()
+	return json.Marshal(acl.Users)
+}
+
+// SetState replaces the ACL users with the users in the serialized state produced by GetState.
+// Registered connections are re-attached to the user with the same username. Connections whose
+// user no longer exists are terminated.
+func (acl *ACL) SetState(state []byte) error {
+	var users []*User
+	if err := json.Unmarshal(state, &users); err != nil {
+		return fmt.Errorf("set ACL state: %v", err)
+	}
+
+	acl.LockUsers()
+	defer acl.UnlockUsers()
+
+	for _, user := range users {
+		user.Normalise()
+	}
+	// The default user must always be present.
+	if !slices.ContainsFunc(users, func(user *User) bool { return user.Username == "default" }) {
+		idx := slices.IndexFunc(acl.Users, func(user *User) bool { return user.Username == "default" })
+		users = append([]*User{acl.Users[idx]}, users...)
+	}
+
+	for connRef, connection := range acl.Connections {
+		idx := slices.IndexFunc(users, func(user *User) bool {
+			return user.Username == connection.User.Username
+		})
+		if idx == -1 {
+			_ = (*connRef).SetReadDeadline(time.Now().Add(-1 * time.Second))
+			continue
+		}
+		connection.User = users[idx]
+		acl.Connections[connRef] = connection
+	}
+
+	acl.Users = users
+	acl.CompileGlobs()
+
+	return nil
+}
+
 func (acl *ACL) CompileGlobs() {
 	// Extract all the relevant globs from all the users
 	var allGlobs []string
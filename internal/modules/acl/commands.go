@@ -20,8 +20,8 @@ import (
 	"fmt"
 	"github.com/echovault/echovault/internal"
 	"github.com/echovault/echovault/internal/constants"
+	"github.com/echovault/echovault/internal/logger"
 	"gopkg.in/yaml.v3"
-	"log"
 	"os"
 	"path"
 	"slices"
@@ -376,7 +376,7 @@ func handleLoad(params internal.HandlerFuncParams) ([]byte, error) {
 
 	defer func() {
 		if err := f.Close(); err != nil {
-			log.Println(err)
+			params.GetLogger().Error("close ACL config", "file", acl.Config.AclConfig, logger.ErrorKey, err)
 		}
 	}()
 
@@ -442,7 +442,7 @@ func handleSave(params internal.HandlerFuncParams) ([]byte, error) {
 
 	defer func() {
 		if err := f.Close(); err != nil {
-			log.Println(err)
+			params.GetLogger().Error("close ACL config", "file", acl.Config.AclConfig, logger.ErrorKey, err)
 		}
 	}()
 
@@ -485,6 +485,9 @@ func Commands() []internal.Command {
 			Categories:  []string{},
 			Description: "Access-Control-List commands",
 			Sync:        false,
+			Arity:       -2,
+			Complexity:  "Depends on subcommand.",
+			Since:       "0.1.0",
 			KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
 				return internal.KeyExtractionFuncResult{
 					Channels:  make([]string, 0),
@@ -499,7 +502,11 @@ func Commands() []internal.Command {
 					Categories: []string{constants.SlowCategory},
 					Description: `(ACL CAT [category]) Lists all the categories. 
 If the optional category is provided, lists all the commands in the category.`,
-					Sync: false,
+					Sync:       false,
+					Arity:      -2,
+					Flags:      []string{constants.NoScriptFlag, constants.LoadingFlag, constants.StaleFlag},
+					Complexity: "O(1)",
+					Since:      "0.1.0",
 					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
 						return internal.KeyExtractionFuncResult{
 							Channels:  make([]string, 0),
@@ -515,6 +522,10 @@ If the optional category is provided, lists all the commands in the category.`,
 					Categories:  []string{constants.AdminCategory, constants.SlowCategory, constants.DangerousCategory},
 					Description: "(ACL USERS) Lists all usernames of the configured ACL users.",
 					Sync:        false,
+					Arity:       2,
+					Flags:       []string{constants.AdminFlag, constants.LoadingFlag, constants.StaleFlag},
+					Complexity:  "O(N). Where N is the number of configured users.",
+					Since:       "0.1.0",
 					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
 						return internal.KeyExtractionFuncResult{
 							Channels:  make([]string, 0),
@@ -530,6 +541,10 @@ If the optional category is provided, lists all the commands in the category.`,
 					Categories:  []string{constants.AdminCategory, constants.SlowCategory, constants.DangerousCategory},
 					Description: "(ACL SETUSER) Configure a new or existing user",
 					Sync:        true,
+					Arity:       -3,
+					Flags:       []string{constants.AdminFlag, constants.LoadingFlag, constants.StaleFlag},
+					Complexity:  "O(N). Where N is the number of rules provided.",
+					Since:       "0.1.0",
 					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
 						return internal.KeyExtractionFuncResult{
 							Channels:  make([]string, 0),
@@ -545,6 +560,10 @@ If the optional category is provided, lists all the commands in the category.`,
 					Categories:  []string{constants.AdminCategory, constants.SlowCategory, constants.DangerousCategory},
 					Description: "(ACL GETUSER username) List the ACL rules of a user.",
 					Sync:        false,
+					Arity:       3,
+					Flags:       []string{constants.AdminFlag, constants.LoadingFlag, constants.StaleFlag},
+					Complexity:  "O(N). Where N is the number of password, command and pattern rules that the user has.",
+					Since:       "0.1.0",
 					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
 						return internal.KeyExtractionFuncResult{
 							Channels:  make([]string, 0),
@@ -560,7 +579,11 @@ If the optional category is provided, lists all the commands in the category.`,
 					Categories: []string{constants.AdminCategory, constants.SlowCategory, constants.DangerousCategory},
 					Description: `(ACL DELUSER username [username ...]) 
 Deletes users and terminates their connections. Cannot delete default user.`,
-					Sync: true,
+					Sync:       true,
+					Arity:      -3,
+					Flags:      []string{constants.AdminFlag, constants.LoadingFlag, constants.StaleFlag},
+					Complexity: "O(1) amortized time considering the typical user.",
+					Since:      "0.1.0",
 					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
 						return internal.KeyExtractionFuncResult{
 							Channels:  make([]string, 0),
@@ -576,6 +599,10 @@ Deletes users and terminates their connections. Cannot delete default user.`,
 					Categories:  []string{constants.FastCategory},
 					Description: "(ACL WHOAMI) Returns the authenticated user of the current connection.",
 					Sync:        true,
+					Arity:       2,
+					Flags:       []string{constants.NoScriptFlag, constants.LoadingFlag, constants.StaleFlag, constants.FastFlag},
+					Complexity:  "O(1)",
+					Since:       "0.1.0",
 					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
 						return internal.KeyExtractionFuncResult{
 							Channels:  make([]string, 0),
@@ -591,6 +618,10 @@ Deletes users and terminates their connections. Cannot delete default user.`,
 					Categories:  []string{constants.AdminCategory, constants.SlowCategory, constants.DangerousCategory},
 					Description: "(ACL LIST) Dumps effective acl rules in ACL DSL format.",
 					Sync:        true,
+					Arity:       2,
+					Flags:       []string{constants.AdminFlag, constants.LoadingFlag, constants.StaleFlag},
+					Complexity:  "O(N). Where N is the number of configured users.",
+					Since:       "0.1.0",
 					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
 						return internal.KeyExtractionFuncResult{
 							Channels:  make([]string, 0),
@@ -608,7 +639,11 @@ Deletes users and terminates their connections. Cannot delete default user.`,
 (ACL LOAD <MERGE | REPLACE>) Reloads the rules from the configured ACL config file.
 When 'MERGE' is passed, users from config file who share a username with users in memory will be merged.
 When 'REPLACE' is passed, users from config file who share a username with users in memory will replace the user in memory.`,
-					Sync: true,
+					Sync:       true,
+					Arity:      3,
+					Flags:      []string{constants.AdminFlag, constants.LoadingFlag, constants.StaleFlag},
+					Complexity: "O(N). Where N is the number of configured users.",
+					Since:      "0.1.0",
 					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
 						return internal.KeyExtractionFuncResult{
 							Channels:  make([]string, 0),
@@ -624,6 +659,10 @@ When 'REPLACE' is passed, users from config file who share a username with users
 					Categories:  []string{constants.AdminCategory, constants.SlowCategory, constants.DangerousCategory},
 					Description: "(ACL SAVE) Saves the effective ACL rules the configured ACL config file.",
 					Sync:        true,
+					Arity:       2,
+					Flags:       []string{constants.AdminFlag, constants.LoadingFlag, constants.StaleFlag},
+					Complexity:  "O(N). Where N is the number of configured users.",
+					Since:       "0.1.0",
 					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
 						return internal.KeyExtractionFuncResult{
 							Channels:  make([]string, 0),
@@ -18,9 +18,13 @@ import (
 	"errors"
 	"fmt"
 	"github.com/echovault/echovault/internal"
+	"github.com/echovault/echovault/internal/config"
 	"github.com/echovault/echovault/internal/constants"
+	"github.com/echovault/echovault/internal/logger"
+	"github.com/echovault/echovault/internal/monitor"
 	"github.com/gobwas/glob"
 	"slices"
+	"strconv"
 	"strings"
 )
 
@@ -186,7 +190,641 @@ func handleCommandList(params internal.HandlerFuncParams) ([]byte, error) {
 }
 
 func handleCommandDocs(params internal.HandlerFuncParams) ([]byte, error) {
-	return []byte("*0\r\n"), nil
+	commands := params.GetAllCommands()
+
+	var metas []commandMeta
+	if len(params.Command) == 2 {
+		for _, command := range commands {
+			metas = append(metas, newCommandMeta(command))
+		}
+	}
+	for _, name := range params.Command[2:] {
+		// Unknown commands are left out of the response.
+		if meta, ok := lookupCommandMeta(commands, name); ok {
+			metas = append(metas, meta)
+		}
+	}
+
+	res := fmt.Sprintf("*%d\r\n", len(metas)*2)
+	for _, meta := range metas {
+		res += fmt.Sprintf("$%d\r\n%s\r\n%s", len(meta.name), meta.name, formatCommandDocs(meta))
+	}
+	return []byte(res), nil
+}
+
+func handleCommandInfo(params internal.HandlerFuncParams) ([]byte, error) {
+	commands := params.GetAllCommands()
+
+	// COMMAND without a subcommand returns the information of every command, like COMMAND INFO without names.
+	if len(params.Command) <= 2 {
+		res := fmt.Sprintf("*%d\r\n", len(commands))
+		for _, command := range commands {
+			res += formatCommandInfo(newCommandMeta(command))
+		}
+		return []byte(res), nil
+	}
+
+	res := fmt.Sprintf("*%d\r\n", len(params.Command[2:]))
+	for _, name := range params.Command[2:] {
+		meta, ok := lookupCommandMeta(commands, name)
+		if !ok {
+			res += "*-1\r\n"
+			continue
+		}
+		res += formatCommandInfo(meta)
+	}
+	return []byte(res), nil
+}
+
+func handleCommandGetKeys(params internal.HandlerFuncParams) ([]byte, error) {
+	if len(params.Command) < 3 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+
+	keys, err := commandKeys(params.GetAllCommands(), params.Command[2:])
+	if err != nil {
+		return nil, err
+	}
+
+	res := fmt.Sprintf("*%d\r\n", len(keys))
+	for _, key := range keys {
+		res += fmt.Sprintf("$%d\r\n%s\r\n", len(key), key)
+	}
+	return []byte(res), nil
+}
+
+// infoSections lists the sections of the INFO command in the order they're returned.
+// The commandstats section is only returned when requested explicitly or by the all and everything sections.
+var infoSections = []struct {
+	name   string
+	format func(info internal.Info) string
+}{
+	{name: "server", format: serverInfo},
+	{name: "clients", format: clientsInfo},
+	{name: "memory", format: memoryInfo},
+	{name: "persistence", format: persistenceInfo},
+	{name: "stats", format: statsInfo},
+	{name: "replication", format: replicationInfo},
+	{name: "cpu", format: cpuInfo},
+	{name: "commandstats", format: commandStatsInfo},
+	{name: "errorstats", format: errorStatsInfo},
+	{name: "cluster", format: clusterInfo},
+	{name: "keyspace", format: keyspaceInfo},
+}
+
+func handleInfo(params internal.HandlerFuncParams) ([]byte, error) {
+	sections := []string{"default"}
+	if len(params.Command) > 1 {
+		sections = params.Command[1:]
+	}
+
+	requested := make(map[string]bool)
+	for _, section := range sections {
+		switch section = strings.ToLower(section); section {
+		case "default":
+			for _, s := range infoSections {
+				requested[s.name] = requested[s.name] || s.name != "commandstats"
+			}
+		case "all", "everything":
+			for _, s := range infoSections {
+				requested[s.name] = true
+			}
+		default:
+			requested[section] = true
+		}
+	}
+
+	info := params.GetInfo()
+	var res []string
+	for _, section := range infoSections {
+		if requested[section.name] {
+			res = append(res, section.format(info))
+		}
+	}
+
+	body := strings.Join(res, "\r\n")
+	return []byte(fmt.Sprintf("$%d\r\n%s\r\n", len(body), body)), nil
+}
+
+func serverInfo(info internal.Info) string {
+	res := "# Server\r\n"
+	res += fmt.Sprintf("server_name:%s\r\n", info.Server.Server)
+	res += fmt.Sprintf("echovault_version:%s\r\n", info.Server.Version)
+	res += fmt.Sprintf("server_id:%s\r\n", info.Server.Id)
+	res += fmt.Sprintf("server_mode:%s\r\n", info.Server.Mode)
+	res += fmt.Sprintf("os:%s\r\n", info.Server.OS)
+	res += fmt.Sprintf("go_version:%s\r\n", info.Server.GoVersion)
+	res += fmt.Sprintf("process_id:%d\r\n", info.Server.ProcessID)
+	res += fmt.Sprintf("tcp_port:%d\r\n", info.Server.TCPPort)
+	res += fmt.Sprintf("uptime_in_seconds:%d\r\n", int64(info.Server.Uptime.Seconds()))
+	res += fmt.Sprintf("uptime_in_days:%d\r\n", int64(info.Server.Uptime.Hours()/24))
+	return res
+}
+
+func clientsInfo(info internal.Info) string {
+	res := "# Clients\r\n"
+	res += fmt.Sprintf("connected_clients:%d\r\n", info.Clients.ConnectedClients)
+	return res
+}
+
+func memoryInfo(info internal.Info) string {
+	res := "# Memory\r\n"
+	res += fmt.Sprintf("used_memory:%d\r\n", info.Memory.HeapAlloc)
+	res += fmt.Sprintf("used_memory_human:%s\r\n", bytesToHuman(info.Memory.HeapAlloc))
+	res += fmt.Sprintf("used_memory_dataset:%d\r\n", info.Memory.Dataset)
+	res += fmt.Sprintf("used_memory_dataset_peak:%d\r\n", info.Memory.PeakDataset)
+	res += fmt.Sprintf("maxmemory:%d\r\n", info.Memory.MaxMemory)
+	res += fmt.Sprintf("maxmemory_human:%s\r\n", bytesToHuman(info.Memory.MaxMemory))
+	res += fmt.Sprintf("maxmemory_policy:%s\r\n", info.Memory.EvictionPolicy)
+	return res
+}
+
+func persistenceInfo(info internal.Info) string {
+	res := "# Persistence\r\n"
+	res += fmt.Sprintf("rdb_changes_since_last_save:%d\r\n", info.Persistence.ChangesSinceSnapshot)
+	res += fmt.Sprintf("rdb_bgsave_in_progress:%d\r\n", boolToInt(info.Persistence.SnapshotInProgress))
+	res += fmt.Sprintf("rdb_last_save_time:%d\r\n", info.Persistence.LastSnapshot/1000)
+	res += fmt.Sprintf("aof_enabled:%d\r\n", boolToInt(info.Persistence.AOFEnabled))
+	res += fmt.Sprintf("aof_rewrite_in_progress:%d\r\n", boolToInt(info.Persistence.AOFRewriteInProgress))
+	if info.Persistence.AOFEnabled {
+		res += fmt.Sprintf("aof_offset:%d\r\n", info.Persistence.AOFOffset)
+		res += fmt.Sprintf("aof_synced_offset:%d\r\n", info.Persistence.AOFSyncedOffset)
+	}
+	return res
+}
+
+func cpuInfo(info internal.Info) string {
+	res := "# CPU\r\n"
+	res += fmt.Sprintf("used_cpu_sys:%.6f\r\n", info.CPU.UsedSys.Seconds())
+	res += fmt.Sprintf("used_cpu_user:%.6f\r\n", info.CPU.UsedUser.Seconds())
+	res += fmt.Sprintf("goroutines:%d\r\n", info.CPU.Goroutines)
+	return res
+}
+
+func commandStatsInfo(info internal.Info) string {
+	res := "# Commandstats\r\n"
+	names := make([]string, 0, len(info.CommandStats))
+	for name := range info.CommandStats {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		stats := info.CommandStats[name]
+		usec := stats.Time.Microseconds()
+		res += fmt.Sprintf("cmdstat_%s:calls=%d,usec=%d,usec_per_call=%.2f,rejected_calls=%d,failed_calls=%d\r\n",
+			name, stats.Calls, usec, float64(usec)/float64(max(stats.Calls, 1)), stats.RejectedCalls, stats.FailedCalls)
+	}
+	return res
+}
+
+func errorStatsInfo(info internal.Info) string {
+	res := "# Errorstats\r\n"
+	prefixes := make([]string, 0, len(info.ErrorStats))
+	for prefix := range info.ErrorStats {
+		prefixes = append(prefixes, prefix)
+	}
+	slices.Sort(prefixes)
+	for _, prefix := range prefixes {
+		res += fmt.Sprintf("errorstat_%s:count=%d\r\n", prefix, info.ErrorStats[prefix])
+	}
+	return res
+}
+
+func keyspaceInfo(info internal.Info) string {
+	res := "# Keyspace\r\n"
+	databases := make([]int, 0, len(info.Keyspace))
+	for database := range info.Keyspace {
+		databases = append(databases, database)
+	}
+	slices.Sort(databases)
+	for _, database := range databases {
+		res += fmt.Sprintf("db%d:keys=%d,expires=%d\r\n",
+			database, info.Keyspace[database].Keys, info.Keyspace[database].Expires)
+	}
+	return res
+}
+
+// bytesToHuman formats a number of bytes with a binary unit suffix (e.g. 1.50M).
+func bytesToHuman(n uint64) string {
+	units := []string{"B", "K", "M", "G", "T", "P"}
+	value, i := float64(n), 0
+	for value >= 1024 && i < len(units)-1 {
+		value /= 1024
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%dB", n)
+	}
+	return fmt.Sprintf("%.2f%s", value, units[i])
+}
+
+func replicationInfo(i internal.Info) string {
+	info := i.Replication
+	res := "# Replication\r\n"
+	if i.Cluster.Enabled {
+		// In cluster mode, the raft leader is the primary and the other voters are its replicas.
+		role := "slave"
+		if i.Cluster.RaftState == "leader" {
+			role = "master"
+		}
+		res += fmt.Sprintf("role:%s\r\n", role)
+		res += fmt.Sprintf("raft_state:%s\r\n", i.Cluster.RaftState)
+		res += fmt.Sprintf("raft_leader:%s\r\n", i.Cluster.Leader)
+		res += fmt.Sprintf("raft_followers:%d\r\n", len(i.Cluster.Followers))
+		for n, follower := range i.Cluster.Followers {
+			res += fmt.Sprintf("raft_follower%d:id=%s\r\n", n, follower)
+		}
+		return res
+	}
+	res += fmt.Sprintf("role:%s\r\n", info.Role)
+	if info.Role == "slave" {
+		linkStatus := "down"
+		if info.LinkStatus == "connected" {
+			linkStatus = "up"
+		}
+		res += fmt.Sprintf("master_host:%s\r\n", info.PrimaryHost)
+		res += fmt.Sprintf("master_port:%d\r\n", info.PrimaryPort)
+		res += fmt.Sprintf("master_link_status:%s\r\n", linkStatus)
+		res += fmt.Sprintf("master_sync_in_progress:%d\r\n", boolToInt(info.LinkStatus == "sync"))
+		res += fmt.Sprintf("slave_repl_offset:%d\r\n", info.Offset)
+	}
+	res += fmt.Sprintf("connected_slaves:%d\r\n", len(info.Replicas))
+	for i, r := range info.Replicas {
+		res += fmt.Sprintf("slave%d:ip=%s,port=%d,state=%s,offset=%d,lag=%d\r\n",
+			i, r.Addr, r.Port, r.State, r.Offset, int64(r.Lag.Seconds()))
+	}
+	res += fmt.Sprintf("master_replid:%s\r\n", info.ReplID)
+	res += fmt.Sprintf("master_repl_offset:%d\r\n", info.Offset)
+	res += fmt.Sprintf("sync_full:%d\r\n", info.SyncFull)
+	res += fmt.Sprintf("sync_partial_ok:%d\r\n", info.SyncPartialOK)
+	res += fmt.Sprintf("sync_partial_err:%d\r\n", info.SyncPartialErr)
+	res += "repl_backlog_active:1\r\n"
+	res += fmt.Sprintf("repl_backlog_size:%d\r\n", info.BacklogSize)
+	res += fmt.Sprintf("repl_backlog_first_byte_offset:%d\r\n", info.BacklogFirstByteOffset)
+	res += fmt.Sprintf("repl_backlog_histlen:%d\r\n", info.BacklogHistLen)
+	return res
+}
+
+func handleClusterForget(params internal.HandlerFuncParams) ([]byte, error) {
+	if len(params.Command) != 3 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	if err := params.ForgetNode(params.Command[2]); err != nil {
+		return nil, err
+	}
+	return []byte(constants.OkResponse), nil
+}
+
+func handleClusterDecommission(params internal.HandlerFuncParams) ([]byte, error) {
+	if len(params.Command) != 2 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	if err := params.DecommissionNode(); err != nil {
+		return nil, err
+	}
+	return []byte(constants.OkResponse), nil
+}
+
+func handleConfigGet(params internal.HandlerFuncParams) ([]byte, error) {
+	conf, ok := params.GetConfig().(config.Config)
+	if !ok {
+		return nil, errors.New("could not load server config")
+	}
+
+	if len(params.Command) < 3 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+
+	res := ""
+	count := 0
+	for _, parameter := range config.Parameters() {
+		if !slices.ContainsFunc(params.Command[2:], func(pattern string) bool {
+			g, err := glob.Compile(strings.ToLower(pattern))
+			return err == nil && g.Match(parameter.Name)
+		}) {
+			continue
+		}
+		value := parameter.Get(conf)
+		res += fmt.Sprintf("$%d\r\n%s\r\n$%d\r\n%s\r\n", len(parameter.Name), parameter.Name, len(value), value)
+		count += 2
+	}
+
+	return []byte(fmt.Sprintf("*%d\r\n%s", count, res)), nil
+}
+
+func handleConfigSet(params internal.HandlerFuncParams) ([]byte, error) {
+	if len(params.Command) < 4 || len(params.Command)%2 != 0 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+
+	values := make(map[string]string, (len(params.Command)-2)/2)
+	for i := 2; i < len(params.Command); i += 2 {
+		name := strings.ToLower(params.Command[i])
+		if _, ok := values[name]; ok {
+			return nil, fmt.Errorf("duplicate CONFIG parameter %s", name)
+		}
+		values[name] = params.Command[i+1]
+	}
+
+	if err := params.SetConfig(values); err != nil {
+		return nil, err
+	}
+
+	return []byte(constants.OkResponse), nil
+}
+
+func handleConfigRewrite(params internal.HandlerFuncParams) ([]byte, error) {
+	if len(params.Command) != 2 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+
+	if err := params.RewriteConfig(); err != nil {
+		return nil, err
+	}
+
+	return []byte(constants.OkResponse), nil
+}
+
+func handleConfigResetStat(params internal.HandlerFuncParams) ([]byte, error) {
+	if len(params.Command) != 2 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+
+	params.ResetStats()
+
+	return []byte(constants.OkResponse), nil
+}
+
+func handleMemoryUsage(params internal.HandlerFuncParams) ([]byte, error) {
+	if len(params.Command) != 3 && len(params.Command) != 5 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+
+	samples := internal.DefaultMemorySamples
+	if len(params.Command) == 5 {
+		if !strings.EqualFold(params.Command[3], "samples") {
+			return nil, fmt.Errorf("unknown option %s", params.Command[3])
+		}
+		var err error
+		samples, err = strconv.Atoi(params.Command[4])
+		if err != nil || samples < 0 {
+			return nil, errors.New("samples must be a positive integer")
+		}
+	}
+
+	size, ok := params.GetMemoryUsage(params.Context, params.Command[2], samples)
+	if !ok {
+		return []byte("$-1\r\n"), nil
+	}
+	return []byte(fmt.Sprintf(":%d\r\n", size)), nil
+}
+
+func handleMemoryStats(params internal.HandlerFuncParams) ([]byte, error) {
+	if len(params.Command) != 2 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+
+	stats := params.GetMemoryStats()
+
+	bytesPerKey := int64(0)
+	if stats.Keys > 0 {
+		bytesPerKey = stats.Dataset / int64(stats.Keys)
+	}
+
+	res := ""
+	count := 0
+	integer := func(name string, value interface{}) {
+		res += fmt.Sprintf("$%d\r\n%s\r\n:%d\r\n", len(name), name, value)
+		count += 2
+	}
+	integer("peak.dataset", stats.PeakDataset)
+	integer("total.allocated", stats.HeapAlloc)
+	integer("dataset.bytes", stats.Dataset)
+	integer("keys.count", stats.Keys)
+	integer("keys.bytes-per-key", bytesPerKey)
+	integer("maxmemory", stats.MaxMemory)
+	res += fmt.Sprintf("$16\r\nmaxmemory-policy\r\n$%d\r\n%s\r\n", len(stats.EvictionPolicy), stats.EvictionPolicy)
+	count += 2
+	integer("evicted.keys", stats.EvictedKeys)
+
+	databases := make([]int, 0, len(stats.Databases))
+	for database, _ := range stats.Databases {
+		databases = append(databases, database)
+	}
+	slices.Sort(databases)
+	for _, database := range databases {
+		name := fmt.Sprintf("db.%d", database)
+		res += fmt.Sprintf("$%d\r\n%s\r\n*4\r\n$4\r\nkeys\r\n:%d\r\n$13\r\ndataset.bytes\r\n:%d\r\n",
+			len(name), name, stats.Databases[database].Keys, stats.Databases[database].Dataset)
+		count += 2
+	}
+
+	return []byte(fmt.Sprintf("*%d\r\n%s", count, res)), nil
+}
+
+func handleEvictionPin(params internal.HandlerFuncParams) ([]byte, error) {
+	if len(params.Command) < 3 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	count, err := params.PinKeys(params.Command[2:])
+	if err != nil {
+		return nil, err
+	}
+	return []byte(fmt.Sprintf(":%d\r\n", count)), nil
+}
+
+func handleEvictionUnpin(params internal.HandlerFuncParams) ([]byte, error) {
+	if len(params.Command) < 3 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	return []byte(fmt.Sprintf(":%d\r\n", params.UnpinKeys(params.Command[2:]))), nil
+}
+
+func handleEvictionPinned(params internal.HandlerFuncParams) ([]byte, error) {
+	if len(params.Command) != 2 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	patterns := params.GetPinnedKeys()
+	res := fmt.Sprintf("*%d\r\n", len(patterns))
+	for _, pattern := range patterns {
+		res += fmt.Sprintf("$%d\r\n%s\r\n", len(pattern), pattern)
+	}
+	return []byte(res), nil
+}
+
+func handleSlowlogGet(params internal.HandlerFuncParams) ([]byte, error) {
+	if len(params.Command) > 3 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+
+	count := 10
+	if len(params.Command) == 3 {
+		var err error
+		if count, err = strconv.Atoi(params.Command[2]); err != nil || count < -1 {
+			return nil, errors.New("count should be greater than or equal to -1")
+		}
+	}
+
+	entries := params.GetSlowlog(count)
+	res := fmt.Sprintf("*%d\r\n", len(entries))
+	for _, entry := range entries {
+		res += fmt.Sprintf("*6\r\n:%d\r\n:%d\r\n:%d\r\n*%d\r\n",
+			entry.ID, entry.Timestamp.Unix(), entry.Duration.Microseconds(), len(entry.Command))
+		for _, arg := range entry.Command {
+			res += fmt.Sprintf("$%d\r\n%s\r\n", len(arg), arg)
+		}
+		res += fmt.Sprintf("$%d\r\n%s\r\n$%d\r\n%s\r\n",
+			len(entry.ClientAddr), entry.ClientAddr, len(entry.ClientName), entry.ClientName)
+	}
+	return []byte(res), nil
+}
+
+func handleSlowlogLen(params internal.HandlerFuncParams) ([]byte, error) {
+	if len(params.Command) != 2 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	return []byte(fmt.Sprintf(":%d\r\n", params.GetSlowlogLen())), nil
+}
+
+func handleSlowlogReset(params internal.HandlerFuncParams) ([]byte, error) {
+	if len(params.Command) != 2 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	params.ResetSlowlog()
+	return []byte(constants.OkResponse), nil
+}
+
+func handleMonitor(params internal.HandlerFuncParams) ([]byte, error) {
+	if len(params.Command) != 1 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+
+	if params.Connection == nil {
+		return nil, errors.New("MONITOR is only supported over a TCP connection")
+	}
+
+	// The subscription ends when the connection closes.
+	entries := params.SubscribeMonitor(params.Context)
+	params.UpdateConnectionInfo(params.Connection, func(info *internal.ConnectionInfo) {
+		info.Monitor = true
+	})
+
+	// The confirmation and the entries are written directly to the connection.
+	conn := *params.Connection
+	if _, err := conn.Write([]byte(constants.OkResponse)); err != nil {
+		return nil, err
+	}
+
+	log := params.GetLogger()
+	go func() {
+		for entry := range entries {
+			if _, err := conn.Write(monitor.Format(entry)); err != nil {
+				log.Debug("monitor disconnected", logger.ErrorKey, err)
+				return
+			}
+		}
+	}()
+
+	return nil, nil
+}
+
+func handleLatencyLatest(params internal.HandlerFuncParams) ([]byte, error) {
+	if len(params.Command) != 2 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	events := params.GetLatestLatency()
+	res := fmt.Sprintf("*%d\r\n", len(events))
+	for _, event := range events {
+		res += fmt.Sprintf("*4\r\n$%d\r\n%s\r\n:%d\r\n:%d\r\n:%d\r\n",
+			len(event.Name), event.Name, event.Timestamp.Unix(), event.Latest.Milliseconds(), event.Max.Milliseconds())
+	}
+	return []byte(res), nil
+}
+
+func handleLatencyHistory(params internal.HandlerFuncParams) ([]byte, error) {
+	if len(params.Command) != 3 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	samples := params.GetLatencyHistory(strings.ToLower(params.Command[2]))
+	res := fmt.Sprintf("*%d\r\n", len(samples))
+	for _, sample := range samples {
+		res += fmt.Sprintf("*2\r\n:%d\r\n:%d\r\n", sample.Timestamp.Unix(), sample.Latency.Milliseconds())
+	}
+	return []byte(res), nil
+}
+
+func handleLatencyReset(params internal.HandlerFuncParams) ([]byte, error) {
+	events := make([]string, len(params.Command)-2)
+	for i, event := range params.Command[2:] {
+		events[i] = strings.ToLower(event)
+	}
+	return []byte(fmt.Sprintf(":%d\r\n", params.ResetLatency(events))), nil
+}
+
+func handleLatencyHistogram(params internal.HandlerFuncParams) ([]byte, error) {
+	histograms := params.GetLatencyHistogram(params.Command[2:])
+
+	commands := make([]string, 0, len(histograms))
+	for command := range histograms {
+		commands = append(commands, command)
+	}
+	slices.Sort(commands)
+
+	res := fmt.Sprintf("*%d\r\n", 2*len(commands))
+	for _, command := range commands {
+		histogram := histograms[command]
+		res += fmt.Sprintf("$%d\r\n%s\r\n*4\r\n$5\r\ncalls\r\n:%d\r\n$14\r\nhistogram_usec\r\n*%d\r\n",
+			len(command), command, histogram.Calls, 2*len(histogram.Buckets))
+		for _, bucket := range histogram.Buckets {
+			res += fmt.Sprintf(":%d\r\n:%d\r\n", bucket.UpperBound.Microseconds(), bucket.Count)
+		}
+	}
+	return []byte(res), nil
+}
+
+func statsInfo(i internal.Info) string {
+	info := i.Stats
+	res := "# Stats\r\n"
+	res += fmt.Sprintf("total_connections_received:%d\r\n", info.TotalConnections)
+	res += fmt.Sprintf("total_commands_processed:%d\r\n", info.TotalCommands)
+	res += fmt.Sprintf("total_error_replies:%d\r\n", info.TotalErrors)
+	res += fmt.Sprintf("keyspace_hits:%d\r\n", info.KeyspaceHits)
+	res += fmt.Sprintf("keyspace_misses:%d\r\n", info.KeyspaceMisses)
+	res += fmt.Sprintf("evicted_keys:%d\r\n", info.EvictedKeys)
+	res += fmt.Sprintf("expired_keys:%d\r\n", info.ExpiredKeys)
+	res += fmt.Sprintf("expired_time_cap_reached_count:%d\r\n", info.TimeCapReached)
+	res += fmt.Sprintf("expire_cycles:%d\r\n", info.Cycles)
+	res += fmt.Sprintf("expired_keys_last_cycle:%d\r\n", info.LastCycleExpired)
+	res += fmt.Sprintf("expire_cycle_cpu_milliseconds:%d\r\n", info.CycleTime.Milliseconds())
+	res += fmt.Sprintf("volatile_keys:%d\r\n", info.VolatileKeys)
+	return res
+}
+
+func clusterInfo(i internal.Info) string {
+	info := i.Cluster
+	res := "# Cluster\r\n"
+	res += fmt.Sprintf("cluster_enabled:%d\r\n", boolToInt(info.Enabled))
+	if !info.Enabled {
+		return res
+	}
+	res += fmt.Sprintf("cluster_join_state:%s\r\n", info.JoinState)
+	res += fmt.Sprintf("cluster_join_attempts:%d\r\n", info.JoinAttempts)
+	if info.JoinError != "" {
+		res += fmt.Sprintf("cluster_join_error:%s\r\n", strings.ReplaceAll(info.JoinError, "\r\n", " "))
+	}
+	res += fmt.Sprintf("cluster_seeds:%s\r\n", strings.Join(info.Seeds, ","))
+	res += fmt.Sprintf("cluster_known_nodes:%d\r\n", info.Members)
+	res += fmt.Sprintf("cluster_raft_state:%s\r\n", info.RaftState)
+	res += fmt.Sprintf("cluster_leader:%s\r\n", info.Leader)
+	res += fmt.Sprintf("cluster_ready:%d\r\n", boolToInt(info.Ready))
+	return res
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
 }
 
 func Commands() []internal.Command {
@@ -197,6 +835,10 @@ func Commands() []internal.Command {
 			Categories:  []string{constants.AdminCategory, constants.SlowCategory},
 			Description: "Get a list of all the commands in available on the echovault with categories and descriptions.",
 			Sync:        false,
+			Arity:       1,
+			Flags:       []string{constants.AdminFlag, constants.LoadingFlag, constants.StaleFlag},
+			Complexity:  "O(N) where N is the total number of commands.",
+			Since:       "0.1.0",
 			KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
 				return internal.KeyExtractionFuncResult{
 					Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
@@ -205,23 +847,34 @@ func Commands() []internal.Command {
 			HandlerFunc: handleGetAllCommands,
 		},
 		{
-			Command:     "command",
-			Module:      constants.AdminModule,
-			Categories:  []string{},
-			Description: "Commands pertaining to echovault commands",
-			Sync:        false,
+			Command:    "command",
+			Module:     constants.AdminModule,
+			Categories: []string{},
+			Description: `(COMMAND) Commands pertaining to echovault commands.
+Without a subcommand, returns the information of every command in the format of COMMAND INFO.`,
+			Sync:       false,
+			Arity:      -1,
+			Complexity: "Depends on subcommand.",
+			Since:      "0.1.0",
 			KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
 				return internal.KeyExtractionFuncResult{
 					Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
 				}, nil
 			},
+			HandlerFunc: handleCommandInfo,
 			SubCommands: []internal.SubCommand{
 				{
-					Command:     "docs",
-					Module:      constants.AdminModule,
-					Categories:  []string{constants.SlowCategory, constants.ConnectionCategory},
-					Description: "Get command documentation",
-					Sync:        false,
+					Command:    "docs",
+					Module:     constants.AdminModule,
+					Categories: []string{constants.SlowCategory, constants.ConnectionCategory},
+					Description: `(COMMAND DOCS [command-name [command-name ...]]) Returns the summary, version, module and
+time complexity of the given commands, or of every command when no names are given.
+Subcommands are named in the form command|subcommand.`,
+					Sync:       false,
+					Arity:      -2,
+					Flags:      []string{constants.LoadingFlag, constants.StaleFlag},
+					Complexity: "O(N) where N is the number of commands to look up.",
+					Since:      "0.1.0",
 					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
 						return internal.KeyExtractionFuncResult{
 							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
@@ -235,6 +888,10 @@ func Commands() []internal.Command {
 					Categories:  []string{constants.AdminCategory, constants.SlowCategory},
 					Description: "Get the dumber of commands in the echovault instance.",
 					Sync:        false,
+					Arity:       2,
+					Flags:       []string{constants.AdminFlag, constants.LoadingFlag, constants.StaleFlag},
+					Complexity:  "O(1)",
+					Since:       "0.1.0",
 					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
 						return internal.KeyExtractionFuncResult{
 							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
@@ -248,7 +905,11 @@ func Commands() []internal.Command {
 					Categories: []string{constants.AdminCategory, constants.SlowCategory},
 					Description: `(COMMAND LIST [FILTERBY <ACLCAT category | PATTERN pattern | MODULE module>]) 
 Get the list of command names. Allows for filtering by ACL category or glob pattern.`,
-					Sync: false,
+					Sync:       false,
+					Arity:      -2,
+					Flags:      []string{constants.AdminFlag, constants.LoadingFlag, constants.StaleFlag},
+					Complexity: "O(N) where N is the total number of commands.",
+					Since:      "0.1.0",
 					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
 						return internal.KeyExtractionFuncResult{
 							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
@@ -256,6 +917,43 @@ Get the list of command names. Allows for filtering by ACL category or glob patt
 					},
 					HandlerFunc: handleCommandList,
 				},
+				{
+					Command:    "info",
+					Module:     constants.AdminModule,
+					Categories: []string{constants.SlowCategory, constants.ConnectionCategory},
+					Description: `(COMMAND INFO [command-name [command-name ...]]) Returns the arity, flags, key positions,
+ACL categories, key specs and subcommands of the given commands, or of every command when no names are given.
+Subcommands are named in the form command|subcommand.`,
+					Sync:       false,
+					Arity:      -2,
+					Flags:      []string{constants.LoadingFlag, constants.StaleFlag},
+					Complexity: "O(N) where N is the number of commands to look up.",
+					Since:      "0.10.1",
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleCommandInfo,
+				},
+				{
+					Command:    "getkeys",
+					Module:     constants.AdminModule,
+					Categories: []string{constants.SlowCategory, constants.ConnectionCategory},
+					Description: `(COMMAND GETKEYS command [arg [arg ...]]) Returns the keys that the given command
+would access, in the order in which they appear in the command.`,
+					Sync:       false,
+					Arity:      -3,
+					Flags:      []string{constants.LoadingFlag, constants.StaleFlag},
+					Complexity: "O(N) where N is the number of arguments to the command.",
+					Since:      "0.10.1",
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleCommandGetKeys,
+				},
 			},
 		},
 		{
@@ -264,6 +962,10 @@ Get the list of command names. Allows for filtering by ACL category or glob patt
 			Categories:  []string{constants.AdminCategory, constants.SlowCategory, constants.DangerousCategory},
 			Description: "(SAVE) Trigger a snapshot save.",
 			Sync:        true,
+			Arity:       1,
+			Flags:       []string{constants.AdminFlag, constants.NoScriptFlag},
+			Complexity:  "O(N) where N is the total number of keys in all databases.",
+			Since:       "0.1.0",
 			KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
 				return internal.KeyExtractionFuncResult{
 					Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
@@ -282,6 +984,10 @@ Get the list of command names. Allows for filtering by ACL category or glob patt
 			Categories:  []string{constants.AdminCategory, constants.FastCategory, constants.DangerousCategory},
 			Description: "(LASTSAVE) Get unix timestamp for the latest snapshot in milliseconds.",
 			Sync:        false,
+			Arity:       1,
+			Flags:       []string{constants.AdminFlag, constants.LoadingFlag, constants.StaleFlag, constants.FastFlag},
+			Complexity:  "O(1)",
+			Since:       "0.1.0",
 			KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
 				return internal.KeyExtractionFuncResult{
 					Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
@@ -301,6 +1007,10 @@ Get the list of command names. Allows for filtering by ACL category or glob patt
 			Categories:  []string{constants.AdminCategory, constants.SlowCategory, constants.DangerousCategory},
 			Description: "(REWRITEAOF) Trigger re-writing of append process.",
 			Sync:        false,
+			Arity:       1,
+			Flags:       []string{constants.AdminFlag, constants.NoScriptFlag},
+			Complexity:  "O(N) where N is the total number of keys in all databases.",
+			Since:       "0.1.0",
 			KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
 				return internal.KeyExtractionFuncResult{
 					Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
@@ -313,11 +1023,504 @@ Get the list of command names. Allows for filtering by ACL category or glob patt
 				return []byte(constants.OkResponse), nil
 			},
 		},
+		{
+			Command:     "cluster",
+			Module:      constants.AdminModule,
+			Categories:  []string{},
+			Description: "",
+			Sync:        false,
+			Arity:       -2,
+			Complexity:  "Depends on subcommand.",
+			Since:       "0.10.1",
+			KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+				return internal.KeyExtractionFuncResult{
+					Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+				}, nil
+			},
+			HandlerFunc: func(_ internal.HandlerFuncParams) ([]byte, error) {
+				return nil, errors.New("provide FORGET or DECOMMISSION subcommand")
+			},
+			SubCommands: []internal.SubCommand{
+				{
+					Command:    "forget",
+					Module:     constants.AdminModule,
+					Categories: []string{constants.AdminCategory, constants.SlowCategory, constants.DangerousCategory},
+					Description: `(CLUSTER FORGET server-id) Removes the node from the cluster's raft configuration.
+Must be sent to the leader. The node is only removed when the remaining voters can still form a quorum.`,
+					Sync:       false,
+					Arity:      3,
+					Flags:      []string{constants.AdminFlag},
+					Complexity: "O(1)",
+					Since:      "0.10.1",
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleClusterForget,
+				},
+				{
+					Command:    "decommission",
+					Module:     constants.AdminModule,
+					Categories: []string{constants.AdminCategory, constants.SlowCategory, constants.DangerousCategory},
+					Description: `(CLUSTER DECOMMISSION) Drains the current node out of the cluster. The node hands leadership
+over, is removed from the raft configuration and leaves the memberlist. The node is only removed when the remaining
+voters can still form a quorum.`,
+					Sync:       false,
+					Arity:      2,
+					Flags:      []string{constants.AdminFlag},
+					Complexity: "O(N) where N is the number of nodes in the cluster.",
+					Since:      "0.10.1",
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleClusterDecommission,
+				},
+			},
+		},
+		{
+			Command:     "config",
+			Module:      constants.AdminModule,
+			Categories:  []string{},
+			Description: "Commands pertaining to the runtime configuration of the server",
+			Sync:        false,
+			Arity:       -2,
+			Complexity:  "Depends on subcommand.",
+			Since:       "0.10.1",
+			KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+				return internal.KeyExtractionFuncResult{
+					Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+				}, nil
+			},
+			HandlerFunc: func(_ internal.HandlerFuncParams) ([]byte, error) {
+				return nil, errors.New("provide GET, SET, REWRITE or RESETSTAT subcommand")
+			},
+			SubCommands: []internal.SubCommand{
+				{
+					Command:    "get",
+					Module:     constants.AdminModule,
+					Categories: []string{constants.AdminCategory, constants.SlowCategory, constants.DangerousCategory},
+					Description: `(CONFIG GET parameter [parameter ...]) Returns the names and values of the configuration
+parameters that match the glob patterns. The parameters are named after their command line flags.`,
+					Sync:       false,
+					Arity:      -3,
+					Flags:      []string{constants.AdminFlag, constants.NoScriptFlag, constants.LoadingFlag, constants.StaleFlag},
+					Complexity: "O(N) when N is the number of configuration parameters provided.",
+					Since:      "0.10.1",
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleConfigGet,
+				},
+				{
+					Command:    "set",
+					Module:     constants.AdminModule,
+					Categories: []string{constants.AdminCategory, constants.SlowCategory, constants.DangerousCategory},
+					Description: `(CONFIG SET parameter value [parameter value ...]) Changes the configuration parameters on this
+server while it's running. Either all the parameters are changed or none of them. The mutable parameters are
+aof-sync-strategy, eviction-policy, eviction-sample, latency-monitor-threshold, lfu-decay-time, lfu-log-factor,
+max-memory, notify-keyspace-events, password, require-pass, slowlog-log-slower-than, slowlog-max-len,
+snapshot-interval and snapshot-threshold.`,
+					Sync:       false,
+					Arity:      -4,
+					Flags:      []string{constants.AdminFlag, constants.NoScriptFlag, constants.LoadingFlag, constants.StaleFlag},
+					Complexity: "O(N) when N is the number of configuration parameters provided.",
+					Since:      "0.10.1",
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleConfigSet,
+				},
+				{
+					Command:    "rewrite",
+					Module:     constants.AdminModule,
+					Categories: []string{constants.AdminCategory, constants.SlowCategory, constants.DangerousCategory},
+					Description: `(CONFIG REWRITE) Writes the values of the mutable configuration parameters to the JSON or YAML
+config file the server was started with. The other entries of the file are kept as they are.`,
+					Sync:       false,
+					Arity:      2,
+					Flags:      []string{constants.AdminFlag, constants.NoScriptFlag, constants.LoadingFlag, constants.StaleFlag},
+					Complexity: "O(1)",
+					Since:      "0.10.1",
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleConfigRewrite,
+				},
+				{
+					Command:    "resetstat",
+					Module:     constants.AdminModule,
+					Categories: []string{constants.AdminCategory, constants.SlowCategory, constants.DangerousCategory},
+					Description: `(CONFIG RESETSTAT) Resets the statistics reported by INFO: the calls, rejected calls and
+failed calls of each command, the errors by prefix, the number of commands processed and the keyspace hits and misses.`,
+					Sync:       false,
+					Arity:      2,
+					Flags:      []string{constants.AdminFlag, constants.NoScriptFlag, constants.LoadingFlag, constants.StaleFlag},
+					Complexity: "O(1)",
+					Since:      "0.10.1",
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleConfigResetStat,
+				},
+			},
+		},
+		{
+			Command:     "memory",
+			Module:      constants.AdminModule,
+			Categories:  []string{},
+			Description: "Commands pertaining to the memory usage of the dataset",
+			Sync:        false,
+			Arity:       -2,
+			Complexity:  "Depends on subcommand.",
+			Since:       "0.10.1",
+			KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+				return internal.KeyExtractionFuncResult{
+					Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+				}, nil
+			},
+			HandlerFunc: func(_ internal.HandlerFuncParams) ([]byte, error) {
+				return nil, errors.New("provide USAGE or STATS subcommand")
+			},
+			SubCommands: []internal.SubCommand{
+				{
+					Command:    "usage",
+					Module:     constants.AdminModule,
+					Categories: []string{constants.ReadCategory, constants.SlowCategory},
+					Description: `(MEMORY USAGE key [SAMPLES count]) Returns the estimated number of bytes used by the key and
+its value. The size of hashes and lists is extrapolated from count sampled elements (5 by default).
+SAMPLES 0 counts every element.`,
+					Sync:       false,
+					Arity:      -3,
+					Flags:      []string{constants.ReadOnlyFlag},
+					FirstKey:   2,
+					LastKey:    2,
+					KeyStep:    1,
+					Complexity: "O(N) where N is the number of samples.",
+					Since:      "0.10.1",
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						if len(cmd) < 3 {
+							return internal.KeyExtractionFuncResult{}, errors.New(constants.WrongArgsResponse)
+						}
+						return internal.KeyExtractionFuncResult{
+							Channels: make([]string, 0), ReadKeys: cmd[2:3], WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleMemoryUsage,
+				},
+				{
+					Command:    "stats",
+					Module:     constants.AdminModule,
+					Categories: []string{constants.AdminCategory, constants.SlowCategory},
+					Description: `(MEMORY STATS) Returns the estimated memory usage of the dataset and of each database,
+which is compared with max memory to evict keys.`,
+					Sync:       false,
+					Arity:      2,
+					Flags:      []string{constants.AdminFlag},
+					Complexity: "O(N) where N is the number of databases.",
+					Since:      "0.10.1",
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleMemoryStats,
+				},
+			},
+		},
+		{
+			Command:     "eviction",
+			Module:      constants.AdminModule,
+			Categories:  []string{},
+			Description: "Commands that pin keys so that they're never evicted when max memory is reached",
+			Sync:        false,
+			Arity:       -2,
+			Complexity:  "Depends on subcommand.",
+			Since:       "0.10.1",
+			KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+				return internal.KeyExtractionFuncResult{
+					Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+				}, nil
+			},
+			HandlerFunc: func(_ internal.HandlerFuncParams) ([]byte, error) {
+				return nil, errors.New("provide PIN, UNPIN or PINNED subcommand")
+			},
+			SubCommands: []internal.SubCommand{
+				{
+					Command:    "pin",
+					Module:     constants.AdminModule,
+					Categories: []string{constants.AdminCategory, constants.SlowCategory, constants.DangerousCategory},
+					Description: `(EVICTION PIN pattern [pattern ...]) Pins the keys that match the glob patterns, so that they're
+never evicted when max memory is reached. Returns the number of patterns that were not pinned before.`,
+					Sync:       false,
+					Arity:      -3,
+					Flags:      []string{constants.AdminFlag},
+					Complexity: "O(N) where N is the number of patterns.",
+					Since:      "0.10.1",
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleEvictionPin,
+				},
+				{
+					Command:    "unpin",
+					Module:     constants.AdminModule,
+					Categories: []string{constants.AdminCategory, constants.SlowCategory, constants.DangerousCategory},
+					Description: `(EVICTION UNPIN pattern [pattern ...]) Removes glob patterns of pinned keys.
+Returns the number of patterns that were removed.`,
+					Sync:       false,
+					Arity:      -3,
+					Flags:      []string{constants.AdminFlag},
+					Complexity: "O(N) where N is the number of patterns.",
+					Since:      "0.10.1",
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleEvictionUnpin,
+				},
+				{
+					Command:     "pinned",
+					Module:      constants.AdminModule,
+					Categories:  []string{constants.AdminCategory, constants.SlowCategory},
+					Description: "(EVICTION PINNED) Returns the glob patterns of pinned keys.",
+					Sync:        false,
+					Arity:       2,
+					Flags:       []string{constants.AdminFlag},
+					Complexity:  "O(N) where N is the number of pinned patterns.",
+					Since:       "0.10.1",
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleEvictionPinned,
+				},
+			},
+		},
+		{
+			Command:     "slowlog",
+			Module:      constants.AdminModule,
+			Categories:  []string{},
+			Description: "Commands that read and reset the log of the commands that exceeded the slow log threshold",
+			Sync:        false,
+			Arity:       -2,
+			Complexity:  "Depends on subcommand.",
+			Since:       "0.10.1",
+			KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+				return internal.KeyExtractionFuncResult{
+					Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+				}, nil
+			},
+			HandlerFunc: func(_ internal.HandlerFuncParams) ([]byte, error) {
+				return nil, errors.New("provide GET, LEN or RESET subcommand")
+			},
+			SubCommands: []internal.SubCommand{
+				{
+					Command:    "get",
+					Module:     constants.AdminModule,
+					Categories: []string{constants.AdminCategory, constants.SlowCategory, constants.DangerousCategory},
+					Description: `(SLOWLOG GET [count]) Returns the latest count entries of the slow log, newest first.
+The default count is 10, and -1 returns every entry. Each entry holds the ID, the unix time, the execution time in
+microseconds, the arguments of the command, and the address and name of the client.`,
+					Sync:       false,
+					Arity:      -2,
+					Flags:      []string{constants.AdminFlag, constants.LoadingFlag, constants.StaleFlag},
+					Complexity: "O(N) where N is the number of entries returned.",
+					Since:      "0.10.1",
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleSlowlogGet,
+				},
+				{
+					Command:     "len",
+					Module:      constants.AdminModule,
+					Categories:  []string{constants.AdminCategory, constants.SlowCategory, constants.DangerousCategory},
+					Description: "(SLOWLOG LEN) Returns the number of entries in the slow log.",
+					Sync:        false,
+					Arity:       2,
+					Flags:       []string{constants.AdminFlag, constants.LoadingFlag, constants.StaleFlag},
+					Complexity:  "O(1)",
+					Since:       "0.10.1",
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleSlowlogLen,
+				},
+				{
+					Command:     "reset",
+					Module:      constants.AdminModule,
+					Categories:  []string{constants.AdminCategory, constants.SlowCategory, constants.DangerousCategory},
+					Description: "(SLOWLOG RESET) Removes every entry from the slow log.",
+					Sync:        false,
+					Arity:       2,
+					Flags:       []string{constants.AdminFlag, constants.LoadingFlag, constants.StaleFlag},
+					Complexity:  "O(N) where N is the number of entries in the slow log.",
+					Since:       "0.10.1",
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleSlowlogReset,
+				},
+			},
+		},
+		{
+			Command:     "latency",
+			Module:      constants.AdminModule,
+			Categories:  []string{},
+			Description: "Commands that report the latency spikes of events and the latency distribution of commands",
+			Sync:        false,
+			Arity:       -2,
+			Complexity:  "Depends on subcommand.",
+			Since:       "0.10.1",
+			KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+				return internal.KeyExtractionFuncResult{
+					Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+				}, nil
+			},
+			HandlerFunc: func(_ internal.HandlerFuncParams) ([]byte, error) {
+				return nil, errors.New("provide LATEST, HISTORY, HISTOGRAM or RESET subcommand")
+			},
+			SubCommands: []internal.SubCommand{
+				{
+					Command:    "latest",
+					Module:     constants.AdminModule,
+					Categories: []string{constants.AdminCategory, constants.SlowCategory, constants.DangerousCategory},
+					Description: `(LATENCY LATEST) Returns the name, unix time of the latest spike, latest latency and
+highest latency in milliseconds of each event recorded by the latency monitor.`,
+					Sync:       false,
+					Arity:      2,
+					Flags:      []string{constants.AdminFlag, constants.LoadingFlag, constants.StaleFlag},
+					Complexity: "O(1)",
+					Since:      "0.10.1",
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleLatencyLatest,
+				},
+				{
+					Command:    "history",
+					Module:     constants.AdminModule,
+					Categories: []string{constants.AdminCategory, constants.SlowCategory, constants.DangerousCategory},
+					Description: `(LATENCY HISTORY event) Returns the unix time and latency in milliseconds of the
+latency spikes recorded for the event.`,
+					Sync:       false,
+					Arity:      3,
+					Flags:      []string{constants.AdminFlag, constants.LoadingFlag, constants.StaleFlag},
+					Complexity: "O(1)",
+					Since:      "0.10.1",
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleLatencyHistory,
+				},
+				{
+					Command:    "histogram",
+					Module:     constants.AdminModule,
+					Categories: []string{constants.AdminCategory, constants.SlowCategory, constants.DangerousCategory},
+					Description: `(LATENCY HISTOGRAM [command [command ...]]) Returns the number of calls and the
+cumulative latency histogram in microseconds of the given commands, or of every command that has been called.`,
+					Sync:       false,
+					Arity:      -2,
+					Flags:      []string{constants.AdminFlag, constants.LoadingFlag, constants.StaleFlag},
+					Complexity: "O(N) where N is the number of commands with latency information being retrieved.",
+					Since:      "0.10.1",
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleLatencyHistogram,
+				},
+				{
+					Command:    "reset",
+					Module:     constants.AdminModule,
+					Categories: []string{constants.AdminCategory, constants.SlowCategory, constants.DangerousCategory},
+					Description: `(LATENCY RESET [event [event ...]]) Removes the latency spikes recorded for the given
+events, or for every event. Returns the number of events reset.`,
+					Sync:       false,
+					Arity:      -2,
+					Flags:      []string{constants.AdminFlag, constants.LoadingFlag, constants.StaleFlag},
+					Complexity: "O(1)",
+					Since:      "0.10.1",
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleLatencyReset,
+				},
+			},
+		},
+		{
+			Command:    "monitor",
+			Module:     constants.AdminModule,
+			Categories: []string{constants.AdminCategory, constants.SlowCategory, constants.DangerousCategory},
+			Description: `(MONITOR) Streams every command processed by the server to the connection.
+Each line holds the unix time, the database, the source of the command (embedded, tcp, aof, replication or raft),
+the address and name of the client, and the arguments. Passwords are redacted.
+Commands are dropped when the connection falls behind, and the number of dropped commands is reported.`,
+			Sync:       false,
+			Arity:      1,
+			Flags:      []string{constants.AdminFlag, constants.LoadingFlag, constants.StaleFlag},
+			Complexity: "O(1)",
+			Since:      "0.10.1",
+			KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+				return internal.KeyExtractionFuncResult{
+					Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+				}, nil
+			},
+			HandlerFunc: handleMonitor,
+		},
+		{
+			Command:     "info",
+			Module:      constants.AdminModule,
+			Categories:  []string{constants.AdminCategory, constants.SlowCategory, constants.DangerousCategory},
+			Description: "(INFO [section [section ...]]) Returns information about the server. The supported sections are server, clients, memory, persistence, stats, replication, cpu, commandstats, errorstats, cluster and keyspace. The default section returns every section except commandstats, while all and everything return every section.",
+			Sync:        false,
+			Arity:       -1,
+			Flags:       []string{constants.AdminFlag, constants.LoadingFlag, constants.StaleFlag},
+			Complexity:  "O(1)",
+			Since:       "0.10.1",
+			KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+				return internal.KeyExtractionFuncResult{
+					Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+				}, nil
+			},
+			HandlerFunc: handleInfo,
+		},
 		{
 			Command:     "module",
 			Module:      constants.AdminModule,
 			Categories:  []string{},
 			Description: "Module commands",
+			Arity:       -2,
+			Complexity:  "Depends on subcommand.",
+			Since:       "0.1.0",
 			KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
 				return internal.KeyExtractionFuncResult{
 					Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
@@ -331,7 +1534,11 @@ Get the list of command names. Allows for filtering by ACL category or glob patt
 					Description: `(MODULE LOAD path [arg [arg ...]]) Load a module from a dynamic library at runtime. 
 The path should be the full path to the module, including the .so filename. Any args will be be passed unmodified to the
 module's key extraction and handler functions.`,
-					Sync: true,
+					Sync:       true,
+					Arity:      -3,
+					Flags:      []string{constants.AdminFlag, constants.NoScriptFlag},
+					Complexity: "O(1)",
+					Since:      "0.1.0",
 					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
 						return internal.KeyExtractionFuncResult{
 							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
@@ -357,7 +1564,11 @@ module's key extraction and handler functions.`,
 					Categories: []string{constants.AdminCategory, constants.SlowCategory, constants.DangerousCategory},
 					Description: `(MODULE UNLOAD name) 
 Unloads a module based on the its name as displayed by the MODULE LIST command.`,
-					Sync: true,
+					Sync:       true,
+					Arity:      3,
+					Flags:      []string{constants.AdminFlag, constants.NoScriptFlag},
+					Complexity: "O(1)",
+					Since:      "0.1.0",
 					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
 						return internal.KeyExtractionFuncResult{
 							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
@@ -377,6 +1588,10 @@ Unloads a module based on the its name as displayed by the MODULE LIST command.`
 					Categories:  []string{constants.AdminModule, constants.SlowCategory, constants.DangerousCategory},
 					Description: `(MODULE LIST) List all the modules that are currently loaded in the server.`,
 					Sync:        false,
+					Arity:       2,
+					Flags:       []string{constants.NoScriptFlag},
+					Complexity:  "O(N) where N is the number of loaded modules.",
+					Since:       "0.1.0",
 					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
 						return internal.KeyExtractionFuncResult{
 							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
@@ -23,14 +23,17 @@ import (
 	"github.com/echovault/echovault/internal/constants"
 	"github.com/echovault/echovault/internal/modules/acl"
 	"github.com/echovault/echovault/internal/modules/admin"
+	"github.com/echovault/echovault/internal/modules/cdc"
 	"github.com/echovault/echovault/internal/modules/connection"
 	"github.com/echovault/echovault/internal/modules/generic"
 	"github.com/echovault/echovault/internal/modules/hash"
 	"github.com/echovault/echovault/internal/modules/list"
 	"github.com/echovault/echovault/internal/modules/pubsub"
+	"github.com/echovault/echovault/internal/modules/replication"
 	"github.com/echovault/echovault/internal/modules/set"
 	"github.com/echovault/echovault/internal/modules/sorted_set"
 	str "github.com/echovault/echovault/internal/modules/string"
+	"github.com/echovault/echovault/internal/modules/xrepl"
 	"github.com/tidwall/resp"
 	"os"
 	"path"
@@ -46,6 +49,7 @@ func setupServer(port uint16) (*echovault.EchoVault, error) {
 	cfg.BindAddr = "localhost"
 	cfg.Port = port
 	cfg.EvictionPolicy = constants.NoEviction
+	cfg.SlowlogThreshold = 0
 	return echovault.NewEchoVault(echovault.WithConfig(cfg))
 }
 
@@ -101,9 +105,12 @@ func Test_AdminCommands(t *testing.T) {
 		commands = append(commands, list.Commands()...)
 		commands = append(commands, connection.Commands()...)
 		commands = append(commands, pubsub.Commands()...)
+		commands = append(commands, replication.Commands()...)
+		commands = append(commands, cdc.Commands()...)
 		commands = append(commands, set.Commands()...)
 		commands = append(commands, sorted_set.Commands()...)
 		commands = append(commands, str.Commands()...)
+		commands = append(commands, xrepl.Commands()...)
 
 		// Flatten the commands and subcommands.
 		var allCommands []string
@@ -153,9 +160,12 @@ func Test_AdminCommands(t *testing.T) {
 		commands = append(commands, list.Commands()...)
 		commands = append(commands, connection.Commands()...)
 		commands = append(commands, pubsub.Commands()...)
+		commands = append(commands, replication.Commands()...)
+		commands = append(commands, cdc.Commands()...)
 		commands = append(commands, set.Commands()...)
 		commands = append(commands, sorted_set.Commands()...)
 		commands = append(commands, str.Commands()...)
+		commands = append(commands, xrepl.Commands()...)
 
 		// Flatten the commands and subcommands.
 		var allCommands []string
@@ -194,9 +204,12 @@ func Test_AdminCommands(t *testing.T) {
 		allCommands = append(allCommands, list.Commands()...)
 		allCommands = append(allCommands, connection.Commands()...)
 		allCommands = append(allCommands, pubsub.Commands()...)
+		allCommands = append(allCommands, replication.Commands()...)
+		allCommands = append(allCommands, cdc.Commands()...)
 		allCommands = append(allCommands, set.Commands()...)
 		allCommands = append(allCommands, sorted_set.Commands()...)
 		allCommands = append(allCommands, str.Commands()...)
+		allCommands = append(allCommands, xrepl.Commands()...)
 
 		tests := []struct {
 			name string
@@ -294,6 +307,167 @@ func Test_AdminCommands(t *testing.T) {
 		}
 	})
 
+	t.Run("Test COMMAND INFO/DOCS/GETKEYS commands", func(t *testing.T) {
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		client := resp.NewConn(conn)
+
+		send := func(cmd ...string) resp.Value {
+			command := make([]resp.Value, len(cmd))
+			for i, c := range cmd {
+				command[i] = resp.StringValue(c)
+			}
+			if err = client.WriteArray(command); err != nil {
+				t.Fatal(err)
+			}
+			res, _, err := client.ReadValue()
+			if err != nil {
+				t.Fatal(err)
+			}
+			return res
+		}
+		strs := func(values []resp.Value) []string {
+			res := make([]string, len(values))
+			for i, v := range values {
+				res[i] = v.String()
+			}
+			return res
+		}
+
+		var commands []internal.Command
+		commands = append(commands, acl.Commands()...)
+		commands = append(commands, admin.Commands()...)
+		commands = append(commands, generic.Commands()...)
+		commands = append(commands, hash.Commands()...)
+		commands = append(commands, list.Commands()...)
+		commands = append(commands, connection.Commands()...)
+		commands = append(commands, pubsub.Commands()...)
+		commands = append(commands, replication.Commands()...)
+		commands = append(commands, cdc.Commands()...)
+		commands = append(commands, set.Commands()...)
+		commands = append(commands, sorted_set.Commands()...)
+		commands = append(commands, str.Commands()...)
+		commands = append(commands, xrepl.Commands()...)
+
+		// Every built-in command and subcommand declares its metadata.
+		for _, c := range commands {
+			if c.Arity == 0 || c.Complexity == "" || c.Since == "" {
+				t.Errorf("command %s is missing its arity, complexity or since version", c.Command)
+			}
+			if len(c.SubCommands) == 0 && len(c.Flags) == 0 {
+				t.Errorf("command %s has no flags", c.Command)
+			}
+			for _, sc := range c.SubCommands {
+				if sc.Arity == 0 || len(sc.Flags) == 0 || sc.Complexity == "" || sc.Since == "" {
+					t.Errorf("subcommand %s|%s is missing its arity, flags, complexity or since version", c.Command, sc.Command)
+				}
+			}
+		}
+
+		// COMMAND and COMMAND INFO without names return every command.
+		if got := len(send("COMMAND").Array()); got != len(commands) {
+			t.Errorf("expected COMMAND to return %d commands, got %d", len(commands), got)
+		}
+		if got := len(send("COMMAND", "INFO").Array()); got != len(commands) {
+			t.Errorf("expected COMMAND INFO to return %d commands, got %d", len(commands), got)
+		}
+
+		res := send("COMMAND", "INFO", "GET", "config|get", "ZUNIONSTORE", "non-existent")
+		if len(res.Array()) != 4 {
+			t.Fatalf("expected 4 entries, got %d", len(res.Array()))
+		}
+		get := res.Array()[0].Array()
+		if get[0].String() != "get" || get[1].Integer() != 2 {
+			t.Errorf("expected name get and arity 2, got %s and %d", get[0].String(), get[1].Integer())
+		}
+		if flags := strs(get[2].Array()); !slices.Contains(flags, "readonly") || !slices.Contains(flags, "fast") {
+			t.Errorf("expected GET flags to contain readonly and fast, got %v", flags)
+		}
+		if get[3].Integer() != 1 || get[4].Integer() != 1 || get[5].Integer() != 1 {
+			t.Errorf("expected GET key positions 1 1 1, got %d %d %d", get[3].Integer(), get[4].Integer(), get[5].Integer())
+		}
+		if categories := strs(get[6].Array()); !slices.Contains(categories, "@read") {
+			t.Errorf("expected GET categories to contain @read, got %v", categories)
+		}
+		if specs := get[8].Array(); len(specs) != 1 || fmt.Sprintf("%v", strs(specs[0].Array()[1].Array())) != "[RO access]" {
+			t.Errorf("expected a single RO access key spec for GET, got %v", specs)
+		}
+		configGet := res.Array()[1].Array()
+		if configGet[0].String() != "config|get" || configGet[1].Integer() != -3 {
+			t.Errorf("expected name config|get and arity -3, got %s and %d", configGet[0].String(), configGet[1].Integer())
+		}
+		zunionstore := res.Array()[2].Array()
+		if flags := strs(zunionstore[2].Array()); !slices.Contains(flags, "write") || !slices.Contains(flags, "movablekeys") {
+			t.Errorf("expected ZUNIONSTORE flags to contain write and movablekeys, got %v", flags)
+		}
+		if specs := zunionstore[8].Array(); len(specs) != 2 {
+			t.Errorf("expected 2 key specs for ZUNIONSTORE, got %d", len(specs))
+		}
+		if !res.Array()[3].IsNull() {
+			t.Errorf("expected nil entry for unknown command, got %v", res.Array()[3])
+		}
+
+		res = send("COMMAND", "DOCS", "SET", "non-existent")
+		if len(res.Array()) != 2 || res.Array()[0].String() != "set" {
+			t.Fatalf("expected the docs of SET only, got %v", res.Array())
+		}
+		docs := strs(res.Array()[1].Array())
+		for _, field := range []string{"since", "0.1.0", "group", "generic", "complexity", "O(1)"} {
+			if !slices.Contains(docs, field) {
+				t.Errorf("expected SET docs to contain %s, got %v", field, docs)
+			}
+		}
+
+		tests := []struct {
+			name     string
+			command  []string
+			expected string
+		}{
+			{
+				name:     "1. Return the keys of a command with a key step",
+				command:  []string{"COMMAND", "GETKEYS", "MSET", "key1", "value1", "key2", "value2"},
+				expected: "[key1 key2]",
+			},
+			{
+				name:     "2. Return the keys in the order of the command",
+				command:  []string{"COMMAND", "GETKEYS", "SMOVE", "source", "destination", "member"},
+				expected: "[source destination]",
+			},
+			{
+				name:     "3. Return the keys of a subcommand",
+				command:  []string{"COMMAND", "GETKEYS", "OBJECT", "FREQ", "key1"},
+				expected: "[key1]",
+			},
+			{
+				name:     "4. Return error for a command without keys",
+				command:  []string{"COMMAND", "GETKEYS", "PING"},
+				expected: "the command has no key arguments",
+			},
+			{
+				name:     "5. Return error for an unknown command",
+				command:  []string{"COMMAND", "GETKEYS", "NON-EXISTENT", "key1"},
+				expected: "invalid command specified",
+			},
+		}
+
+		for _, test := range tests {
+			res := send(test.command...)
+			got := res.String()
+			if res.Type() == resp.Array {
+				got = fmt.Sprintf("%v", strs(res.Array()))
+			}
+			if !strings.Contains(got, test.expected) {
+				t.Errorf("%s: expected response \"%s\", got \"%s\"", test.name, test.expected, got)
+			}
+		}
+	})
+
 	t.Run("Test MODULE LOAD command", func(t *testing.T) {
 		tests := []struct {
 			name        string
@@ -676,6 +850,531 @@ func Test_AdminCommands(t *testing.T) {
 		}
 	})
 
+	t.Run("Test INFO command", func(t *testing.T) {
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		client := resp.NewConn(conn)
+
+		tests := []struct {
+			name     string
+			setup    []resp.Value // A command to execute before INFO.
+			command  []resp.Value
+			expected []string
+			excluded []string
+		}{
+			{
+				name:    "1. Return the default sections",
+				command: []resp.Value{resp.StringValue("INFO")},
+				expected: []string{
+					"# Server", "# Clients", "# Memory", "# Persistence", "# Stats", "# Replication", "role:master",
+					"connected_slaves:0", "master_repl_offset:", "# CPU", "# Errorstats", "# Cluster", "# Keyspace",
+				},
+				excluded: []string{"# Commandstats"},
+			},
+			{
+				name:     "2. Return the replication section",
+				command:  []resp.Value{resp.StringValue("INFO"), resp.StringValue("replication")},
+				expected: []string{"# Replication", "role:master", "repl_backlog_size:"},
+			},
+			{
+				name:     "3. Return the cluster section",
+				command:  []resp.Value{resp.StringValue("INFO"), resp.StringValue("cluster")},
+				expected: []string{"# Cluster", "cluster_enabled:0"},
+			},
+			{
+				name:    "4. Return the stats section",
+				command: []resp.Value{resp.StringValue("INFO"), resp.StringValue("stats")},
+				expected: []string{
+					"# Stats", "total_connections_received:", "total_commands_processed:", "keyspace_hits:",
+					"keyspace_misses:", "expired_keys:", "expired_time_cap_reached_count:", "volatile_keys:",
+				},
+				excluded: []string{"# Replication"},
+			},
+			{
+				name:    "5. Return the server section",
+				command: []resp.Value{resp.StringValue("INFO"), resp.StringValue("server")},
+				expected: []string{
+					"# Server", "server_name:echovault", "server_mode:standalone", fmt.Sprintf("tcp_port:%d", port),
+					"process_id:", "uptime_in_seconds:",
+				},
+			},
+			{
+				name:     "6. Return the clients, memory and persistence sections",
+				command:  []resp.Value{resp.StringValue("INFO"), resp.StringValue("clients"), resp.StringValue("MEMORY"), resp.StringValue("persistence")},
+				expected: []string{"# Clients", "connected_clients:", "# Memory", "used_memory:", "maxmemory_policy:", "# Persistence", "rdb_last_save_time:", "aof_rewrite_in_progress:0"},
+				excluded: []string{"# Server"},
+			},
+			{
+				name:     "7. Return the keyspace section",
+				setup:    []resp.Value{resp.StringValue("SET"), resp.StringValue("InfoKey1"), resp.StringValue("value")},
+				command:  []resp.Value{resp.StringValue("INFO"), resp.StringValue("keyspace")},
+				expected: []string{"# Keyspace", "db0:keys="},
+			},
+			{
+				name:     "8. Return the commandstats section",
+				command:  []resp.Value{resp.StringValue("INFO"), resp.StringValue("commandstats")},
+				expected: []string{"# Commandstats", "cmdstat_set:calls=", "cmdstat_info:calls="},
+			},
+			{
+				name:     "9. Return the errorstats section",
+				setup:    []resp.Value{resp.StringValue("UNKNOWNCOMMAND")},
+				command:  []resp.Value{resp.StringValue("INFO"), resp.StringValue("errorstats")},
+				expected: []string{"# Errorstats", "errorstat_ERR:count="},
+			},
+			{
+				name:     "10. Return every section with all",
+				command:  []resp.Value{resp.StringValue("INFO"), resp.StringValue("all")},
+				expected: []string{"# Server", "# Commandstats", "# Keyspace", "# CPU", "used_cpu_user:"},
+			},
+		}
+
+		for _, test := range tests {
+			if test.setup != nil {
+				if err = client.WriteArray(test.setup); err != nil {
+					t.Error(err)
+					return
+				}
+				if _, _, err = client.ReadValue(); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+			if err = client.WriteArray(test.command); err != nil {
+				t.Error(err)
+				return
+			}
+			res, _, err := client.ReadValue()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			for _, expected := range test.expected {
+				if !strings.Contains(res.String(), expected) {
+					t.Errorf("%s: expected INFO response to contain \"%s\", got \"%s\"", test.name, expected, res.String())
+				}
+			}
+			for _, excluded := range test.excluded {
+				if strings.Contains(res.String(), excluded) {
+					t.Errorf("%s: expected INFO response not to contain \"%s\", got \"%s\"", test.name, excluded, res.String())
+				}
+			}
+		}
+	})
+
+	t.Run("Test MEMORY USAGE/STATS commands", func(t *testing.T) {
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		client := resp.NewConn(conn)
+
+		do := func(command ...string) resp.Value {
+			values := make([]resp.Value, len(command))
+			for i, arg := range command {
+				values[i] = resp.StringValue(arg)
+			}
+			if err := client.WriteArray(values); err != nil {
+				t.Fatal(err)
+			}
+			res, _, err := client.ReadValue()
+			if err != nil {
+				t.Fatal(err)
+			}
+			return res
+		}
+
+		do("SET", "MemoryKey1", "value1")
+		do("SET", "MemoryKey2", strings.Repeat("a", 1000))
+
+		small := do("MEMORY", "USAGE", "MemoryKey1")
+		large := do("MEMORY", "USAGE", "MemoryKey2", "SAMPLES", "0")
+		if small.Integer() <= 0 || large.Integer() <= small.Integer()+900 {
+			t.Errorf("expected MemoryKey2 to use at least 900 bytes more than MemoryKey1, got %d and %d",
+				large.Integer(), small.Integer())
+		}
+		if res := do("MEMORY", "USAGE", "MemoryKey3"); !res.IsNull() {
+			t.Errorf("expected nil usage for missing key, got %v", res)
+		}
+		if res := do("MEMORY", "USAGE", "MemoryKey1", "SAMPLES", "-1"); res.Error() == nil {
+			t.Errorf("expected error for negative samples, got %v", res)
+		}
+		if res := do("MEMORY", "USAGE"); res.Error() == nil {
+			t.Errorf("expected wrong number of arguments error, got %v", res)
+		}
+
+		stats := do("MEMORY", "STATS").Array()
+		values := make(map[string]resp.Value)
+		for i := 0; i+1 < len(stats); i += 2 {
+			values[stats[i].String()] = stats[i+1]
+		}
+		if values["dataset.bytes"].Integer() < large.Integer()+small.Integer() {
+			t.Errorf("expected dataset.bytes to include both keys, got %d", values["dataset.bytes"].Integer())
+		}
+		if values["keys.count"].Integer() < 2 {
+			t.Errorf("expected keys.count to be at least 2, got %d", values["keys.count"].Integer())
+		}
+		if values["maxmemory-policy"].String() != constants.NoEviction {
+			t.Errorf("expected maxmemory-policy %s, got %s", constants.NoEviction, values["maxmemory-policy"].String())
+		}
+		if db := values["db.0"].Array(); len(db) != 4 || db[0].String() != "keys" || db[1].Integer() < 2 {
+			t.Errorf("expected db.0 to contain at least 2 keys, got %v", values["db.0"])
+		}
+	})
+
+	t.Run("Test EVICTION PIN/UNPIN/PINNED commands", func(t *testing.T) {
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		client := resp.NewConn(conn)
+
+		tests := []struct {
+			name     string
+			command  []string
+			expected string
+		}{
+			{name: "1. Pin key patterns", command: []string{"EVICTION", "PIN", "config:*", "session:*"}, expected: "2"},
+			{name: "2. Pin a pinned key pattern", command: []string{"EVICTION", "PIN", "config:*"}, expected: "0"},
+			{name: "3. Unpin key patterns", command: []string{"EVICTION", "UNPIN", "session:*", "other:*"}, expected: "1"},
+			{name: "4. Return the pinned key patterns", command: []string{"EVICTION", "PINNED"}, expected: "[config:*]"},
+			{name: "5. Return error for an invalid pattern", command: []string{"EVICTION", "PIN", "config[*"}, expected: "invalid no-evict pattern"},
+			{name: "6. Return error when no pattern is passed", command: []string{"EVICTION", "UNPIN"}, expected: constants.WrongArgsResponse},
+		}
+
+		for _, test := range tests {
+			command := make([]resp.Value, len(test.command))
+			for i, c := range test.command {
+				command[i] = resp.StringValue(c)
+			}
+			if err = client.WriteArray(command); err != nil {
+				t.Error(err)
+				return
+			}
+			res, _, err := client.ReadValue()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			got := res.String()
+			if res.Type() == resp.Array {
+				values := make([]string, len(res.Array()))
+				for i, v := range res.Array() {
+					values[i] = v.String()
+				}
+				got = fmt.Sprintf("%v", values)
+			}
+			if !strings.Contains(got, test.expected) {
+				t.Errorf("%s: expected response \"%s\", got \"%s\"", test.name, test.expected, got)
+			}
+		}
+	})
+
+	t.Run("Test SLOWLOG GET/LEN/RESET commands", func(t *testing.T) {
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		client := resp.NewConn(conn)
+
+		send := func(cmd ...string) resp.Value {
+			command := make([]resp.Value, len(cmd))
+			for i, c := range cmd {
+				command[i] = resp.StringValue(c)
+			}
+			if err = client.WriteArray(command); err != nil {
+				t.Fatal(err)
+			}
+			res, _, err := client.ReadValue()
+			if err != nil {
+				t.Fatal(err)
+			}
+			return res
+		}
+
+		if res := send("SLOWLOG", "RESET"); !strings.EqualFold(res.String(), "ok") {
+			t.Errorf("expected SLOWLOG RESET response \"OK\", got \"%s\"", res.String())
+		}
+		send("HELLO", "2", "SETNAME", "slowlog-client")
+		send("ECHO", "hello")
+
+		// The entries are the SLOWLOG RESET, HELLO and ECHO commands.
+		res := send("SLOWLOG", "GET", "1")
+		if len(res.Array()) != 1 {
+			t.Fatalf("expected 1 slowlog entry, got %d", len(res.Array()))
+		}
+		entry := res.Array()[0].Array()
+		if len(entry) != 6 {
+			t.Fatalf("expected slowlog entry with 6 fields, got %d", len(entry))
+		}
+		var args []string
+		for _, arg := range entry[3].Array() {
+			args = append(args, arg.String())
+		}
+		if !slices.Equal(args, []string{"ECHO", "hello"}) {
+			t.Errorf("expected slowlog arguments %v, got %v", []string{"ECHO", "hello"}, args)
+		}
+		if entry[4].String() != conn.LocalAddr().String() {
+			t.Errorf("expected slowlog client address \"%s\", got \"%s\"", conn.LocalAddr().String(), entry[4].String())
+		}
+		if entry[5].String() != "slowlog-client" {
+			t.Errorf("expected slowlog client name \"slowlog-client\", got \"%s\"", entry[5].String())
+		}
+
+		// SLOWLOG GET is logged too, so there are now 4 entries.
+		if res = send("SLOWLOG", "LEN"); res.Integer() != 4 {
+			t.Errorf("expected SLOWLOG LEN response 4, got %d", res.Integer())
+		}
+		if res = send("SLOWLOG", "GET", "-1"); len(res.Array()) != 5 {
+			t.Errorf("expected 5 slowlog entries, got %d", len(res.Array()))
+		}
+		if res = send("SLOWLOG", "GET", "-2"); !strings.Contains(res.Error().Error(), "count should be greater than or equal to -1") {
+			t.Errorf("expected invalid count error, got \"%s\"", res.String())
+		}
+	})
+
+	t.Run("Test LATENCY LATEST/HISTORY/HISTOGRAM/RESET commands", func(t *testing.T) {
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		client := resp.NewConn(conn)
+
+		send := func(cmd ...string) resp.Value {
+			command := make([]resp.Value, len(cmd))
+			for i, c := range cmd {
+				command[i] = resp.StringValue(c)
+			}
+			if err = client.WriteArray(command); err != nil {
+				t.Fatal(err)
+			}
+			res, _, err := client.ReadValue()
+			if err != nil {
+				t.Fatal(err)
+			}
+			return res
+		}
+
+		// The latency monitor is disabled by default, so no events are recorded.
+		if res := send("LATENCY", "LATEST"); len(res.Array()) != 0 {
+			t.Errorf("expected no latency events, got %d", len(res.Array()))
+		}
+		if res := send("LATENCY", "HISTORY", "command"); len(res.Array()) != 0 {
+			t.Errorf("expected no latency samples, got %d", len(res.Array()))
+		}
+		if res := send("LATENCY", "RESET"); res.Integer() != 0 {
+			t.Errorf("expected LATENCY RESET response 0, got %d", res.Integer())
+		}
+
+		send("ECHO", "hello")
+		send("ECHO", "world")
+		res := send("LATENCY", "HISTOGRAM", "echo", "unknown")
+		if len(res.Array()) != 2 {
+			t.Fatalf("expected histogram for 1 command, got %v", res.Array())
+		}
+		if res.Array()[0].String() != "echo" {
+			t.Errorf("expected histogram for \"echo\", got \"%s\"", res.Array()[0].String())
+		}
+		histogram := res.Array()[1].Array()
+		if len(histogram) != 4 || histogram[0].String() != "calls" || histogram[2].String() != "histogram_usec" {
+			t.Fatalf("unexpected histogram response %v", histogram)
+		}
+		if histogram[1].Integer() < 2 {
+			t.Errorf("expected at least 2 echo calls, got %d", histogram[1].Integer())
+		}
+		buckets := histogram[3].Array()
+		if len(buckets) == 0 || buckets[len(buckets)-1].Integer() != histogram[1].Integer() {
+			t.Errorf("expected last cumulative bucket count %d, got %v", histogram[1].Integer(), buckets)
+		}
+	})
+
+	t.Run("Test MONITOR command", func(t *testing.T) {
+		monitorConn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = monitorConn.Close()
+		}()
+		monitorClient := resp.NewConn(monitorConn)
+
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		client := resp.NewConn(conn)
+
+		if err = monitorClient.WriteArray([]resp.Value{resp.StringValue("MONITOR")}); err != nil {
+			t.Error(err)
+			return
+		}
+		res, _, err := monitorClient.ReadValue()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if !strings.EqualFold(res.String(), "ok") {
+			t.Errorf("expected MONITOR response \"OK\", got \"%s\"", res.String())
+		}
+
+		tests := []struct {
+			name     string
+			command  []string
+			expected string
+		}{
+			{
+				name:     "1. Stream a command sent by a TCP client",
+				command:  []string{"SET", "MonitorKey1", "value1"},
+				expected: fmt.Sprintf(`[0 tcp %s] "SET" "MonitorKey1" "value1"`, conn.LocalAddr().String()),
+			},
+			{
+				name:     "2. Redact the password of AUTH",
+				command:  []string{"AUTH", "user", "password"},
+				expected: `"AUTH" "(redacted)" "(redacted)"`,
+			},
+		}
+
+		for _, test := range tests {
+			command := make([]resp.Value, len(test.command))
+			for i, c := range test.command {
+				command[i] = resp.StringValue(c)
+			}
+			if err = client.WriteArray(command); err != nil {
+				t.Error(err)
+				return
+			}
+			if _, _, err = client.ReadValue(); err != nil {
+				t.Error(err)
+				return
+			}
+			res, _, err := monitorClient.ReadValue()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if !strings.HasSuffix(res.String(), test.expected) {
+				t.Errorf("%s: expected monitor entry ending with \"%s\", got \"%s\"", test.name, test.expected, res.String())
+			}
+		}
+	})
+
+	t.Run("Test CONFIG GET/SET/REWRITE commands", func(t *testing.T) {
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		client := resp.NewConn(conn)
+
+		tests := []struct {
+			name     string
+			command  []string
+			expected string
+		}{
+			{
+				name:     "1. Set several parameters at once",
+				command:  []string{"CONFIG", "SET", "lfu-log-factor", "20", "LFU-DECAY-TIME", "2"},
+				expected: "OK",
+			},
+			{
+				name:     "2. Get the parameters that match the patterns",
+				command:  []string{"CONFIG", "GET", "lfu-*"},
+				expected: "[lfu-decay-time 2 lfu-log-factor 20]",
+			},
+			{
+				name:     "3. Get an immutable parameter",
+				command:  []string{"CONFIG", "GET", "port"},
+				expected: fmt.Sprintf("[port %d]", port),
+			},
+			{
+				name:     "4. Change no parameter when one of the values is invalid",
+				command:  []string{"CONFIG", "SET", "lfu-log-factor", "30", "lfu-decay-time", "-1"},
+				expected: "lfu-decay-time must be a positive integer",
+			},
+			{
+				name:     "5. Keep the values after a failed CONFIG SET",
+				command:  []string{"CONFIG", "GET", "lfu-log-factor"},
+				expected: "[lfu-log-factor 20]",
+			},
+			{
+				name:     "6. Return error for an immutable parameter",
+				command:  []string{"CONFIG", "SET", "data-dir", "/tmp"},
+				expected: "CONFIG parameter data-dir can't be changed while the server is running",
+			},
+			{
+				name:     "7. Return error for a duplicate parameter",
+				command:  []string{"CONFIG", "SET", "lfu-log-factor", "10", "lfu-log-factor", "20"},
+				expected: "duplicate CONFIG parameter lfu-log-factor",
+			},
+			{
+				name:     "8. Return error when a value is missing",
+				command:  []string{"CONFIG", "SET", "lfu-log-factor", "10", "lfu-decay-time"},
+				expected: constants.WrongArgsResponse,
+			},
+			{
+				name:     "9. Return error when rewriting without a config file",
+				command:  []string{"CONFIG", "REWRITE"},
+				expected: "the server is running without a config file",
+			},
+		}
+
+		for _, test := range tests {
+			command := make([]resp.Value, len(test.command))
+			for i, c := range test.command {
+				command[i] = resp.StringValue(c)
+			}
+			if err = client.WriteArray(command); err != nil {
+				t.Error(err)
+				return
+			}
+			res, _, err := client.ReadValue()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			got := res.String()
+			if res.Type() == resp.Array {
+				values := make([]string, len(res.Array()))
+				for i, v := range res.Array() {
+					values[i] = v.String()
+				}
+				got = fmt.Sprintf("%v", values)
+			}
+			if !strings.Contains(got, test.expected) {
+				t.Errorf("%s: expected response \"%s\", got \"%s\"", test.name, test.expected, got)
+			}
+		}
+	})
+
 	t.Run("Test SAVE/LASTSAVE commands", func(t *testing.T) {
 		t.Parallel()
 
@@ -0,0 +1,254 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"fmt"
+	"github.com/echovault/echovault/internal"
+	"github.com/echovault/echovault/internal/constants"
+	"slices"
+	"strings"
+)
+
+// commandMeta holds the metadata of a command or subcommand reported by COMMAND INFO and COMMAND DOCS.
+type commandMeta struct {
+	name        string
+	module      string
+	description string
+	arity       int
+	flags       []string
+	categories  []string
+	firstKey    int
+	lastKey     int
+	keyStep     int
+	keySpecs    []internal.KeySpec
+	complexity  string
+	since       string
+	subCommands []commandMeta
+}
+
+func newCommandMeta(command internal.Command) commandMeta {
+	meta := commandMeta{
+		name:        strings.ToLower(command.Command),
+		module:      command.Module,
+		description: command.Description,
+		arity:       command.Arity,
+		flags:       command.Flags,
+		categories:  command.Categories,
+		firstKey:    command.FirstKey,
+		lastKey:     command.LastKey,
+		keyStep:     command.KeyStep,
+		keySpecs:    command.KeySpecs,
+		complexity:  command.Complexity,
+		since:       command.Since,
+	}
+	for _, subCommand := range command.SubCommands {
+		meta.subCommands = append(meta.subCommands, newSubCommandMeta(command, subCommand))
+	}
+	return meta
+}
+
+func newSubCommandMeta(command internal.Command, subCommand internal.SubCommand) commandMeta {
+	return commandMeta{
+		name:        strings.ToLower(fmt.Sprintf("%s|%s", command.Command, subCommand.Command)),
+		module:      subCommand.Module,
+		description: subCommand.Description,
+		arity:       subCommand.Arity,
+		flags:       subCommand.Flags,
+		categories:  subCommand.Categories,
+		firstKey:    subCommand.FirstKey,
+		lastKey:     subCommand.LastKey,
+		keyStep:     subCommand.KeyStep,
+		keySpecs:    subCommand.KeySpecs,
+		complexity:  subCommand.Complexity,
+		since:       subCommand.Since,
+	}
+}
+
+// lookupCommandMeta returns the metadata of the command with the given name.
+// Subcommands are looked up with the "command|subcommand" notation.
+func lookupCommandMeta(commands []internal.Command, name string) (commandMeta, bool) {
+	commandName, subCommandName, isSubCommand := strings.Cut(name, "|")
+	for _, command := range commands {
+		if !strings.EqualFold(command.Command, commandName) {
+			continue
+		}
+		if !isSubCommand {
+			return newCommandMeta(command), true
+		}
+		for _, subCommand := range command.SubCommands {
+			if strings.EqualFold(subCommand.Command, subCommandName) {
+				return newSubCommandMeta(command, subCommand), true
+			}
+		}
+		return commandMeta{}, false
+	}
+	return commandMeta{}, false
+}
+
+// specs returns the key specs of the command. Commands that don't declare their key specs get a single spec
+// covering the keys between firstKey and lastKey.
+func (meta commandMeta) specs() []internal.KeySpec {
+	if len(meta.keySpecs) > 0 || meta.firstKey <= 0 {
+		return meta.keySpecs
+	}
+	lastKey := meta.lastKey
+	if lastKey >= 0 {
+		lastKey -= meta.firstKey
+	}
+	flags := []string{constants.KeySpecRO, constants.KeySpecAccess}
+	if slices.Contains(meta.flags, constants.WriteFlag) {
+		flags = []string{constants.KeySpecRW, constants.KeySpecUpdate}
+	}
+	return []internal.KeySpec{
+		{Flags: flags, BeginSearch: meta.firstKey, LastKey: lastKey, KeyStep: max(meta.keyStep, 1)},
+	}
+}
+
+// summary returns the description of the command without the syntax in parentheses at the start of it.
+func (meta commandMeta) summary() string {
+	description := strings.TrimSpace(meta.description)
+	if strings.HasPrefix(description, "(") {
+		depth := 0
+		for i, c := range description {
+			if c == '(' {
+				depth++
+			} else if c == ')' {
+				if depth--; depth == 0 {
+					description = strings.TrimLeft(description[i+1:], ". \r\n")
+					break
+				}
+			}
+		}
+	}
+	return strings.Join(strings.Fields(description), " ")
+}
+
+func formatCommandInfo(meta commandMeta) string {
+	flags := slices.Clone(meta.flags)
+	for _, spec := range meta.specs() {
+		if slices.Contains(spec.Flags, constants.KeySpecIncomplete) && !slices.Contains(flags, constants.MovableKeysFlag) {
+			flags = append(flags, constants.MovableKeysFlag)
+		}
+	}
+
+	res := fmt.Sprintf("*10\r\n$%d\r\n%s\r\n:%d\r\n", len(meta.name), meta.name, meta.arity)
+	res += fmt.Sprintf("*%d\r\n", len(flags))
+	for _, flag := range flags {
+		res += fmt.Sprintf("+%s\r\n", flag)
+	}
+	res += fmt.Sprintf(":%d\r\n:%d\r\n:%d\r\n", meta.firstKey, meta.lastKey, meta.keyStep)
+	res += fmt.Sprintf("*%d\r\n", len(meta.categories))
+	for _, category := range meta.categories {
+		res += fmt.Sprintf("+@%s\r\n", category)
+	}
+	// Command tips are not supported.
+	res += "*0\r\n"
+	specs := meta.specs()
+	res += fmt.Sprintf("*%d\r\n", len(specs))
+	for _, spec := range specs {
+		res += formatKeySpec(spec)
+	}
+	res += fmt.Sprintf("*%d\r\n", len(meta.subCommands))
+	for _, subCommand := range meta.subCommands {
+		res += formatCommandInfo(subCommand)
+	}
+	return res
+}
+
+func formatKeySpec(spec internal.KeySpec) string {
+	res := fmt.Sprintf("*6\r\n$5\r\nflags\r\n*%d\r\n", len(spec.Flags))
+	for _, flag := range spec.Flags {
+		res += fmt.Sprintf("+%s\r\n", flag)
+	}
+	res += fmt.Sprintf("$12\r\nbegin_search\r\n*4\r\n$4\r\ntype\r\n$5\r\nindex\r\n$4\r\nspec\r\n*2\r\n$5\r\nindex\r\n:%d\r\n",
+		spec.BeginSearch)
+	res += "$9\r\nfind_keys\r\n*4\r\n$4\r\ntype\r\n$5\r\nrange\r\n$4\r\nspec\r\n"
+	res += fmt.Sprintf("*6\r\n$7\r\nlastkey\r\n:%d\r\n$7\r\nkeystep\r\n:%d\r\n$5\r\nlimit\r\n:0\r\n", spec.LastKey, spec.KeyStep)
+	return res
+}
+
+func formatCommandDocs(meta commandMeta) string {
+	fields := []string{"summary", meta.summary()}
+	if meta.since != "" {
+		fields = append(fields, "since", meta.since)
+	}
+	fields = append(fields, "group", meta.module)
+	if meta.complexity != "" {
+		fields = append(fields, "complexity", meta.complexity)
+	}
+
+	length := len(fields)
+	if len(meta.subCommands) > 0 {
+		length += 2
+	}
+	res := fmt.Sprintf("*%d\r\n", length)
+	for _, field := range fields {
+		res += fmt.Sprintf("$%d\r\n%s\r\n", len(field), field)
+	}
+	if len(meta.subCommands) > 0 {
+		res += fmt.Sprintf("$11\r\nsubcommands\r\n*%d\r\n", len(meta.subCommands)*2)
+		for _, subCommand := range meta.subCommands {
+			res += fmt.Sprintf("$%d\r\n%s\r\n%s", len(subCommand.name), subCommand.name, formatCommandDocs(subCommand))
+		}
+	}
+	return res
+}
+
+// commandKeys returns the keys extracted from cmd by the command's KeyExtractionFunc, in the order
+// in which they appear in cmd.
+func commandKeys(commands []internal.Command, cmd []string) ([]string, error) {
+	index := slices.IndexFunc(commands, func(command internal.Command) bool {
+		return strings.EqualFold(command.Command, cmd[0])
+	})
+	if index == -1 {
+		return nil, fmt.Errorf("invalid command specified")
+	}
+	command := commands[index]
+
+	keyExtractionFunc := command.KeyExtractionFunc
+	sc, err := internal.GetSubCommand(command, cmd)
+	if err != nil {
+		return nil, err
+	}
+	if subCommand, ok := sc.(internal.SubCommand); ok {
+		keyExtractionFunc = subCommand.KeyExtractionFunc
+	}
+	if keyExtractionFunc == nil {
+		return nil, fmt.Errorf("the command has no key arguments")
+	}
+
+	result, err := keyExtractionFunc(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("invalid arguments specified for command")
+	}
+
+	remaining := make(map[string]int)
+	for _, key := range append(result.WriteKeys, result.ReadKeys...) {
+		remaining[key] += 1
+	}
+
+	var keys []string
+	for _, arg := range cmd[1:] {
+		if remaining[arg] > 0 {
+			keys = append(keys, arg)
+			remaining[arg] -= 1
+		}
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("the command has no key arguments")
+	}
+	return keys, nil
+}
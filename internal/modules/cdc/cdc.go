@@ -0,0 +1,240 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cdc
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"github.com/echovault/echovault/internal"
+	"github.com/echovault/echovault/internal/clock"
+	"sync"
+)
+
+// Mutation event types.
+const (
+	EventSet    = "set"    // A key was created, or its value or expiry time was changed.
+	EventDel    = "del"    // A key was deleted by a command.
+	EventExpire = "expire" // A key was deleted because its TTL elapsed.
+	EventEvict  = "evict"  // A key was deleted to free memory.
+	EventFlush  = "flush"  // Every key in a database, or in every database, was deleted.
+)
+
+// Feed is the change data capture feed. It assigns each mutation an offset, keeps the latest events in a
+// backlog so that subscribers can resume from an offset, and fans the events out to the subscribers in order.
+type Feed struct {
+	mut         sync.Mutex
+	clock       clock.Clock
+	id          string                   // Random ID of the feed. Offsets are only meaningful within the same feed.
+	offset      uint64                   // The offset of the latest event.
+	backlog     []internal.MutationEvent // Ring buffer holding the latest events.
+	start       int                      // The index of the oldest event in the backlog.
+	count       int                      // The number of events in the backlog.
+	subscribers map[*subscriber]struct{}
+}
+
+type subscriber struct {
+	queue  []internal.MutationEvent
+	notify chan struct{}
+	closed bool
+}
+
+func WithClock(clock clock.Clock) func(feed *Feed) {
+	return func(feed *Feed) {
+		feed.clock = clock
+	}
+}
+
+// WithBacklogSize sets the number of events kept for subscribers that resume from an offset.
+// Subscribers that fall further behind than the backlog size are disconnected.
+func WithBacklogSize(size int) func(feed *Feed) {
+	return func(feed *Feed) {
+		if size > 0 {
+			feed.backlog = make([]internal.MutationEvent, size)
+		}
+	}
+}
+
+// WithResume resumes the feed with the given ID that was closed at the given offset, so that the subscribers that
+// received its events can resume from their offset. The backlog of the closed feed is lost, so only a subscriber
+// that received every event can resume. A new feed is created when the ID is empty.
+func WithResume(id string, offset uint64) func(feed *Feed) {
+	return func(feed *Feed) {
+		if id != "" {
+			feed.id = id
+			feed.offset = offset
+		}
+	}
+}
+
+func NewFeed(options ...func(feed *Feed)) *Feed {
+	feed := &Feed{
+		clock:       clock.NewClock(),
+		id:          newFeedId(),
+		backlog:     make([]internal.MutationEvent, 10000),
+		subscribers: make(map[*subscriber]struct{}),
+	}
+	for _, option := range options {
+		option(feed)
+	}
+	return feed
+}
+
+func newFeedId() string {
+	b := make([]byte, 20)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// ID returns the random ID of the feed. A new ID is generated every time the server starts, unless the feed is
+// resumed, so a subscriber can only resume from an offset it received from a feed with the same ID.
+func (feed *Feed) ID() string {
+	return feed.id
+}
+
+// Publish assigns the next offset to the event, adds it to the backlog and sends it to the subscribers.
+// The event is timestamped with the current time unless it already has a timestamp.
+// It returns the published event.
+func (feed *Feed) Publish(event internal.MutationEvent) internal.MutationEvent {
+	feed.mut.Lock()
+	defer feed.mut.Unlock()
+
+	feed.offset += 1
+	event.Offset = feed.offset
+	if event.Timestamp.IsZero() {
+		event.Timestamp = feed.clock.Now()
+	}
+
+	if feed.count < len(feed.backlog) {
+		feed.backlog[(feed.start+feed.count)%len(feed.backlog)] = event
+		feed.count += 1
+	} else {
+		feed.backlog[feed.start] = event
+		feed.start = (feed.start + 1) % len(feed.backlog)
+	}
+
+	for sub := range feed.subscribers {
+		if len(sub.queue) >= len(feed.backlog) {
+			// The subscriber can't keep up. Closing its channel lets it resume from the last offset it received.
+			feed.closeSubscriberLocked(sub)
+			continue
+		}
+		sub.queue = append(sub.queue, event)
+		select {
+		case sub.notify <- struct{}{}:
+		default:
+		}
+	}
+
+	return event
+}
+
+// Offset returns the offset of the latest event. It's 0 when no events have been published.
+func (feed *Feed) Offset() uint64 {
+	feed.mut.Lock()
+	defer feed.mut.Unlock()
+	return feed.offset
+}
+
+// FirstOffset returns the offset of the oldest event in the backlog.
+func (feed *Feed) FirstOffset() uint64 {
+	feed.mut.Lock()
+	defer feed.mut.Unlock()
+	return feed.offset - uint64(feed.count) + 1
+}
+
+// Subscribe returns a channel that receives the events starting at offset, followed by every new event.
+// An offset of 0 only subscribes to new events. The channel is closed when the context is cancelled, or when the
+// subscriber falls too far behind. In that case, the subscriber can subscribe again from the offset after the last
+// event it received.
+func (feed *Feed) Subscribe(ctx context.Context, offset uint64) (<-chan internal.MutationEvent, error) {
+	feed.mut.Lock()
+
+	sub := &subscriber{notify: make(chan struct{}, 1)}
+
+	if offset != 0 {
+		first := feed.offset - uint64(feed.count) + 1
+		if offset < first || offset > feed.offset+1 {
+			feed.mut.Unlock()
+			return nil, fmt.Errorf("offset %d is out of range, the backlog holds offsets %d to %d",
+				offset, first, feed.offset)
+		}
+		for i := int(offset - first); i < feed.count; i++ {
+			sub.queue = append(sub.queue, feed.backlog[(feed.start+i)%len(feed.backlog)])
+		}
+	}
+
+	feed.subscribers[sub] = struct{}{}
+	feed.mut.Unlock()
+
+	events := make(chan internal.MutationEvent)
+	go feed.stream(ctx, sub, events)
+
+	return events, nil
+}
+
+// stream sends the queued events to the subscriber's channel in order.
+func (feed *Feed) stream(ctx context.Context, sub *subscriber, events chan<- internal.MutationEvent) {
+	defer func() {
+		feed.mut.Lock()
+		feed.closeSubscriberLocked(sub)
+		feed.mut.Unlock()
+		close(events)
+	}()
+
+	for {
+		feed.mut.Lock()
+		queue, closed := sub.queue, sub.closed
+		sub.queue = nil
+		feed.mut.Unlock()
+
+		for _, event := range queue {
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		if closed {
+			return
+		}
+
+		if len(queue) == 0 {
+			select {
+			case <-sub.notify:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+func (feed *Feed) closeSubscriberLocked(sub *subscriber) {
+	sub.closed = true
+	delete(feed.subscribers, sub)
+	select {
+	case sub.notify <- struct{}{}:
+	default:
+	}
+}
+
+// NumSubscribers returns the number of active subscribers.
+func (feed *Feed) NumSubscribers() int {
+	feed.mut.Lock()
+	defer feed.mut.Unlock()
+	return len(feed.subscribers)
+}
@@ -0,0 +1,163 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cdc
+
+import (
+	"errors"
+	"fmt"
+	"github.com/echovault/echovault/internal"
+	"github.com/echovault/echovault/internal/constants"
+	"github.com/echovault/echovault/internal/logger"
+	"strconv"
+	"strings"
+)
+
+func handleCDCSubscribe(params internal.HandlerFuncParams) ([]byte, error) {
+	feed, ok := params.GetCDC().(*Feed)
+	if !ok {
+		return nil, errors.New("could not load cdc module")
+	}
+
+	if len(params.Command) > 3 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+
+	if params.Connection == nil {
+		return nil, errors.New("CDC SUBSCRIBE is only supported over a TCP connection")
+	}
+
+	var offset uint64
+	if len(params.Command) == 3 {
+		var err error
+		if offset, err = strconv.ParseUint(params.Command[2], 10, 64); err != nil {
+			return nil, errors.New("offset must be a positive integer")
+		}
+	}
+
+	events, err := feed.Subscribe(params.Context, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	// The confirmation and the events are written directly to the connection.
+	conn := *params.Connection
+	if _, err = conn.Write([]byte(fmt.Sprintf("*2\r\n$9\r\nsubscribe\r\n:%d\r\n", feed.Offset()))); err != nil {
+		return nil, err
+	}
+
+	log := params.GetLogger()
+	go func() {
+		for event := range events {
+			if _, err := conn.Write(EncodeEvent(event)); err != nil {
+				log.Debug("cdc subscriber disconnected", logger.ErrorKey, err)
+				return
+			}
+		}
+	}()
+
+	return nil, nil
+}
+
+func handleCDCOffset(params internal.HandlerFuncParams) ([]byte, error) {
+	feed, ok := params.GetCDC().(*Feed)
+	if !ok {
+		return nil, errors.New("could not load cdc module")
+	}
+
+	if len(params.Command) != 2 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+
+	return []byte(fmt.Sprintf("*2\r\n:%d\r\n:%d\r\n", feed.FirstOffset(), feed.Offset())), nil
+}
+
+// EncodeEvent encodes the event as the RESP array sent to CDC subscribers:
+// "mutation", offset, type, database, key, unix timestamp in milliseconds, command.
+func EncodeEvent(event internal.MutationEvent) []byte {
+	var b strings.Builder
+	b.WriteString("*7\r\n$8\r\nmutation\r\n")
+	b.WriteString(fmt.Sprintf(":%d\r\n", event.Offset))
+	b.WriteString(fmt.Sprintf("$%d\r\n%s\r\n", len(event.Type), event.Type))
+	b.WriteString(fmt.Sprintf(":%d\r\n", event.Database))
+	b.WriteString(fmt.Sprintf("$%d\r\n%s\r\n", len(event.Key), event.Key))
+	b.WriteString(fmt.Sprintf(":%d\r\n", event.Timestamp.UnixMilli()))
+	b.WriteString(fmt.Sprintf("*%d\r\n", len(event.Command)))
+	for _, arg := range event.Command {
+		b.WriteString(fmt.Sprintf("$%d\r\n%s\r\n", len(arg), arg))
+	}
+	return []byte(b.String())
+}
+
+func Commands() []internal.Command {
+	return []internal.Command{
+		{
+			Command:     "cdc",
+			Module:      constants.CDCModule,
+			Categories:  []string{},
+			Description: "",
+			Sync:        false,
+			Arity:       -2,
+			Complexity:  "Depends on subcommand.",
+			Since:       "0.10.1",
+			KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+				return internal.KeyExtractionFuncResult{
+					Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+				}, nil
+			},
+			HandlerFunc: func(_ internal.HandlerFuncParams) ([]byte, error) {
+				return nil, errors.New("provide SUBSCRIBE or OFFSET subcommand")
+			},
+			SubCommands: []internal.SubCommand{
+				{
+					Command:    "subscribe",
+					Module:     constants.CDCModule,
+					Categories: []string{constants.AdminCategory, constants.SlowCategory, constants.DangerousCategory},
+					Description: `(CDC SUBSCRIBE [offset]) Streams every mutation of the keyspace to the connection.
+Each event is an array of "mutation", offset, type (set, del, expire, evict or flush), database, key,
+unix timestamp in milliseconds and the command that caused the change.
+When an offset is provided, the stream resumes from that offset if it's still in the backlog.`,
+					Sync:       false,
+					Arity:      -2,
+					Flags:      []string{constants.AdminFlag, constants.NoScriptFlag},
+					Complexity: "O(N) where N is the number of mutations in the backlog after the offset.",
+					Since:      "0.10.1",
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleCDCSubscribe,
+				},
+				{
+					Command:     "offset",
+					Module:      constants.CDCModule,
+					Categories:  []string{constants.AdminCategory, constants.FastCategory},
+					Description: `(CDC OFFSET) Returns the oldest offset in the backlog and the latest offset of the feed.`,
+					Sync:        false,
+					Arity:       2,
+					Flags:       []string{constants.AdminFlag, constants.FastFlag},
+					Complexity:  "O(1)",
+					Since:       "0.10.1",
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleCDCOffset,
+				},
+			},
+		},
+	}
+}
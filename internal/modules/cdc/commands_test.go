@@ -0,0 +1,206 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cdc_test
+
+import (
+	"github.com/echovault/echovault/echovault"
+	"github.com/echovault/echovault/internal"
+	"github.com/echovault/echovault/internal/constants"
+	"github.com/tidwall/resp"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func setupServer(port uint16) (*echovault.EchoVault, error) {
+	cfg := echovault.DefaultConfig()
+	cfg.DataDir = ""
+	cfg.BindAddr = "localhost"
+	cfg.Port = port
+	cfg.EvictionPolicy = constants.NoEviction
+	return echovault.NewEchoVault(echovault.WithConfig(cfg))
+}
+
+type event struct {
+	offset   int
+	typ      string
+	database int
+	key      string
+	command  []string
+}
+
+// readEvent reads the next event from the CDC stream.
+func readEvent(t *testing.T, conn net.Conn, rd *resp.Reader) event {
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	value, _, err := rd.ReadValue()
+	if err != nil {
+		t.Fatal(err)
+	}
+	arr := value.Array()
+	if len(arr) != 7 || arr[0].String() != "mutation" {
+		t.Fatalf("expected a mutation event, got %v", value)
+	}
+	var command []string
+	for _, v := range arr[6].Array() {
+		command = append(command, v.String())
+	}
+	return event{
+		offset:   arr[1].Integer(),
+		typ:      arr[2].String(),
+		database: arr[3].Integer(),
+		key:      arr[4].String(),
+		command:  command,
+	}
+}
+
+func Test_CDC(t *testing.T) {
+	port, err := internal.GetFreePort()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	mockServer, err := setupServer(uint16(port))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	go func() {
+		mockServer.Start()
+	}()
+
+	t.Cleanup(func() {
+		mockServer.ShutDown()
+	})
+
+	subscribe := func(t *testing.T, args ...string) (net.Conn, *resp.Reader, resp.Value) {
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rd := resp.NewReader(conn)
+		if _, err = conn.Write(internal.EncodeCommand(append([]string{"CDC", "SUBSCRIBE"}, args...))); err != nil {
+			t.Fatal(err)
+		}
+		_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		res, _, err := rd.ReadValue()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return conn, rd, res
+	}
+
+	writer, err := internal.GetConnection("localhost", port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = writer.Close()
+	}()
+	client := resp.NewConn(writer)
+	write := func(t *testing.T, command ...string) {
+		if err := client.WriteArray(func() []resp.Value {
+			var values []resp.Value
+			for _, arg := range command {
+				values = append(values, resp.StringValue(arg))
+			}
+			return values
+		}()); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := client.ReadValue(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var startOffset int
+
+	t.Run("Test CDC SUBSCRIBE streams mutations in order", func(t *testing.T) {
+		conn, rd, res := subscribe(t)
+		defer func() {
+			_ = conn.Close()
+		}()
+		if len(res.Array()) != 2 || res.Array()[0].String() != "subscribe" {
+			t.Fatalf("expected subscribe confirmation, got %v", res)
+		}
+
+		write(t, "SET", "CdcKey1", "value1")
+		write(t, "HSET", "CdcKey2", "field1", "value1")
+		write(t, "DEL", "CdcKey1")
+		write(t, "SELECT", "1")
+		write(t, "SET", "CdcKey3", "value3")
+		write(t, "FLUSHDB")
+		write(t, "SELECT", "0")
+
+		expected := []event{
+			{typ: "set", database: 0, key: "CdcKey1", command: []string{"SET", "CdcKey1", "value1"}},
+			{typ: "set", database: 0, key: "CdcKey2", command: []string{"HSET", "CdcKey2", "field1", "value1"}},
+			{typ: "del", database: 0, key: "CdcKey1", command: []string{"DEL", "CdcKey1"}},
+			{typ: "set", database: 1, key: "CdcKey3", command: []string{"SET", "CdcKey3", "value3"}},
+			{typ: "flush", database: 1, key: "", command: []string{"FLUSHDB"}},
+		}
+
+		for i, e := range expected {
+			got := readEvent(t, conn, rd)
+			if i == 0 {
+				startOffset = got.offset
+			} else if got.offset != startOffset+i {
+				t.Errorf("expected event %d to have offset %d, got %d", i, startOffset+i, got.offset)
+			}
+			if got.typ != e.typ || got.database != e.database || got.key != e.key ||
+				!slices.Equal(got.command, e.command) {
+				t.Errorf("expected event %d to be %+v, got %+v", i, e, got)
+			}
+		}
+	})
+
+	t.Run("Test CDC SUBSCRIBE resumes from an offset", func(t *testing.T) {
+		conn, rd, _ := subscribe(t, strconv.Itoa(startOffset+1))
+		defer func() {
+			_ = conn.Close()
+		}()
+		got := readEvent(t, conn, rd)
+		if got.offset != startOffset+1 || got.key != "CdcKey2" {
+			t.Errorf("expected to resume at offset %d with key CdcKey2, got %+v", startOffset+1, got)
+		}
+	})
+
+	t.Run("Test CDC SUBSCRIBE rejects offsets outside the backlog", func(t *testing.T) {
+		conn, _, res := subscribe(t, "1000000")
+		defer func() {
+			_ = conn.Close()
+		}()
+		if res.Error() == nil || !strings.Contains(res.Error().Error(), "out of range") {
+			t.Errorf("expected out of range error, got %v", res)
+		}
+	})
+
+	t.Run("Test CDC OFFSET", func(t *testing.T) {
+		if err := client.WriteArray([]resp.Value{resp.StringValue("CDC"), resp.StringValue("OFFSET")}); err != nil {
+			t.Fatal(err)
+		}
+		res, _, err := client.ReadValue()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res.Array()) != 2 || res.Array()[0].Integer() != 1 || res.Array()[1].Integer() != startOffset+4 {
+			t.Errorf("expected offsets [1 %d], got %v", startOffset+4, res)
+		}
+	})
+}
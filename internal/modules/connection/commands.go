@@ -20,9 +20,12 @@ import (
 	"github.com/echovault/echovault/internal/modules/acl"
 	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/echovault/echovault/internal"
 	"github.com/echovault/echovault/internal/constants"
+	"github.com/echovault/echovault/internal/tracking"
 )
 
 func handleAuth(params internal.HandlerFuncParams) ([]byte, error) {
@@ -171,6 +174,328 @@ func handleSwapDB(params internal.HandlerFuncParams) ([]byte, error) {
 	return []byte(constants.OkResponse), nil
 }
 
+func handleClientList(params internal.HandlerFuncParams) ([]byte, error) {
+	options, err := getClientListOptions(params.Command[2:], clientListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	now := params.GetClock().Now()
+	var res strings.Builder
+	for _, info := range params.ListConnections() {
+		if options.clientType != "" && !matchesClientType(info, options.clientType) {
+			continue
+		}
+		if len(options.ids) > 0 && !slices.Contains(options.ids, info.Id) {
+			continue
+		}
+		res.WriteString(formatClientInfo(info, now))
+		res.WriteString("\n")
+	}
+
+	return []byte(fmt.Sprintf("$%d\r\n%s\r\n", res.Len(), res.String())), nil
+}
+
+func handleClientInfo(params internal.HandlerFuncParams) ([]byte, error) {
+	if len(params.Command) != 2 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	if params.Connection == nil {
+		return nil, errors.New("CLIENT INFO is only supported over a TCP connection")
+	}
+
+	id := params.GetConnectionInfo(params.Connection).Id
+	connections := params.ListConnections()
+	i := slices.IndexFunc(connections, func(info internal.ConnectionInfo) bool {
+		return info.Id == id
+	})
+	if i == -1 {
+		return nil, errors.New("connection not found")
+	}
+
+	info := formatClientInfo(connections[i], params.GetClock().Now()) + "\n"
+	return []byte(fmt.Sprintf("$%d\r\n%s\r\n", len(info), info)), nil
+}
+
+func handleClientKill(params internal.HandlerFuncParams) ([]byte, error) {
+	if len(params.Command) < 3 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+
+	var currentId uint64
+	if params.Connection != nil {
+		currentId = params.GetConnectionInfo(params.Connection).Id
+	}
+
+	// The old form CLIENT KILL addr kills the connection with the address, and returns OK.
+	if len(params.Command) == 3 {
+		for _, info := range params.ListConnections() {
+			if info.Addr == params.Command[2] {
+				params.KillConnections([]uint64{info.Id})
+				return []byte(constants.OkResponse), nil
+			}
+		}
+		return nil, errors.New("no such client")
+	}
+
+	now := params.GetClock().Now()
+	options, err := getClientKillOptions(params.Command[2:], now, clientKillOptions{skipMe: true})
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []uint64
+	for _, info := range params.ListConnections() {
+		if options.skipMe && info.Id == currentId && params.Connection != nil {
+			continue
+		}
+		if slices.ContainsFunc(options.filters, func(filter func(info internal.ConnectionInfo) bool) bool {
+			return !filter(info)
+		}) {
+			continue
+		}
+		ids = append(ids, info.Id)
+	}
+
+	return []byte(fmt.Sprintf(":%d\r\n", params.KillConnections(ids))), nil
+}
+
+func handleClientSetName(params internal.HandlerFuncParams) ([]byte, error) {
+	if len(params.Command) != 3 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	if params.Connection == nil {
+		return nil, errors.New("CLIENT SETNAME is only supported over a TCP connection")
+	}
+
+	name := params.Command[2]
+	if strings.ContainsFunc(name, func(r rune) bool {
+		return r <= ' ' || r > '~'
+	}) {
+		return nil, errors.New("client names cannot contain spaces, newlines or special characters")
+	}
+
+	// An empty name removes the name of the connection.
+	params.UpdateConnectionInfo(params.Connection, func(info *internal.ConnectionInfo) {
+		info.Name = name
+	})
+	return []byte(constants.OkResponse), nil
+}
+
+func handleClientGetName(params internal.HandlerFuncParams) ([]byte, error) {
+	if len(params.Command) != 2 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	if params.Connection == nil {
+		return nil, errors.New("CLIENT GETNAME is only supported over a TCP connection")
+	}
+
+	name := params.GetConnectionInfo(params.Connection).Name
+	if name == "" {
+		return []byte("$-1\r\n"), nil
+	}
+	return []byte(fmt.Sprintf("$%d\r\n%s\r\n", len(name), name)), nil
+}
+
+func handleClientId(params internal.HandlerFuncParams) ([]byte, error) {
+	if len(params.Command) != 2 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	if params.Connection == nil {
+		return nil, errors.New("CLIENT ID is only supported over a TCP connection")
+	}
+	return []byte(fmt.Sprintf(":%d\r\n", params.GetConnectionInfo(params.Connection).Id)), nil
+}
+
+func handleClientPause(params internal.HandlerFuncParams) ([]byte, error) {
+	if len(params.Command) < 3 || len(params.Command) > 4 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+
+	timeout, err := strconv.ParseInt(params.Command[2], 10, 64)
+	if err != nil || timeout < 0 {
+		return nil, errors.New("timeout is not an integer or out of range")
+	}
+
+	writeOnly := false
+	if len(params.Command) == 4 {
+		switch strings.ToLower(params.Command[3]) {
+		case "write":
+			writeOnly = true
+		case "all":
+		default:
+			return nil, errors.New("mode must be WRITE or ALL")
+		}
+	}
+
+	params.PauseClients(time.Duration(timeout)*time.Millisecond, writeOnly)
+	return []byte(constants.OkResponse), nil
+}
+
+func handleClientUnpause(params internal.HandlerFuncParams) ([]byte, error) {
+	if len(params.Command) != 2 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	params.UnpauseClients()
+	return []byte(constants.OkResponse), nil
+}
+
+func handleClientNoEvict(params internal.HandlerFuncParams) ([]byte, error) {
+	if len(params.Command) != 3 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	if params.Connection == nil {
+		return nil, errors.New("CLIENT NO-EVICT is only supported over a TCP connection")
+	}
+
+	var noEvict bool
+	switch strings.ToLower(params.Command[2]) {
+	case "on":
+		noEvict = true
+	case "off":
+		noEvict = false
+	default:
+		return nil, errors.New("argument must be ON or OFF")
+	}
+
+	params.UpdateConnectionInfo(params.Connection, func(info *internal.ConnectionInfo) {
+		info.NoEvict = noEvict
+	})
+	return []byte(constants.OkResponse), nil
+}
+
+func handleClientTracking(params internal.HandlerFuncParams) ([]byte, error) {
+	if len(params.Command) < 3 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	if params.Connection == nil {
+		return nil, errors.New("CLIENT TRACKING is only supported over a TCP connection")
+	}
+	clientTracking, ok := params.GetTracking().(*tracking.Tracking)
+	if !ok {
+		return nil, errors.New("could not load tracking engine")
+	}
+
+	options, err := getClientTrackingOptions(params.Command[3:], tracking.Options{})
+	if err != nil {
+		return nil, err
+	}
+
+	connectionInfo := params.GetConnectionInfo(params.Connection)
+
+	switch strings.ToLower(params.Command[2]) {
+	default:
+		return nil, errors.New("argument must be ON or OFF")
+	case "off":
+		clientTracking.Disable(connectionInfo.Id)
+		return []byte(constants.OkResponse), nil
+	case "on":
+	}
+
+	if options.Redirect == 0 && connectionInfo.Protocol != 3 {
+		return nil, errors.New("invalidation messages can only be received with RESP3 or a REDIRECT connection")
+	}
+	if options.Redirect != 0 && !slices.ContainsFunc(params.ListConnections(), func(info internal.ConnectionInfo) bool {
+		return info.Id == options.Redirect
+	}) {
+		return nil, errors.New("the client ID you want redirect to does not exist")
+	}
+
+	connectionID, _ := params.Context.Value(internal.ContextConnID("ConnectionID")).(string)
+	if err = clientTracking.Enable(connectionInfo.Id, connectionID, options); err != nil {
+		return nil, err
+	}
+	return []byte(constants.OkResponse), nil
+}
+
+func handleClientCaching(params internal.HandlerFuncParams) ([]byte, error) {
+	if len(params.Command) != 3 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	if params.Connection == nil {
+		return nil, errors.New("CLIENT CACHING is only supported over a TCP connection")
+	}
+	clientTracking, ok := params.GetTracking().(*tracking.Tracking)
+	if !ok {
+		return nil, errors.New("could not load tracking engine")
+	}
+
+	var yes bool
+	switch strings.ToLower(params.Command[2]) {
+	case "yes":
+		yes = true
+	case "no":
+		yes = false
+	default:
+		return nil, errors.New("argument must be YES or NO")
+	}
+
+	if err := clientTracking.SetCaching(params.GetConnectionInfo(params.Connection).Id, yes); err != nil {
+		return nil, err
+	}
+	return []byte(constants.OkResponse), nil
+}
+
+func handleClientGetRedir(params internal.HandlerFuncParams) ([]byte, error) {
+	if len(params.Command) != 2 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	if params.Connection == nil {
+		return nil, errors.New("CLIENT GETREDIR is only supported over a TCP connection")
+	}
+	clientTracking, ok := params.GetTracking().(*tracking.Tracking)
+	if !ok {
+		return nil, errors.New("could not load tracking engine")
+	}
+
+	options, ok := clientTracking.Options(params.GetConnectionInfo(params.Connection).Id)
+	if !ok {
+		return []byte(":-1\r\n"), nil
+	}
+	return []byte(fmt.Sprintf(":%d\r\n", options.Redirect)), nil
+}
+
+func handleClientTrackingInfo(params internal.HandlerFuncParams) ([]byte, error) {
+	if len(params.Command) != 2 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	if params.Connection == nil {
+		return nil, errors.New("CLIENT TRACKINGINFO is only supported over a TCP connection")
+	}
+	clientTracking, ok := params.GetTracking().(*tracking.Tracking)
+	if !ok {
+		return nil, errors.New("could not load tracking engine")
+	}
+
+	flags := []string{"off"}
+	redirect := int64(-1)
+	var prefixes []string
+	if options, ok := clientTracking.Options(params.GetConnectionInfo(params.Connection).Id); ok {
+		flags = []string{"on"}
+		for flag, set := range map[string]bool{
+			"bcast": options.BCast, "optin": options.OptIn, "optout": options.OptOut, "noloop": options.NoLoop,
+		} {
+			if set {
+				flags = append(flags, flag)
+			}
+		}
+		slices.Sort(flags[1:])
+		redirect = int64(options.Redirect)
+		prefixes = options.Prefixes
+	}
+
+	res := fmt.Sprintf("*6\r\n$5\r\nflags\r\n*%d\r\n", len(flags))
+	for _, flag := range flags {
+		res += fmt.Sprintf("$%d\r\n%s\r\n", len(flag), flag)
+	}
+	res += fmt.Sprintf("$8\r\nredirect\r\n:%d\r\n", redirect)
+	res += fmt.Sprintf("$8\r\nprefixes\r\n*%d\r\n", len(prefixes))
+	for _, prefix := range prefixes {
+		res += fmt.Sprintf("$%d\r\n%s\r\n", len(prefix), prefix)
+	}
+	return []byte(res), nil
+}
+
 func Commands() []internal.Command {
 	return []internal.Command{
 		{
@@ -180,7 +505,11 @@ func Commands() []internal.Command {
 			Description: `(AUTH [username] password) 
 Authenticates the connection. If the username is not provided, the connection will be authenticated against the
 default ACL user. Otherwise, it is authenticated against the ACL user with the provided username.`,
-			Sync: false,
+			Sync:       false,
+			Arity:      -2,
+			Flags:      []string{constants.NoScriptFlag, constants.LoadingFlag, constants.StaleFlag, constants.NoAuthFlag},
+			Complexity: "O(N) where N is the number of passwords defined for the user.",
+			Since:      "0.1.0",
 			KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
 				return internal.KeyExtractionFuncResult{
 					Channels:  make([]string, 0),
@@ -197,7 +526,11 @@ default ACL user. Otherwise, it is authenticated against the ACL user with the p
 			Description: `(PING [message])
 Ping the echovault server. If a message is provided, the message will be echoed back to the client.
 Otherwise, the server will return "PONG".`,
-			Sync: false,
+			Sync:       false,
+			Arity:      -1,
+			Flags:      []string{constants.FastFlag},
+			Complexity: "O(1)",
+			Since:      "0.1.0",
 			KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
 				return internal.KeyExtractionFuncResult{
 					Channels:  make([]string, 0),
@@ -213,6 +546,10 @@ Otherwise, the server will return "PONG".`,
 			Categories:  []string{constants.ConnectionCategory, constants.FastCategory},
 			Description: `(ECHO message) Echo the message back to the client.`,
 			Sync:        false,
+			Arity:       2,
+			Flags:       []string{constants.FastFlag},
+			Complexity:  "O(1)",
+			Since:       "0.1.0",
 			KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
 				return internal.KeyExtractionFuncResult{
 					Channels:  make([]string, 0),
@@ -229,7 +566,11 @@ Otherwise, the server will return "PONG".`,
 			Description: `(HELLO [protover [AUTH username password] [SETNAME clientname]])
 Switch to a different protocol, optionally authenticating and setting the connection's name. 
 This command returns a contextual client report.`,
-			Sync: false,
+			Sync:       false,
+			Arity:      -1,
+			Flags:      []string{constants.NoScriptFlag, constants.LoadingFlag, constants.StaleFlag, constants.FastFlag, constants.NoAuthFlag},
+			Complexity: "O(1)",
+			Since:      "0.1.0",
 			KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
 				return internal.KeyExtractionFuncResult{
 					Channels:  make([]string, 0),
@@ -245,6 +586,10 @@ This command returns a contextual client report.`,
 			Categories:  []string{constants.FastCategory, constants.ConnectionCategory},
 			Description: `(SELECT index) Change the logical database that the current connection is operating from.`,
 			Sync:        false,
+			Arity:       2,
+			Flags:       []string{constants.LoadingFlag, constants.StaleFlag, constants.FastFlag},
+			Complexity:  "O(1)",
+			Since:       "0.1.0",
 			KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
 				return internal.KeyExtractionFuncResult{
 					Channels:  make([]string, 0),
@@ -267,7 +612,11 @@ This command returns a contextual client report.`,
 This command swaps two databases, 
 so that immediately all the clients connected to a given database will see the data of the other database, 
 and the other way around.`,
-			Sync: false,
+			Sync:       false,
+			Arity:      3,
+			Flags:      []string{constants.NoScriptFlag},
+			Complexity: "O(N) where N is the count of clients watching or blocking on keys from both databases.",
+			Since:      "0.1.0",
 			KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
 				return internal.KeyExtractionFuncResult{
 					Channels:  make([]string, 0),
@@ -277,5 +626,289 @@ and the other way around.`,
 			},
 			HandlerFunc: handleSwapDB,
 		},
+		{
+			Command:     "client",
+			Module:      constants.ConnectionModule,
+			Categories:  []string{},
+			Description: "Commands pertaining to the client connections",
+			Sync:        false,
+			Arity:       -2,
+			Complexity:  "Depends on subcommand.",
+			Since:       "0.10.1",
+			KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+				return internal.KeyExtractionFuncResult{
+					Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+				}, nil
+			},
+			HandlerFunc: func(_ internal.HandlerFuncParams) ([]byte, error) {
+				return nil, errors.New("provide LIST, INFO, KILL, SETNAME, GETNAME, ID, PAUSE, UNPAUSE, NO-EVICT, TRACKING, CACHING, GETREDIR or TRACKINGINFO subcommand")
+			},
+			SubCommands: []internal.SubCommand{
+				{
+					Command: "list",
+					Module:  constants.ConnectionModule,
+					Categories: []string{
+						constants.AdminCategory,
+						constants.SlowCategory,
+						constants.DangerousCategory,
+						constants.ConnectionCategory,
+					},
+					Description: `(CLIENT LIST [TYPE normal|master|replica|pubsub] [ID id [id ...]])
+Returns information about the TCP connections, one line per connection. Each line holds the id, address, local
+address, name, age and idle time in seconds, flags, database, subscriptions, size of the last command and reply,
+last command, authenticated user and RESP protocol of the connection.`,
+					Sync:       false,
+					Arity:      -2,
+					Flags:      []string{constants.AdminFlag, constants.NoScriptFlag, constants.LoadingFlag, constants.StaleFlag},
+					Complexity: "O(N) where N is the number of client connections.",
+					Since:      "0.10.1",
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleClientList,
+				},
+				{
+					Command:     "info",
+					Module:      constants.ConnectionModule,
+					Categories:  []string{constants.SlowCategory, constants.ConnectionCategory},
+					Description: `(CLIENT INFO) Returns information about the current connection in the format of CLIENT LIST.`,
+					Sync:        false,
+					Arity:       2,
+					Flags:       []string{constants.NoScriptFlag, constants.LoadingFlag, constants.StaleFlag},
+					Complexity:  "O(1)",
+					Since:       "0.10.1",
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleClientInfo,
+				},
+				{
+					Command: "kill",
+					Module:  constants.ConnectionModule,
+					Categories: []string{
+						constants.AdminCategory,
+						constants.SlowCategory,
+						constants.DangerousCategory,
+						constants.ConnectionCategory,
+					},
+					Description: `(CLIENT KILL addr | CLIENT KILL [ID id] [ADDR addr] [LADDR laddr] [USER username]
+[TYPE normal|master|replica|pubsub] [MAXAGE seconds] [SKIPME yes|no])
+Closes the connections that match all the filters and returns the number of connections closed. The current
+connection is skipped unless SKIPME is no. The old form closes the connection with the address and returns OK.`,
+					Sync:       false,
+					Arity:      -3,
+					Flags:      []string{constants.AdminFlag, constants.NoScriptFlag, constants.LoadingFlag, constants.StaleFlag},
+					Complexity: "O(N) where N is the number of client connections.",
+					Since:      "0.10.1",
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleClientKill,
+				},
+				{
+					Command:     "setname",
+					Module:      constants.ConnectionModule,
+					Categories:  []string{constants.SlowCategory, constants.ConnectionCategory},
+					Description: `(CLIENT SETNAME name) Sets the name of the current connection. An empty name removes the name.`,
+					Sync:        false,
+					Arity:       3,
+					Flags:       []string{constants.NoScriptFlag, constants.LoadingFlag, constants.StaleFlag},
+					Complexity:  "O(1)",
+					Since:       "0.10.1",
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleClientSetName,
+				},
+				{
+					Command:     "getname",
+					Module:      constants.ConnectionModule,
+					Categories:  []string{constants.SlowCategory, constants.ConnectionCategory},
+					Description: `(CLIENT GETNAME) Returns the name of the current connection, or nil if it has no name.`,
+					Sync:        false,
+					Arity:       2,
+					Flags:       []string{constants.NoScriptFlag, constants.LoadingFlag, constants.StaleFlag},
+					Complexity:  "O(1)",
+					Since:       "0.10.1",
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleClientGetName,
+				},
+				{
+					Command:     "id",
+					Module:      constants.ConnectionModule,
+					Categories:  []string{constants.SlowCategory, constants.ConnectionCategory},
+					Description: `(CLIENT ID) Returns the id of the current connection.`,
+					Sync:        false,
+					Arity:       2,
+					Flags:       []string{constants.NoScriptFlag, constants.LoadingFlag, constants.StaleFlag},
+					Complexity:  "O(1)",
+					Since:       "0.10.1",
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleClientId,
+				},
+				{
+					Command: "pause",
+					Module:  constants.ConnectionModule,
+					Categories: []string{
+						constants.AdminCategory,
+						constants.SlowCategory,
+						constants.DangerousCategory,
+						constants.ConnectionCategory,
+					},
+					Description: `(CLIENT PAUSE timeout [WRITE|ALL]) Suspends the commands of the clients for timeout milliseconds.
+With WRITE, only the write commands are suspended. With ALL, the default, every command is suspended.
+The CLIENT commands are never suspended. Keys are not actively expired while the clients are paused.`,
+					Sync:       false,
+					Arity:      -3,
+					Flags:      []string{constants.AdminFlag, constants.NoScriptFlag, constants.LoadingFlag, constants.StaleFlag},
+					Complexity: "O(1)",
+					Since:      "0.10.1",
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleClientPause,
+				},
+				{
+					Command: "unpause",
+					Module:  constants.ConnectionModule,
+					Categories: []string{
+						constants.AdminCategory,
+						constants.SlowCategory,
+						constants.DangerousCategory,
+						constants.ConnectionCategory,
+					},
+					Description: `(CLIENT UNPAUSE) Resumes the commands suspended by CLIENT PAUSE.`,
+					Sync:        false,
+					Arity:       2,
+					Flags:       []string{constants.AdminFlag, constants.NoScriptFlag, constants.LoadingFlag, constants.StaleFlag},
+					Complexity:  "O(N) where N is the number of paused clients.",
+					Since:       "0.10.1",
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleClientUnpause,
+				},
+				{
+					Command: "no-evict",
+					Module:  constants.ConnectionModule,
+					Categories: []string{
+						constants.AdminCategory,
+						constants.SlowCategory,
+						constants.DangerousCategory,
+						constants.ConnectionCategory,
+					},
+					Description: `(CLIENT NO-EVICT ON|OFF) Sets the no-evict flag of the current connection, reported as flag e
+by CLIENT LIST. EchoVault does not evict connections, so the flag has no other effect.`,
+					Sync:       false,
+					Arity:      3,
+					Flags:      []string{constants.AdminFlag, constants.NoScriptFlag, constants.LoadingFlag, constants.StaleFlag},
+					Complexity: "O(1)",
+					Since:      "0.10.1",
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleClientNoEvict,
+				},
+				{
+					Command:    "tracking",
+					Module:     constants.ConnectionModule,
+					Categories: []string{constants.SlowCategory, constants.ConnectionCategory},
+					Description: `(CLIENT TRACKING ON|OFF [REDIRECT id] [PREFIX prefix [PREFIX prefix ...]] [BCAST] [OPTIN] [OPTOUT] [NOLOOP])
+Turns server-assisted client-side caching on or off for the connection. The server remembers the keys read by the
+connection and sends an invalidation message when they are modified, expire or are evicted. The messages are RESP3
+push messages, or pub/sub messages of the __redis__:invalidate channel sent to the REDIRECT connection.
+With BCAST, every key that matches the prefixes is invalidated, whether the connection read it or not.
+With OPTIN, only the keys read after CLIENT CACHING YES are tracked. With OPTOUT, the keys read after
+CLIENT CACHING NO are not tracked. With NOLOOP, the keys modified by the connection itself are not invalidated.`,
+					Sync:       false,
+					Arity:      -3,
+					Flags:      []string{constants.NoScriptFlag, constants.LoadingFlag, constants.StaleFlag},
+					Complexity: "O(1). Some options may introduce additional complexity.",
+					Since:      "0.10.1",
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleClientTracking,
+				},
+				{
+					Command:    "caching",
+					Module:     constants.ConnectionModule,
+					Categories: []string{constants.SlowCategory, constants.ConnectionCategory},
+					Description: `(CLIENT CACHING YES|NO) Decides whether the keys read by the next command are tracked,
+when tracking is on in OPTIN or OPTOUT mode.`,
+					Sync:       false,
+					Arity:      3,
+					Flags:      []string{constants.NoScriptFlag, constants.LoadingFlag, constants.StaleFlag},
+					Complexity: "O(1)",
+					Since:      "0.10.1",
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleClientCaching,
+				},
+				{
+					Command:    "getredir",
+					Module:     constants.ConnectionModule,
+					Categories: []string{constants.SlowCategory, constants.ConnectionCategory},
+					Description: `(CLIENT GETREDIR) Returns the id of the connection that receives the invalidation messages,
+0 when they're sent to the current connection, or -1 when tracking is off.`,
+					Sync:       false,
+					Arity:      2,
+					Flags:      []string{constants.NoScriptFlag, constants.LoadingFlag, constants.StaleFlag},
+					Complexity: "O(1)",
+					Since:      "0.10.1",
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleClientGetRedir,
+				},
+				{
+					Command:    "trackinginfo",
+					Module:     constants.ConnectionModule,
+					Categories: []string{constants.SlowCategory, constants.ConnectionCategory},
+					Description: `(CLIENT TRACKINGINFO) Returns the tracking flags, redirect connection id and prefixes of the
+current connection.`,
+					Sync:       false,
+					Arity:      2,
+					Flags:      []string{constants.NoScriptFlag, constants.LoadingFlag, constants.StaleFlag},
+					Complexity: "O(1)",
+					Since:      "0.10.1",
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleClientTrackingInfo,
+				},
+			},
+		},
 	}
 }
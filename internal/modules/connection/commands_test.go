@@ -26,6 +26,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/echovault/echovault/echovault"
 	"github.com/echovault/echovault/internal"
@@ -997,4 +998,330 @@ func Test_Connection(t *testing.T) {
 			}
 		}
 	})
+
+	t.Run("Test_HandleClient", func(t *testing.T) {
+		t.Parallel()
+
+		port, err := internal.GetFreePort()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		mockServer, err := setUpServer(port, false, "")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		go func() {
+			mockServer.Start()
+		}()
+		t.Cleanup(func() {
+			mockServer.ShutDown()
+		})
+
+		clients := make([]*resp.Conn, 3)
+		for i := range clients {
+			conn, err := internal.GetConnection("localhost", port)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer func() {
+				_ = conn.Close()
+			}()
+			clients[i] = resp.NewConn(conn)
+		}
+
+		send := func(client *resp.Conn, command ...string) (resp.Value, error) {
+			values := make([]resp.Value, len(command))
+			for i, c := range command {
+				values[i] = resp.StringValue(c)
+			}
+			if err := client.WriteArray(values); err != nil {
+				return resp.Value{}, err
+			}
+			res, _, err := client.ReadValue()
+			return res, err
+		}
+
+		tests := []struct {
+			name    string
+			command []string
+			want    string
+		}{
+			{name: "1. Get the name of a connection without a name", command: []string{"CLIENT", "GETNAME"}, want: ""},
+			{name: "2. Set the name of the connection", command: []string{"CLIENT", "SETNAME", "client-0"}, want: "OK"},
+			{name: "3. Get the name of the connection", command: []string{"CLIENT", "GETNAME"}, want: "client-0"},
+			{
+				name:    "4. Return error when the name contains spaces",
+				command: []string{"CLIENT", "SETNAME", "client 0"},
+				want:    "client names cannot contain spaces, newlines or special characters",
+			},
+			{name: "5. Set the no-evict flag", command: []string{"CLIENT", "NO-EVICT", "on"}, want: "OK"},
+			{
+				name:    "6. Return error for an invalid no-evict argument",
+				command: []string{"CLIENT", "NO-EVICT", "maybe"},
+				want:    "argument must be ON or OFF",
+			},
+			{
+				name:    "7. Return error for an invalid client type",
+				command: []string{"CLIENT", "LIST", "TYPE", "unknown"},
+				want:    "unknown client type 'unknown'",
+			},
+			{
+				name:    "8. Return error for an unknown kill filter",
+				command: []string{"CLIENT", "KILL", "NAME", "client-0"},
+				want:    "unknown filter NAME",
+			},
+			{
+				name:    "9. Return error for an invalid pause timeout",
+				command: []string{"CLIENT", "PAUSE", "-1"},
+				want:    "timeout is not an integer or out of range",
+			},
+			{
+				name:    "10. Return error for an invalid pause mode",
+				command: []string{"CLIENT", "PAUSE", "100", "READ"},
+				want:    "mode must be WRITE or ALL",
+			},
+			{
+				name:    "11. Return error when the subcommand is missing",
+				command: []string{"CLIENT"},
+				want:    "provide LIST, INFO, KILL, SETNAME",
+			},
+		}
+
+		for _, test := range tests {
+			res, err := send(clients[0], test.command...)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if !strings.Contains(res.String(), test.want) {
+				t.Errorf("%s: expected response \"%s\", got \"%s\"", test.name, test.want, res.String())
+			}
+		}
+
+		// Check the information of the connection.
+		res, err := send(clients[0], "CLIENT", "ID")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		id := res.Integer()
+		res, err = send(clients[0], "CLIENT", "INFO")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		for _, field := range []string{
+			fmt.Sprintf("id=%d ", id), "name=client-0 ", "flags=e ", "db=0 ", "cmd=client|info ", "user=default ", "resp=2",
+		} {
+			if !strings.Contains(res.String(), field) {
+				t.Errorf("expected CLIENT INFO to contain \"%s\", got \"%s\"", field, res.String())
+			}
+		}
+
+		// Check that CLIENT LIST returns a line for each connection, and filters them by id.
+		res, err = send(clients[0], "CLIENT", "LIST")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if lines := strings.Split(strings.TrimSpace(res.String()), "\n"); len(lines) != len(clients) {
+			t.Errorf("expected %d connections in CLIENT LIST, got %d", len(clients), len(lines))
+		}
+		res, err = send(clients[0], "CLIENT", "LIST", "ID", strconv.Itoa(id))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if lines := strings.Split(strings.TrimSpace(res.String()), "\n"); len(lines) != 1 ||
+			!strings.HasPrefix(lines[0], fmt.Sprintf("id=%d ", id)) {
+			t.Errorf("expected CLIENT LIST ID to return connection %d, got \"%s\"", id, res.String())
+		}
+
+		// Check that CLIENT PAUSE WRITE suspends the writes until CLIENT UNPAUSE, but not the reads.
+		if res, err = send(clients[0], "CLIENT", "PAUSE", "10000", "WRITE"); err != nil || res.String() != "OK" {
+			t.Errorf("expected OK response from CLIENT PAUSE, got \"%s\" (%v)", res.String(), err)
+			return
+		}
+		done := make(chan string)
+		go func() {
+			res, _ := send(clients[1], "SET", "key", "value")
+			done <- res.String()
+		}()
+		if res, err = send(clients[2], "GET", "key"); err != nil || !res.IsNull() {
+			t.Errorf("expected nil response from GET while paused, got \"%s\" (%v)", res.String(), err)
+		}
+		select {
+		case res := <-done:
+			t.Errorf("expected SET to be suspended while paused, got \"%s\"", res)
+		case <-time.After(200 * time.Millisecond):
+		}
+		if res, err = send(clients[0], "CLIENT", "UNPAUSE"); err != nil || res.String() != "OK" {
+			t.Errorf("expected OK response from CLIENT UNPAUSE, got \"%s\" (%v)", res.String(), err)
+		}
+		select {
+		case res := <-done:
+			if res != "OK" {
+				t.Errorf("expected OK response from SET after unpause, got \"%s\"", res)
+			}
+		case <-time.After(5 * time.Second):
+			t.Error("expected SET to complete after unpause")
+		}
+
+		// Check that CLIENT KILL closes the connections that match the filters, skipping the current connection.
+		res, err = send(clients[0], "CLIENT", "KILL", "USER", "default")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if res.Integer() != len(clients)-1 {
+			t.Errorf("expected CLIENT KILL to close %d connections, got %d", len(clients)-1, res.Integer())
+		}
+		for _, client := range clients[1:] {
+			if _, err = send(client, "PING"); err == nil {
+				t.Error("expected killed connection to be closed")
+			}
+		}
+		if res, err = send(clients[0], "PING"); err != nil || res.String() != "PONG" {
+			t.Errorf("expected the current connection to stay open, got \"%s\" (%v)", res.String(), err)
+		}
+		if _, err = send(clients[0], "CLIENT", "KILL", "ID", strconv.Itoa(id), "SKIPME", "no"); err != nil {
+			t.Error(err)
+		}
+		if _, err = send(clients[0], "PING"); err == nil {
+			t.Error("expected the connection to be closed after killing itself")
+		}
+	})
+
+	t.Run("Test_HandleClientTracking", func(t *testing.T) {
+		t.Parallel()
+
+		port, err := internal.GetFreePort()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		mockServer, err := setUpServer(port, false, "")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		go func() {
+			mockServer.Start()
+		}()
+		t.Cleanup(func() {
+			mockServer.ShutDown()
+		})
+
+		// The RESP3 connection reads the replies and the push messages as raw bytes.
+		tracked, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = tracked.Close()
+		}()
+		expect := func(command []string, want string) {
+			if command != nil {
+				if _, err := tracked.Write(internal.EncodeCommand(command)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			_ = tracked.SetReadDeadline(time.Now().Add(5 * time.Second))
+			var received []byte
+			buf := make([]byte, 1024)
+			for !bytes.Contains(received, []byte(want)) {
+				n, err := tracked.Read(buf)
+				if err != nil {
+					t.Fatalf("expected to receive %q, got %q: %v", want, received, err)
+				}
+				received = append(received, buf[:n]...)
+			}
+		}
+
+		clients := make([]*resp.Conn, 3)
+		for i := range clients {
+			conn, err := internal.GetConnection("localhost", port)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer func() {
+				_ = conn.Close()
+			}()
+			clients[i] = resp.NewConn(conn)
+		}
+		writer, subscriber, redirected := clients[0], clients[1], clients[2]
+		send := func(client *resp.Conn, command ...string) resp.Value {
+			values := make([]resp.Value, len(command))
+			for i, c := range command {
+				values[i] = resp.StringValue(c)
+			}
+			if err := client.WriteArray(values); err != nil {
+				t.Fatal(err)
+			}
+			res, _, err := client.ReadValue()
+			if err != nil {
+				t.Fatal(err)
+			}
+			return res
+		}
+
+		// RESP2 connections need a REDIRECT connection.
+		if res := send(writer, "CLIENT", "TRACKING", "ON"); !strings.Contains(res.String(), "RESP3") {
+			t.Errorf("expected error when turning tracking on with RESP2, got \"%s\"", res.String())
+		}
+
+		// The keys read by the connection are invalidated when they're modified.
+		send(writer, "SET", "key1", "value1")
+		expect([]string{"HELLO", "3"}, "proto")
+		expect([]string{"CLIENT", "TRACKING", "ON"}, "+OK\r\n")
+		expect([]string{"GET", "key1"}, "value1\r\n")
+		send(writer, "SET", "key1", "value2")
+		expect(nil, ">2\r\n$10\r\ninvalidate\r\n*1\r\n$4\r\nkey1\r\n")
+
+		// The keys are also invalidated when their expiry time changes and when they're deleted.
+		send(writer, "SET", "key2", "value")
+		expect([]string{"GET", "key2"}, "value\r\n")
+		send(writer, "EXPIRE", "key2", "100")
+		expect(nil, ">2\r\n$10\r\ninvalidate\r\n*1\r\n$4\r\nkey2\r\n")
+		expect([]string{"GET", "key2"}, "value\r\n")
+		send(writer, "DEL", "key2")
+		expect(nil, ">2\r\n$10\r\ninvalidate\r\n*1\r\n$4\r\nkey2\r\n")
+
+		// Flushing the database invalidates every key.
+		send(writer, "FLUSHALL")
+		expect(nil, ">2\r\n$10\r\ninvalidate\r\n_\r\n")
+
+		// In BCAST mode, the invalidations are sent to the REDIRECT connection for the keys matching the prefixes.
+		subscriberId := send(subscriber, "CLIENT", "ID").Integer()
+		if res := send(subscriber, "SUBSCRIBE", "__redis__:invalidate"); len(res.Array()) != 3 {
+			t.Fatalf("expected subscribe confirmation, got %v", res)
+		}
+		if res := send(redirected, "CLIENT", "TRACKING", "ON", "REDIRECT", strconv.Itoa(subscriberId),
+			"BCAST", "PREFIX", "user:"); res.String() != "OK" {
+			t.Fatalf("expected OK response from CLIENT TRACKING, got \"%s\"", res.String())
+		}
+		if res := send(redirected, "CLIENT", "GETREDIR"); res.Integer() != subscriberId {
+			t.Errorf("expected CLIENT GETREDIR to return %d, got %d", subscriberId, res.Integer())
+		}
+		send(writer, "SET", "order:1", "value")
+		send(writer, "SET", "user:1", "value")
+		res, _, err := subscriber.ReadValue()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if arr := res.Array(); len(arr) != 3 || arr[0].String() != "message" ||
+			arr[1].String() != "__redis__:invalidate" || len(arr[2].Array()) != 1 || arr[2].Array()[0].String() != "user:1" {
+			t.Errorf("expected the invalidation of user:1, got %v", res)
+		}
+
+		// CLIENT LIST reports the tracking connections with the t flag.
+		if res := send(redirected, "CLIENT", "INFO"); !strings.Contains(res.String(), "flags=t ") {
+			t.Errorf("expected the t flag in CLIENT INFO, got \"%s\"", res.String())
+		}
+	})
 }
@@ -1,10 +1,15 @@
 package connection
 
 import (
+	"errors"
 	"fmt"
 	"github.com/echovault/echovault/internal"
 	"github.com/echovault/echovault/internal/constants"
+	"github.com/echovault/echovault/internal/tracking"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type helloOptions struct {
@@ -64,3 +69,169 @@ func BuildHelloResponse(serverInfo internal.ServerInfo, connectionInfo internal.
 	}
 	return res
 }
+
+// formatClientInfo formats the connection information as a line of CLIENT LIST.
+func formatClientInfo(info internal.ConnectionInfo, now time.Time) string {
+	cmd := info.LastCommand
+	if cmd == "" {
+		cmd = "NULL"
+	}
+	return fmt.Sprintf(
+		"id=%d addr=%s laddr=%s name=%s age=%d idle=%d flags=%s db=%d sub=%d psub=%d qbuf=%d omem=%d cmd=%s user=%s resp=%d",
+		info.Id, info.Addr, info.LocalAddr, info.Name,
+		int64(now.Sub(info.CreatedAt).Seconds()), int64(now.Sub(info.LastInteraction).Seconds()),
+		info.Flags(), info.Database, info.Subscriptions, info.PatternSubscriptions,
+		info.QueryBuffer, info.OutputBuffer, cmd, info.User, info.Protocol,
+	)
+}
+
+func validClientType(clientType string) error {
+	if !slices.Contains([]string{"normal", "master", "replica", "slave", "pubsub"}, strings.ToLower(clientType)) {
+		return fmt.Errorf("unknown client type '%s'", clientType)
+	}
+	return nil
+}
+
+func matchesClientType(info internal.ConnectionInfo, clientType string) bool {
+	clientType = strings.ToLower(clientType)
+	if clientType == "slave" {
+		clientType = "replica"
+	}
+	return info.Type() == clientType
+}
+
+type clientListOptions struct {
+	clientType string
+	ids        []uint64
+}
+
+func getClientListOptions(cmd []string, options clientListOptions) (clientListOptions, error) {
+	if len(cmd) == 0 {
+		return options, nil
+	}
+	switch strings.ToLower(cmd[0]) {
+	case "type":
+		if len(cmd) < 2 {
+			return options, errors.New(constants.WrongArgsResponse)
+		}
+		if err := validClientType(cmd[1]); err != nil {
+			return options, err
+		}
+		options.clientType = cmd[1]
+		return getClientListOptions(cmd[2:], options)
+	case "id":
+		if len(cmd) < 2 {
+			return options, errors.New(constants.WrongArgsResponse)
+		}
+		// The ids run until the end of the command.
+		for _, arg := range cmd[1:] {
+			id, err := strconv.ParseUint(arg, 10, 64)
+			if err != nil {
+				return options, fmt.Errorf("invalid client id %s", arg)
+			}
+			options.ids = append(options.ids, id)
+		}
+		return options, nil
+	default:
+		return options, fmt.Errorf("unknown keyword %s", strings.ToUpper(cmd[0]))
+	}
+}
+
+type clientKillOptions struct {
+	skipMe  bool
+	filters []func(info internal.ConnectionInfo) bool
+}
+
+func getClientKillOptions(cmd []string, now time.Time, options clientKillOptions) (clientKillOptions, error) {
+	if len(cmd) == 0 {
+		return options, nil
+	}
+	if len(cmd) < 2 {
+		return options, errors.New(constants.WrongArgsResponse)
+	}
+	value := cmd[1]
+	switch strings.ToLower(cmd[0]) {
+	case "id":
+		id, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return options, fmt.Errorf("invalid client id %s", value)
+		}
+		options.filters = append(options.filters, func(info internal.ConnectionInfo) bool {
+			return info.Id == id
+		})
+	case "addr":
+		options.filters = append(options.filters, func(info internal.ConnectionInfo) bool {
+			return info.Addr == value
+		})
+	case "laddr":
+		options.filters = append(options.filters, func(info internal.ConnectionInfo) bool {
+			return info.LocalAddr == value
+		})
+	case "user":
+		options.filters = append(options.filters, func(info internal.ConnectionInfo) bool {
+			return info.User == value
+		})
+	case "type":
+		if err := validClientType(value); err != nil {
+			return options, err
+		}
+		options.filters = append(options.filters, func(info internal.ConnectionInfo) bool {
+			return matchesClientType(info, value)
+		})
+	case "maxage":
+		seconds, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return options, errors.New("maxage must be an integer")
+		}
+		options.filters = append(options.filters, func(info internal.ConnectionInfo) bool {
+			return now.Sub(info.CreatedAt) >= time.Duration(seconds)*time.Second
+		})
+	case "skipme":
+		switch strings.ToLower(value) {
+		case "yes":
+			options.skipMe = true
+		case "no":
+			options.skipMe = false
+		default:
+			return options, errors.New("skipme must be yes or no")
+		}
+	default:
+		return options, fmt.Errorf("unknown filter %s", strings.ToUpper(cmd[0]))
+	}
+	return getClientKillOptions(cmd[2:], now, options)
+}
+
+func getClientTrackingOptions(cmd []string, options tracking.Options) (tracking.Options, error) {
+	if len(cmd) == 0 {
+		return options, nil
+	}
+	switch strings.ToLower(cmd[0]) {
+	case "redirect":
+		if len(cmd) < 2 {
+			return options, errors.New(constants.WrongArgsResponse)
+		}
+		id, err := strconv.ParseUint(cmd[1], 10, 64)
+		if err != nil {
+			return options, fmt.Errorf("invalid client id %s", cmd[1])
+		}
+		options.Redirect = id
+		return getClientTrackingOptions(cmd[2:], options)
+	case "prefix":
+		if len(cmd) < 2 {
+			return options, errors.New(constants.WrongArgsResponse)
+		}
+		options.Prefixes = append(options.Prefixes, cmd[1])
+		return getClientTrackingOptions(cmd[2:], options)
+	case "bcast":
+		options.BCast = true
+	case "optin":
+		options.OptIn = true
+	case "optout":
+		options.OptOut = true
+	case "noloop":
+		options.NoLoop = true
+	default:
+		return options, fmt.Errorf("unknown keyword %s", strings.ToUpper(cmd[0]))
+	}
+	return getClientTrackingOptions(cmd[1:], options)
+}
@@ -17,13 +17,13 @@ package generic
 import (
 	"errors"
 	"fmt"
-	"log"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/echovault/echovault/internal"
 	"github.com/echovault/echovault/internal/constants"
+	"github.com/echovault/echovault/internal/logger"
 )
 
 type KeyObject struct {
@@ -164,7 +164,7 @@ func handleDel(params internal.HandlerFuncParams) ([]byte, error) {
 		}
 		err = params.DeleteKey(params.Context, key)
 		if err != nil {
-			log.Printf("could not delete key %s due to error: %+v\n", key, err)
+			params.GetLogger().Error("delete key", "key", key, logger.ErrorKey, err)
 			continue
 		}
 		count += 1
@@ -712,6 +712,36 @@ func handleGetdel(params internal.HandlerFuncParams) ([]byte, error) {
 	return []byte(fmt.Sprintf("+%v\r\n", value)), nil
 }
 
+func handleObjectFreq(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := objectKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	freq, ok, err := params.GetObjectFreq(params.Context, keys.ReadKeys[0])
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return []byte("$-1\r\n"), nil
+	}
+	return []byte(fmt.Sprintf(":%d\r\n", freq)), nil
+}
+
+func handleObjectIdleTime(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := objectKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	idle, ok, err := params.GetObjectIdleTime(params.Context, keys.ReadKeys[0])
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return []byte("$-1\r\n"), nil
+	}
+	return []byte(fmt.Sprintf(":%d\r\n", int64(idle.Seconds()))), nil
+}
+
 func Commands() []internal.Command {
 	return []internal.Command{
 		{
@@ -729,15 +759,32 @@ PX - Expire the key after the specified number of milliseconds (positive integer
 EXAT - Expire at the exact time in unix seconds (positive integer).
 PXAT - Expire at the exat time in unix milliseconds (positive integer).`,
 			Sync:              true,
+			Arity:             -3,
+			Flags:             []string{constants.WriteFlag, constants.DenyOOMFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(1)",
+			Since:             "0.1.0",
 			KeyExtractionFunc: setKeyFunc,
 			HandlerFunc:       handleSet,
 		},
 		{
-			Command:           "mset",
-			Module:            constants.GenericModule,
-			Categories:        []string{constants.WriteCategory, constants.SlowCategory},
-			Description:       "(MSET key value [key value ...]) Automatically set or modify multiple key/value pairs.",
-			Sync:              true,
+			Command:     "mset",
+			Module:      constants.GenericModule,
+			Categories:  []string{constants.WriteCategory, constants.SlowCategory},
+			Description: "(MSET key value [key value ...]) Automatically set or modify multiple key/value pairs.",
+			Sync:        true,
+			Arity:       -3,
+			Flags:       []string{constants.WriteFlag, constants.DenyOOMFlag},
+			FirstKey:    1,
+			LastKey:     -1,
+			KeyStep:     2,
+			KeySpecs: []internal.KeySpec{
+				{Flags: []string{constants.KeySpecOW, constants.KeySpecUpdate}, BeginSearch: 1, LastKey: -1, KeyStep: 2},
+			},
+			Complexity:        "O(N) where N is the number of keys to set.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: msetKeyFunc,
 			HandlerFunc:       handleMSet,
 		},
@@ -747,6 +794,13 @@ PXAT - Expire at the exat time in unix milliseconds (positive integer).`,
 			Categories:        []string{constants.ReadCategory, constants.FastCategory},
 			Description:       "(GET key) Get the value at the specified key.",
 			Sync:              false,
+			Arity:             2,
+			Flags:             []string{constants.ReadOnlyFlag, constants.FastFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(1)",
+			Since:             "0.1.0",
 			KeyExtractionFunc: getKeyFunc,
 			HandlerFunc:       handleGet,
 		},
@@ -756,15 +810,32 @@ PXAT - Expire at the exat time in unix milliseconds (positive integer).`,
 			Categories:        []string{constants.ReadCategory, constants.FastCategory},
 			Description:       "(MGET key [key ...]) Get multiple values from the specified keys.",
 			Sync:              false,
+			Arity:             -2,
+			Flags:             []string{constants.ReadOnlyFlag, constants.FastFlag},
+			FirstKey:          1,
+			LastKey:           -1,
+			KeyStep:           1,
+			Complexity:        "O(N) where N is the number of keys to retrieve.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: mgetKeyFunc,
 			HandlerFunc:       handleMGet,
 		},
 		{
-			Command:           "del",
-			Module:            constants.GenericModule,
-			Categories:        []string{constants.KeyspaceCategory, constants.WriteCategory, constants.FastCategory},
-			Description:       "(DEL key [key ...]) Removes one or more keys from the store.",
-			Sync:              true,
+			Command:     "del",
+			Module:      constants.GenericModule,
+			Categories:  []string{constants.KeyspaceCategory, constants.WriteCategory, constants.FastCategory},
+			Description: "(DEL key [key ...]) Removes one or more keys from the store.",
+			Sync:        true,
+			Arity:       -2,
+			Flags:       []string{constants.WriteFlag, constants.FastFlag},
+			FirstKey:    1,
+			LastKey:     -1,
+			KeyStep:     1,
+			KeySpecs: []internal.KeySpec{
+				{Flags: []string{constants.KeySpecRM, constants.KeySpecDelete}, BeginSearch: 1, LastKey: -1, KeyStep: 1},
+			},
+			Complexity:        "O(N) where N is the number of keys that will be removed.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: delKeyFunc,
 			HandlerFunc:       handleDel,
 		},
@@ -775,6 +846,13 @@ PXAT - Expire at the exat time in unix milliseconds (positive integer).`,
 			Description: `(PERSIST key) Removes the TTl associated with a key,
 turning it from a volatile key to a persistent key.`,
 			Sync:              true,
+			Arity:             2,
+			Flags:             []string{constants.WriteFlag, constants.FastFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(1)",
+			Since:             "0.1.0",
 			KeyExtractionFunc: persistKeyFunc,
 			HandlerFunc:       handlePersist,
 		},
@@ -786,6 +864,13 @@ turning it from a volatile key to a persistent key.`,
 Return -1 if the key exists but has no associated expiry time.
 Returns -2 if the key does not exist.`,
 			Sync:              false,
+			Arity:             2,
+			Flags:             []string{constants.ReadOnlyFlag, constants.FastFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(1)",
+			Since:             "0.1.0",
 			KeyExtractionFunc: expireTimeKeyFunc,
 			HandlerFunc:       handleExpireTime,
 		},
@@ -797,6 +882,13 @@ Returns -2 if the key does not exist.`,
 Return -1 if the key exists but has no associated expiry time.
 Returns -2 if the key does not exist.`,
 			Sync:              false,
+			Arity:             2,
+			Flags:             []string{constants.ReadOnlyFlag, constants.FastFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(1)",
+			Since:             "0.1.0",
 			KeyExtractionFunc: expireTimeKeyFunc,
 			HandlerFunc:       handleExpireTime,
 		},
@@ -808,6 +900,13 @@ Returns -2 if the key does not exist.`,
 If the key exists but does not have an associated expiry time, -1 is returned.
 If the key does not exist, -2 is returned.`,
 			Sync:              false,
+			Arity:             2,
+			Flags:             []string{constants.ReadOnlyFlag, constants.FastFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(1)",
+			Since:             "0.1.0",
 			KeyExtractionFunc: ttlKeyFunc,
 			HandlerFunc:       handleTTL,
 		},
@@ -819,6 +918,13 @@ If the key does not exist, -2 is returned.`,
 If the key exists but does not have an associated expiry time, -1 is returned.
 If the key does not exist, -2 is returned.`,
 			Sync:              false,
+			Arity:             2,
+			Flags:             []string{constants.ReadOnlyFlag, constants.FastFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(1)",
+			Since:             "0.1.0",
 			KeyExtractionFunc: ttlKeyFunc,
 			HandlerFunc:       handleTTL,
 		},
@@ -833,6 +939,13 @@ XX - Only set the expiry time if the key already has an expiry time.
 GT - Only set the expiry time if the new expiry time is greater than the current one.
 LT - Only set the expiry time if the new expiry time is less than the current one.`,
 			Sync:              true,
+			Arity:             -3,
+			Flags:             []string{constants.WriteFlag, constants.FastFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(1)",
+			Since:             "0.1.0",
 			KeyExtractionFunc: expireKeyFunc,
 			HandlerFunc:       handleExpire,
 		},
@@ -847,6 +960,13 @@ XX - Only set the expiry time if the key already has an expiry time.
 GT - Only set the expiry time if the new expiry time is greater than the current one.
 LT - Only set the expiry time if the new expiry time is less than the current one.`,
 			Sync:              true,
+			Arity:             -3,
+			Flags:             []string{constants.WriteFlag, constants.FastFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(1)",
+			Since:             "0.1.0",
 			KeyExtractionFunc: expireKeyFunc,
 			HandlerFunc:       handleExpire,
 		},
@@ -862,6 +982,13 @@ XX - Only set the expiry time if the key already has an expiry time.
 GT - Only set the expiry time if the new expiry time is greater than the current one.
 LT - Only set the expiry time if the new expiry time is less than the current one.`,
 			Sync:              true,
+			Arity:             -3,
+			Flags:             []string{constants.WriteFlag, constants.FastFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(1)",
+			Since:             "0.1.0",
 			KeyExtractionFunc: expireAtKeyFunc,
 			HandlerFunc:       handleExpireAt,
 		},
@@ -877,6 +1004,13 @@ XX - Only set the expiry time if the key already has an expiry time.
 GT - Only set the expiry time if the new expiry time is greater than the current one.
 LT - Only set the expiry time if the new expiry time is less than the current one.`,
 			Sync:              true,
+			Arity:             -3,
+			Flags:             []string{constants.WriteFlag, constants.FastFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(1)",
+			Since:             "0.1.0",
 			KeyExtractionFunc: expireAtKeyFunc,
 			HandlerFunc:       handleExpireAt,
 		},
@@ -889,6 +1023,13 @@ Increments the number stored at key by one. If the key does not exist, it is set
 An error is returned if the key contains a value of the wrong type or contains a string that cannot be represented as integer.
 This operation is limited to 64 bit signed integers.`,
 			Sync:              true,
+			Arity:             2,
+			Flags:             []string{constants.WriteFlag, constants.DenyOOMFlag, constants.FastFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(1)",
+			Since:             "0.1.0",
 			KeyExtractionFunc: incrKeyFunc,
 			HandlerFunc:       handleIncr,
 		},
@@ -902,6 +1043,13 @@ If the key does not exist, it is set to 0 before performing the operation.
 An error is returned if the key contains a value of the wrong type or contains a string that cannot be represented as integer.
 This operation is limited to 64 bit signed integers.`,
 			Sync:              true,
+			Arity:             2,
+			Flags:             []string{constants.WriteFlag, constants.DenyOOMFlag, constants.FastFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(1)",
+			Since:             "0.1.0",
 			KeyExtractionFunc: decrKeyFunc,
 			HandlerFunc:       handleDecr,
 		},
@@ -913,6 +1061,13 @@ This operation is limited to 64 bit signed integers.`,
 Increments the number stored at key by increment. If the key does not exist, it is set to 0 before performing the operation.
 An error is returned if the key contains a value of the wrong type or contains a string that can not be represented as integer.`,
 			Sync:              true,
+			Arity:             3,
+			Flags:             []string{constants.WriteFlag, constants.DenyOOMFlag, constants.FastFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(1)",
+			Since:             "0.1.0",
 			KeyExtractionFunc: incrByKeyFunc,
 			HandlerFunc:       handleIncrBy,
 		},
@@ -924,6 +1079,13 @@ An error is returned if the key contains a value of the wrong type or contains a
 Increments the number stored at key by increment. If the key does not exist, it is set to 0 before performing the operation.
 An error is returned if the key contains a value of the wrong type or contains a string that cannot be represented as float.`,
 			Sync:              true,
+			Arity:             3,
+			Flags:             []string{constants.WriteFlag, constants.DenyOOMFlag, constants.FastFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(1)",
+			Since:             "0.1.0",
 			KeyExtractionFunc: incrByFloatKeyFunc,
 			HandlerFunc:       handleIncrByFloat,
 		},
@@ -936,6 +1098,13 @@ The DECRBY command reduces the value stored at the specified key by the specifie
 If the key does not exist, it is initialized with a value of 0 before performing the operation.
 If the key's value is not of the correct type or cannot be represented as an integer, an error is returned.`,
 			Sync:              true,
+			Arity:             3,
+			Flags:             []string{constants.WriteFlag, constants.DenyOOMFlag, constants.FastFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(1)",
+			Since:             "0.1.0",
 			KeyExtractionFunc: decrByKeyFunc,
 			HandlerFunc:       handleDecrBy,
 		},
@@ -945,7 +1114,18 @@ If the key's value is not of the correct type or cannot be represented as an int
 			Categories: []string{constants.KeyspaceCategory, constants.WriteCategory, constants.FastCategory},
 			Description: `(RENAME key newkey)
 Renames key to newkey. If newkey already exists, it is overwritten. If key does not exist, an error is returned.`,
-			Sync:              true,
+			Sync:     true,
+			Arity:    3,
+			Flags:    []string{constants.WriteFlag, constants.FastFlag},
+			FirstKey: 1,
+			LastKey:  2,
+			KeyStep:  1,
+			KeySpecs: []internal.KeySpec{
+				{Flags: []string{constants.KeySpecRW, constants.KeySpecAccess, constants.KeySpecDelete}, BeginSearch: 1, LastKey: 0, KeyStep: 1},
+				{Flags: []string{constants.KeySpecOW, constants.KeySpecUpdate}, BeginSearch: 2, LastKey: 0, KeyStep: 1},
+			},
+			Complexity:        "O(1)",
+			Since:             "0.1.0",
 			KeyExtractionFunc: renameKeyFunc,
 			HandlerFunc:       handleRename,
 		},
@@ -960,6 +1140,10 @@ Renames key to newkey. If newkey already exists, it is overwritten. If key does
 			},
 			Description: `(FLUSHALL) Delete all the keys in all the existing databases. This command is always synchronous.`,
 			Sync:        true,
+			Arity:       1,
+			Flags:       []string{constants.WriteFlag},
+			Complexity:  "O(N) where N is the total number of keys in all databases.",
+			Since:       "0.1.0",
 			KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
 				return internal.KeyExtractionFuncResult{
 					Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
@@ -978,7 +1162,11 @@ Renames key to newkey. If newkey already exists, it is overwritten. If key does
 			},
 			Description: `(FLUSHDB)
 Delete all the keys in the currently selected database. This command is always synchronous.`,
-			Sync: true,
+			Sync:       true,
+			Arity:      1,
+			Flags:      []string{constants.WriteFlag},
+			Complexity: "O(N) where N is the number of keys in the selected database.",
+			Since:      "0.1.0",
 			KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
 				return internal.KeyExtractionFuncResult{
 					Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
@@ -992,17 +1180,85 @@ Delete all the keys in the currently selected database. This command is always s
 			Categories:        []string{constants.KeyspaceCategory, constants.ReadCategory, constants.SlowCategory},
 			Description:       "(RANDOMKEY) Returns a random key from the current selected database.",
 			Sync:              false,
+			Arity:             1,
+			Flags:             []string{constants.ReadOnlyFlag},
+			Complexity:        "O(1)",
+			Since:             "0.1.0",
 			KeyExtractionFunc: randomKeyFunc,
 			HandlerFunc:       handleRandomkey,
 		},
 		{
-			Command:           "getdel",
-			Module:            constants.GenericModule,
-			Categories:        []string{constants.WriteCategory, constants.FastCategory},
-			Description:       "(GETDEL key) Get the value of key and delete the key. This command is similar to [GET], but deletes key on success.",
-			Sync:              true,
+			Command:     "getdel",
+			Module:      constants.GenericModule,
+			Categories:  []string{constants.WriteCategory, constants.FastCategory},
+			Description: "(GETDEL key) Get the value of key and delete the key. This command is similar to [GET], but deletes key on success.",
+			Sync:        true,
+			Arity:       2,
+			Flags:       []string{constants.WriteFlag, constants.FastFlag},
+			FirstKey:    1,
+			LastKey:     1,
+			KeyStep:     1,
+			KeySpecs: []internal.KeySpec{
+				{Flags: []string{constants.KeySpecRW, constants.KeySpecAccess, constants.KeySpecDelete}, BeginSearch: 1, LastKey: 0, KeyStep: 1},
+			},
+			Complexity:        "O(1)",
+			Since:             "0.1.0",
 			KeyExtractionFunc: getDelKeyFunc,
 			HandlerFunc:       handleGetdel,
 		},
+		{
+			Command:     "object",
+			Module:      constants.GenericModule,
+			Categories:  []string{},
+			Description: "Commands that inspect the internals of keys",
+			Sync:        false,
+			Arity:       -2,
+			Complexity:  "Depends on subcommand.",
+			Since:       "0.10.1",
+			KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+				return internal.KeyExtractionFuncResult{
+					Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+				}, nil
+			},
+			HandlerFunc: func(_ internal.HandlerFuncParams) ([]byte, error) {
+				return nil, errors.New("provide FREQ or IDLETIME subcommand")
+			},
+			SubCommands: []internal.SubCommand{
+				{
+					Command:    "freq",
+					Module:     constants.GenericModule,
+					Categories: []string{constants.KeyspaceCategory, constants.ReadCategory, constants.SlowCategory},
+					Description: `(OBJECT FREQ key) Returns the logarithmic access frequency counter of the key.
+Only available when the eviction policy is allkeys-lfu or volatile-lfu.`,
+					Sync:              false,
+					Arity:             3,
+					Flags:             []string{constants.ReadOnlyFlag},
+					FirstKey:          2,
+					LastKey:           2,
+					KeyStep:           1,
+					Complexity:        "O(1)",
+					Since:             "0.10.1",
+					KeyExtractionFunc: objectKeyFunc,
+					HandlerFunc:       handleObjectFreq,
+				},
+				{
+					Command:    "idletime",
+					Module:     constants.GenericModule,
+					Categories: []string{constants.KeyspaceCategory, constants.ReadCategory, constants.SlowCategory},
+					Description: `(OBJECT IDLETIME key) Returns the number of seconds since the key was last read or written.
+Not available when the eviction policy is allkeys-lfu or volatile-lfu.`,
+					Sync:              false,
+					Arity:             3,
+					Flags:             []string{constants.ReadOnlyFlag},
+					FirstKey:          2,
+					LastKey:           2,
+					KeyStep:           1,
+					Complexity:        "O(1)",
+					Since:             "0.10.1",
+					KeyExtractionFunc: objectKeyFunc,
+					HandlerFunc:       handleObjectIdleTime,
+				},
+			},
+		},
 	}
 }
@@ -2930,4 +2930,80 @@ func Test_Generic(t *testing.T) {
 		}
 	})
 
+	t.Run("Test_HandleOBJECT", func(t *testing.T) {
+		t.Parallel()
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		client := resp.NewConn(conn)
+
+		if err = client.WriteArray([]resp.Value{
+			resp.StringValue("SET"), resp.StringValue("ObjectKey1"), resp.StringValue("value1"),
+		}); err != nil {
+			t.Error(err)
+		}
+		if _, _, err = client.ReadValue(); err != nil {
+			t.Error(err)
+		}
+
+		tests := []struct {
+			name     string
+			command  []string
+			expected interface{}
+		}{
+			{
+				name:     "1. Return the idle time of the key",
+				command:  []string{"OBJECT", "IDLETIME", "ObjectKey1"},
+				expected: 0,
+			},
+			{
+				name:     "2. Return nil idle time for a non-existent key",
+				command:  []string{"OBJECT", "IDLETIME", "ObjectKey2"},
+				expected: nil,
+			},
+			{
+				name:     "3. Return error for the access frequency without an lfu eviction policy",
+				command:  []string{"OBJECT", "FREQ", "ObjectKey1"},
+				expected: errors.New("an lfu eviction policy is not selected"),
+			},
+			{
+				name:     "4. Return error when no OBJECT IDLETIME key is passed",
+				command:  []string{"OBJECT", "IDLETIME"},
+				expected: errors.New(constants.WrongArgsResponse),
+			},
+		}
+
+		for _, test := range tests {
+			command := make([]resp.Value, len(test.command))
+			for i, c := range test.command {
+				command[i] = resp.StringValue(c)
+			}
+			if err = client.WriteArray(command); err != nil {
+				t.Error(err)
+			}
+			res, _, err := client.ReadValue()
+			if err != nil {
+				t.Error(err)
+			}
+			switch expected := test.expected.(type) {
+			case error:
+				if res.Error() == nil || !strings.Contains(res.Error().Error(), expected.Error()) {
+					t.Errorf("%s: expected error \"%s\", got %+v", test.name, expected.Error(), res)
+				}
+			case nil:
+				if !res.IsNull() {
+					t.Errorf("%s: expected nil, got %+v", test.name, res)
+				}
+			default:
+				if res.Integer() != expected {
+					t.Errorf("%s: expected %v, got %+v", test.name, expected, res)
+				}
+			}
+		}
+	})
 }
@@ -44,10 +44,11 @@ func handleHSET(params internal.HandlerFuncParams) ([]byte, error) {
 	}
 
 	if !keyExists {
-		if err != nil {
-			return nil, err
+		var growth int64
+		for field, value := range entries {
+			growth += internal.EstimateFieldSize(field, value)
 		}
-		if err = params.SetValues(params.Context, map[string]interface{}{key: entries}); err != nil {
+		if err = params.UpdateValues(params.Context, map[string]interface{}{key: entries}, map[string]int64{key: growth}); err != nil {
 			return nil, err
 		}
 		return []byte(fmt.Sprintf(":%d\r\n", len(entries))), nil
@@ -63,12 +64,14 @@ func handleHSET(params internal.HandlerFuncParams) ([]byte, error) {
 	}
 
 	count := 0
+	var growth int64
 	switch strings.ToLower(params.Command[0]) {
 	case "hsetnx":
 		// Handle HSETNX
-		for field, _ := range entries {
+		for field, value := range entries {
 			if hash[field] == nil {
 				count += 1
+				growth += internal.EstimateFieldSize(field, value)
 			}
 		}
 		for field, value := range hash {
@@ -76,6 +79,12 @@ func handleHSET(params internal.HandlerFuncParams) ([]byte, error) {
 		}
 	default:
 		// Handle HSET
+		for field, value := range entries {
+			growth += internal.EstimateFieldSize(field, value)
+			if hash[field] != nil {
+				growth -= internal.EstimateFieldSize(field, hash[field])
+			}
+		}
 		for field, value := range hash {
 			if entries[field] == nil {
 				entries[field] = value
@@ -84,7 +93,7 @@ func handleHSET(params internal.HandlerFuncParams) ([]byte, error) {
 		count = len(entries)
 	}
 
-	if err = params.SetValues(params.Context, map[string]interface{}{key: entries}); err != nil {
+	if err = params.UpdateValues(params.Context, map[string]interface{}{key: entries}, map[string]int64{key: growth}); err != nil {
 		return nil, err
 	}
 
@@ -412,13 +421,15 @@ func handleHINCRBY(params internal.HandlerFuncParams) ([]byte, error) {
 		hash := make(map[string]interface{})
 		if strings.EqualFold(params.Command[0], "hincrbyfloat") {
 			hash[field] = floatIncrement
-			if err = params.SetValues(params.Context, map[string]interface{}{key: hash}); err != nil {
+			growth := map[string]int64{key: internal.EstimateFieldSize(field, hash[field])}
+			if err = params.UpdateValues(params.Context, map[string]interface{}{key: hash}, growth); err != nil {
 				return nil, err
 			}
 			return []byte(fmt.Sprintf("+%s\r\n", strconv.FormatFloat(floatIncrement, 'f', -1, 64))), nil
 		} else {
 			hash[field] = intIncrement
-			if err = params.SetValues(params.Context, map[string]interface{}{key: hash}); err != nil {
+			growth := map[string]int64{key: internal.EstimateFieldSize(field, hash[field])}
+			if err = params.UpdateValues(params.Context, map[string]interface{}{key: hash}, growth); err != nil {
 				return nil, err
 			}
 			return []byte(fmt.Sprintf(":%d\r\n", intIncrement)), nil
@@ -430,8 +441,10 @@ func handleHINCRBY(params internal.HandlerFuncParams) ([]byte, error) {
 		return nil, fmt.Errorf("value at %s is not a hash", key)
 	}
 
+	var growth int64
 	if hash[field] == nil {
 		hash[field] = 0
+		growth = internal.EstimateFieldSize(field, hash[field])
 	}
 
 	switch hash[field].(type) {
@@ -453,7 +466,7 @@ func handleHINCRBY(params internal.HandlerFuncParams) ([]byte, error) {
 		}
 	}
 
-	if err = params.SetValues(params.Context, map[string]interface{}{key: hash}); err != nil {
+	if err = params.UpdateValues(params.Context, map[string]interface{}{key: hash}, map[string]int64{key: growth}); err != nil {
 		return nil, err
 	}
 
@@ -547,15 +560,17 @@ func handleHDEL(params internal.HandlerFuncParams) ([]byte, error) {
 	}
 
 	count := 0
+	var growth int64
 
 	for _, field := range fields {
 		if hash[field] != nil {
+			growth -= internal.EstimateFieldSize(field, hash[field])
 			delete(hash, field)
 			count += 1
 		}
 	}
 
-	if err = params.SetValues(params.Context, map[string]interface{}{key: hash}); err != nil {
+	if err = params.UpdateValues(params.Context, map[string]interface{}{key: hash}, map[string]int64{key: growth}); err != nil {
 		return nil, err
 	}
 
@@ -571,6 +586,13 @@ func Commands() []internal.Command {
 			Description: `(HSET key field value [field value ...]) 
 Set update each field of the hash with the corresponding value.`,
 			Sync:              true,
+			Arity:             -4,
+			Flags:             []string{constants.WriteFlag, constants.DenyOOMFlag, constants.FastFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(1) for each field/value pair added, so O(N) to add N field/value pairs.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: hsetKeyFunc,
 			HandlerFunc:       handleHSET,
 		},
@@ -580,7 +602,17 @@ Set update each field of the hash with the corresponding value.`,
 			Categories: []string{constants.HashCategory, constants.WriteCategory, constants.FastCategory},
 			Description: `(HSETNX key field value [field value ...]) 
 Set hash field value only if the field does not exist.`,
-			Sync:              true,
+			Sync:     true,
+			Arity:    -4,
+			Flags:    []string{constants.WriteFlag, constants.DenyOOMFlag, constants.FastFlag},
+			FirstKey: 1,
+			LastKey:  1,
+			KeyStep:  1,
+			KeySpecs: []internal.KeySpec{
+				{Flags: []string{constants.KeySpecRW, constants.KeySpecInsert}, BeginSearch: 1, LastKey: 0, KeyStep: 1},
+			},
+			Complexity:        "O(1) for each field/value pair added, so O(N) to add N field/value pairs.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: hsetnxKeyFunc,
 			HandlerFunc:       handleHSET,
 		},
@@ -591,6 +623,13 @@ Set hash field value only if the field does not exist.`,
 			Description: `(HGET key field [field ...]) 
 Retrieve the value of each of the listed fields from the hash.`,
 			Sync:              false,
+			Arity:             -3,
+			Flags:             []string{constants.ReadOnlyFlag, constants.FastFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(N) where N is the number of fields requested.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: hgetKeyFunc,
 			HandlerFunc:       handleHGET,
 		},
@@ -601,6 +640,13 @@ Retrieve the value of each of the listed fields from the hash.`,
 			Description: `(HSTRLEN key field [field ...]) 
 Return the string length of the values stored at the specified fields. 0 if the value does not exist.`,
 			Sync:              false,
+			Arity:             -3,
+			Flags:             []string{constants.ReadOnlyFlag, constants.FastFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(N) where N is the number of fields requested.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: hstrlenKeyFunc,
 			HandlerFunc:       handleHSTRLEN,
 		},
@@ -610,6 +656,13 @@ Return the string length of the values stored at the specified fields. 0 if the
 			Categories:        []string{constants.HashCategory, constants.ReadCategory, constants.SlowCategory},
 			Description:       `(HVALS key) Returns all the values of the hash at key.`,
 			Sync:              false,
+			Arity:             2,
+			Flags:             []string{constants.ReadOnlyFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(N) where N is the size of the hash.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: hvalsKeyFunc,
 			HandlerFunc:       handleHVALS,
 		},
@@ -619,6 +672,13 @@ Return the string length of the values stored at the specified fields. 0 if the
 			Categories:        []string{constants.HashCategory, constants.ReadCategory, constants.SlowCategory},
 			Description:       `(HRANDFIELD key [count [WITHVALUES]]) Returns one or more random fields from the hash.`,
 			Sync:              false,
+			Arity:             -2,
+			Flags:             []string{constants.ReadOnlyFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(N) where N is the number of fields returned.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: hrandfieldKeyFunc,
 			HandlerFunc:       handleHRANDFIELD,
 		},
@@ -628,6 +688,13 @@ Return the string length of the values stored at the specified fields. 0 if the
 			Categories:        []string{constants.HashCategory, constants.ReadCategory, constants.FastCategory},
 			Description:       `(HLEN key) Returns the number of fields in the hash.`,
 			Sync:              false,
+			Arity:             2,
+			Flags:             []string{constants.ReadOnlyFlag, constants.FastFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(1)",
+			Since:             "0.1.0",
 			KeyExtractionFunc: hlenKeyFunc,
 			HandlerFunc:       handleHLEN,
 		},
@@ -637,6 +704,13 @@ Return the string length of the values stored at the specified fields. 0 if the
 			Categories:        []string{constants.HashCategory, constants.ReadCategory, constants.SlowCategory},
 			Description:       `(HKEYS key) Returns all the fields in a hash.`,
 			Sync:              false,
+			Arity:             2,
+			Flags:             []string{constants.ReadOnlyFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(N) where N is the size of the hash.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: hkeysKeyFunc,
 			HandlerFunc:       handleHKEYS,
 		},
@@ -646,6 +720,13 @@ Return the string length of the values stored at the specified fields. 0 if the
 			Categories:        []string{constants.HashCategory, constants.WriteCategory, constants.FastCategory},
 			Description:       `(HINCRBYFLOAT key field increment) Increment the hash value by the float increment.`,
 			Sync:              true,
+			Arity:             4,
+			Flags:             []string{constants.WriteFlag, constants.DenyOOMFlag, constants.FastFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(1)",
+			Since:             "0.1.0",
 			KeyExtractionFunc: hincrbyKeyFunc,
 			HandlerFunc:       handleHINCRBY,
 		},
@@ -655,6 +736,13 @@ Return the string length of the values stored at the specified fields. 0 if the
 			Categories:        []string{constants.HashCategory, constants.WriteCategory, constants.FastCategory},
 			Description:       `(HINCRBY key field increment) Increment the hash value by the integer increment`,
 			Sync:              true,
+			Arity:             4,
+			Flags:             []string{constants.WriteFlag, constants.DenyOOMFlag, constants.FastFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(1)",
+			Since:             "0.1.0",
 			KeyExtractionFunc: hincrbyKeyFunc,
 			HandlerFunc:       handleHINCRBY,
 		},
@@ -664,6 +752,13 @@ Return the string length of the values stored at the specified fields. 0 if the
 			Categories:        []string{constants.HashCategory, constants.ReadCategory, constants.SlowCategory},
 			Description:       `(HGETALL key) Get all fields and values of a hash.`,
 			Sync:              false,
+			Arity:             2,
+			Flags:             []string{constants.ReadOnlyFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(N) where N is the size of the hash.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: hgetallKeyFunc,
 			HandlerFunc:       handleHGETALL,
 		},
@@ -673,15 +768,32 @@ Return the string length of the values stored at the specified fields. 0 if the
 			Categories:        []string{constants.HashCategory, constants.ReadCategory, constants.FastCategory},
 			Description:       `(HEXISTS key field) Returns if field is an existing field in the hash.`,
 			Sync:              false,
+			Arity:             3,
+			Flags:             []string{constants.ReadOnlyFlag, constants.FastFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(1)",
+			Since:             "0.1.0",
 			KeyExtractionFunc: hexistsKeyFunc,
 			HandlerFunc:       handleHEXISTS,
 		},
 		{
-			Command:           "hdel",
-			Module:            constants.HashModule,
-			Categories:        []string{constants.HashCategory, constants.WriteCategory, constants.FastCategory},
-			Description:       `(HDEL key field [field ...]) Deletes the specified fields from the hash.`,
-			Sync:              true,
+			Command:     "hdel",
+			Module:      constants.HashModule,
+			Categories:  []string{constants.HashCategory, constants.WriteCategory, constants.FastCategory},
+			Description: `(HDEL key field [field ...]) Deletes the specified fields from the hash.`,
+			Sync:        true,
+			Arity:       -3,
+			Flags:       []string{constants.WriteFlag, constants.FastFlag},
+			FirstKey:    1,
+			LastKey:     1,
+			KeyStep:     1,
+			KeySpecs: []internal.KeySpec{
+				{Flags: []string{constants.KeySpecRW, constants.KeySpecDelete}, BeginSearch: 1, LastKey: 0, KeyStep: 1},
+			},
+			Complexity:        "O(N) where N is the number of fields to be removed.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: hdelKeyFunc,
 			HandlerFunc:       handleHDEL,
 		},
@@ -160,8 +160,9 @@ func handleLSet(params internal.HandlerFuncParams) ([]byte, error) {
 		return nil, errors.New("index must be within list range")
 	}
 
+	growth := internal.EstimateMemberSize(params.Command[3]) - internal.EstimateMemberSize(list[index])
 	list[index] = params.Command[3]
-	if err = params.SetValues(params.Context, map[string]interface{}{key: list}); err != nil {
+	if err = params.UpdateValues(params.Context, map[string]interface{}{key: list}, map[string]int64{key: growth}); err != nil {
 		return nil, err
 	}
 
@@ -219,7 +220,15 @@ func handleLTrim(params internal.HandlerFuncParams) ([]byte, error) {
 		end += 1
 	}
 
-	if err = params.SetValues(params.Context, map[string]interface{}{key: list[start:end]}); err != nil {
+	var growth int64
+	for _, elem := range list[:start] {
+		growth -= internal.EstimateMemberSize(elem)
+	}
+	for _, elem := range list[end:] {
+		growth -= internal.EstimateMemberSize(elem)
+	}
+
+	if err = params.UpdateValues(params.Context, map[string]interface{}{key: list[start:end]}, map[string]int64{key: growth}); err != nil {
 		return nil, err
 	}
 
@@ -287,7 +296,9 @@ func handleLRem(params internal.HandlerFuncParams) ([]byte, error) {
 		}
 	}
 
-	if err = params.SetValues(params.Context, map[string]interface{}{key: list}); err != nil {
+	// Only the elements equal to the value are removed.
+	growth := -int64(removedCount-len(list)) * internal.EstimateMemberSize(value)
+	if err = params.UpdateValues(params.Context, map[string]interface{}{key: list}, map[string]int64{key: growth}); err != nil {
 		return nil, err
 	}
 
@@ -322,9 +333,18 @@ func handleLMove(params internal.HandlerFuncParams) ([]byte, error) {
 		return nil, errors.New("both source and destination must be lists")
 	}
 
+	// The moved element leaves the source and joins the destination, which may be the same list.
+	moved := sourceList[0]
+	if whereFrom == "right" {
+		moved = sourceList[len(sourceList)-1]
+	}
+	growth := map[string]int64{source: 0, destination: 0}
+	growth[source] -= internal.EstimateMemberSize(moved)
+	growth[destination] += internal.EstimateMemberSize(moved)
+
 	switch whereFrom {
 	case "left":
-		err = params.SetValues(params.Context, map[string]interface{}{
+		err = params.UpdateValues(params.Context, map[string]interface{}{
 			source: append([]string{}, sourceList[1:]...),
 			destination: func() []string {
 				if whereTo == "left" {
@@ -333,9 +353,9 @@ func handleLMove(params internal.HandlerFuncParams) ([]byte, error) {
 				// whereTo == "right"
 				return append(destinationList, sourceList[0])
 			}(),
-		})
+		}, growth)
 	case "right":
-		err = params.SetValues(params.Context, map[string]interface{}{
+		err = params.UpdateValues(params.Context, map[string]interface{}{
 			source: append([]string{}, sourceList[:len(sourceList)-1]...),
 			destination: func() []string {
 				if whereTo == "left" {
@@ -344,7 +364,7 @@ func handleLMove(params internal.HandlerFuncParams) ([]byte, error) {
 				// whereTo == "right"
 				return append(destinationList, sourceList[len(sourceList)-1])
 			}(),
-		})
+		}, growth)
 	}
 
 	if err != nil {
@@ -386,7 +406,12 @@ func handleLPush(params internal.HandlerFuncParams) ([]byte, error) {
 		return nil, errors.New("LPUSH command on non-list item")
 	}
 
-	if err = params.SetValues(params.Context, map[string]interface{}{key: append(newElems, l...)}); err != nil {
+	growth := map[string]int64{key: 0}
+	for _, elem := range newElems {
+		growth[key] += internal.EstimateMemberSize(elem)
+	}
+
+	if err = params.UpdateValues(params.Context, map[string]interface{}{key: append(newElems, l...)}, growth); err != nil {
 		return nil, err
 	}
 
@@ -425,7 +450,12 @@ func handleRPush(params internal.HandlerFuncParams) ([]byte, error) {
 		return nil, errors.New("RPUSH command on non-list item")
 	}
 
-	if err = params.SetValues(params.Context, map[string]interface{}{key: append(l, newElems...)}); err != nil {
+	growth := map[string]int64{key: 0}
+	for _, elem := range newElems {
+		growth[key] += internal.EstimateMemberSize(elem)
+	}
+
+	if err = params.UpdateValues(params.Context, map[string]interface{}{key: append(l, newElems...)}, growth); err != nil {
 		return nil, err
 	}
 	return []byte(fmt.Sprintf(":%d\r\n", len(l)+len(newElems))), nil
@@ -471,6 +501,7 @@ func handlePop(params internal.HandlerFuncParams) ([]byte, error) {
 	}
 
 	var popped []string
+	var growth int64
 	for i := 0; i < count; i++ {
 		if strings.EqualFold(params.Command[0], "lpop") {
 			// Pop from the left
@@ -481,8 +512,9 @@ func handlePop(params internal.HandlerFuncParams) ([]byte, error) {
 			popped = append(popped, list[len(list)-1])
 			list = list[:len(list)-1]
 		}
+		growth -= internal.EstimateMemberSize(popped[i])
 	}
-	if err = params.SetValues(params.Context, map[string]interface{}{key: list}); err != nil {
+	if err = params.UpdateValues(params.Context, map[string]interface{}{key: list}, map[string]int64{key: growth}); err != nil {
 		return nil, err
 	}
 
@@ -506,7 +538,17 @@ func Commands() []internal.Command {
 			Categories: []string{constants.ListCategory, constants.WriteCategory, constants.FastCategory},
 			Description: `(LPUSH key element [element ...]) 
 Prepends one or more values to the beginning of a list, creates the list if it does not exist.`,
-			Sync:              true,
+			Sync:     true,
+			Arity:    -3,
+			Flags:    []string{constants.WriteFlag, constants.DenyOOMFlag, constants.FastFlag},
+			FirstKey: 1,
+			LastKey:  1,
+			KeyStep:  1,
+			KeySpecs: []internal.KeySpec{
+				{Flags: []string{constants.KeySpecRW, constants.KeySpecInsert}, BeginSearch: 1, LastKey: 0, KeyStep: 1},
+			},
+			Complexity:        "O(1) for each element added, so O(N) to add N elements.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: lpushKeyFunc,
 			HandlerFunc:       handleLPush,
 		},
@@ -516,7 +558,17 @@ Prepends one or more values to the beginning of a list, creates the list if it d
 			Categories: []string{constants.ListCategory, constants.WriteCategory, constants.FastCategory},
 			Description: `(LPUSHX key element [element ...]) 
 Prepends a value to the beginning of a list only if the list exists.`,
-			Sync:              true,
+			Sync:     true,
+			Arity:    -3,
+			Flags:    []string{constants.WriteFlag, constants.DenyOOMFlag, constants.FastFlag},
+			FirstKey: 1,
+			LastKey:  1,
+			KeyStep:  1,
+			KeySpecs: []internal.KeySpec{
+				{Flags: []string{constants.KeySpecRW, constants.KeySpecInsert}, BeginSearch: 1, LastKey: 0, KeyStep: 1},
+			},
+			Complexity:        "O(1) for each element added, so O(N) to add N elements.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: lpushKeyFunc,
 			HandlerFunc:       handleLPush,
 		},
@@ -528,7 +580,17 @@ Prepends a value to the beginning of a list only if the list exists.`,
 Removes count elements from the beginning of the list and returns an array of the elements removed.
 Returns a bulk string of the first element when called without count.
 Returns an array of n elements from the beginning of the list when called with a count when n=count. `,
-			Sync:              true,
+			Sync:     true,
+			Arity:    -2,
+			Flags:    []string{constants.WriteFlag, constants.FastFlag},
+			FirstKey: 1,
+			LastKey:  1,
+			KeyStep:  1,
+			KeySpecs: []internal.KeySpec{
+				{Flags: []string{constants.KeySpecRW, constants.KeySpecAccess, constants.KeySpecDelete}, BeginSearch: 1, LastKey: 0, KeyStep: 1},
+			},
+			Complexity:        "O(N) where N is the number of elements returned.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: popKeyFunc,
 			HandlerFunc:       handlePop,
 		},
@@ -538,6 +600,13 @@ Returns an array of n elements from the beginning of the list when called with a
 			Categories:        []string{constants.ListCategory, constants.ReadCategory, constants.FastCategory},
 			Description:       "(LLEN key) Return the length of a list.",
 			Sync:              false,
+			Arity:             2,
+			Flags:             []string{constants.ReadOnlyFlag, constants.FastFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(1)",
+			Since:             "0.1.0",
 			KeyExtractionFunc: llenKeyFunc,
 			HandlerFunc:       handleLLen,
 		},
@@ -547,6 +616,13 @@ Returns an array of n elements from the beginning of the list when called with a
 			Categories:        []string{constants.ListCategory, constants.ReadCategory, constants.SlowCategory},
 			Description:       "(LRANGE key start end) Return a range of elements between the given indices.",
 			Sync:              false,
+			Arity:             4,
+			Flags:             []string{constants.ReadOnlyFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(S+N) where S is the distance of start offset from the head and N is the number of elements in the range.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: lrangeKeyFunc,
 			HandlerFunc:       handleLRange,
 		},
@@ -556,6 +632,13 @@ Returns an array of n elements from the beginning of the list when called with a
 			Categories:        []string{constants.ListCategory, constants.ReadCategory, constants.FastCategory},
 			Description:       "(LINDEX key index) Gets list element by index.",
 			Sync:              false,
+			Arity:             3,
+			Flags:             []string{constants.ReadOnlyFlag, constants.FastFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(N) where N is the number of elements to traverse to get to the element at index.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: lindexKeyFunc,
 			HandlerFunc:       handleLIndex,
 		},
@@ -565,6 +648,13 @@ Returns an array of n elements from the beginning of the list when called with a
 			Categories:        []string{constants.ListCategory, constants.WriteCategory, constants.FastCategory},
 			Description:       "(LSET key index element) Sets the value of an element in a list by its index.",
 			Sync:              true,
+			Arity:             4,
+			Flags:             []string{constants.WriteFlag, constants.DenyOOMFlag, constants.FastFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(N) where N is the length of the list.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: lsetKeyFunc,
 			HandlerFunc:       handleLSet,
 		},
@@ -574,15 +664,32 @@ Returns an array of n elements from the beginning of the list when called with a
 			Categories:        []string{constants.ListCategory, constants.WriteCategory, constants.SlowCategory},
 			Description:       "(LTRIM key start end) Trims a list using the specified range.",
 			Sync:              true,
+			Arity:             4,
+			Flags:             []string{constants.WriteFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(N) where N is the number of elements to be removed by the operation.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: ltrimKeyFunc,
 			HandlerFunc:       handleLTrim,
 		},
 		{
-			Command:           "lrem",
-			Module:            constants.ListModule,
-			Categories:        []string{constants.ListCategory, constants.WriteCategory, constants.SlowCategory},
-			Description:       "(LREM key count element) Remove <count> elements from list.",
-			Sync:              true,
+			Command:     "lrem",
+			Module:      constants.ListModule,
+			Categories:  []string{constants.ListCategory, constants.WriteCategory, constants.SlowCategory},
+			Description: "(LREM key count element) Remove <count> elements from list.",
+			Sync:        true,
+			Arity:       4,
+			Flags:       []string{constants.WriteFlag},
+			FirstKey:    1,
+			LastKey:     1,
+			KeyStep:     1,
+			KeySpecs: []internal.KeySpec{
+				{Flags: []string{constants.KeySpecRW, constants.KeySpecDelete}, BeginSearch: 1, LastKey: 0, KeyStep: 1},
+			},
+			Complexity:        "O(N+M) where N is the length of the list and M is the number of elements removed.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: lremKeyFunc,
 			HandlerFunc:       handleLRem,
 		},
@@ -592,7 +699,18 @@ Returns an array of n elements from the beginning of the list when called with a
 			Categories: []string{constants.ListCategory, constants.WriteCategory, constants.SlowCategory},
 			Description: `(LMOVE source destination <LEFT | RIGHT> <LEFT | RIGHT>) 
 Move element from one list to the other specifying left/right for both lists.`,
-			Sync:              true,
+			Sync:     true,
+			Arity:    5,
+			Flags:    []string{constants.WriteFlag, constants.DenyOOMFlag},
+			FirstKey: 1,
+			LastKey:  2,
+			KeyStep:  1,
+			KeySpecs: []internal.KeySpec{
+				{Flags: []string{constants.KeySpecRW, constants.KeySpecAccess, constants.KeySpecDelete}, BeginSearch: 1, LastKey: 0, KeyStep: 1},
+				{Flags: []string{constants.KeySpecRW, constants.KeySpecInsert}, BeginSearch: 2, LastKey: 0, KeyStep: 1},
+			},
+			Complexity:        "O(1)",
+			Since:             "0.1.0",
 			KeyExtractionFunc: lmoveKeyFunc,
 			HandlerFunc:       handleLMove,
 		},
@@ -604,25 +722,55 @@ Move element from one list to the other specifying left/right for both lists.`,
 Removes count elements from the end of the list and returns an array of the elements removed.
 Returns a bulk string of the last element when called without count.
 Returns an array of n elements from the end of the list when called with a count when n=count.`,
-			Sync:              true,
+			Sync:     true,
+			Arity:    -2,
+			Flags:    []string{constants.WriteFlag, constants.FastFlag},
+			FirstKey: 1,
+			LastKey:  1,
+			KeyStep:  1,
+			KeySpecs: []internal.KeySpec{
+				{Flags: []string{constants.KeySpecRW, constants.KeySpecAccess, constants.KeySpecDelete}, BeginSearch: 1, LastKey: 0, KeyStep: 1},
+			},
+			Complexity:        "O(N) where N is the number of elements returned.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: popKeyFunc,
 			HandlerFunc:       handlePop,
 		},
 		{
-			Command:           "rpush",
-			Module:            constants.ListModule,
-			Categories:        []string{constants.ListCategory, constants.WriteCategory, constants.FastCategory},
-			Description:       "(RPUSH key element [element ...]) Appends one or multiple elements to the end of a list.",
-			Sync:              true,
+			Command:     "rpush",
+			Module:      constants.ListModule,
+			Categories:  []string{constants.ListCategory, constants.WriteCategory, constants.FastCategory},
+			Description: "(RPUSH key element [element ...]) Appends one or multiple elements to the end of a list.",
+			Sync:        true,
+			Arity:       -3,
+			Flags:       []string{constants.WriteFlag, constants.DenyOOMFlag, constants.FastFlag},
+			FirstKey:    1,
+			LastKey:     1,
+			KeyStep:     1,
+			KeySpecs: []internal.KeySpec{
+				{Flags: []string{constants.KeySpecRW, constants.KeySpecInsert}, BeginSearch: 1, LastKey: 0, KeyStep: 1},
+			},
+			Complexity:        "O(1) for each element added, so O(N) to add N elements.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: rpushKeyFunc,
 			HandlerFunc:       handleRPush,
 		},
 		{
-			Command:           "rpushx",
-			Module:            constants.ListModule,
-			Categories:        []string{constants.ListCategory, constants.WriteCategory, constants.FastCategory},
-			Description:       "(RPUSHX key element [element ...]) Appends an element to the end of a list, only if the list exists.",
-			Sync:              true,
+			Command:     "rpushx",
+			Module:      constants.ListModule,
+			Categories:  []string{constants.ListCategory, constants.WriteCategory, constants.FastCategory},
+			Description: "(RPUSHX key element [element ...]) Appends an element to the end of a list, only if the list exists.",
+			Sync:        true,
+			Arity:       -3,
+			Flags:       []string{constants.WriteFlag, constants.DenyOOMFlag, constants.FastFlag},
+			FirstKey:    1,
+			LastKey:     1,
+			KeyStep:     1,
+			KeySpecs: []internal.KeySpec{
+				{Flags: []string{constants.KeySpecRW, constants.KeySpecInsert}, BeginSearch: 1, LastKey: 0, KeyStep: 1},
+			},
+			Complexity:        "O(1) for each element added, so O(N) to add N elements.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: rpushKeyFunc,
 			HandlerFunc:       handleRPush,
 		},
@@ -15,9 +15,9 @@
 package pubsub
 
 import (
+	"github.com/echovault/echovault/internal/logger"
 	"github.com/gobwas/glob"
 	"github.com/tidwall/resp"
-	"log"
 	"net"
 	"sync"
 )
@@ -28,6 +28,7 @@ type Channel struct {
 	subscribersRWMut sync.RWMutex             // RWMutex to concurrency control when accessing channel subscribers.
 	subscribers      map[*net.Conn]*resp.Conn // Map containing the channel subscribers.
 	messageChan      *chan string             // Messages published to this channel will be sent to this channel.
+	logger           logger.Logger            // Logger for the errors of writing messages to the subscribers.
 }
 
 // WithName option sets the channels name.
@@ -45,6 +46,13 @@ func WithPattern(pattern string) func(channel *Channel) {
 	}
 }
 
+// WithLogger option sets the logger the errors of writing messages to the subscribers are written to.
+func WithLogger(logger logger.Logger) func(channel *Channel) {
+	return func(channel *Channel) {
+		channel.logger = logger
+	}
+}
+
 func NewChannel(options ...func(channel *Channel)) *Channel {
 	messageChan := make(chan string, 4096)
 
@@ -54,6 +62,7 @@ func NewChannel(options ...func(channel *Channel)) *Channel {
 		subscribersRWMut: sync.RWMutex{},
 		subscribers:      make(map[*net.Conn]*resp.Conn),
 		messageChan:      &messageChan,
+		logger:           logger.Default(),
 	}
 
 	for _, option := range options {
@@ -77,7 +86,7 @@ func (ch *Channel) Start() {
 						resp.StringValue(ch.name),
 						resp.StringValue(message),
 					}); err != nil {
-						log.Println(err)
+						ch.logger.Debug("write message to subscriber", "channel", ch.name, logger.ErrorKey, err)
 					}
 				}(conn)
 			}
@@ -137,6 +146,14 @@ func (ch *Channel) NumSubs() int {
 	return n
 }
 
+// IsSubscribed returns true when the connection is subscribed to the channel.
+func (ch *Channel) IsSubscribed(conn *net.Conn) bool {
+	ch.subscribersRWMut.RLock()
+	defer ch.subscribersRWMut.RUnlock()
+	_, ok := ch.subscribers[conn]
+	return ok
+}
+
 func (ch *Channel) Subscribers() map[*net.Conn]*resp.Conn {
 	ch.subscribersRWMut.RLock()
 	defer ch.subscribersRWMut.RUnlock()
@@ -108,6 +108,10 @@ func Commands() []internal.Command {
 			Categories:  []string{constants.PubSubCategory, constants.ConnectionCategory, constants.SlowCategory},
 			Description: "(SUBSCRIBE channel [channel ...]) Subscribe to one or more channels.",
 			Sync:        false,
+			Arity:       -2,
+			Flags:       []string{constants.PubSubFlag, constants.NoScriptFlag, constants.LoadingFlag, constants.StaleFlag},
+			Complexity:  "O(N) where N is the number of channels to subscribe to.",
+			Since:       "0.1.0",
 			KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
 				// Treat the channels as keys
 				if len(cmd) < 2 {
@@ -127,6 +131,10 @@ func Commands() []internal.Command {
 			Categories:  []string{constants.PubSubCategory, constants.ConnectionCategory, constants.SlowCategory},
 			Description: "(PSUBSCRIBE pattern [pattern ...]) Subscribe to one or more glob patterns.",
 			Sync:        false,
+			Arity:       -2,
+			Flags:       []string{constants.PubSubFlag, constants.NoScriptFlag, constants.LoadingFlag, constants.StaleFlag},
+			Complexity:  "O(N) where N is the number of patterns to subscribe to.",
+			Since:       "0.1.0",
 			KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
 				// Treat the patterns as keys
 				if len(cmd) < 2 {
@@ -146,6 +154,10 @@ func Commands() []internal.Command {
 			Categories:  []string{constants.PubSubCategory, constants.FastCategory},
 			Description: "(PUBLISH channel message) Publish a message to the specified channel.",
 			Sync:        true,
+			Arity:       3,
+			Flags:       []string{constants.PubSubFlag, constants.LoadingFlag, constants.StaleFlag, constants.FastFlag, constants.MayReplicateFlag},
+			Complexity:  "O(N+M) where N is the number of clients subscribed to the receiving channel and M is the total number of subscribed patterns.",
+			Since:       "0.1.0",
 			KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
 				// Treat the channel as a key
 				if len(cmd) != 3 {
@@ -166,7 +178,11 @@ func Commands() []internal.Command {
 			Description: `(UNSUBSCRIBE [channel [channel ...]]) Unsubscribe from a list of channels.
 If the channel list is not provided, then the connection will be unsubscribed from all the channels that
 it's currently subscribe to.`,
-			Sync: false,
+			Sync:       false,
+			Arity:      -1,
+			Flags:      []string{constants.PubSubFlag, constants.NoScriptFlag, constants.LoadingFlag, constants.StaleFlag},
+			Complexity: "O(N) where N is the number of channels to unsubscribe from.",
+			Since:      "0.1.0",
 			KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
 				// Treat the channels as keys
 				return internal.KeyExtractionFuncResult{
@@ -184,7 +200,11 @@ it's currently subscribe to.`,
 			Description: `(PUNSUBSCRIBE [pattern [pattern ...]]) Unsubscribe from a list of channels using patterns.
 If the pattern list is not provided, then the connection will be unsubscribed from all the patterns that
 it's currently subscribe to.`,
-			Sync: false,
+			Sync:       false,
+			Arity:      -1,
+			Flags:      []string{constants.PubSubFlag, constants.NoScriptFlag, constants.LoadingFlag, constants.StaleFlag},
+			Complexity: "O(N) where N is the number of patterns to unsubscribe from.",
+			Since:      "0.1.0",
 			KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
 				return internal.KeyExtractionFuncResult{
 					Channels:  cmd[1:],
@@ -200,6 +220,9 @@ it's currently subscribe to.`,
 			Categories:  []string{},
 			Description: "",
 			Sync:        false,
+			Arity:       -2,
+			Complexity:  "Depends on subcommand.",
+			Since:       "0.1.0",
 			KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
 				return internal.KeyExtractionFuncResult{
 					Channels:  make([]string, 0),
@@ -218,7 +241,11 @@ it's currently subscribe to.`,
 					Description: `(PUBSUB CHANNELS [pattern]) Returns an array containing the list of channels that
 match the given pattern. If no pattern is provided, all active channels are returned. Active channels are 
 channels with 1 or more subscribers.`,
-					Sync: false,
+					Sync:       false,
+					Arity:      -2,
+					Flags:      []string{constants.PubSubFlag, constants.LoadingFlag, constants.StaleFlag},
+					Complexity: "O(N) where N is the number of active channels.",
+					Since:      "0.1.0",
 					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
 						return internal.KeyExtractionFuncResult{
 							Channels:  make([]string, 0),
@@ -234,6 +261,10 @@ channels with 1 or more subscribers.`,
 					Categories:  []string{constants.PubSubCategory, constants.SlowCategory},
 					Description: `(PUBSUB NUMPAT) Return the number of patterns that are currently subscribed to by clients.`,
 					Sync:        false,
+					Arity:       2,
+					Flags:       []string{constants.PubSubFlag, constants.LoadingFlag, constants.StaleFlag},
+					Complexity:  "O(1)",
+					Since:       "0.1.0",
 					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
 						return internal.KeyExtractionFuncResult{
 							Channels:  make([]string, 0),
@@ -249,7 +280,11 @@ channels with 1 or more subscribers.`,
 					Categories: []string{constants.PubSubCategory, constants.SlowCategory},
 					Description: `(PUBSUB NUMSUB [channel [channel ...]]) Return an array of arrays containing the provided
 channel name and how many clients are currently subscribed to the channel.`,
-					Sync: false,
+					Sync:       false,
+					Arity:      -2,
+					Flags:      []string{constants.PubSubFlag, constants.LoadingFlag, constants.StaleFlag},
+					Complexity: "O(N) where N is the number of requested channels.",
+					Since:      "0.1.0",
 					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
 						return internal.KeyExtractionFuncResult{
 							Channels:  cmd[2:],
@@ -17,14 +17,17 @@ package pubsub_test
 import (
 	"github.com/echovault/echovault/echovault"
 	"github.com/echovault/echovault/internal"
+	"github.com/echovault/echovault/internal/clock"
 	"github.com/echovault/echovault/internal/config"
 	"github.com/echovault/echovault/internal/constants"
 	"github.com/tidwall/resp"
 	"net"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 )
 
 func setUpServer(port int) (*echovault.EchoVault, error) {
@@ -1011,3 +1014,124 @@ func Test_PubSub(t *testing.T) {
 		}
 	})
 }
+
+func Test_KeyspaceNotifications(t *testing.T) {
+	port, err := internal.GetFreePort()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	mockServer, err := setUpServer(port)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	go func() {
+		mockServer.Start()
+	}()
+
+	t.Cleanup(func() {
+		mockServer.ShutDown()
+	})
+
+	connect := func() *resp.Conn {
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() {
+			_ = conn.Close()
+		})
+		return resp.NewConn(conn)
+	}
+	client := connect()
+	subscriber := connect()
+
+	do := func(client *resp.Conn, command ...string) resp.Value {
+		values := make([]resp.Value, len(command))
+		for i, arg := range command {
+			values[i] = resp.StringValue(arg)
+		}
+		if err := client.WriteArray(values); err != nil {
+			t.Fatal(err)
+		}
+		res, _, err := client.ReadValue()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	// readMessages reads the next n messages sent to the subscriber. Messages published to different channels
+	// can arrive in any order, so they're returned sorted.
+	readMessages := func(n int) []string {
+		messages := make([]string, n)
+		for i := 0; i < n; i++ {
+			res, _, err := subscriber.ReadValue()
+			if err != nil {
+				t.Fatal(err)
+			}
+			parts := make([]string, len(res.Array()))
+			for j, v := range res.Array() {
+				parts[j] = v.String()
+			}
+			messages[i] = strings.Join(parts, " ")
+		}
+		slices.Sort(messages)
+		return messages
+	}
+
+	do(subscriber, "SUBSCRIBE", "__keyspace@0__:NotifyKey1", "__keyevent@0__:del", "__keyevent@0__:expired")
+	for i := 0; i < 2; i++ {
+		// Read the remaining subscription confirmations.
+		if _, _, err = subscriber.ReadValue(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Keyspace notifications are disabled by default.
+	if res := do(client, "CONFIG", "GET", "notify-keyspace-events"); len(res.Array()) != 2 || res.Array()[1].String() != "" {
+		t.Errorf("expected notify-keyspace-events to be empty, got %v", res)
+	}
+	do(client, "SET", "NotifyKey1", "value1")
+
+	// Enable every event class.
+	if res := do(client, "CONFIG", "SET", "notify-keyspace-events", "KEA"); res.String() != "OK" {
+		t.Fatalf("expected OK, got %v", res)
+	}
+	if res := do(client, "CONFIG", "GET", "notify-*"); len(res.Array()) != 2 || res.Array()[1].String() != "KEA" {
+		t.Errorf("expected notify-keyspace-events to be KEA, got %v", res)
+	}
+
+	do(client, "SET", "NotifyKey1", "value2")
+	if got := readMessages(1); !slices.Equal(got, []string{"message __keyspace@0__:NotifyKey1 set"}) {
+		t.Errorf("expected set notification, got %v", got)
+	}
+
+	do(client, "DEL", "NotifyKey1")
+	if got := readMessages(2); !slices.Equal(got, []string{
+		"message __keyevent@0__:del NotifyKey1",
+		"message __keyspace@0__:NotifyKey1 del",
+	}) {
+		t.Errorf("expected del notifications, got %v", got)
+	}
+
+	// Only publish keyevent notifications for expired keys.
+	if res := do(client, "CONFIG", "SET", "notify-keyspace-events", "Ex"); res.String() != "OK" {
+		t.Fatalf("expected OK, got %v", res)
+	}
+	do(client, "SET", "NotifyKey1", "value3")
+	do(client, "DEL", "NotifyKey1")
+	do(client, "SET", "NotifyKey2", "value")
+	do(client, "PEXPIREAT", "NotifyKey2", strconv.FormatInt(clock.NewClock().Now().Add(-time.Second).UnixMilli(), 10))
+	do(client, "GET", "NotifyKey2")
+	if got := readMessages(1); !slices.Equal(got, []string{"message __keyevent@0__:expired NotifyKey2"}) {
+		t.Errorf("expected expired notification, got %v", got)
+	}
+
+	if res := do(client, "CONFIG", "SET", "notify-keyspace-events", "KEQ"); res.Error() == nil {
+		t.Errorf("expected error for invalid flag, got %v", res)
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Keyspace notification flags. They use the same characters as the notify-keyspace-events
+// parameter in Redis.
+//
+// K and E select the channels the notifications are published to, and the other flags select
+// the classes of events that are published. At least one of K or E and one event class must be set
+// for any notification to be published.
+const (
+	NotifyKeyspace  = 1 << iota // K: Publish to __keyspace@<db>__:<key> with the event as the message.
+	NotifyKeyevent              // E: Publish to __keyevent@<db>__:<event> with the key as the message.
+	NotifyGeneric               // g: Generic commands like DEL, EXPIRE and PERSIST.
+	NotifyString                // $: String commands.
+	NotifyList                  // l: List commands.
+	NotifySet                   // s: Set commands.
+	NotifyHash                  // h: Hash commands.
+	NotifySortedSet             // z: Sorted set commands.
+	NotifyExpired               // x: Keys deleted because they expired.
+	NotifyEvicted               // e: Keys evicted when max memory is reached.
+
+	// NotifyAll is the A flag, an alias for g$lshzxe.
+	NotifyAll = NotifyGeneric | NotifyString | NotifyList | NotifySet | NotifyHash | NotifySortedSet |
+		NotifyExpired | NotifyEvicted
+)
+
+var notifyFlags = []struct {
+	flag  byte
+	class int
+}{
+	{flag: 'K', class: NotifyKeyspace},
+	{flag: 'E', class: NotifyKeyevent},
+	{flag: 'g', class: NotifyGeneric},
+	{flag: '$', class: NotifyString},
+	{flag: 'l', class: NotifyList},
+	{flag: 's', class: NotifySet},
+	{flag: 'h', class: NotifyHash},
+	{flag: 'z', class: NotifySortedSet},
+	{flag: 'x', class: NotifyExpired},
+	{flag: 'e', class: NotifyEvicted},
+}
+
+// ParseKeyspaceEvents parses a notify-keyspace-events string like "KEA" or "Kx" into notification flags.
+// An empty string disables keyspace notifications.
+func ParseKeyspaceEvents(events string) (int, error) {
+	flags := 0
+	for i := 0; i < len(events); i++ {
+		if events[i] == 'A' {
+			flags |= NotifyAll
+			continue
+		}
+		found := false
+		for _, f := range notifyFlags {
+			if f.flag == events[i] {
+				flags |= f.class
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("invalid keyspace events flag '%c'", events[i])
+		}
+	}
+	return flags, nil
+}
+
+// FormatKeyspaceEvents formats notification flags into a notify-keyspace-events string.
+func FormatKeyspaceEvents(flags int) string {
+	var b strings.Builder
+	for _, f := range notifyFlags {
+		if flags&f.class == 0 {
+			continue
+		}
+		if f.class&NotifyAll != 0 && flags&NotifyAll == NotifyAll {
+			// Use the A alias when every event class is enabled.
+			b.WriteByte('A')
+			break
+		}
+		b.WriteByte(f.flag)
+	}
+	return b.String()
+}
+
+// SetKeyspaceEvents sets the keyspace notification flags. It can be called while the server is running.
+func (ps *PubSub) SetKeyspaceEvents(flags int) {
+	ps.keyspaceEvents.Store(int64(flags))
+}
+
+// KeyspaceEvents returns the keyspace notification flags.
+func (ps *PubSub) KeyspaceEvents() int {
+	return int(ps.keyspaceEvents.Load())
+}
+
+// NotifyKeyspaceEvent publishes a keyspace notification for the event on the key when the event's class
+// is enabled. Notifications are published to regular channels, so subscribers need the channel permissions
+// of the __keyspace@<db>__:<key> and __keyevent@<db>__:<event> channels they subscribe to.
+func (ps *PubSub) NotifyKeyspaceEvent(ctx context.Context, class int, event string, database int, key string) {
+	flags := ps.KeyspaceEvents()
+	if flags&class == 0 {
+		return
+	}
+	if flags&NotifyKeyspace != 0 {
+		ps.Publish(ctx, event, fmt.Sprintf("__keyspace@%d__:%s", database, key))
+	}
+	if flags&NotifyKeyevent != 0 {
+		ps.Publish(ctx, key, fmt.Sprintf("__keyevent@%d__:%s", database, event))
+	}
+}
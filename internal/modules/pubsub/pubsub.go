@@ -17,23 +17,27 @@ package pubsub
 import (
 	"context"
 	"fmt"
+	"github.com/echovault/echovault/internal/logger"
 	"github.com/gobwas/glob"
 	"github.com/tidwall/resp"
-	"log"
 	"net"
 	"slices"
 	"sync"
+	"sync/atomic"
 )
 
 type PubSub struct {
-	channels      []*Channel
-	channelsRWMut sync.RWMutex
+	channels       []*Channel
+	channelsRWMut  sync.RWMutex
+	keyspaceEvents atomic.Int64  // The keyspace notification flags.
+	logger         logger.Logger // Logger passed to the channels.
 }
 
-func NewPubSub() *PubSub {
+func NewPubSub(logger logger.Logger) *PubSub {
 	return &PubSub{
 		channels:      []*Channel{},
 		channelsRWMut: sync.RWMutex{},
+		logger:        logger,
 	}
 }
 
@@ -60,9 +64,9 @@ func (ps *PubSub) Subscribe(_ context.Context, conn *net.Conn, channels []string
 			// Create new channel, start it, and subscribe to it
 			var newChan *Channel
 			if withPattern {
-				newChan = NewChannel(WithPattern(channels[i]))
+				newChan = NewChannel(WithPattern(channels[i]), WithLogger(ps.logger))
 			} else {
-				newChan = NewChannel(WithName(channels[i]))
+				newChan = NewChannel(WithName(channels[i]), WithLogger(ps.logger))
 			}
 			newChan.Start()
 			if newChan.Subscribe(conn) {
@@ -71,7 +75,7 @@ func (ps *PubSub) Subscribe(_ context.Context, conn *net.Conn, channels []string
 					resp.StringValue(newChan.name),
 					resp.IntegerValue(i + 1),
 				}); err != nil {
-					log.Println(err)
+					ps.logger.Debug("write subscribe reply", logger.ErrorKey, err)
 				}
 				ps.channels = append(ps.channels, newChan)
 			}
@@ -83,7 +87,7 @@ func (ps *PubSub) Subscribe(_ context.Context, conn *net.Conn, channels []string
 					resp.StringValue(ps.channels[channelIdx].name),
 					resp.IntegerValue(i + 1),
 				}); err != nil {
-					log.Println(err)
+					ps.logger.Debug("write subscribe reply", logger.ErrorKey, err)
 				}
 			}
 		}
@@ -231,6 +235,20 @@ func (ps *PubSub) Channels(pattern string) []byte {
 	return []byte(fmt.Sprintf("*%d\r\n%s", count, res))
 }
 
+// NumChannels returns the number of channels, excluding patterns, with at least one subscriber.
+func (ps *PubSub) NumChannels() int {
+	ps.channelsRWMut.RLock()
+	defer ps.channelsRWMut.RUnlock()
+
+	var count int
+	for _, channel := range ps.channels {
+		if channel.pattern == nil && channel.IsActive() {
+			count += 1
+		}
+	}
+	return count
+}
+
 func (ps *PubSub) NumPat() int {
 	ps.channelsRWMut.RLock()
 	defer ps.channelsRWMut.RUnlock()
@@ -244,6 +262,25 @@ func (ps *PubSub) NumPat() int {
 	return count
 }
 
+// Subscriptions returns the number of channels and the number of patterns the connection is subscribed to.
+func (ps *PubSub) Subscriptions(conn *net.Conn) (int, int) {
+	ps.channelsRWMut.RLock()
+	defer ps.channelsRWMut.RUnlock()
+
+	var channels, patterns int
+	for _, channel := range ps.channels {
+		if !channel.IsSubscribed(conn) {
+			continue
+		}
+		if channel.pattern != nil {
+			patterns += 1
+			continue
+		}
+		channels += 1
+	}
+	return channels, patterns
+}
+
 func (ps *PubSub) NumSub(channels []string) []byte {
 	ps.channelsRWMut.RLock()
 	defer ps.channelsRWMut.RUnlock()
@@ -0,0 +1,87 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replication
+
+import (
+	"fmt"
+)
+
+// Backlog is a fixed size circular buffer holding the most recent bytes of the replication stream.
+// Every byte in the stream has an offset. The backlog allows a replica that briefly lost its connection
+// to continue from its last offset instead of requesting a full resynchronization.
+// Backlog is not safe for concurrent use, the replication engine guards it with its own mutex.
+type Backlog struct {
+	buf   []byte
+	start int64 // The offset of the oldest byte held in the backlog.
+	end   int64 // The offset right after the newest byte held in the backlog.
+}
+
+// NewBacklog creates a backlog of the given size whose next written byte will have the provided offset.
+func NewBacklog(size int, offset int64) *Backlog {
+	if size <= 0 {
+		size = 1
+	}
+	return &Backlog{
+		buf:   make([]byte, size),
+		start: offset,
+		end:   offset,
+	}
+}
+
+// Write appends p to the backlog, overwriting the oldest bytes when the backlog is full.
+func (backlog *Backlog) Write(p []byte) {
+	size := int64(len(backlog.buf))
+	for _, b := range p {
+		backlog.buf[backlog.end%size] = b
+		backlog.end++
+	}
+	if backlog.end-backlog.start > size {
+		backlog.start = backlog.end - size
+	}
+}
+
+// ReadFrom returns a copy of all the bytes from the given offset up to the end of the backlog.
+// An error is returned when the offset is no longer (or not yet) held in the backlog.
+func (backlog *Backlog) ReadFrom(offset int64) ([]byte, error) {
+	if !backlog.Contains(offset) {
+		return nil, fmt.Errorf("offset %d is outside the backlog range %d-%d", offset, backlog.start, backlog.end)
+	}
+	size := int64(len(backlog.buf))
+	res := make([]byte, backlog.end-offset)
+	for i := range res {
+		res[i] = backlog.buf[(offset+int64(i))%size]
+	}
+	return res, nil
+}
+
+// Contains returns true if a replica at the given offset can continue streaming from the backlog.
+func (backlog *Backlog) Contains(offset int64) bool {
+	return offset >= backlog.start && offset <= backlog.end
+}
+
+// Start returns the offset of the oldest byte held in the backlog.
+func (backlog *Backlog) Start() int64 {
+	return backlog.start
+}
+
+// End returns the offset that will be assigned to the next byte written to the backlog.
+func (backlog *Backlog) End() int64 {
+	return backlog.end
+}
+
+// Size returns the capacity of the backlog in bytes.
+func (backlog *Backlog) Size() int {
+	return len(backlog.buf)
+}
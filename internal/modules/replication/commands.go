@@ -0,0 +1,317 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replication
+
+import (
+	"errors"
+	"fmt"
+	"github.com/echovault/echovault/internal"
+	"github.com/echovault/echovault/internal/constants"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func handleReplicaOf(params internal.HandlerFuncParams) ([]byte, error) {
+	replication, ok := params.GetReplication().(*Replication)
+	if !ok {
+		return nil, errors.New("could not load replication module")
+	}
+
+	if len(params.Command) != 3 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+
+	if params.GetServerInfo().Mode == "cluster" {
+		return nil, errors.New("REPLICAOF not allowed in cluster mode")
+	}
+
+	if strings.EqualFold(params.Command[1], "no") && strings.EqualFold(params.Command[2], "one") {
+		replication.ReplicaOfNoOne()
+		return []byte(constants.OkResponse), nil
+	}
+
+	port, err := strconv.Atoi(params.Command[2])
+	if err != nil || port <= 0 || port > 65535 {
+		return nil, errors.New("port must be a valid port number")
+	}
+
+	replication.ReplicaOf(params.Command[1], port)
+
+	return []byte(constants.OkResponse), nil
+}
+
+func handlePSync(params internal.HandlerFuncParams) ([]byte, error) {
+	replication, ok := params.GetReplication().(*Replication)
+	if !ok {
+		return nil, errors.New("could not load replication module")
+	}
+
+	if len(params.Command) != 3 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+
+	if params.Connection == nil {
+		return nil, errors.New("PSYNC is only supported over a TCP connection")
+	}
+
+	offset, err := strconv.ParseInt(params.Command[2], 10, 64)
+	if err != nil {
+		return nil, errors.New("offset must be an integer")
+	}
+
+	// The response and the replication stream are written directly to the connection.
+	if err = replication.PSync(params.Connection, params.Command[1], offset); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func handleReplConf(params internal.HandlerFuncParams) ([]byte, error) {
+	replication, ok := params.GetReplication().(*Replication)
+	if !ok {
+		return nil, errors.New("could not load replication module")
+	}
+
+	if len(params.Command) < 3 || len(params.Command)%2 != 1 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+
+	if params.Connection == nil {
+		return nil, errors.New("REPLCONF is only supported over a TCP connection")
+	}
+
+	ack := false
+	for i := 1; i < len(params.Command); i += 2 {
+		if err := replication.ReplConf(params.Connection, params.Command[i], params.Command[i+1]); err != nil {
+			return nil, err
+		}
+		if strings.EqualFold(params.Command[i], "ack") {
+			ack = true
+		}
+	}
+
+	// The primary does not reply to acknowledgements.
+	if ack {
+		return nil, nil
+	}
+
+	return []byte(constants.OkResponse), nil
+}
+
+func handleRole(params internal.HandlerFuncParams) ([]byte, error) {
+	if len(params.Command) != 1 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+
+	info := params.GetReplicationInfo()
+
+	if info.Role == "slave" {
+		offset := info.Offset
+		if info.LinkStatus != "connected" {
+			offset = -1
+		}
+		return []byte(fmt.Sprintf("*5\r\n$5\r\nslave\r\n$%d\r\n%s\r\n:%d\r\n$%d\r\n%s\r\n:%d\r\n",
+			len(info.PrimaryHost), info.PrimaryHost, info.PrimaryPort,
+			len(info.LinkStatus), info.LinkStatus, offset)), nil
+	}
+
+	res := fmt.Sprintf("*3\r\n$6\r\nmaster\r\n:%d\r\n*%d\r\n", info.Offset, len(info.Replicas))
+	for _, r := range info.Replicas {
+		port := strconv.Itoa(r.Port)
+		offset := strconv.FormatInt(r.Offset, 10)
+		res += fmt.Sprintf("*3\r\n$%d\r\n%s\r\n$%d\r\n%s\r\n$%d\r\n%s\r\n",
+			len(r.Addr), r.Addr, len(port), port, len(offset), offset)
+	}
+
+	return []byte(res), nil
+}
+
+func handleWait(params internal.HandlerFuncParams) ([]byte, error) {
+	if len(params.Command) != 3 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+
+	numReplicas, err := strconv.Atoi(params.Command[1])
+	if err != nil || numReplicas < 0 {
+		return nil, errors.New("numreplicas must be a non-negative integer")
+	}
+
+	timeout, err := parseTimeout(params.Command[2])
+	if err != nil {
+		return nil, err
+	}
+
+	replicas, err := params.Wait(numReplicas, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(fmt.Sprintf(":%d\r\n", replicas)), nil
+}
+
+func handleWaitAOF(params internal.HandlerFuncParams) ([]byte, error) {
+	if len(params.Command) != 4 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+
+	numLocal, err := strconv.Atoi(params.Command[1])
+	if err != nil || numLocal < 0 || numLocal > 1 {
+		return nil, errors.New("numlocal must be 0 or 1")
+	}
+
+	numReplicas, err := strconv.Atoi(params.Command[2])
+	if err != nil || numReplicas < 0 {
+		return nil, errors.New("numreplicas must be a non-negative integer")
+	}
+
+	timeout, err := parseTimeout(params.Command[3])
+	if err != nil {
+		return nil, err
+	}
+
+	local, replicas, err := params.WaitAOF(numLocal, numReplicas, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(fmt.Sprintf("*2\r\n:%d\r\n:%d\r\n", local, replicas)), nil
+}
+
+// parseTimeout parses a timeout in milliseconds. A timeout of 0 means block indefinitely.
+func parseTimeout(timeout string) (time.Duration, error) {
+	ms, err := strconv.ParseInt(timeout, 10, 64)
+	if err != nil {
+		return 0, errors.New("timeout is not an integer or out of range")
+	}
+	if ms < 0 {
+		return 0, errors.New("timeout is negative")
+	}
+	return time.Duration(ms) * time.Millisecond, nil
+}
+
+func Commands() []internal.Command {
+	return []internal.Command{
+		{
+			Command:    "replicaof",
+			Module:     constants.ReplicationModule,
+			Categories: []string{constants.AdminCategory, constants.SlowCategory, constants.DangerousCategory},
+			Description: `(REPLICAOF host port | NO ONE) Make the server a replica of the primary at host:port.
+REPLICAOF NO ONE stops replication and promotes the server to a primary.`,
+			Sync:       false,
+			Arity:      3,
+			Flags:      []string{constants.AdminFlag, constants.NoScriptFlag, constants.StaleFlag},
+			Complexity: "O(1)",
+			Since:      "0.10.1",
+			KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+				return internal.KeyExtractionFuncResult{
+					Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+				}, nil
+			},
+			HandlerFunc: handleReplicaOf,
+		},
+		{
+			Command:     "psync",
+			Module:      constants.ReplicationModule,
+			Categories:  []string{constants.AdminCategory, constants.SlowCategory, constants.DangerousCategory},
+			Description: `(PSYNC replicationid offset) Internal command used by a replica to synchronize with its primary.`,
+			Sync:        false,
+			Arity:       3,
+			Flags:       []string{constants.AdminFlag, constants.NoScriptFlag},
+			Complexity:  "O(N) where N is the total number of keys in all databases when a full resynchronization is needed.",
+			Since:       "0.10.1",
+			KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+				return internal.KeyExtractionFuncResult{
+					Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+				}, nil
+			},
+			HandlerFunc: handlePSync,
+		},
+		{
+			Command:    "replconf",
+			Module:     constants.ReplicationModule,
+			Categories: []string{constants.AdminCategory, constants.SlowCategory, constants.DangerousCategory},
+			Description: `(REPLCONF option value [option value ...]) Internal command used by a replica to configure
+the replication link. The supported options are listening-port, ACK and FACK.`,
+			Sync:       false,
+			Arity:      -3,
+			Flags:      []string{constants.AdminFlag, constants.NoScriptFlag, constants.LoadingFlag, constants.StaleFlag},
+			Complexity: "O(1)",
+			Since:      "0.10.1",
+			KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+				return internal.KeyExtractionFuncResult{
+					Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+				}, nil
+			},
+			HandlerFunc: handleReplConf,
+		},
+		{
+			Command:     "role",
+			Module:      constants.ReplicationModule,
+			Categories:  []string{constants.AdminCategory, constants.FastCategory, constants.DangerousCategory},
+			Description: `(ROLE) Returns the replication role of the server, its offset and its replicas or primary.`,
+			Sync:        false,
+			Arity:       1,
+			Flags:       []string{constants.AdminFlag, constants.NoScriptFlag, constants.LoadingFlag, constants.StaleFlag, constants.FastFlag},
+			Complexity:  "O(1)",
+			Since:       "0.10.1",
+			KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+				return internal.KeyExtractionFuncResult{
+					Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+				}, nil
+			},
+			HandlerFunc: handleRole,
+		},
+		{
+			Command:    "wait",
+			Module:     constants.ReplicationModule,
+			Categories: []string{constants.SlowCategory, constants.ConnectionCategory},
+			Description: `(WAIT numreplicas timeout) Blocks until the writes issued before it are acknowledged by at least
+numreplicas replicas or the timeout in milliseconds elapses. A timeout of 0 blocks indefinitely.
+Returns the number of replicas that acknowledged the writes.`,
+			Sync:       false,
+			Arity:      3,
+			Flags:      []string{constants.NoScriptFlag, constants.BlockingFlag},
+			Complexity: "O(1)",
+			Since:      "0.10.1",
+			KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+				return internal.KeyExtractionFuncResult{
+					Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+				}, nil
+			},
+			HandlerFunc: handleWait,
+		},
+		{
+			Command:    "waitaof",
+			Module:     constants.ReplicationModule,
+			Categories: []string{constants.SlowCategory, constants.ConnectionCategory},
+			Description: `(WAITAOF numlocal numreplicas timeout) Blocks until the writes issued before it are fsynced to
+the local append-only log (when numlocal is 1) and by at least numreplicas replicas, or the timeout in milliseconds
+elapses. A timeout of 0 blocks indefinitely. Returns the number of local and replica fsyncs.`,
+			Sync:       false,
+			Arity:      4,
+			Flags:      []string{constants.NoScriptFlag, constants.BlockingFlag},
+			Complexity: "O(1)",
+			Since:      "0.10.1",
+			KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+				return internal.KeyExtractionFuncResult{
+					Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+				}, nil
+			},
+			HandlerFunc: handleWaitAOF,
+		},
+	}
+}
@@ -0,0 +1,351 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replication_test
+
+import (
+	"bytes"
+	"github.com/echovault/echovault/echovault"
+	"github.com/echovault/echovault/internal"
+	"github.com/echovault/echovault/internal/constants"
+	"github.com/tidwall/resp"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func setupServer(port uint16) (*echovault.EchoVault, error) {
+	cfg := echovault.DefaultConfig()
+	cfg.DataDir = ""
+	cfg.BindAddr = "localhost"
+	cfg.Port = port
+	cfg.EvictionPolicy = constants.NoEviction
+	return echovault.NewEchoVault(echovault.WithConfig(cfg))
+}
+
+// handshake performs the replica side of the replication handshake and returns the PSYNC response.
+func handshake(t *testing.T, conn net.Conn, rd *resp.Reader, replId string, offset int64) []string {
+	if _, err := conn.Write(internal.EncodeCommand([]string{"REPLCONF", "listening-port", "7000"})); err != nil {
+		t.Fatal(err)
+	}
+	res, _, err := rd.ReadValue()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.EqualFold(res.String(), "ok") {
+		t.Fatalf("expected REPLCONF response OK, got %s", res.String())
+	}
+	if _, err = conn.Write(internal.EncodeCommand([]string{
+		"PSYNC", replId, strconv.FormatInt(offset, 10),
+	})); err != nil {
+		t.Fatal(err)
+	}
+	res, _, err = rd.ReadValue()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Fields(res.String())
+}
+
+// readCommand reads the next command from the replication stream, returning it with its size in bytes.
+func readCommand(t *testing.T, conn net.Conn, rd *resp.Reader) ([]string, int64) {
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	value, n, err := rd.ReadValue()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cmd []string
+	for _, v := range value.Array() {
+		cmd = append(cmd, v.String())
+	}
+	return cmd, int64(n)
+}
+
+func Test_Replication(t *testing.T) {
+	port, err := internal.GetFreePort()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	mockServer, err := setupServer(uint16(port))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	go func() {
+		mockServer.Start()
+	}()
+
+	t.Cleanup(func() {
+		mockServer.ShutDown()
+	})
+
+	t.Run("Test WAIT and WAITAOF commands", func(t *testing.T) {
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		client := resp.NewConn(conn)
+
+		tests := []struct {
+			name        string
+			command     []string
+			expected    []int
+			expectedErr string
+		}{
+			{
+				name:     "1. WAIT returns immediately when no replicas are requested",
+				command:  []string{"WAIT", "0", "0"},
+				expected: []int{0},
+			},
+			{
+				name:     "2. WAIT returns the number of replicas that acknowledged after the timeout",
+				command:  []string{"WAIT", "1", "100"},
+				expected: []int{0},
+			},
+			{
+				name:     "3. WAITAOF returns no fsyncs when the append-only log is disabled",
+				command:  []string{"WAITAOF", "0", "0", "0"},
+				expected: []int{0, 0},
+			},
+			{
+				name:        "4. WAITAOF returns error when numlocal is set but the append-only log is disabled",
+				command:     []string{"WAITAOF", "1", "0", "0"},
+				expectedErr: "WAITAOF cannot be used when numlocal is set but appendonly is disabled",
+			},
+			{
+				name:        "5. WAITAOF returns error when numlocal is greater than 1",
+				command:     []string{"WAITAOF", "2", "0", "0"},
+				expectedErr: "numlocal must be 0 or 1",
+			},
+			{
+				name:        "6. WAIT returns error when the timeout is negative",
+				command:     []string{"WAIT", "0", "-1"},
+				expectedErr: "timeout is negative",
+			},
+			{
+				name:        "7. WAIT returns error when the wrong number of arguments is passed",
+				command:     []string{"WAIT", "0"},
+				expectedErr: constants.WrongArgsResponse,
+			},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				command := make([]resp.Value, len(test.command))
+				for i, c := range test.command {
+					command[i] = resp.StringValue(c)
+				}
+				if err = client.WriteArray(command); err != nil {
+					t.Fatal(err)
+				}
+				res, _, err := client.ReadValue()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if test.expectedErr != "" {
+					if !strings.Contains(res.Error().Error(), test.expectedErr) {
+						t.Errorf("expected error to contain \"%s\", got \"%s\"", test.expectedErr, res.Error().Error())
+					}
+					return
+				}
+				var got []int
+				if res.Type() == resp.Array {
+					for _, v := range res.Array() {
+						got = append(got, v.Integer())
+					}
+				} else {
+					got = []int{res.Integer()}
+				}
+				if !slices.Equal(got, test.expected) {
+					t.Errorf("expected response %v, got %v", test.expected, got)
+				}
+			})
+		}
+	})
+
+	t.Run("Test PSYNC full and partial resync", func(t *testing.T) {
+		if _, _, err := mockServer.Set("PsyncKey1", "value1", echovault.SetOptions{}); err != nil {
+			t.Fatal(err)
+		}
+
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rd := resp.NewReader(conn)
+
+		res := handshake(t, conn, rd, "?", -1)
+		if len(res) != 3 || res[0] != "FULLRESYNC" {
+			t.Fatalf("expected FULLRESYNC response, got %v", res)
+		}
+		replId := res[1]
+		offset, err := strconv.ParseInt(res[2], 10, 64)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The payload contains the commands that rebuild the dataset.
+		payload, _, err := rd.ReadValue()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Contains(payload.Bytes(), internal.EncodeCommand([]string{"SET", "PsyncKey1", "value1"})) {
+			t.Errorf("expected full resync payload to contain PsyncKey1, got %q", payload.String())
+		}
+
+		// Writes after the full resync are streamed, starting with the selected database.
+		if _, _, err = mockServer.Set("PsyncKey2", "value2", echovault.SetOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		for _, expected := range [][]string{{"SELECT", "0"}, {"SET", "PsyncKey2", "value2"}} {
+			cmd, n := readCommand(t, conn, rd)
+			if !slices.Equal(cmd, expected) {
+				t.Errorf("expected streamed command %v, got %v", expected, cmd)
+			}
+			offset += n
+		}
+		_ = conn.Close()
+
+		// Writes while the replica is disconnected are kept in the backlog.
+		if _, _, err = mockServer.Set("PsyncKey3", "value3", echovault.SetOptions{}); err != nil {
+			t.Fatal(err)
+		}
+
+		conn, err = internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		rd = resp.NewReader(conn)
+
+		res = handshake(t, conn, rd, replId, offset)
+		if len(res) != 2 || res[0] != "CONTINUE" || res[1] != replId {
+			t.Fatalf("expected CONTINUE %s response, got %v", replId, res)
+		}
+		cmd, _ := readCommand(t, conn, rd)
+		if expected := []string{"SET", "PsyncKey3", "value3"}; !slices.Equal(cmd, expected) {
+			t.Errorf("expected streamed command %v, got %v", expected, cmd)
+		}
+
+		info := mockServer.GetReplicationInfo()
+		if info.SyncPartialOK < 1 {
+			t.Errorf("expected at least 1 partial resync, got %d", info.SyncPartialOK)
+		}
+	})
+
+	t.Run("Test PSYNC with unknown replication ID triggers full resync", func(t *testing.T) {
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		rd := resp.NewReader(conn)
+
+		res := handshake(t, conn, rd, "unknown-replication-id", 0)
+		if len(res) != 3 || res[0] != "FULLRESYNC" {
+			t.Fatalf("expected FULLRESYNC response, got %v", res)
+		}
+		if res[1] != mockServer.GetReplicationInfo().ReplID {
+			t.Errorf("expected replication ID %s, got %s", mockServer.GetReplicationInfo().ReplID, res[1])
+		}
+	})
+
+	t.Run("Test ROLE command on primary", func(t *testing.T) {
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		client := resp.NewConn(conn)
+
+		if err = client.WriteArray([]resp.Value{resp.StringValue("ROLE")}); err != nil {
+			t.Fatal(err)
+		}
+		res, _, err := client.ReadValue()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res.Array()) != 3 {
+			t.Fatalf("expected ROLE response of length 3, got %d", len(res.Array()))
+		}
+		if res.Array()[0].String() != "master" {
+			t.Errorf("expected role master, got %s", res.Array()[0].String())
+		}
+		if offset := int64(res.Array()[1].Integer()); offset != mockServer.GetReplicationInfo().Offset {
+			t.Errorf("expected offset %d, got %d", mockServer.GetReplicationInfo().Offset, offset)
+		}
+	})
+
+	t.Run("Test REPLICAOF command", func(t *testing.T) {
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		client := resp.NewConn(conn)
+
+		tests := []struct {
+			name        string
+			command     []string
+			expectedErr string
+		}{
+			{
+				name:        "1. Return error when the wrong number of arguments is passed",
+				command:     []string{"REPLICAOF", "localhost"},
+				expectedErr: constants.WrongArgsResponse,
+			},
+			{
+				name:        "2. Return error when the port is not a valid port number",
+				command:     []string{"REPLICAOF", "localhost", "port"},
+				expectedErr: "port must be a valid port number",
+			},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				command := make([]resp.Value, len(test.command))
+				for i, c := range test.command {
+					command[i] = resp.StringValue(c)
+				}
+				if err = client.WriteArray(command); err != nil {
+					t.Fatal(err)
+				}
+				res, _, err := client.ReadValue()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !strings.Contains(res.Error().Error(), test.expectedErr) {
+					t.Errorf("expected error to contain \"%s\", got \"%s\"", test.expectedErr, res.Error().Error())
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,804 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replication
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/echovault/echovault/internal"
+	"github.com/echovault/echovault/internal/clock"
+	"github.com/echovault/echovault/internal/logger"
+	"github.com/tidwall/resp"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// This package contains the asynchronous primary/replica replication engine used in standalone mode.
+//
+// The primary appends every write command to a replication stream. Each byte of the stream has an offset,
+// and the most recent bytes are kept in a backlog. A replica connects with PSYNC <replid> <offset>.
+// If the primary can serve the stream from that offset, it replies with +CONTINUE and streams the backlog.
+// Otherwise, it replies with +FULLRESYNC <replid> <offset> followed by a bulk string of commands that rebuild
+// the dataset, and then streams the commands written after that offset.
+
+const (
+	linkConnect    = "connect"    // The replica is waiting to (re)connect to the primary.
+	linkConnecting = "connecting" // The replica is performing the handshake with the primary.
+	linkSync       = "sync"       // The replica is synchronizing the dataset with the primary.
+	linkConnected  = "connected"  // The replica is streaming commands from the primary.
+)
+
+type replica struct {
+	conn       *net.Conn
+	addr       string
+	port       int
+	state      string    // wait_bgsave while the full resync payload is prepared, online afterwards.
+	offset     int64     // The offset of the next byte to stream to the replica.
+	ackOffset  int64     // The latest offset acknowledged by the replica.
+	fackOffset int64     // The latest offset the replica acknowledged as fsynced to its append-only log.
+	lastAck    time.Time // The time of the latest acknowledgement.
+	closed     bool
+}
+
+type primaryLink struct {
+	host   string
+	port   int
+	status string
+	conn   net.Conn
+	stop   chan struct{}
+}
+
+type Replication struct {
+	mut           sync.Mutex
+	cond          *sync.Cond
+	clock         clock.Clock
+	listeningPort int
+	backlogSize   int
+	retryInterval time.Duration
+	ackInterval   time.Duration
+
+	replId          string
+	prevReplId      string // The replication ID this server used before it was promoted from a replica.
+	prevOffsetLimit int64  // Replicas of prevReplId can continue up to this offset.
+	backlog         *Backlog
+	currentDatabase int // The database selected by the last SELECT written to the stream. -1 forces a new SELECT.
+
+	replicas  map[*net.Conn]*replica
+	ackNotify chan struct{} // Closed and replaced whenever a replica acknowledges an offset.
+	primary   *primaryLink  // Link to the primary. Nil when this server is a primary.
+
+	// applyMut is held by a replica while applying a command from the primary and writing it to its own backlog.
+	// Full resyncs served by a replica hold it too, so that the payload and the offset always match.
+	applyMut       sync.Mutex
+	streamDatabase int           // The database selected in the stream received from the primary.
+	ackNow         chan struct{} // Signals the replica to acknowledge its offset immediately.
+	pendingFack    struct {      // Offsets recorded at the previous acknowledgement, used to compute FACK.
+		replOffset int64
+		aofOffset  uint64
+	}
+
+	syncFull       uint64
+	syncPartialOK  uint64
+	syncPartialErr uint64
+
+	getSyncPayloadFunc func() ([]byte, int64)
+	handleCommandFunc  func(database int, command []byte) error
+	aofProgressFunc    func() (written uint64, synced uint64, enabled bool)
+	logger             logger.Logger
+}
+
+func WithClock(clock clock.Clock) func(replication *Replication) {
+	return func(replication *Replication) {
+		replication.clock = clock
+	}
+}
+
+func WithLogger(logger logger.Logger) func(replication *Replication) {
+	return func(replication *Replication) {
+		replication.logger = logger
+	}
+}
+
+// WithListeningPort sets the port announced to the primary when this server becomes a replica.
+func WithListeningPort(port int) func(replication *Replication) {
+	return func(replication *Replication) {
+		replication.listeningPort = port
+	}
+}
+
+// WithBacklogSize sets the capacity of the replication backlog in bytes.
+func WithBacklogSize(size int) func(replication *Replication) {
+	return func(replication *Replication) {
+		replication.backlogSize = size
+	}
+}
+
+// WithGetSyncPayloadFunc sets the function that serializes the dataset into RESP commands for a full resync.
+// It must also return the replication offset that the payload corresponds to, see SyncPoint.
+func WithGetSyncPayloadFunc(f func() ([]byte, int64)) func(replication *Replication) {
+	return func(replication *Replication) {
+		replication.getSyncPayloadFunc = f
+	}
+}
+
+// WithHandleCommandFunc sets the function used by a replica to apply the commands received from the primary.
+func WithHandleCommandFunc(f func(database int, command []byte) error) func(replication *Replication) {
+	return func(replication *Replication) {
+		replication.handleCommandFunc = f
+	}
+}
+
+// WithAOFProgressFunc sets the function that reports how many bytes were written to the append-only log and how
+// many of them are fsynced. A replica uses it to acknowledge the offsets that are durable on its disk.
+func WithAOFProgressFunc(f func() (written uint64, synced uint64, enabled bool)) func(replication *Replication) {
+	return func(replication *Replication) {
+		replication.aofProgressFunc = f
+	}
+}
+
+func NewReplication(options ...func(replication *Replication)) *Replication {
+	replication := &Replication{
+		clock:           clock.NewClock(),
+		backlogSize:     1024 * 1024,
+		retryInterval:   time.Second,
+		ackInterval:     time.Second,
+		replId:          newReplId(),
+		currentDatabase: -1,
+		replicas:        make(map[*net.Conn]*replica),
+		ackNotify:       make(chan struct{}),
+		ackNow:          make(chan struct{}, 1),
+		getSyncPayloadFunc: func() ([]byte, int64) {
+			return []byte{}, 0
+		},
+		handleCommandFunc: func(database int, command []byte) error {
+			return nil
+		},
+		aofProgressFunc: func() (uint64, uint64, bool) {
+			return 0, 0, false
+		},
+		logger: logger.Default(),
+	}
+
+	for _, option := range options {
+		option(replication)
+	}
+
+	replication.cond = sync.NewCond(&replication.mut)
+	replication.backlog = NewBacklog(replication.backlogSize, 0)
+
+	return replication
+}
+
+func newReplId() string {
+	b := make([]byte, 20)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func selectCommand(database int) []byte {
+	return internal.EncodeCommand([]string{"SELECT", strconv.Itoa(database)})
+}
+
+// Feed appends a write command executed on the given database to the replication stream.
+func (replication *Replication) Feed(database int, command []byte) {
+	replication.mut.Lock()
+	defer replication.mut.Unlock()
+
+	// Replicas only relay the stream received from their primary.
+	if replication.primary != nil {
+		return
+	}
+
+	if database != replication.currentDatabase {
+		replication.backlog.Write(selectCommand(database))
+		replication.currentDatabase = database
+	}
+	replication.backlog.Write(command)
+
+	replication.cond.Broadcast()
+}
+
+// SyncPoint returns the current replication offset. It must be called while the dataset is being copied for a
+// full resync, with writes blocked, so that the copy reflects exactly the stream up to the returned offset.
+func (replication *Replication) SyncPoint() int64 {
+	replication.mut.Lock()
+	defer replication.mut.Unlock()
+	// The replica starts applying the stream after the payload without knowing the selected database,
+	// so the next command in the stream must be preceded by a SELECT.
+	replication.currentDatabase = -1
+	return replication.backlog.End()
+}
+
+// Offset returns the current replication offset.
+func (replication *Replication) Offset() int64 {
+	replication.mut.Lock()
+	defer replication.mut.Unlock()
+	return replication.backlog.End()
+}
+
+// IsReplica returns true when this server replicates from a primary.
+func (replication *Replication) IsReplica() bool {
+	replication.mut.Lock()
+	defer replication.mut.Unlock()
+	return replication.primary != nil
+}
+
+// IsReplicaConnection returns true when the connection belongs to a replica streaming from this server.
+func (replication *Replication) IsReplicaConnection(conn *net.Conn) bool {
+	replication.mut.Lock()
+	defer replication.mut.Unlock()
+	_, ok := replication.replicas[conn]
+	return ok
+}
+
+// getReplica returns the replica registered for the connection, registering it if it's not known yet.
+// The caller must hold replication.mut.
+func (replication *Replication) getReplica(conn *net.Conn) *replica {
+	if r, ok := replication.replicas[conn]; ok {
+		return r
+	}
+	r := &replica{conn: conn, state: "wait_bgsave", lastAck: replication.clock.Now()}
+	if addr, ok := (*conn).RemoteAddr().(*net.TCPAddr); ok {
+		r.addr = addr.IP.String()
+		r.port = addr.Port
+	}
+	replication.replicas[conn] = r
+	return r
+}
+
+// ReplConf handles the REPLCONF options sent by a replica over its connection.
+func (replication *Replication) ReplConf(conn *net.Conn, option string, value string) error {
+	replication.mut.Lock()
+	defer replication.mut.Unlock()
+
+	r := replication.getReplica(conn)
+
+	switch strings.ToLower(option) {
+	case "listening-port":
+		port, err := strconv.Atoi(value)
+		if err != nil {
+			return errors.New("listening-port must be an integer")
+		}
+		r.port = port
+	case "ack", "fack":
+		offset, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%s offset must be an integer", strings.ToLower(option))
+		}
+		if strings.EqualFold(option, "ack") {
+			r.ackOffset = offset
+			r.lastAck = replication.clock.Now()
+		} else {
+			r.fackOffset = offset
+		}
+		close(replication.ackNotify)
+		replication.ackNotify = make(chan struct{})
+	}
+
+	return nil
+}
+
+// Wait blocks until numReplicas replicas acknowledge the given offset or the timeout elapses, and returns the
+// number of replicas that acknowledged it. When fsynced is true, only the offsets that the replicas fsynced to
+// their append-only log count. A timeout of 0 blocks indefinitely.
+func (replication *Replication) Wait(offset int64, numReplicas int, timeout time.Duration, fsynced bool) int {
+	var deadline <-chan time.Time
+	if timeout > 0 {
+		deadline = replication.clock.After(timeout)
+	}
+
+	requested := false
+	for {
+		replication.mut.Lock()
+		acked := replication.countAcked(offset, fsynced)
+		if acked >= numReplicas {
+			replication.mut.Unlock()
+			return acked
+		}
+		if !requested {
+			// Ask the replicas to acknowledge right away instead of waiting for their next periodic acknowledgement.
+			replication.backlog.Write(internal.EncodeCommand([]string{"REPLCONF", "GETACK", "*"}))
+			replication.cond.Broadcast()
+			requested = true
+		}
+		notify := replication.ackNotify
+		replication.mut.Unlock()
+
+		select {
+		case <-notify:
+		case <-deadline:
+			replication.mut.Lock()
+			defer replication.mut.Unlock()
+			return replication.countAcked(offset, fsynced)
+		}
+	}
+}
+
+// countAcked returns the number of online replicas that acknowledged the offset.
+// The caller must hold replication.mut.
+func (replication *Replication) countAcked(offset int64, fsynced bool) int {
+	acked := 0
+	for _, r := range replication.replicas {
+		ackOffset := r.ackOffset
+		if fsynced {
+			ackOffset = r.fackOffset
+		}
+		if r.state == "online" && ackOffset >= offset {
+			acked += 1
+		}
+	}
+	return acked
+}
+
+// PSync serves a PSYNC request from a replica. It responds with either a partial or full resync and then streams
+// the replication stream to the connection in the background.
+func (replication *Replication) PSync(conn *net.Conn, replId string, offset int64) error {
+	replication.mut.Lock()
+	r := replication.getReplica(conn)
+
+	canContinue := replId == replication.replId ||
+		(replId == replication.prevReplId && offset <= replication.prevOffsetLimit)
+
+	if canContinue && replication.backlog.Contains(offset) {
+		replication.syncPartialOK += 1
+		r.state = "online"
+		r.offset = offset
+		r.ackOffset = offset
+		_, err := (*conn).Write([]byte(fmt.Sprintf("+CONTINUE %s\r\n", replication.replId)))
+		replication.mut.Unlock()
+		if err != nil {
+			replication.dropReplica(r)
+			return err
+		}
+		go replication.stream(r)
+		return nil
+	}
+
+	if replId != "?" {
+		replication.syncPartialErr += 1
+	}
+	replication.syncFull += 1
+	replication.mut.Unlock()
+
+	replication.applyMut.Lock()
+	payload, syncOffset := replication.getSyncPayloadFunc()
+	replication.mut.Lock()
+	replId = replication.replId
+	replication.mut.Unlock()
+	replication.applyMut.Unlock()
+
+	message := bytes.NewBufferString(fmt.Sprintf("+FULLRESYNC %s %d\r\n", replId, syncOffset))
+	message.WriteString(fmt.Sprintf("$%d\r\n", len(payload)))
+	message.Write(payload)
+	message.WriteString("\r\n")
+	if _, err := (*conn).Write(message.Bytes()); err != nil {
+		replication.dropReplica(r)
+		return err
+	}
+
+	replication.mut.Lock()
+	r.state = "online"
+	r.offset = syncOffset
+	r.ackOffset = syncOffset
+	replication.mut.Unlock()
+
+	go replication.stream(r)
+
+	return nil
+}
+
+// stream writes the replication stream to the replica as it grows.
+func (replication *Replication) stream(r *replica) {
+	for {
+		replication.mut.Lock()
+		for !r.closed && r.offset >= replication.backlog.End() {
+			replication.cond.Wait()
+		}
+		if r.closed {
+			replication.mut.Unlock()
+			return
+		}
+		chunk, err := replication.backlog.ReadFrom(r.offset)
+		replication.mut.Unlock()
+
+		if err != nil {
+			// The replica fell too far behind. Dropping it makes it reconnect and request a full resync.
+			replication.logger.Warn("drop replica", "replica", fmt.Sprintf("%s:%d", r.addr, r.port), logger.ErrorKey, err)
+			replication.dropReplica(r)
+			return
+		}
+
+		if _, err = (*r.conn).Write(chunk); err != nil {
+			replication.logger.Warn("drop replica", "replica", fmt.Sprintf("%s:%d", r.addr, r.port), logger.ErrorKey, err)
+			replication.dropReplica(r)
+			return
+		}
+
+		replication.mut.Lock()
+		r.offset += int64(len(chunk))
+		replication.mut.Unlock()
+	}
+}
+
+func (replication *Replication) dropReplica(r *replica) {
+	replication.mut.Lock()
+	defer replication.mut.Unlock()
+	replication.dropReplicaLocked(r)
+}
+
+// dropReplicaLocked closes the connection to the replica. The caller must hold replication.mut.
+func (replication *Replication) dropReplicaLocked(r *replica) {
+	if r.closed {
+		return
+	}
+	r.closed = true
+	delete(replication.replicas, r.conn)
+	if err := (*r.conn).Close(); err != nil {
+		replication.logger.Debug("close replica connection", logger.ErrorKey, err)
+	}
+	replication.cond.Broadcast()
+}
+
+// dropAllReplicasLocked disconnects every attached replica. The caller must hold replication.mut.
+func (replication *Replication) dropAllReplicasLocked() {
+	for _, r := range replication.replicas {
+		replication.dropReplicaLocked(r)
+	}
+}
+
+// ReplicaOf turns this server into a replica of the primary at the given address.
+// The server keeps its dataset until the primary responds with a full resync.
+func (replication *Replication) ReplicaOf(host string, port int) {
+	replication.mut.Lock()
+	defer replication.mut.Unlock()
+
+	if replication.primary != nil {
+		if replication.primary.host == host && replication.primary.port == port {
+			return
+		}
+		replication.primary.close()
+	}
+
+	// Replicas attached to this server follow a different history from now on.
+	replication.dropAllReplicasLocked()
+
+	link := &primaryLink{
+		host:   host,
+		port:   port,
+		status: linkConnect,
+		stop:   make(chan struct{}),
+	}
+	replication.primary = link
+
+	go replication.replicate(link)
+}
+
+// ReplicaOfNoOne stops replicating and promotes this server to a primary.
+// The dataset is kept, and replicas of the former primary can partially resync with this server
+// up to the offset at which it was promoted.
+func (replication *Replication) ReplicaOfNoOne() {
+	replication.mut.Lock()
+	defer replication.mut.Unlock()
+
+	if replication.primary == nil {
+		return
+	}
+	replication.primary.close()
+	replication.primary = nil
+
+	replication.prevReplId = replication.replId
+	replication.prevOffsetLimit = replication.backlog.End()
+	replication.replId = newReplId()
+	replication.currentDatabase = -1
+}
+
+// Close stops the link to the primary and disconnects all the attached replicas.
+func (replication *Replication) Close() {
+	replication.mut.Lock()
+	defer replication.mut.Unlock()
+
+	if replication.primary != nil {
+		replication.primary.close()
+	}
+	replication.dropAllReplicasLocked()
+}
+
+func (link *primaryLink) close() {
+	close(link.stop)
+	if link.conn != nil {
+		_ = link.conn.Close()
+	}
+}
+
+func (link *primaryLink) stopped() bool {
+	select {
+	case <-link.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// replicate keeps the link to the primary alive until it's stopped.
+func (replication *Replication) replicate(link *primaryLink) {
+	for {
+		err := replication.syncWithPrimary(link)
+		if link.stopped() {
+			return
+		}
+		replication.logger.Warn("replication link lost",
+			"primary", fmt.Sprintf("%s:%d", link.host, link.port), logger.ErrorKey, err)
+
+		replication.mut.Lock()
+		link.status = linkConnect
+		replication.mut.Unlock()
+
+		select {
+		case <-link.stop:
+			return
+		case <-replication.clock.After(replication.retryInterval):
+		}
+	}
+}
+
+func (replication *Replication) syncWithPrimary(link *primaryLink) error {
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(link.host, strconv.Itoa(link.port)), 5*time.Second)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	replication.mut.Lock()
+	if link.stopped() {
+		replication.mut.Unlock()
+		return errors.New("replication link stopped")
+	}
+	link.conn = conn
+	link.status = linkConnecting
+	replication.mut.Unlock()
+
+	rd := resp.NewReader(conn)
+
+	// Announce the port this server listens on, so that the primary reports it correctly.
+	if _, err = conn.Write(internal.EncodeCommand([]string{
+		"REPLCONF", "listening-port", strconv.Itoa(replication.listeningPort),
+	})); err != nil {
+		return err
+	}
+	if res, _, err := rd.ReadValue(); err != nil {
+		return err
+	} else if res.Type() == resp.Error {
+		return res.Error()
+	}
+
+	replication.mut.Lock()
+	replId, offset := replication.replId, replication.backlog.End()
+	link.status = linkSync
+	replication.mut.Unlock()
+
+	if _, err = conn.Write(internal.EncodeCommand([]string{
+		"PSYNC", replId, strconv.FormatInt(offset, 10),
+	})); err != nil {
+		return err
+	}
+	res, _, err := rd.ReadValue()
+	if err != nil {
+		return err
+	}
+	if res.Type() == resp.Error {
+		return res.Error()
+	}
+
+	fields := strings.Fields(res.String())
+	switch {
+	case len(fields) == 3 && strings.EqualFold(fields[0], "fullresync"):
+		syncOffset, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid full resync offset %s", fields[2])
+		}
+		payload, _, err := rd.ReadValue()
+		if err != nil {
+			return err
+		}
+		if err = replication.loadPayload(fields[1], syncOffset, payload.Bytes()); err != nil {
+			return err
+		}
+	case len(fields) == 2 && strings.EqualFold(fields[0], "continue"):
+		replication.mut.Lock()
+		replication.replId = fields[1]
+		replication.mut.Unlock()
+	default:
+		return fmt.Errorf("unexpected psync response %q", res.String())
+	}
+
+	replication.mut.Lock()
+	link.status = linkConnected
+	replication.mut.Unlock()
+
+	// Acknowledge the processed offset to the primary periodically, and whenever the primary asks for it.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			if _, err := conn.Write(replication.ackCommand()); err != nil {
+				return
+			}
+			select {
+			case <-done:
+				return
+			case <-replication.ackNow:
+			case <-replication.clock.After(replication.ackInterval):
+			}
+		}
+	}()
+
+	for {
+		value, _, err := rd.ReadValue()
+		if err != nil {
+			return err
+		}
+		command, err := value.MarshalRESP()
+		if err != nil {
+			return err
+		}
+
+		replication.applyMut.Lock()
+		replication.apply(&replication.streamDatabase, command)
+		replication.mut.Lock()
+		replication.backlog.Write(command)
+		replication.cond.Broadcast()
+		replication.mut.Unlock()
+		replication.applyMut.Unlock()
+	}
+}
+
+// ackCommand returns the REPLCONF ACK command that acknowledges the processed offset. When the append-only log is
+// enabled, it also acknowledges the offset that's fsynced with FACK.
+func (replication *Replication) ackCommand() []byte {
+	// Hold applyMut so that the replication offset matches the bytes written to the append-only log.
+	replication.applyMut.Lock()
+	replication.mut.Lock()
+	offset := replication.backlog.End()
+	replication.mut.Unlock()
+	written, synced, enabled := replication.aofProgressFunc()
+	replication.applyMut.Unlock()
+
+	cmd := []string{"REPLCONF", "ACK", strconv.FormatInt(offset, 10)}
+	if !enabled {
+		return internal.EncodeCommand(cmd)
+	}
+
+	// The log may not be synced yet. In that case, acknowledge the offset recorded at the previous
+	// acknowledgement once the log is synced past it.
+	fack := int64(-1)
+	if synced >= written {
+		fack = offset
+	} else if synced >= replication.pendingFack.aofOffset {
+		fack = replication.pendingFack.replOffset
+	}
+	replication.pendingFack.replOffset = offset
+	replication.pendingFack.aofOffset = written
+
+	if fack >= 0 {
+		cmd = append(cmd, "FACK", strconv.FormatInt(fack, 10))
+	}
+	return internal.EncodeCommand(cmd)
+}
+
+// loadPayload applies the full resync payload and resets the stream to the offset of the primary.
+func (replication *Replication) loadPayload(replId string, offset int64, payload []byte) error {
+	replication.applyMut.Lock()
+	defer replication.applyMut.Unlock()
+
+	database := 0
+	rd := resp.NewReader(bytes.NewReader(payload))
+	for {
+		value, _, err := rd.ReadValue()
+		if err != nil && errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("load full resync payload: %v", err)
+		}
+		command, err := value.MarshalRESP()
+		if err != nil {
+			return err
+		}
+		replication.apply(&database, command)
+	}
+
+	replication.mut.Lock()
+	defer replication.mut.Unlock()
+	replication.replId = replId
+	replication.prevReplId = ""
+	replication.backlog = NewBacklog(replication.backlogSize, offset)
+	// The dataset was replaced, so attached replicas must resync too.
+	replication.dropAllReplicasLocked()
+
+	return nil
+}
+
+// apply executes a command received from the primary. SELECT commands switch the database in place.
+func (replication *Replication) apply(database *int, command []byte) {
+	cmd, err := internal.Decode(command)
+	if err != nil || len(cmd) == 0 {
+		replication.logger.Error("decode replicated command", logger.ErrorKey, err)
+		return
+	}
+	if strings.EqualFold(cmd[0], "select") && len(cmd) == 2 {
+		if db, err := strconv.Atoi(cmd[1]); err == nil {
+			*database = db
+		}
+		return
+	}
+	if strings.EqualFold(cmd[0], "replconf") && len(cmd) > 1 && strings.EqualFold(cmd[1], "getack") {
+		select {
+		case replication.ackNow <- struct{}{}:
+		default:
+		}
+		return
+	}
+	if err = replication.handleCommandFunc(*database, command); err != nil {
+		replication.logger.Error("apply replicated command", logger.CommandKey, cmd[0],
+			logger.DatabaseKey, *database, logger.ErrorKey, err)
+	}
+}
+
+// Info returns the replication state of the server.
+func (replication *Replication) Info() internal.ReplicationInfo {
+	replication.mut.Lock()
+	defer replication.mut.Unlock()
+
+	info := internal.ReplicationInfo{
+		Role:                   "master",
+		ReplID:                 replication.replId,
+		Offset:                 replication.backlog.End(),
+		BacklogSize:            replication.backlog.Size(),
+		BacklogFirstByteOffset: replication.backlog.Start(),
+		BacklogHistLen:         replication.backlog.End() - replication.backlog.Start(),
+		SyncFull:               replication.syncFull,
+		SyncPartialOK:          replication.syncPartialOK,
+		SyncPartialErr:         replication.syncPartialErr,
+		Replicas:               make([]internal.ReplicaInfo, 0, len(replication.replicas)),
+	}
+
+	if replication.primary != nil {
+		info.Role = "slave"
+		info.PrimaryHost = replication.primary.host
+		info.PrimaryPort = replication.primary.port
+		info.LinkStatus = replication.primary.status
+	}
+
+	for _, r := range replication.replicas {
+		info.Replicas = append(info.Replicas, internal.ReplicaInfo{
+			Addr:   r.addr,
+			Port:   r.port,
+			State:  r.state,
+			Offset: r.ackOffset,
+			Lag:    replication.clock.Now().Sub(r.lastAck),
+		})
+	}
+
+	return info
+}
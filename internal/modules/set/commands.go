@@ -49,6 +49,12 @@ func handleSADD(params internal.HandlerFuncParams) ([]byte, error) {
 
 	count := set.Add(params.Command[2:])
 
+	if count > 0 {
+		if err = params.SetValues(params.Context, map[string]interface{}{key: set}); err != nil {
+			return nil, err
+		}
+	}
+
 	return []byte(fmt.Sprintf(":%d\r\n", count)), nil
 }
 
@@ -403,6 +409,15 @@ func handleSMOVE(params internal.HandlerFuncParams) ([]byte, error) {
 
 	res := sourceSet.Move(destinationSet, member)
 
+	if res > 0 {
+		if err = params.SetValues(params.Context, map[string]interface{}{
+			source:      sourceSet,
+			destination: destinationSet,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
 	return []byte(fmt.Sprintf(":%d\r\n", res)), nil
 }
 
@@ -435,6 +450,12 @@ func handleSPOP(params internal.HandlerFuncParams) ([]byte, error) {
 
 	members := set.Pop(count)
 
+	if len(members) > 0 {
+		if err = params.SetValues(params.Context, map[string]interface{}{key: set}); err != nil {
+			return nil, err
+		}
+	}
+
 	res := fmt.Sprintf("*%d", len(members))
 	for i, m := range members {
 		res = fmt.Sprintf("%s\r\n$%d\r\n%s", res, len(m), m)
@@ -507,6 +528,12 @@ func handleSREM(params internal.HandlerFuncParams) ([]byte, error) {
 
 	count := set.Remove(members)
 
+	if count > 0 {
+		if err = params.SetValues(params.Context, map[string]interface{}{key: set}); err != nil {
+			return nil, err
+		}
+	}
+
 	return []byte(fmt.Sprintf(":%d\r\n", count)), nil
 }
 
@@ -575,7 +602,17 @@ func Commands() []internal.Command {
 			Categories: []string{constants.SetCategory, constants.WriteCategory, constants.FastCategory},
 			Description: `(SADD key member [member...]) 
 Add one or more members to the set. If the set does not exist, it's created.`,
-			Sync:              true,
+			Sync:     true,
+			Arity:    -3,
+			Flags:    []string{constants.WriteFlag, constants.DenyOOMFlag, constants.FastFlag},
+			FirstKey: 1,
+			LastKey:  1,
+			KeyStep:  1,
+			KeySpecs: []internal.KeySpec{
+				{Flags: []string{constants.KeySpecRW, constants.KeySpecInsert}, BeginSearch: 1, LastKey: 0, KeyStep: 1},
+			},
+			Complexity:        "O(1) for each element added, so O(N) to add N elements.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: saddKeyFunc,
 			HandlerFunc:       handleSADD,
 		},
@@ -585,6 +622,13 @@ Add one or more members to the set. If the set does not exist, it's created.`,
 			Categories:        []string{constants.SetCategory, constants.WriteCategory, constants.FastCategory},
 			Description:       "(SCARD key) Returns the cardinality of the set.",
 			Sync:              false,
+			Arity:             2,
+			Flags:             []string{constants.WriteFlag, constants.FastFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(1)",
+			Since:             "0.1.0",
 			KeyExtractionFunc: scardKeyFunc,
 			HandlerFunc:       handleSCARD,
 		},
@@ -596,6 +640,13 @@ Add one or more members to the set. If the set does not exist, it's created.`,
 If the first key provided is the only valid set, then this key's set will be returned as the result.
 All keys that are non-existed or hold values that are not sets will be skipped.`,
 			Sync:              false,
+			Arity:             -2,
+			Flags:             []string{constants.ReadOnlyFlag},
+			FirstKey:          1,
+			LastKey:           -1,
+			KeyStep:           1,
+			Complexity:        "O(N) where N is the total number of elements in all given sets.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: sdiffKeyFunc,
 			HandlerFunc:       handleSDIFF,
 		},
@@ -605,7 +656,18 @@ All keys that are non-existed or hold values that are not sets will be skipped.`
 			Categories: []string{constants.SetCategory, constants.WriteCategory, constants.SlowCategory},
 			Description: `(SDIFFSTORE destination key [key...]) Works the same as SDIFF but also stores the result at 'destination'.
 Returns the cardinality of the new set.`,
-			Sync:              true,
+			Sync:     true,
+			Arity:    -3,
+			Flags:    []string{constants.WriteFlag, constants.DenyOOMFlag},
+			FirstKey: 1,
+			LastKey:  -1,
+			KeyStep:  1,
+			KeySpecs: []internal.KeySpec{
+				{Flags: []string{constants.KeySpecOW, constants.KeySpecUpdate}, BeginSearch: 1, LastKey: 0, KeyStep: 1},
+				{Flags: []string{constants.KeySpecRO, constants.KeySpecAccess}, BeginSearch: 2, LastKey: -1, KeyStep: 1},
+			},
+			Complexity:        "O(N) where N is the total number of elements in all given sets.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: sdiffstoreKeyFunc,
 			HandlerFunc:       handleSDIFFSTORE,
 		},
@@ -615,6 +677,13 @@ Returns the cardinality of the new set.`,
 			Categories:        []string{constants.SetCategory, constants.WriteCategory, constants.SlowCategory},
 			Description:       "(SINTER key [key...]) Returns the intersection of multiple sets.",
 			Sync:              false,
+			Arity:             -2,
+			Flags:             []string{constants.WriteFlag},
+			FirstKey:          1,
+			LastKey:           -1,
+			KeyStep:           1,
+			Complexity:        "O(N*M) worst case where N is the cardinality of the smallest set and M is the number of sets.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: sinterKeyFunc,
 			HandlerFunc:       handleSINTER,
 		},
@@ -624,16 +693,34 @@ Returns the cardinality of the new set.`,
 			Categories: []string{constants.SetCategory, constants.ReadCategory, constants.SlowCategory},
 			Description: `(SINTERCARD key [key...] [LIMIT limit]) 
 Returns the cardinality of the intersection between multiple sets.`,
-			Sync:              false,
+			Sync:  false,
+			Arity: -2,
+			Flags: []string{constants.ReadOnlyFlag},
+			KeySpecs: []internal.KeySpec{
+				{Flags: []string{constants.KeySpecRO, constants.KeySpecAccess, constants.KeySpecIncomplete}, BeginSearch: 1, LastKey: -1, KeyStep: 1},
+			},
+			Complexity:        "O(N*M) worst case where N is the cardinality of the smallest set and M is the number of sets.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: sintercardKeyFunc,
 			HandlerFunc:       handleSINTERCARD,
 		},
 		{
-			Command:           "sinterstore",
-			Module:            constants.SetModule,
-			Categories:        []string{constants.SetCategory, constants.WriteCategory, constants.SlowCategory},
-			Description:       "(SINTERSTORE destination key [key...]) Stores the intersection of multiple sets at the destination key.",
-			Sync:              true,
+			Command:     "sinterstore",
+			Module:      constants.SetModule,
+			Categories:  []string{constants.SetCategory, constants.WriteCategory, constants.SlowCategory},
+			Description: "(SINTERSTORE destination key [key...]) Stores the intersection of multiple sets at the destination key.",
+			Sync:        true,
+			Arity:       -3,
+			Flags:       []string{constants.WriteFlag, constants.DenyOOMFlag},
+			FirstKey:    1,
+			LastKey:     -1,
+			KeyStep:     1,
+			KeySpecs: []internal.KeySpec{
+				{Flags: []string{constants.KeySpecOW, constants.KeySpecUpdate}, BeginSearch: 1, LastKey: 0, KeyStep: 1},
+				{Flags: []string{constants.KeySpecRO, constants.KeySpecAccess}, BeginSearch: 2, LastKey: -1, KeyStep: 1},
+			},
+			Complexity:        "O(N*M) worst case where N is the cardinality of the smallest set and M is the number of sets.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: sinterstoreKeyFunc,
 			HandlerFunc:       handleSINTERSTORE,
 		},
@@ -643,6 +730,13 @@ Returns the cardinality of the intersection between multiple sets.`,
 			Categories:        []string{constants.SetCategory, constants.ReadCategory, constants.FastCategory},
 			Description:       "(SISMEMBER key member) Returns if member is contained in the set.",
 			Sync:              false,
+			Arity:             3,
+			Flags:             []string{constants.ReadOnlyFlag, constants.FastFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(1)",
+			Since:             "0.1.0",
 			KeyExtractionFunc: sismemberKeyFunc,
 			HandlerFunc:       handleSISMEMBER,
 		},
@@ -652,6 +746,13 @@ Returns the cardinality of the intersection between multiple sets.`,
 			Categories:        []string{constants.SetCategory, constants.ReadCategory, constants.SlowCategory},
 			Description:       "(SMEMBERS key) Returns all members of a set.",
 			Sync:              false,
+			Arity:             2,
+			Flags:             []string{constants.ReadOnlyFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(N) where N is the set cardinality.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: smembersKeyFunc,
 			HandlerFunc:       handleSMEMBERS,
 		},
@@ -661,25 +762,53 @@ Returns the cardinality of the intersection between multiple sets.`,
 			Categories:        []string{constants.SetCategory, constants.ReadCategory, constants.FastCategory},
 			Description:       "(SMISMEMBER key member [member...]) Returns if multiple members are in the set.",
 			Sync:              false,
+			Arity:             -3,
+			Flags:             []string{constants.ReadOnlyFlag, constants.FastFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(N) where N is the number of elements being checked for membership.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: smismemberKeyFunc,
 			HandlerFunc:       handleSMISMEMBER,
 		},
 
 		{
-			Command:           "smove",
-			Module:            constants.SetModule,
-			Categories:        []string{constants.SetCategory, constants.WriteCategory, constants.FastCategory},
-			Description:       "(SMOVE source destination member) Moves a member from source set to destination set.",
-			Sync:              true,
+			Command:     "smove",
+			Module:      constants.SetModule,
+			Categories:  []string{constants.SetCategory, constants.WriteCategory, constants.FastCategory},
+			Description: "(SMOVE source destination member) Moves a member from source set to destination set.",
+			Sync:        true,
+			Arity:       4,
+			Flags:       []string{constants.WriteFlag, constants.FastFlag},
+			FirstKey:    1,
+			LastKey:     2,
+			KeyStep:     1,
+			KeySpecs: []internal.KeySpec{
+				{Flags: []string{constants.KeySpecRW, constants.KeySpecDelete}, BeginSearch: 1, LastKey: 0, KeyStep: 1},
+				{Flags: []string{constants.KeySpecRW, constants.KeySpecInsert}, BeginSearch: 2, LastKey: 0, KeyStep: 1},
+			},
+			Complexity:        "O(1)",
+			Since:             "0.1.0",
 			KeyExtractionFunc: smoveKeyFunc,
 			HandlerFunc:       handleSMOVE,
 		},
 		{
-			Command:           "spop",
-			Module:            constants.SetModule,
-			Categories:        []string{constants.SetCategory, constants.WriteCategory, constants.SlowCategory},
-			Description:       "(SPOP key [count]) Returns and removes one or more random members from the set.",
-			Sync:              true,
+			Command:     "spop",
+			Module:      constants.SetModule,
+			Categories:  []string{constants.SetCategory, constants.WriteCategory, constants.SlowCategory},
+			Description: "(SPOP key [count]) Returns and removes one or more random members from the set.",
+			Sync:        true,
+			Arity:       -2,
+			Flags:       []string{constants.WriteFlag},
+			FirstKey:    1,
+			LastKey:     1,
+			KeyStep:     1,
+			KeySpecs: []internal.KeySpec{
+				{Flags: []string{constants.KeySpecRW, constants.KeySpecAccess, constants.KeySpecDelete}, BeginSearch: 1, LastKey: 0, KeyStep: 1},
+			},
+			Complexity:        "O(N) where N is the number of elements returned.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: spopKeyFunc,
 			HandlerFunc:       handleSPOP,
 		},
@@ -689,15 +818,32 @@ Returns the cardinality of the intersection between multiple sets.`,
 			Categories:        []string{constants.SetCategory, constants.ReadCategory, constants.SlowCategory},
 			Description:       "(SRANDMEMBER key [count]) Returns one or more random members from the set without removing them.",
 			Sync:              false,
+			Arity:             -2,
+			Flags:             []string{constants.ReadOnlyFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(N) where N is the number of elements returned.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: srandmemberKeyFunc,
 			HandlerFunc:       handleSRANDMEMBER,
 		},
 		{
-			Command:           "srem",
-			Module:            constants.SetModule,
-			Categories:        []string{constants.SetCategory, constants.WriteCategory, constants.FastCategory},
-			Description:       "(SREM key member [member...]) Remove one or more members from a set.",
-			Sync:              true,
+			Command:     "srem",
+			Module:      constants.SetModule,
+			Categories:  []string{constants.SetCategory, constants.WriteCategory, constants.FastCategory},
+			Description: "(SREM key member [member...]) Remove one or more members from a set.",
+			Sync:        true,
+			Arity:       -3,
+			Flags:       []string{constants.WriteFlag, constants.FastFlag},
+			FirstKey:    1,
+			LastKey:     1,
+			KeyStep:     1,
+			KeySpecs: []internal.KeySpec{
+				{Flags: []string{constants.KeySpecRW, constants.KeySpecDelete}, BeginSearch: 1, LastKey: 0, KeyStep: 1},
+			},
+			Complexity:        "O(N) where N is the number of members to be removed.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: sremKeyFunc,
 			HandlerFunc:       handleSREM,
 		},
@@ -707,15 +853,33 @@ Returns the cardinality of the intersection between multiple sets.`,
 			Categories:        []string{constants.SetCategory, constants.ReadCategory, constants.SlowCategory},
 			Description:       "(SUNION key [key...]) Returns the members of the set resulting from the union of the provided sets.",
 			Sync:              false,
+			Arity:             -2,
+			Flags:             []string{constants.ReadOnlyFlag},
+			FirstKey:          1,
+			LastKey:           -1,
+			KeyStep:           1,
+			Complexity:        "O(N) where N is the total number of elements in all given sets.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: sunionKeyFunc,
 			HandlerFunc:       handleSUNION,
 		},
 		{
-			Command:           "sunionstore",
-			Module:            constants.SetModule,
-			Categories:        []string{constants.SetCategory, constants.WriteCategory, constants.SlowCategory},
-			Description:       "(SUNIONSTORE destination key [key...]) Stores the union of the given sets into destination.",
-			Sync:              true,
+			Command:     "sunionstore",
+			Module:      constants.SetModule,
+			Categories:  []string{constants.SetCategory, constants.WriteCategory, constants.SlowCategory},
+			Description: "(SUNIONSTORE destination key [key...]) Stores the union of the given sets into destination.",
+			Sync:        true,
+			Arity:       -3,
+			Flags:       []string{constants.WriteFlag, constants.DenyOOMFlag},
+			FirstKey:    1,
+			LastKey:     -1,
+			KeyStep:     1,
+			KeySpecs: []internal.KeySpec{
+				{Flags: []string{constants.KeySpecOW, constants.KeySpecUpdate}, BeginSearch: 1, LastKey: 0, KeyStep: 1},
+				{Flags: []string{constants.KeySpecRO, constants.KeySpecAccess}, BeginSearch: 2, LastKey: -1, KeyStep: 1},
+			},
+			Complexity:        "O(N) where N is the total number of elements in all given sets.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: sunionstoreKeyFunc,
 			HandlerFunc:       handleSUNIONSTORE,
 		},
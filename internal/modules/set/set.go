@@ -23,6 +23,7 @@ import (
 type Set struct {
 	members map[string]interface{}
 	length  int
+	size    int64 // The estimated size of the members in bytes.
 }
 
 func NewSet(elems []string) *Set {
@@ -39,6 +40,7 @@ func (set *Set) Add(elems []string) int {
 	for _, e := range elems {
 		if !set.Contains(e) {
 			set.members[e] = struct{}{}
+			set.size += internal.EstimateMemberSize(e)
 			count += 1
 		}
 	}
@@ -62,6 +64,11 @@ func (set *Set) Cardinality() int {
 	return set.length
 }
 
+// Size returns the estimated number of bytes used by the set. It's maintained as members are added and removed.
+func (set *Set) Size() int64 {
+	return set.size
+}
+
 func (set *Set) GetRandom(count int) []string {
 	keys := set.GetAll()
 
@@ -105,6 +112,7 @@ func (set *Set) Remove(elems []string) int {
 	for _, e := range elems {
 		if set.Get(e) != nil {
 			delete(set.members, e)
+			set.size -= internal.EstimateMemberSize(e)
 			count += 1
 		}
 	}
@@ -609,6 +609,10 @@ func handleZMPOP(params internal.HandlerFuncParams) ([]byte, error) {
 				return nil, err
 			}
 
+			if err = params.SetValues(params.Context, map[string]interface{}{keys.WriteKeys[i]: v}); err != nil {
+				return nil, err
+			}
+
 			res := fmt.Sprintf("*%d", popped.Cardinality())
 
 			for _, m := range popped.GetAll() {
@@ -663,6 +667,12 @@ func handleZPOP(params internal.HandlerFuncParams) ([]byte, error) {
 		return nil, err
 	}
 
+	if popped.Cardinality() > 0 {
+		if err = params.SetValues(params.Context, map[string]interface{}{key: set}); err != nil {
+			return nil, err
+		}
+	}
+
 	res := fmt.Sprintf("*%d", popped.Cardinality())
 	for _, m := range popped.GetAll() {
 		res += fmt.Sprintf("\r\n*2\r\n$%d\r\n%s\r\n+%s",
@@ -837,6 +847,12 @@ func handleZREM(params internal.HandlerFuncParams) ([]byte, error) {
 		}
 	}
 
+	if deletedCount > 0 {
+		if err = params.SetValues(params.Context, map[string]interface{}{key: set}); err != nil {
+			return nil, err
+		}
+	}
+
 	return []byte(fmt.Sprintf(":%d\r\n", deletedCount)), nil
 }
 
@@ -904,6 +920,12 @@ func handleZREMRANGEBYSCORE(params internal.HandlerFuncParams) ([]byte, error) {
 		}
 	}
 
+	if deletedCount > 0 {
+		if err = params.SetValues(params.Context, map[string]interface{}{key: set}); err != nil {
+			return nil, err
+		}
+	}
+
 	return []byte(fmt.Sprintf(":%d\r\n", deletedCount)), nil
 }
 
@@ -965,6 +987,12 @@ func handleZREMRANGEBYRANK(params internal.HandlerFuncParams) ([]byte, error) {
 		}
 	}
 
+	if deletedCount > 0 {
+		if err = params.SetValues(params.Context, map[string]interface{}{key: set}); err != nil {
+			return nil, err
+		}
+	}
+
 	return []byte(fmt.Sprintf(":%d\r\n", deletedCount)), nil
 }
 
@@ -1008,6 +1036,12 @@ func handleZREMRANGEBYLEX(params internal.HandlerFuncParams) ([]byte, error) {
 		}
 	}
 
+	if deletedCount > 0 {
+		if err = params.SetValues(params.Context, map[string]interface{}{key: set}); err != nil {
+			return nil, err
+		}
+	}
+
 	return []byte(fmt.Sprintf(":%d\r\n", deletedCount)), nil
 }
 
@@ -1381,6 +1415,13 @@ Adds all the specified members with the specified scores to the sorted set at th
 "CH" modifies the result to return total number of members changed + added, instead of only new members added.
 "INCR" modifies the command to act like ZINCRBY, only one score/member pair can be specified in this mode.`,
 			Sync:              true,
+			Arity:             -4,
+			Flags:             []string{constants.WriteFlag, constants.DenyOOMFlag, constants.FastFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(log(N)) for each item added, where N is the number of elements in the sorted set.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: zaddKeyFunc,
 			HandlerFunc:       handleZADD,
 		},
@@ -1392,6 +1433,13 @@ Adds all the specified members with the specified scores to the sorted set at th
 If the key does not exist, 0 is returned, otherwise the cardinality of the sorted set is returned.
 If the key holds a value that is not a sorted set, this command will return an error.`,
 			Sync:              false,
+			Arity:             2,
+			Flags:             []string{constants.ReadOnlyFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(1)",
+			Since:             "0.1.0",
 			KeyExtractionFunc: zcardKeyFunc,
 			HandlerFunc:       handleZCARD,
 		},
@@ -1404,6 +1452,13 @@ Returns the number of elements in the sorted set key with scores in the range of
 If the key does not exist, a count of 0 is returned, otherwise return the count.
 If the key holds a value that is not a sorted set, an error is returned.`,
 			Sync:              false,
+			Arity:             4,
+			Flags:             []string{constants.ReadOnlyFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(log(N)) with N being the number of elements in the sorted set.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: zcountKeyFunc,
 			HandlerFunc:       handleZCOUNT,
 		},
@@ -1413,7 +1468,14 @@ If the key holds a value that is not a sorted set, an error is returned.`,
 			Categories: []string{constants.SortedSetCategory, constants.ReadCategory, constants.SlowCategory},
 			Description: `(ZDIFF key [key...] [WITHSCORES]) 
 Computes the difference between all the sorted sets specified in the list of keys and returns the result.`,
-			Sync:              false,
+			Sync:  false,
+			Arity: -2,
+			Flags: []string{constants.ReadOnlyFlag},
+			KeySpecs: []internal.KeySpec{
+				{Flags: []string{constants.KeySpecRO, constants.KeySpecAccess, constants.KeySpecIncomplete}, BeginSearch: 1, LastKey: -1, KeyStep: 1},
+			},
+			Complexity:        "O(L + (N-K)log(N)) worst case where L is the total number of elements in all the sets, N is the size of the first set, and K is the size of the result set.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: zdiffKeyFunc,
 			HandlerFunc:       handleZDIFF,
 		},
@@ -1424,7 +1486,18 @@ Computes the difference between all the sorted sets specified in the list of key
 			Description: `(ZDIFFSTORE destination key [key...]). 
 Computes the difference between all the sorted sets specifies in the list of keys. Stores the result in destination.
 If the base set (first key) does not exist, return 0, otherwise, return the cardinality of the diff.`,
-			Sync:              true,
+			Sync:     true,
+			Arity:    -3,
+			Flags:    []string{constants.WriteFlag, constants.DenyOOMFlag},
+			FirstKey: 1,
+			LastKey:  -1,
+			KeyStep:  1,
+			KeySpecs: []internal.KeySpec{
+				{Flags: []string{constants.KeySpecOW, constants.KeySpecUpdate}, BeginSearch: 1, LastKey: 0, KeyStep: 1},
+				{Flags: []string{constants.KeySpecRO, constants.KeySpecAccess}, BeginSearch: 2, LastKey: -1, KeyStep: 1},
+			},
+			Complexity:        "O(L + (N-K)log(N)) worst case where L is the total number of elements in all the sets, N is the size of the first set, and K is the size of the result set.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: zdiffstoreKeyFunc,
 			HandlerFunc:       handleZDIFFSTORE,
 		},
@@ -1436,6 +1509,13 @@ If the base set (first key) does not exist, return 0, otherwise, return the card
 Increments the score of the specified sorted set's member by the increment. If the member does not exist, it is created.
 If the key does not exist, it is created with new sorted set and the member added with the increment as its score.`,
 			Sync:              true,
+			Arity:             4,
+			Flags:             []string{constants.WriteFlag, constants.DenyOOMFlag, constants.FastFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(log(N)) where N is the number of elements in the sorted set.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: zincrbyKeyFunc,
 			HandlerFunc:       handleZINCRBY,
 		},
@@ -1445,7 +1525,14 @@ If the key does not exist, it is created with new sorted set and the member adde
 			Categories: []string{constants.SortedSetCategory, constants.ReadCategory, constants.SlowCategory},
 			Description: `(ZINTER key [key ...] [WEIGHTS weight [weight ...]] [AGGREGATE <SUM | MIN | MAX>] [WITHSCORES]).
 Computes the intersection of the sets in the keys, with weights, aggregate and scores`,
-			Sync:              false,
+			Sync:  false,
+			Arity: -2,
+			Flags: []string{constants.ReadOnlyFlag},
+			KeySpecs: []internal.KeySpec{
+				{Flags: []string{constants.KeySpecRO, constants.KeySpecAccess, constants.KeySpecIncomplete}, BeginSearch: 1, LastKey: -1, KeyStep: 1},
+			},
+			Complexity:        "O(N*K)+O(M*log(M)) worst case with N being the smallest input sorted set, K being the number of input sorted sets and M being the number of elements in the resulting sorted set.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: zinterKeyFunc,
 			HandlerFunc:       handleZINTER,
 		},
@@ -1456,7 +1543,15 @@ Computes the intersection of the sets in the keys, with weights, aggregate and s
 			Description: `
 (ZINTERSTORE destination key [key ...] [WEIGHTS weight [weight ...]] [AGGREGATE <SUM | MIN | MAX>] [WITHSCORES]).
 Computes the intersection of the sets in the keys, with weights, aggregate and scores. The result is stored in destination.`,
-			Sync:              true,
+			Sync:  true,
+			Arity: -3,
+			Flags: []string{constants.WriteFlag, constants.DenyOOMFlag},
+			KeySpecs: []internal.KeySpec{
+				{Flags: []string{constants.KeySpecOW, constants.KeySpecUpdate}, BeginSearch: 1, LastKey: 0, KeyStep: 1},
+				{Flags: []string{constants.KeySpecRO, constants.KeySpecAccess, constants.KeySpecIncomplete}, BeginSearch: 2, LastKey: -1, KeyStep: 1},
+			},
+			Complexity:        "O(N*K)+O(M*log(M)) worst case with N being the smallest input sorted set, K being the number of input sorted sets and M being the number of elements in the resulting sorted set.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: zinterstoreKeyFunc,
 			HandlerFunc:       handleZINTERSTORE,
 		},
@@ -1467,7 +1562,14 @@ Computes the intersection of the sets in the keys, with weights, aggregate and s
 			Description: `(ZMPOP key [key ...] <MIN | MAX> [COUNT count])
 Pop a 'count' elements from multiple sorted sets. MIN or MAX determines whether to pop elements with the lowest or highest scores
 respectively.`,
-			Sync:              true,
+			Sync:  true,
+			Arity: -2,
+			Flags: []string{constants.WriteFlag},
+			KeySpecs: []internal.KeySpec{
+				{Flags: []string{constants.KeySpecRW, constants.KeySpecAccess, constants.KeySpecDelete, constants.KeySpecIncomplete}, BeginSearch: 1, LastKey: -1, KeyStep: 1},
+			},
+			Complexity:        "O(K) + O(M*log(N)) where K is the number of provided keys, N being the number of elements in the sorted set, and M being the number of elements popped.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: zmpopKeyFunc,
 			HandlerFunc:       handleZMPOP,
 		},
@@ -1479,6 +1581,13 @@ respectively.`,
 Returns the associated scores of the specified member in the sorted set. 
 Returns nil for members that do not exist in the set`,
 			Sync:              false,
+			Arity:             -3,
+			Flags:             []string{constants.ReadOnlyFlag, constants.FastFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(N) where N is the number of members being requested.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: zmscoreKeyFunc,
 			HandlerFunc:       handleZMSCORE,
 		},
@@ -1488,7 +1597,17 @@ Returns nil for members that do not exist in the set`,
 			Categories: []string{constants.SortedSetCategory, constants.WriteCategory, constants.SlowCategory},
 			Description: `(ZPOPMAX key [count])
 Removes and returns 'count' number of members in the sorted set with the highest scores. Default count is 1.`,
-			Sync:              true,
+			Sync:     true,
+			Arity:    -2,
+			Flags:    []string{constants.WriteFlag},
+			FirstKey: 1,
+			LastKey:  1,
+			KeyStep:  1,
+			KeySpecs: []internal.KeySpec{
+				{Flags: []string{constants.KeySpecRW, constants.KeySpecAccess, constants.KeySpecDelete}, BeginSearch: 1, LastKey: 0, KeyStep: 1},
+			},
+			Complexity:        "O(log(N)*M) with N being the number of elements in the sorted set, and M being the number of elements popped.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: zpopKeyFunc,
 			HandlerFunc:       handleZPOP,
 		},
@@ -1498,7 +1617,17 @@ Removes and returns 'count' number of members in the sorted set with the highest
 			Categories: []string{constants.SortedSetCategory, constants.WriteCategory, constants.SlowCategory},
 			Description: `(ZPOPMIN key [count])
 Removes and returns 'count' number of members in the sorted set with the lowest scores. Default count is 1.`,
-			Sync:              true,
+			Sync:     true,
+			Arity:    -2,
+			Flags:    []string{constants.WriteFlag},
+			FirstKey: 1,
+			LastKey:  1,
+			KeyStep:  1,
+			KeySpecs: []internal.KeySpec{
+				{Flags: []string{constants.KeySpecRW, constants.KeySpecAccess, constants.KeySpecDelete}, BeginSearch: 1, LastKey: 0, KeyStep: 1},
+			},
+			Complexity:        "O(log(N)*M) with N being the number of elements in the sorted set, and M being the number of elements popped.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: zpopKeyFunc,
 			HandlerFunc:       handleZPOP,
 		},
@@ -1511,6 +1640,13 @@ Return a list of length equivalent to count containing random members of the sor
 If count is negative, repeated elements are allowed. If count is positive, the returned elements will be distinct.
 WITHSCORES modifies the result to include scores in the result.`,
 			Sync:              false,
+			Arity:             -2,
+			Flags:             []string{constants.ReadOnlyFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(N) where N is the number of members returned.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: zrandmemberKeyFunc,
 			HandlerFunc:       handleZRANDMEMBER,
 		},
@@ -1521,6 +1657,13 @@ WITHSCORES modifies the result to include scores in the result.`,
 			Description: `(ZRANK key member [WITHSCORE])
 Returns the rank of the specified member in the sorted set. WITHSCORE modifies the result to also return the score.`,
 			Sync:              false,
+			Arity:             -3,
+			Flags:             []string{constants.ReadOnlyFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(log(N))",
+			Since:             "0.1.0",
 			KeyExtractionFunc: zrankKeyFunc,
 			HandlerFunc:       handleZRANK,
 		},
@@ -1532,6 +1675,13 @@ Returns the rank of the specified member in the sorted set. WITHSCORE modifies t
 Returns the rank of the member in the sorted set in reverse order. 
 WITHSCORE modifies the result to include the score.`,
 			Sync:              false,
+			Arity:             -3,
+			Flags:             []string{constants.ReadOnlyFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(log(N))",
+			Since:             "0.1.0",
 			KeyExtractionFunc: zrevrankKeyFunc,
 			HandlerFunc:       handleZRANK,
 		},
@@ -1541,7 +1691,17 @@ WITHSCORE modifies the result to include the score.`,
 			Categories: []string{constants.SortedSetCategory, constants.WriteCategory, constants.FastCategory},
 			Description: `(ZREM key member [member ...]) Removes the listed members from the sorted set.
 Returns the number of elements removed.`,
-			Sync:              true,
+			Sync:     true,
+			Arity:    -3,
+			Flags:    []string{constants.WriteFlag, constants.FastFlag},
+			FirstKey: 1,
+			LastKey:  1,
+			KeyStep:  1,
+			KeySpecs: []internal.KeySpec{
+				{Flags: []string{constants.KeySpecRW, constants.KeySpecDelete}, BeginSearch: 1, LastKey: 0, KeyStep: 1},
+			},
+			Complexity:        "O(M*log(N)) with N being the number of elements in the sorted set and M the number of elements to be removed.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: zremKeyFunc,
 			HandlerFunc:       handleZREM,
 		},
@@ -1551,15 +1711,32 @@ Returns the number of elements removed.`,
 			Categories:        []string{constants.SortedSetCategory, constants.ReadCategory, constants.FastCategory},
 			Description:       `(ZSCORE key member) Returns the score of the member in the sorted set.`,
 			Sync:              false,
+			Arity:             3,
+			Flags:             []string{constants.ReadOnlyFlag, constants.FastFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(1)",
+			Since:             "0.1.0",
 			KeyExtractionFunc: zscoreKeyFunc,
 			HandlerFunc:       handleZSCORE,
 		},
 		{
-			Command:           "zremrangebylex",
-			Module:            constants.SortedSetModule,
-			Categories:        []string{constants.SortedSetCategory, constants.WriteCategory, constants.SlowCategory},
-			Description:       `(ZREMRANGEBYLEX key min max) Removes the elements in the lexicographical range between min and max`,
-			Sync:              true,
+			Command:     "zremrangebylex",
+			Module:      constants.SortedSetModule,
+			Categories:  []string{constants.SortedSetCategory, constants.WriteCategory, constants.SlowCategory},
+			Description: `(ZREMRANGEBYLEX key min max) Removes the elements in the lexicographical range between min and max`,
+			Sync:        true,
+			Arity:       4,
+			Flags:       []string{constants.WriteFlag},
+			FirstKey:    1,
+			LastKey:     1,
+			KeyStep:     1,
+			KeySpecs: []internal.KeySpec{
+				{Flags: []string{constants.KeySpecRW, constants.KeySpecDelete}, BeginSearch: 1, LastKey: 0, KeyStep: 1},
+			},
+			Complexity:        "O(log(N)+M) with N being the number of elements in the sorted set and M the number of elements removed by the operation.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: zremrangebylexKeyFunc,
 			HandlerFunc:       handleZREMRANGEBYLEX,
 		},
@@ -1569,16 +1746,36 @@ Returns the number of elements removed.`,
 			Categories: []string{constants.SortedSetCategory, constants.WriteCategory, constants.SlowCategory},
 			Description: `(ZREMRANGEBYRANK key start stop) Removes the elements in the rank range between start and stop.
 The elements are ordered from lowest score to highest score`,
-			Sync:              true,
+			Sync:     true,
+			Arity:    4,
+			Flags:    []string{constants.WriteFlag},
+			FirstKey: 1,
+			LastKey:  1,
+			KeyStep:  1,
+			KeySpecs: []internal.KeySpec{
+				{Flags: []string{constants.KeySpecRW, constants.KeySpecDelete}, BeginSearch: 1, LastKey: 0, KeyStep: 1},
+			},
+			Complexity:        "O(log(N)+M) with N being the number of elements in the sorted set and M the number of elements removed by the operation.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: zremrangebyrankKeyFunc,
 			HandlerFunc:       handleZREMRANGEBYRANK,
 		},
 		{
-			Command:           "zremrangebyscore",
-			Module:            constants.SortedSetModule,
-			Categories:        []string{constants.SortedSetCategory, constants.WriteCategory, constants.SlowCategory},
-			Description:       `(ZREMRANGEBYSCORE key min max) Removes the elements whose scores are in the range between min and max`,
-			Sync:              true,
+			Command:     "zremrangebyscore",
+			Module:      constants.SortedSetModule,
+			Categories:  []string{constants.SortedSetCategory, constants.WriteCategory, constants.SlowCategory},
+			Description: `(ZREMRANGEBYSCORE key min max) Removes the elements whose scores are in the range between min and max`,
+			Sync:        true,
+			Arity:       4,
+			Flags:       []string{constants.WriteFlag},
+			FirstKey:    1,
+			LastKey:     1,
+			KeyStep:     1,
+			KeySpecs: []internal.KeySpec{
+				{Flags: []string{constants.KeySpecRW, constants.KeySpecDelete}, BeginSearch: 1, LastKey: 0, KeyStep: 1},
+			},
+			Complexity:        "O(log(N)+M) with N being the number of elements in the sorted set and M the number of elements removed by the operation.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: zremrangebyscoreKeyFunc,
 			HandlerFunc:       handleZREMRANGEBYSCORE,
 		},
@@ -1590,6 +1787,13 @@ The elements are ordered from lowest score to highest score`,
 lexicographical range between min and max. Returns 0, if the keys does not exist or if all the members do not have
 the same score. If the value held at key is not a sorted set, an error is returned.`,
 			Sync:              false,
+			Arity:             4,
+			Flags:             []string{constants.ReadOnlyFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(log(N)) with N being the number of elements in the sorted set.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: zlexcountKeyFunc,
 			HandlerFunc:       handleZLEXCOUNT,
 		},
@@ -1600,6 +1804,13 @@ the same score. If the value held at key is not a sorted set, an error is return
 			Description: `(ZRANGE key start stop [BYSCORE | BYLEX] [REV] [LIMIT offset count]
   [WITHSCORES]) Returns the range of elements in the sorted set.`,
 			Sync:              false,
+			Arity:             -4,
+			Flags:             []string{constants.ReadOnlyFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(log(N)+M) with N being the number of elements in the sorted set and M the number of elements returned.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: zrangeKeyCount,
 			HandlerFunc:       handleZRANGE,
 		},
@@ -1609,7 +1820,18 @@ the same score. If the value held at key is not a sorted set, an error is return
 			Categories: []string{constants.SortedSetCategory, constants.WriteCategory, constants.SlowCategory},
 			Description: `ZRANGESTORE destination source start stop [BYSCORE | BYLEX] [REV] [LIMIT offset count]
   [WITHSCORES] Retrieve the range of elements in the sorted set and store it in destination.`,
-			Sync:              true,
+			Sync:     true,
+			Arity:    -5,
+			Flags:    []string{constants.WriteFlag, constants.DenyOOMFlag},
+			FirstKey: 1,
+			LastKey:  2,
+			KeyStep:  1,
+			KeySpecs: []internal.KeySpec{
+				{Flags: []string{constants.KeySpecOW, constants.KeySpecUpdate}, BeginSearch: 1, LastKey: 0, KeyStep: 1},
+				{Flags: []string{constants.KeySpecRO, constants.KeySpecAccess}, BeginSearch: 2, LastKey: 0, KeyStep: 1},
+			},
+			Complexity:        "O(log(N)+M) with N being the number of elements in the sorted set and M the number of elements stored into the destination key.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: zrangeStoreKeyFunc,
 			HandlerFunc:       handleZRANGESTORE,
 		},
@@ -1621,7 +1843,14 @@ the same score. If the value held at key is not a sorted set, an error is return
 [AGGREGATE <SUM | MIN | MAX>] [WITHSCORES]) Return the union of the sorted sets in keys. The scores of each member of 
 a sorted set are multiplied by the corresponding weight in WEIGHTS. Aggregate determines how the scores are combined.
 WITHSCORES option determines whether to return the result with scores included.`,
-			Sync:              false,
+			Sync:  false,
+			Arity: -2,
+			Flags: []string{constants.ReadOnlyFlag},
+			KeySpecs: []internal.KeySpec{
+				{Flags: []string{constants.KeySpecRO, constants.KeySpecAccess, constants.KeySpecIncomplete}, BeginSearch: 1, LastKey: -1, KeyStep: 1},
+			},
+			Complexity:        "O(N)+O(M*log(M)) with N being the sum of the sizes of the input sorted sets, and M being the number of elements in the resulting sorted set.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: zunionKeyFunc,
 			HandlerFunc:       handleZUNION,
 		},
@@ -1633,7 +1862,15 @@ WITHSCORES option determines whether to return the result with scores included.`
 [AGGREGATE <SUM | MIN | MAX>] [WITHSCORES]) Return the union of the sorted sets in keys. The scores of each member of 
 a sorted set are multiplied by the corresponding weight in WEIGHTS. Aggregate determines how the scores are combined.
 The resulting union is stored at the destination key.`,
-			Sync:              true,
+			Sync:  true,
+			Arity: -3,
+			Flags: []string{constants.WriteFlag, constants.DenyOOMFlag},
+			KeySpecs: []internal.KeySpec{
+				{Flags: []string{constants.KeySpecOW, constants.KeySpecUpdate}, BeginSearch: 1, LastKey: 0, KeyStep: 1},
+				{Flags: []string{constants.KeySpecRO, constants.KeySpecAccess, constants.KeySpecIncomplete}, BeginSearch: 2, LastKey: -1, KeyStep: 1},
+			},
+			Complexity:        "O(N)+O(M*log(M)) with N being the sum of the sizes of the input sorted sets, and M being the number of elements in the resulting sorted set.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: zunionstoreKeyFunc,
 			HandlerFunc:       handleZUNIONSTORE,
 		},
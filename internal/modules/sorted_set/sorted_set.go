@@ -43,6 +43,7 @@ type MemberParam struct {
 
 type SortedSet struct {
 	members map[Value]MemberObject
+	size    int64 // The estimated size of the members in bytes.
 }
 
 func NewSortedSet(members []MemberParam) *SortedSet {
@@ -50,15 +51,34 @@ func NewSortedSet(members []MemberParam) *SortedSet {
 		members: make(map[Value]MemberObject),
 	}
 	for _, m := range members {
-		s.members[m.Value] = MemberObject{
+		s.put(MemberObject{
 			Value:  m.Value,
 			Score:  m.Score,
 			Exists: true,
-		}
+		})
 	}
 	return s
 }
 
+// put adds or updates the member, and adds the size of new members to the size of the set.
+func (set *SortedSet) put(member MemberObject) {
+	if !set.Contains(member.Value) {
+		set.size += memberSize(member.Value)
+	}
+	set.members[member.Value] = member
+}
+
+// memberSize returns the estimated size of a member, including its score.
+func memberSize(v Value) int64 {
+	return internal.EstimateMemberSize(string(v)) + 8
+}
+
+// Size returns the estimated number of bytes used by the sorted set.
+// It's maintained as members are added and removed.
+func (set *SortedSet) Size() int64 {
+	return set.size
+}
+
 func (set *SortedSet) Contains(m Value) bool {
 	return set.members[m].Exists
 }
@@ -150,11 +170,11 @@ func (set *SortedSet) AddOrUpdate(
 		for _, m := range members {
 			if !set.Contains(m.Value) {
 				// If the member is not contained, add it with the increment as its Score
-				set.members[m.Value] = MemberObject{
+				set.put(MemberObject{
 					Value:  m.Value,
 					Score:  m.Score,
 					Exists: true,
-				}
+				})
 				// Always add count because this is the addition of a new element
 				count += 1
 				return count, err
@@ -162,11 +182,11 @@ func (set *SortedSet) AddOrUpdate(
 			if slices.Contains([]Score{Score(math.Inf(-1)), Score(math.Inf(1))}, set.members[m.Value].Score) {
 				return count, errors.New("cannot increment -inf or +inf")
 			}
-			set.members[m.Value] = MemberObject{
+			set.put(MemberObject{
 				Value:  m.Value,
 				Score:  set.members[m.Value].Score + m.Score,
 				Exists: true,
-			}
+			})
 			if strings.EqualFold(ch, "ch") {
 				count += 1
 			}
@@ -178,11 +198,11 @@ func (set *SortedSet) AddOrUpdate(
 		if strings.EqualFold(policy, "xx") {
 			// Only update existing elements, do not add new elements
 			if set.Contains(m.Value) {
-				set.members[m.Value] = MemberObject{
+				set.put(MemberObject{
 					Value:  m.Value,
 					Score:  compareScores(set.members[m.Value].Score, m.Score, comp),
 					Exists: true,
-				}
+				})
 				if strings.EqualFold(ch, "ch") {
 					count += 1
 				}
@@ -192,11 +212,11 @@ func (set *SortedSet) AddOrUpdate(
 		if strings.EqualFold(policy, "nx") {
 			// Only add new elements, do not update existing elements
 			if !set.Contains(m.Value) {
-				set.members[m.Value] = MemberObject{
+				set.put(MemberObject{
 					Value:  m.Value,
 					Score:  m.Score,
 					Exists: true,
-				}
+				})
 				count += 1
 			}
 			continue
@@ -205,11 +225,11 @@ func (set *SortedSet) AddOrUpdate(
 		if set.members[m.Value].Score != m.Score || !set.members[m.Value].Exists {
 			count += 1
 		}
-		set.members[m.Value] = MemberObject{
+		set.put(MemberObject{
 			Value:  m.Value,
 			Score:  compareScores(set.members[m.Value].Score, m.Score, comp),
 			Exists: true,
-		}
+		})
 	}
 	return count, nil
 }
@@ -217,6 +237,7 @@ func (set *SortedSet) AddOrUpdate(
 func (set *SortedSet) Remove(v Value) bool {
 	if set.Contains(v) {
 		delete(set.members, v)
+		set.size -= memberSize(v)
 		return true
 	}
 	return false
@@ -206,6 +206,13 @@ func Commands() []internal.Command {
 			Description: `(SETRANGE key offset value) 
 Overwrites part of a string value with another by offset. Creates the key if it doesn't exist.`,
 			Sync:              true,
+			Arity:             4,
+			Flags:             []string{constants.WriteFlag, constants.DenyOOMFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(1), not counting the time taken to copy the new string in place.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: setRangeKeyFunc,
 			HandlerFunc:       handleSetRange,
 		},
@@ -215,6 +222,13 @@ Overwrites part of a string value with another by offset. Creates the key if it
 			Categories:        []string{constants.StringCategory, constants.ReadCategory, constants.FastCategory},
 			Description:       "(STRLEN key) Returns length of the key's value if it's a string.",
 			Sync:              false,
+			Arity:             2,
+			Flags:             []string{constants.ReadOnlyFlag, constants.FastFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(1)",
+			Since:             "0.1.0",
 			KeyExtractionFunc: strLenKeyFunc,
 			HandlerFunc:       handleStrLen,
 		},
@@ -224,6 +238,13 @@ Overwrites part of a string value with another by offset. Creates the key if it
 			Categories:        []string{constants.StringCategory, constants.ReadCategory, constants.SlowCategory},
 			Description:       "(SUBSTR key start end) Returns a substring from the string value.",
 			Sync:              false,
+			Arity:             4,
+			Flags:             []string{constants.ReadOnlyFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(N) where N is the length of the returned string.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: subStrKeyFunc,
 			HandlerFunc:       handleSubStr,
 		},
@@ -233,15 +254,32 @@ Overwrites part of a string value with another by offset. Creates the key if it
 			Categories:        []string{constants.StringCategory, constants.ReadCategory, constants.SlowCategory},
 			Description:       "(GETRANGE key start end) Returns a substring from the string value.",
 			Sync:              false,
+			Arity:             4,
+			Flags:             []string{constants.ReadOnlyFlag},
+			FirstKey:          1,
+			LastKey:           1,
+			KeyStep:           1,
+			Complexity:        "O(N) where N is the length of the returned string.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: subStrKeyFunc,
 			HandlerFunc:       handleSubStr,
 		},
 		{
-			Command:           "append",
-			Module:            constants.StringModule,
-			Categories:        []string{constants.StringCategory, constants.WriteCategory, constants.SlowCategory},
-			Description:       `(APPEND key value) If key already exists and is a string, this command appends the value at the end of the string. If key does not exist it is created and set as an empty string, so APPEND will be similar to [SET] in this special case.`,
-			Sync:              true,
+			Command:     "append",
+			Module:      constants.StringModule,
+			Categories:  []string{constants.StringCategory, constants.WriteCategory, constants.SlowCategory},
+			Description: `(APPEND key value) If key already exists and is a string, this command appends the value at the end of the string. If key does not exist it is created and set as an empty string, so APPEND will be similar to [SET] in this special case.`,
+			Sync:        true,
+			Arity:       3,
+			Flags:       []string{constants.WriteFlag, constants.DenyOOMFlag},
+			FirstKey:    1,
+			LastKey:     1,
+			KeyStep:     1,
+			KeySpecs: []internal.KeySpec{
+				{Flags: []string{constants.KeySpecRW, constants.KeySpecInsert}, BeginSearch: 1, LastKey: 0, KeyStep: 1},
+			},
+			Complexity:        "O(1). The amortized time complexity is O(1) assuming the appended value is small.",
+			Since:             "0.1.0",
 			KeyExtractionFunc: appendKeyFunc,
 			HandlerFunc:       handleAppend,
 		},
@@ -0,0 +1,325 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xrepl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/echovault/echovault/internal"
+	"github.com/echovault/echovault/internal/logger"
+	"github.com/echovault/echovault/internal/modules/cdc"
+	"github.com/tidwall/resp"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errInactive = errors.New("server is no longer the leader")
+
+// link is a connection to a node of the remote cluster.
+type link struct {
+	conn   net.Conn
+	client *resp.Conn
+}
+
+// do sends the command to the remote node and returns its response.
+func (l *link) do(command ...string) (resp.Value, error) {
+	values := make([]resp.Value, len(command))
+	for i, arg := range command {
+		values[i] = resp.StringValue(arg)
+	}
+	_ = l.conn.SetDeadline(time.Now().Add(10 * time.Second))
+	if err := l.client.WriteArray(values); err != nil {
+		return resp.Value{}, err
+	}
+	res, _, err := l.client.ReadValue()
+	if err != nil {
+		return resp.Value{}, err
+	}
+	if res.Type() == resp.Error {
+		return res, fmt.Errorf("%s: %v", strings.ToLower(command[0]), res.Error())
+	}
+	return res, nil
+}
+
+// Start starts the agent in the background when targets are configured.
+func (xrepl *XRepl) Start() {
+	xrepl.forgetFeed()
+	if len(xrepl.targets) > 0 {
+		go xrepl.run()
+	}
+}
+
+// Close stops the agent and waits for it to exit.
+func (xrepl *XRepl) Close() {
+	xrepl.stopOnce.Do(func() {
+		close(xrepl.stop)
+	})
+	if len(xrepl.targets) > 0 {
+		<-xrepl.done
+	}
+}
+
+func (xrepl *XRepl) run() {
+	defer close(xrepl.done)
+	for {
+		if !xrepl.isActiveFunc() {
+			xrepl.setState(stateIdle, "")
+		} else if err := xrepl.replicate(); err != nil && !errors.Is(err, errInactive) {
+			select {
+			case <-xrepl.stop:
+				return
+			default:
+			}
+			xrepl.mut.Lock()
+			target := xrepl.target
+			xrepl.state, xrepl.target, xrepl.lastError = stateConnecting, "", err.Error()
+			xrepl.mut.Unlock()
+			xrepl.logger.Warn("xrepl link lost", "target", target, logger.ErrorKey, err)
+		}
+
+		timer := time.NewTimer(xrepl.retryInterval)
+		select {
+		case <-xrepl.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+func (xrepl *XRepl) setState(state string, target string) {
+	xrepl.mut.Lock()
+	defer xrepl.mut.Unlock()
+	xrepl.state = state
+	xrepl.target = target
+}
+
+// connect connects to the first reachable node of the remote cluster, starting with the node after the one
+// that failed last.
+func (xrepl *XRepl) connect() (*link, string, error) {
+	var errs []error
+	for i := 0; i < len(xrepl.targets); i++ {
+		target := xrepl.targets[(xrepl.nextTarget+i)%len(xrepl.targets)]
+		conn, err := net.DialTimeout("tcp", target, 5*time.Second)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		l := &link{conn: conn, client: resp.NewConn(conn)}
+		if xrepl.password != "" {
+			auth := []string{"AUTH", xrepl.password}
+			if xrepl.username != "" {
+				auth = []string{"AUTH", xrepl.username, xrepl.password}
+			}
+			if _, err = l.do(auth...); err != nil {
+				_ = conn.Close()
+				errs = append(errs, fmt.Errorf("%s: %v", target, err))
+				continue
+			}
+		}
+		// The next attempt starts with the node after this one in case the connection fails later.
+		xrepl.nextTarget = (xrepl.nextTarget + i + 1) % len(xrepl.targets)
+		return l, target, nil
+	}
+	return nil, "", errors.Join(errs...)
+}
+
+// replicate connects to the remote cluster, resumes from the checkpoint or sends every key, and streams the feed
+// until the connection fails, the server loses leadership or the agent is stopped.
+func (xrepl *XRepl) replicate() error {
+	xrepl.setState(stateConnecting, "")
+	l, target, err := xrepl.connect()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-xrepl.stop:
+		case <-ctx.Done():
+		}
+		// Closing the connection interrupts pending requests.
+		_ = l.conn.Close()
+	}()
+
+	res, err := l.do("XREPL", "CHECKPOINT", xrepl.sourceID)
+	if err != nil {
+		return err
+	}
+
+	feedID := xrepl.feed.ID()
+	var events <-chan internal.MutationEvent
+	if checkpoint := res.Array(); len(checkpoint) == 2 && checkpoint[0].String() == feedID {
+		// The subscription fails when the checkpoint is no longer in the backlog.
+		events, _ = xrepl.feed.Subscribe(ctx, uint64(checkpoint[1].Integer())+1)
+	}
+
+	if events == nil {
+		xrepl.setState(stateSync, target)
+		// Subscribing before listing the keys ensures that no change is missed.
+		// The changes made while the keys are sent are sent again, which is safe because XREPL APPLY is idempotent.
+		offset := xrepl.feed.Offset()
+		if events, err = xrepl.feed.Subscribe(ctx, offset+1); err != nil {
+			return err
+		}
+		for database, keys := range xrepl.getKeysFunc() {
+			for _, key := range keys {
+				if !xrepl.matches(database, key) {
+					continue
+				}
+				if err = xrepl.send(l, database, key); err != nil {
+					return err
+				}
+			}
+		}
+		if err = xrepl.checkpoint(l, feedID, offset); err != nil {
+			return err
+		}
+		xrepl.mut.Lock()
+		xrepl.fullSyncs += 1
+		xrepl.mut.Unlock()
+	}
+
+	xrepl.setState(stateConnected, target)
+
+	var pending int // The number of events replicated since the latest checkpoint.
+	var offset uint64
+	for {
+		var event internal.MutationEvent
+		var ok bool
+		select {
+		case event, ok = <-events:
+		default:
+			// Checkpoint whenever the agent catches up with the feed.
+			if pending > 0 {
+				if err = xrepl.checkpoint(l, feedID, offset); err != nil {
+					return err
+				}
+				pending = 0
+			}
+			timer := time.NewTimer(xrepl.retryInterval)
+			select {
+			case event, ok = <-events:
+				timer.Stop()
+			case <-timer.C:
+				if !xrepl.isActiveFunc() {
+					return errInactive
+				}
+				continue
+			}
+		}
+		if !ok {
+			return errors.New("change data capture subscription closed")
+		}
+
+		if !xrepl.isActiveFunc() {
+			return errInactive
+		}
+
+		// Writes replicated from other clusters are not sent back, and flushes and evictions are not replicated.
+		if event.Origin == "" && event.Type != cdc.EventFlush && event.Type != cdc.EventEvict &&
+			xrepl.matches(event.Database, event.Key) {
+			if err = xrepl.send(l, event.Database, event.Key); err != nil {
+				return err
+			}
+		}
+
+		offset = event.Offset
+		pending += 1
+		if pending >= xrepl.checkpointEvents {
+			if err = xrepl.checkpoint(l, feedID, offset); err != nil {
+				return err
+			}
+			pending = 0
+		}
+	}
+}
+
+// send sends the current state of the key to the remote cluster along with the time of its latest write.
+// Sending the current state rather than the command makes retries safe.
+func (xrepl *XRepl) send(l *link, database int, key string) error {
+	data, write, exists := xrepl.getKeyFunc(database, key)
+
+	args := []string{typeNone, "0"}
+	if exists {
+		var err error
+		if args, err = EncodeKeyData(data); err != nil {
+			xrepl.logger.Error("encode xrepl key", logger.DatabaseKey, database, "key", key, logger.ErrorKey, err)
+			return nil
+		}
+	}
+
+	// Keys without a known write time are sent with timestamp 0, so they never overwrite newer writes.
+	source, timestamp := write.Source, int64(0)
+	if source == "" {
+		source = xrepl.sourceID
+	}
+	if !write.Timestamp.IsZero() {
+		timestamp = write.Timestamp.UnixMilli()
+	}
+
+	command := append([]string{
+		"XREPL", "APPLY", source, strconv.FormatInt(timestamp, 10), strconv.Itoa(database), key,
+	}, args...)
+	if _, err := l.do(command...); err != nil {
+		return err
+	}
+
+	xrepl.mut.Lock()
+	xrepl.applied += 1
+	xrepl.mut.Unlock()
+	return nil
+}
+
+// checkpoint stores the offset replicated so far on the remote cluster.
+func (xrepl *XRepl) checkpoint(l *link, feedID string, offset uint64) error {
+	if _, err := l.do("XREPL", "CHECKPOINT", xrepl.sourceID, feedID, strconv.FormatUint(offset, 10)); err != nil {
+		return err
+	}
+	xrepl.mut.Lock()
+	xrepl.offset = offset
+	xrepl.mut.Unlock()
+	return nil
+}
+
+// Status returns the state of cross-cluster replication as "field:value" lines.
+func (xrepl *XRepl) Status() string {
+	xrepl.mut.Lock()
+	defer xrepl.mut.Unlock()
+
+	enabled := 0
+	if xrepl.enabled {
+		enabled = 1
+	}
+
+	lines := []string{
+		fmt.Sprintf("enabled:%d", enabled),
+		fmt.Sprintf("source_id:%s", xrepl.sourceID),
+		fmt.Sprintf("state:%s", xrepl.state),
+		fmt.Sprintf("target:%s", xrepl.target),
+		fmt.Sprintf("feed_id:%s", xrepl.feed.ID()),
+		fmt.Sprintf("feed_offset:%d", xrepl.feed.Offset()),
+		fmt.Sprintf("checkpoint_offset:%d", xrepl.offset),
+		fmt.Sprintf("applied:%d", xrepl.applied),
+		fmt.Sprintf("full_syncs:%d", xrepl.fullSyncs),
+		fmt.Sprintf("last_error:%s", xrepl.lastError),
+	}
+	return strings.Join(lines, "\r\n") + "\r\n"
+}
@@ -0,0 +1,221 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xrepl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/echovault/echovault/internal"
+	"github.com/echovault/echovault/internal/constants"
+	"strconv"
+	"time"
+)
+
+func handleXReplApply(params internal.HandlerFuncParams) ([]byte, error) {
+	xrepl, ok := params.GetXRepl().(*XRepl)
+	if !ok {
+		return nil, errors.New("could not load xrepl module")
+	}
+
+	if len(params.Command) < 8 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+
+	timestamp, err := strconv.ParseInt(params.Command[3], 10, 64)
+	if err != nil || timestamp < 0 {
+		return nil, errors.New("timestamp must be a positive integer")
+	}
+	database, err := strconv.Atoi(params.Command[4])
+	if err != nil || database < 0 {
+		return nil, errors.New("database must be a positive integer")
+	}
+	key := params.Command[5]
+	data, err := decodeKeyData(params.Command[6:])
+	if err != nil {
+		return nil, err
+	}
+
+	write := internal.WriteOrigin{Source: params.Command[2]}
+	if timestamp > 0 {
+		write.Timestamp = time.UnixMilli(timestamp)
+	}
+
+	if !xrepl.Accepts(database, key, write) {
+		return []byte(":0\r\n"), nil
+	}
+
+	// The keyspace checks the write again while it's locked, in case the key changes in the meantime.
+	ctx := context.WithValue(params.Context, "Database", database)
+	ctx = context.WithValue(ctx, internal.ContextWriteOrigin("WriteOrigin"), write)
+
+	if data.Value == nil {
+		if params.KeysExist(ctx, []string{key})[key] {
+			if err = params.DeleteKey(ctx, key); err != nil {
+				return nil, err
+			}
+		}
+		// Keep the time of the deletion even when the key does not exist here, so that older writes are rejected.
+		xrepl.RecordWrite(database, key, write)
+		return []byte(":1\r\n"), nil
+	}
+
+	if err = params.SetValues(ctx, map[string]interface{}{key: data.Value}); err != nil {
+		return nil, err
+	}
+	if !params.GetExpiry(ctx, key).Equal(data.ExpireAt) {
+		params.SetExpiry(ctx, key, data.ExpireAt, false)
+	}
+
+	return []byte(":1\r\n"), nil
+}
+
+func handleXReplCheckpoint(params internal.HandlerFuncParams) ([]byte, error) {
+	xrepl, ok := params.GetXRepl().(*XRepl)
+	if !ok {
+		return nil, errors.New("could not load xrepl module")
+	}
+
+	source := ""
+	if len(params.Command) > 2 {
+		source = params.Command[2]
+	}
+
+	switch len(params.Command) {
+	case 3:
+		checkpoint, ok := xrepl.Checkpoint(source)
+		if !ok {
+			return []byte("$-1\r\n"), nil
+		}
+		return []byte(fmt.Sprintf("*2\r\n$%d\r\n%s\r\n:%d\r\n",
+			len(checkpoint.FeedID), checkpoint.FeedID, checkpoint.Offset)), nil
+	case 5:
+		offset, err := strconv.ParseUint(params.Command[4], 10, 64)
+		if err != nil {
+			return nil, errors.New("offset must be a positive integer")
+		}
+		if err = xrepl.SetCheckpoint(source, Checkpoint{FeedID: params.Command[3], Offset: offset}); err != nil {
+			return nil, err
+		}
+		return []byte(constants.OkResponse), nil
+	default:
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+}
+
+func handleXReplStatus(params internal.HandlerFuncParams) ([]byte, error) {
+	xrepl, ok := params.GetXRepl().(*XRepl)
+	if !ok {
+		return nil, errors.New("could not load xrepl module")
+	}
+
+	if len(params.Command) != 2 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+
+	status := xrepl.Status()
+	return []byte(fmt.Sprintf("$%d\r\n%s\r\n", len(status), status)), nil
+}
+
+func Commands() []internal.Command {
+	return []internal.Command{
+		{
+			Command:     "xrepl",
+			Module:      constants.XReplModule,
+			Categories:  []string{},
+			Description: "",
+			Sync:        false,
+			Arity:       -2,
+			Complexity:  "Depends on subcommand.",
+			Since:       "0.10.1",
+			KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+				return internal.KeyExtractionFuncResult{
+					Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+				}, nil
+			},
+			HandlerFunc: func(_ internal.HandlerFuncParams) ([]byte, error) {
+				return nil, errors.New("provide APPLY, CHECKPOINT or STATUS subcommand")
+			},
+			SubCommands: []internal.SubCommand{
+				{
+					Command: "apply",
+					Module:  constants.XReplModule,
+					Categories: []string{constants.AdminCategory, constants.WriteCategory,
+						constants.SlowCategory, constants.DangerousCategory},
+					Description: `(XREPL APPLY source timestamp database key type expireat [element ...])
+Internal command used by the cross-cluster replication agent to write the state of a key replicated from
+another cluster. The write is applied only when it's not older than the latest write of the key.
+type is one of none (the key was deleted), string, hash, list, set or zset, and expireat is the expiry time in unix
+milliseconds or 0. Returns 1 when the write is applied and 0 when it's rejected.`,
+					Sync:     true,
+					Arity:    -8,
+					Flags:    []string{constants.WriteFlag, constants.DenyOOMFlag, constants.AdminFlag},
+					FirstKey: 5,
+					LastKey:  5,
+					KeyStep:  1,
+					KeySpecs: []internal.KeySpec{
+						{Flags: []string{constants.KeySpecOW, constants.KeySpecUpdate}, BeginSearch: 5, LastKey: 0, KeyStep: 1},
+					},
+					Complexity: "O(N) where N is the number of elements of the value.",
+					Since:      "0.10.1",
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						if len(cmd) < 8 {
+							return internal.KeyExtractionFuncResult{}, errors.New(constants.WrongArgsResponse)
+						}
+						return internal.KeyExtractionFuncResult{
+							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: cmd[5:6],
+						}, nil
+					},
+					HandlerFunc: handleXReplApply,
+				},
+				{
+					Command:    "checkpoint",
+					Module:     constants.XReplModule,
+					Categories: []string{constants.AdminCategory, constants.FastCategory, constants.DangerousCategory},
+					Description: `(XREPL CHECKPOINT source [feedid offset]) Internal command used by the cross-cluster
+replication agent to get or set the feed offset of the source that has been replicated to this cluster.`,
+					Sync:       true,
+					Arity:      -3,
+					Flags:      []string{constants.AdminFlag, constants.FastFlag},
+					Complexity: "O(1)",
+					Since:      "0.10.1",
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleXReplCheckpoint,
+				},
+				{
+					Command:     "status",
+					Module:      constants.XReplModule,
+					Categories:  []string{constants.AdminCategory, constants.FastCategory, constants.DangerousCategory},
+					Description: `(XREPL STATUS) Returns the state of the cross-cluster replication agent.`,
+					Sync:        false,
+					Arity:       2,
+					Flags:       []string{constants.AdminFlag, constants.FastFlag},
+					Complexity:  "O(1)",
+					Since:       "0.10.1",
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleXReplStatus,
+				},
+			},
+		},
+	}
+}
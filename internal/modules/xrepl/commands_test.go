@@ -0,0 +1,268 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xrepl_test
+
+import (
+	"github.com/echovault/echovault/echovault"
+	"github.com/echovault/echovault/internal"
+	"github.com/echovault/echovault/internal/clock"
+	"github.com/echovault/echovault/internal/constants"
+	"github.com/tidwall/resp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func setupServer(port uint16) (*echovault.EchoVault, error) {
+	cfg := echovault.DefaultConfig()
+	cfg.DataDir = ""
+	cfg.BindAddr = "localhost"
+	cfg.Port = port
+	cfg.EvictionPolicy = constants.NoEviction
+	cfg.XRepl = true
+	cfg.XReplSourceID = "region-b"
+	return echovault.NewEchoVault(echovault.WithConfig(cfg))
+}
+
+func Test_XRepl(t *testing.T) {
+	port, err := internal.GetFreePort()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	mockServer, err := setupServer(uint16(port))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	go func() {
+		mockServer.Start()
+	}()
+
+	t.Cleanup(func() {
+		mockServer.ShutDown()
+	})
+
+	conn, err := internal.GetConnection("localhost", port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	client := resp.NewConn(conn)
+
+	do := func(t *testing.T, command ...string) resp.Value {
+		values := make([]resp.Value, len(command))
+		for i, arg := range command {
+			values[i] = resp.StringValue(arg)
+		}
+		if err := client.WriteArray(values); err != nil {
+			t.Fatal(err)
+		}
+		res, _, err := client.ReadValue()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	now := clock.NewClock().Now()
+	ms := func(d time.Duration) string {
+		return strconv.FormatInt(now.Add(d).UnixMilli(), 10)
+	}
+
+	t.Run("Test XREPL APPLY", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			command []string
+			want    int
+			check   []string
+			result  []string
+		}{
+			{
+				name:    "1. Apply a string to a new key",
+				command: []string{"XREPL", "APPLY", "region-a", ms(time.Second), "0", "ApplyKey1", "string", "0", "value1"},
+				want:    1,
+				check:   []string{"GET", "ApplyKey1"},
+				result:  []string{"value1"},
+			},
+			{
+				name:    "2. Reject a write older than the latest write of the key",
+				command: []string{"XREPL", "APPLY", "region-a", ms(0), "0", "ApplyKey1", "string", "0", "value2"},
+				want:    0,
+				check:   []string{"GET", "ApplyKey1"},
+				result:  []string{"value1"},
+			},
+			{
+				name:    "3. Reject a write made at the same time by a source that sorts lower",
+				command: []string{"XREPL", "APPLY", "region-0", ms(time.Second), "0", "ApplyKey1", "string", "0", "value3"},
+				want:    0,
+				check:   []string{"GET", "ApplyKey1"},
+				result:  []string{"value1"},
+			},
+			{
+				name:    "4. Accept a write made at the same time by a source that sorts higher",
+				command: []string{"XREPL", "APPLY", "region-c", ms(time.Second), "0", "ApplyKey1", "string", "0", "value4"},
+				want:    1,
+				check:   []string{"GET", "ApplyKey1"},
+				result:  []string{"value4"},
+			},
+			{
+				name: "5. Apply a hash with an expiry time",
+				command: []string{"XREPL", "APPLY", "region-a", ms(time.Second), "0", "ApplyKey2", "hash",
+					ms(100 * time.Second), "field1", "value1", "field2", "2"},
+				want:   1,
+				check:  []string{"PEXPIRETIME", "ApplyKey2"},
+				result: []string{ms(100 * time.Second)},
+			},
+			{
+				name:    "6. Apply a list",
+				command: []string{"XREPL", "APPLY", "region-a", ms(time.Second), "0", "ApplyKey3", "list", "0", "a", "b", "c"},
+				want:    1,
+				check:   []string{"LRANGE", "ApplyKey3", "0", "-1"},
+				result:  []string{"a", "b", "c"},
+			},
+			{
+				name:    "7. Apply a set",
+				command: []string{"XREPL", "APPLY", "region-a", ms(time.Second), "0", "ApplyKey4", "set", "0", "a", "b"},
+				want:    1,
+				check:   []string{"SCARD", "ApplyKey4"},
+				result:  []string{"2"},
+			},
+			{
+				name: "8. Apply a sorted set",
+				command: []string{"XREPL", "APPLY", "region-a", ms(time.Second), "0", "ApplyKey5", "zset", "0",
+					"1.5", "a", "2", "b"},
+				want:   1,
+				check:  []string{"ZSCORE", "ApplyKey5", "a"},
+				result: []string{"1.5"},
+			},
+			{
+				name:    "9. Apply a deletion",
+				command: []string{"XREPL", "APPLY", "region-a", ms(2 * time.Second), "0", "ApplyKey3", "none", "0"},
+				want:    1,
+				check:   []string{"LRANGE", "ApplyKey3", "0", "-1"},
+				result:  []string{},
+			},
+			{
+				name:    "10. Reject a write older than the deletion of the key",
+				command: []string{"XREPL", "APPLY", "region-a", ms(time.Second), "0", "ApplyKey3", "list", "0", "d"},
+				want:    0,
+				check:   []string{"LRANGE", "ApplyKey3", "0", "-1"},
+				result:  []string{},
+			},
+			{
+				name:    "11. Apply a write to another database",
+				command: []string{"XREPL", "APPLY", "region-a", ms(time.Second), "1", "ApplyKey1", "string", "0", "db1"},
+				want:    1,
+				check:   []string{"GET", "ApplyKey1"},
+				result:  []string{"value4"},
+			},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				res := do(t, test.command...)
+				if res.Error() != nil {
+					t.Fatal(res.Error())
+				}
+				if res.Integer() != test.want {
+					t.Errorf("expected XREPL APPLY to return %d, got %d", test.want, res.Integer())
+				}
+				res = do(t, test.check...)
+				var got []string
+				if res.Type() == resp.Array {
+					got = make([]string, 0)
+					for _, v := range res.Array() {
+						got = append(got, v.String())
+					}
+				} else {
+					got = []string{res.String()}
+				}
+				if !slices.Equal(got, test.result) {
+					t.Errorf("expected %v to return %v, got %v", test.check, test.result, got)
+				}
+			})
+		}
+	})
+
+	t.Run("Test XREPL APPLY against local writes", func(t *testing.T) {
+		// Local writes are timestamped with the server's clock.
+		do(t, "SET", "LocalKey", "local")
+		if res := do(t, "XREPL", "APPLY", "region-a", ms(-time.Second), "0", "LocalKey", "string", "0", "remote"); res.Integer() != 0 {
+			t.Errorf("expected write older than the local write to be rejected, got %v", res)
+		}
+		if res := do(t, "XREPL", "APPLY", "region-a", ms(time.Second), "0", "LocalKey", "string", "0", "remote"); res.Integer() != 1 {
+			t.Errorf("expected write newer than the local write to be applied, got %v", res)
+		}
+		if res := do(t, "GET", "LocalKey"); res.String() != "remote" {
+			t.Errorf("expected LocalKey to be remote, got %s", res.String())
+		}
+		// Keys without a known write time are sent with timestamp 0 and don't overwrite local writes.
+		if res := do(t, "XREPL", "APPLY", "region-a", "0", "0", "LocalKey", "string", "0", "unknown"); res.Integer() != 0 {
+			t.Errorf("expected write without a timestamp to be rejected, got %v", res)
+		}
+	})
+
+	t.Run("Test XREPL APPLY errors", func(t *testing.T) {
+		tests := []struct {
+			command []string
+			err     string
+		}{
+			{command: []string{"XREPL", "APPLY", "region-a", ms(0), "0", "ErrKey"}, err: constants.WrongArgsResponse},
+			{command: []string{"XREPL", "APPLY", "region-a", "ts", "0", "ErrKey", "string", "0", "v"}, err: "timestamp"},
+			{command: []string{"XREPL", "APPLY", "region-a", ms(0), "-1", "ErrKey", "string", "0", "v"}, err: "database"},
+			{command: []string{"XREPL", "APPLY", "region-a", ms(0), "0", "ErrKey", "stream", "0", "v"}, err: "unsupported value type"},
+			{command: []string{"XREPL", "APPLY", "region-a", ms(0), "0", "ErrKey", "hash", "0", "field"}, err: "field/value pairs"},
+			{command: []string{"XREPL", "APPLY", "region-a", ms(0), "0", "ErrKey", "zset", "0", "score", "a"}, err: "invalid score"},
+		}
+		for _, test := range tests {
+			res := do(t, test.command...)
+			if res.Error() == nil || !strings.Contains(res.Error().Error(), test.err) {
+				t.Errorf("expected %v to return error containing %q, got %v", test.command, test.err, res)
+			}
+		}
+	})
+
+	t.Run("Test XREPL CHECKPOINT", func(t *testing.T) {
+		if res := do(t, "XREPL", "CHECKPOINT", "region-a"); !res.IsNull() {
+			t.Errorf("expected no checkpoint, got %v", res)
+		}
+		if res := do(t, "XREPL", "CHECKPOINT", "region-a", "feed1", "42"); res.String() != "OK" {
+			t.Errorf("expected OK, got %v", res)
+		}
+		res := do(t, "XREPL", "CHECKPOINT", "region-a")
+		if len(res.Array()) != 2 || res.Array()[0].String() != "feed1" || res.Array()[1].Integer() != 42 {
+			t.Errorf("expected checkpoint [feed1 42], got %v", res)
+		}
+		if res = do(t, "XREPL", "CHECKPOINT", "region-a", "feed1", "-1"); res.Error() == nil {
+			t.Errorf("expected error for negative offset, got %v", res)
+		}
+	})
+
+	t.Run("Test XREPL STATUS", func(t *testing.T) {
+		status := do(t, "XREPL", "STATUS").String()
+		for _, line := range []string{"enabled:1", "source_id:region-b", "state:none"} {
+			if !strings.Contains(status, line) {
+				t.Errorf("expected status to contain %q, got %q", line, status)
+			}
+		}
+	})
+}
@@ -0,0 +1,128 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xrepl
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/echovault/echovault/internal/logger"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// stateFile is the name of the file in the data directory that holds the cross-cluster replication state.
+const stateFile = "xrepl.json"
+
+// State is the cross-cluster replication state kept in the data directory across restarts.
+//
+// The checkpoints of the sources replicating to this cluster are written every time they change. The ID and
+// offset of the feed are only written when the server shuts down, and removed when it starts again, so that
+// the feed is only resumed when no event was lost. After a crash, the feed gets a new ID and the agent sends
+// every key again.
+type State struct {
+	FeedID      string                `json:"FeedID,omitempty"`
+	FeedOffset  uint64                `json:"FeedOffset,omitempty"`
+	Checkpoints map[string]Checkpoint `json:"Checkpoints"`
+}
+
+// ReadState reads the state saved in the directory. It returns an empty state when there's none.
+func ReadState(directory string) (State, error) {
+	state := State{Checkpoints: make(map[string]Checkpoint)}
+	if directory == "" {
+		return state, nil
+	}
+	b, err := os.ReadFile(filepath.Join(directory, stateFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return state, fmt.Errorf("read xrepl state: %+v", err)
+	}
+	if err = json.Unmarshal(b, &state); err != nil {
+		return State{Checkpoints: make(map[string]Checkpoint)}, fmt.Errorf("read xrepl state: %+v", err)
+	}
+	if state.Checkpoints == nil {
+		state.Checkpoints = make(map[string]Checkpoint)
+	}
+	return state, nil
+}
+
+// WithDirectory sets the data directory the state is saved in. The state is not saved when it's empty.
+func WithDirectory(directory string) func(xrepl *XRepl) {
+	return func(xrepl *XRepl) {
+		xrepl.directory = directory
+	}
+}
+
+// WithCheckpoints sets the checkpoints read from the saved state.
+func WithCheckpoints(checkpoints map[string]Checkpoint) func(xrepl *XRepl) {
+	return func(xrepl *XRepl) {
+		for source, checkpoint := range checkpoints {
+			xrepl.checkpoints[source] = checkpoint
+		}
+	}
+}
+
+// Save saves the checkpoints along with the ID and offset of the feed, so that the agent resumes from the
+// checkpoint of the remote cluster after a restart. It must be called once the server no longer writes to the
+// keyspace, so that the offset is the offset of the last event.
+func (xrepl *XRepl) Save() error {
+	xrepl.mut.Lock()
+	defer xrepl.mut.Unlock()
+	return xrepl.saveLocked(true)
+}
+
+// saveLocked writes the state to the data directory when cross-cluster replication is enabled. The feed is only
+// included when withFeed is true. The caller must hold xrepl.mut.
+func (xrepl *XRepl) saveLocked(withFeed bool) error {
+	if xrepl.directory == "" || !xrepl.enabled {
+		return nil
+	}
+
+	state := State{Checkpoints: xrepl.checkpoints}
+	if withFeed {
+		state.FeedID, state.FeedOffset = xrepl.feed.ID(), xrepl.feed.Offset()
+	}
+	b, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("save xrepl state: %+v", err)
+	}
+
+	if err = os.MkdirAll(xrepl.directory, os.ModePerm); err != nil {
+		return fmt.Errorf("save xrepl state: %+v", err)
+	}
+	// Write a temporary file and rename it so that the state is never left half written.
+	file := filepath.Join(xrepl.directory, stateFile)
+	tmp := file + ".tmp"
+	if err = os.WriteFile(tmp, b, 0644); err != nil {
+		return fmt.Errorf("save xrepl state: %+v", err)
+	}
+	if err = os.Rename(tmp, file); err != nil {
+		return fmt.Errorf("save xrepl state: %+v", err)
+	}
+	return nil
+}
+
+// forgetFeed removes the feed from the saved state once the server has started, so that the feed is not resumed
+// after a crash.
+func (xrepl *XRepl) forgetFeed() {
+	xrepl.mut.Lock()
+	defer xrepl.mut.Unlock()
+	if err := xrepl.saveLocked(false); err != nil {
+		xrepl.logger.Error("save xrepl state", logger.ErrorKey, err)
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xrepl
+
+import (
+	"errors"
+	"fmt"
+	"github.com/echovault/echovault/internal"
+	"github.com/echovault/echovault/internal/modules/set"
+	"github.com/echovault/echovault/internal/modules/sorted_set"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Value types sent with XREPL APPLY.
+const (
+	typeNone      = "none" // The key was deleted.
+	typeString    = "string"
+	typeHash      = "hash"
+	typeList      = "list"
+	typeSet       = "set"
+	typeSortedSet = "zset"
+)
+
+// EncodeKeyData encodes the state of a key into the arguments of XREPL APPLY that follow the key:
+// the value type, the expiry time in unix milliseconds (0 when the key does not expire), and the elements.
+// String values have one element, hashes have field/value pairs, lists and sets have their members,
+// and sorted sets have score/member pairs. Empty collections are encoded as deleted keys, as they cannot exist
+// in the keyspace.
+func EncodeKeyData(data internal.KeyData) ([]string, error) {
+	var expireAt int64
+	if !data.ExpireAt.IsZero() {
+		expireAt = data.ExpireAt.UnixMilli()
+	}
+	args := func(valueType string, elements ...string) []string {
+		if valueType != typeString && len(elements) == 0 {
+			return []string{typeNone, "0"}
+		}
+		return append([]string{valueType, strconv.FormatInt(expireAt, 10)}, elements...)
+	}
+
+	switch v := data.Value.(type) {
+	case string, int, float64:
+		return args(typeString, formatValue(v)), nil
+	case map[string]interface{}:
+		elements := make([]string, 0, len(v)*2)
+		for field, value := range v {
+			elements = append(elements, field, formatValue(value))
+		}
+		return args(typeHash, elements...), nil
+	case []string:
+		return args(typeList, v...), nil
+	case *set.Set:
+		return args(typeSet, v.GetAll()...), nil
+	case *sorted_set.SortedSet:
+		elements := make([]string, 0, v.Cardinality()*2)
+		for _, member := range v.GetAll() {
+			elements = append(elements, strconv.FormatFloat(float64(member.Score), 'f', -1, 64), string(member.Value))
+		}
+		return args(typeSortedSet, elements...), nil
+	default:
+		return nil, fmt.Errorf("unsupported value type %T", data.Value)
+	}
+}
+
+// decodeKeyData decodes the value type, expiry time and elements sent with XREPL APPLY.
+// The returned value is nil when the key was deleted.
+func decodeKeyData(args []string) (internal.KeyData, error) {
+	if len(args) < 2 {
+		return internal.KeyData{}, errors.New("value type and expiry time are required")
+	}
+
+	expireAt, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil || expireAt < 0 {
+		return internal.KeyData{}, errors.New("expiry time must be a positive integer")
+	}
+	data := internal.KeyData{}
+	if expireAt > 0 {
+		data.ExpireAt = time.UnixMilli(expireAt)
+	}
+
+	elements := args[2:]
+	switch strings.ToLower(args[0]) {
+	case typeNone:
+		if len(elements) != 0 {
+			return data, errors.New("deleted keys have no elements")
+		}
+	case typeString:
+		if len(elements) != 1 {
+			return data, errors.New("string values have exactly one element")
+		}
+		data.Value = internal.AdaptType(elements[0])
+	case typeHash:
+		if len(elements) == 0 || len(elements)%2 != 0 {
+			return data, errors.New("hash values have field/value pairs")
+		}
+		hash := make(map[string]interface{}, len(elements)/2)
+		for i := 0; i < len(elements); i += 2 {
+			hash[elements[i]] = internal.AdaptType(elements[i+1])
+		}
+		data.Value = hash
+	case typeList:
+		if len(elements) == 0 {
+			return data, errors.New("list values have at least one element")
+		}
+		data.Value = slices.Clone(elements)
+	case typeSet:
+		if len(elements) == 0 {
+			return data, errors.New("set values have at least one member")
+		}
+		data.Value = set.NewSet(elements)
+	case typeSortedSet:
+		if len(elements) == 0 || len(elements)%2 != 0 {
+			return data, errors.New("sorted set values have score/member pairs")
+		}
+		members := make([]sorted_set.MemberParam, 0, len(elements)/2)
+		for i := 0; i < len(elements); i += 2 {
+			score, err := strconv.ParseFloat(elements[i], 64)
+			if err != nil {
+				return data, fmt.Errorf("invalid score %s", elements[i])
+			}
+			members = append(members, sorted_set.MemberParam{
+				Value: sorted_set.Value(elements[i+1]),
+				Score: sorted_set.Score(score),
+			})
+		}
+		data.Value = sorted_set.NewSortedSet(members)
+	default:
+		return data, fmt.Errorf("unsupported value type %s", args[0])
+	}
+
+	return data, nil
+}
+
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
@@ -0,0 +1,408 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xrepl
+
+import (
+	"github.com/echovault/echovault/internal"
+	"github.com/echovault/echovault/internal/logger"
+	"github.com/echovault/echovault/internal/modules/cdc"
+	"github.com/gobwas/glob"
+	"slices"
+	"sync"
+	"time"
+)
+
+// This package contains the asynchronous cross-cluster replication engine.
+//
+// Every cluster has a source ID. The agent runs on the raft leader (or on a standalone primary), tails the change
+// data capture feed and sends the latest state of every changed key to a remote cluster with XREPL APPLY.
+// Each replicated write carries the source ID and the time of the original write. Every cluster keeps the latest
+// write of each key, and only applies a replicated write that's not older than it (last-writer-wins).
+// Writes made at the same time are ordered by their source ID.
+//
+// The remote cluster also stores the feed offset that the agent has replicated up to (the checkpoint).
+// When the agent reconnects, it resumes from the checkpoint if the offset is still in the feed's backlog.
+// Otherwise, it sends every key before streaming the feed again. The checkpoints, and the feed when the server
+// shuts down, are saved in the data directory so that the agent can resume after a restart.
+//
+// The latest write of a deleted key (its tombstone) is kept for the tombstone TTL, so that older writes replicated
+// later don't recreate the key. The TTL must be longer than the replication lag between the clusters.
+
+const (
+	stateNone       = "none"       // No targets are configured.
+	stateIdle       = "idle"       // The server is not the leader, so another node replicates the cluster.
+	stateConnecting = "connecting" // The agent is connecting to the remote cluster.
+	stateSync       = "sync"       // The agent is sending every key to the remote cluster.
+	stateConnected  = "connected"  // The agent is streaming the feed to the remote cluster.
+)
+
+// defaultTombstoneTTL is how long the latest write of a deleted key is kept by default.
+const defaultTombstoneTTL = 24 * time.Hour
+
+// tombstone is the latest write of a key that no longer exists on this server.
+type tombstone struct {
+	database  int
+	key       string
+	write     internal.WriteOrigin
+	expiresAt time.Time // The time after which the write is forgotten.
+}
+
+// Checkpoint is the position in a source's feed that has been replicated to this cluster.
+type Checkpoint struct {
+	FeedID string
+	Offset uint64
+}
+
+type XRepl struct {
+	mut      sync.Mutex
+	enabled  bool
+	sourceID string
+
+	writes      map[int]map[string]internal.WriteOrigin // The latest write of every key, including deleted keys.
+	flushes     map[int]internal.WriteOrigin            // The latest flush of each database. -1 for FLUSHALL.
+	checkpoints map[string]Checkpoint                   // The checkpoint of each source replicating to this cluster.
+
+	tombstones   []tombstone   // The writes of deleted keys, in the order they're forgotten.
+	tombstoneTTL time.Duration // How long the write of a deleted key is kept.
+	directory    string        // The data directory the state is saved in.
+
+	// Agent configuration.
+	feed             *cdc.Feed
+	targets          []string
+	username         string
+	password         string
+	databases        []int
+	keyPatterns      []glob.Glob
+	retryInterval    time.Duration
+	checkpointEvents int // The maximum number of events replicated between checkpoints.
+	isActiveFunc     func() bool
+	getKeysFunc      func() map[int][]string
+	getKeyFunc       func(database int, key string) (internal.KeyData, internal.WriteOrigin, bool)
+	logger           logger.Logger
+
+	// Agent state.
+	state      string
+	target     string // The address of the remote node the agent is connected to.
+	nextTarget int    // The index of the next target to connect to.
+	offset     uint64 // The offset of the latest event replicated to the remote cluster.
+	applied    uint64 // The number of writes sent to the remote cluster.
+	fullSyncs  uint64 // The number of times every key was sent to the remote cluster.
+	lastError  string
+	stop       chan struct{}
+	stopOnce   sync.Once
+	done       chan struct{}
+}
+
+// WithLogger sets the logger the agent's errors are written to.
+// It must come before WithKeyPatterns for the invalid patterns to be logged with it.
+func WithLogger(logger logger.Logger) func(xrepl *XRepl) {
+	return func(xrepl *XRepl) {
+		xrepl.logger = logger
+	}
+}
+
+// WithEnabled enables last-writer-wins conflict resolution and XREPL APPLY on this server.
+func WithEnabled(enabled bool) func(xrepl *XRepl) {
+	return func(xrepl *XRepl) {
+		xrepl.enabled = enabled
+	}
+}
+
+// WithSourceID sets the ID that identifies this cluster on remote clusters.
+// Every node of the cluster must use the same source ID.
+func WithSourceID(sourceID string) func(xrepl *XRepl) {
+	return func(xrepl *XRepl) {
+		xrepl.sourceID = sourceID
+	}
+}
+
+// WithTombstoneTTL sets how long the latest write of a deleted key is kept to reject older replicated writes.
+func WithTombstoneTTL(ttl time.Duration) func(xrepl *XRepl) {
+	return func(xrepl *XRepl) {
+		xrepl.tombstoneTTL = ttl
+	}
+}
+
+// WithFeed sets the change data capture feed tailed by the agent.
+func WithFeed(feed *cdc.Feed) func(xrepl *XRepl) {
+	return func(xrepl *XRepl) {
+		xrepl.feed = feed
+	}
+}
+
+// WithTargets sets the addresses (host:port) of the nodes of the remote cluster.
+// The agent connects to the first node that's reachable. Setting targets enables cross-cluster replication.
+func WithTargets(targets []string) func(xrepl *XRepl) {
+	return func(xrepl *XRepl) {
+		xrepl.targets = targets
+		if len(targets) > 0 {
+			xrepl.enabled = true
+		}
+	}
+}
+
+// WithTargetAuth sets the credentials used to authenticate with the remote cluster.
+// When the username is empty, the agent authenticates as the default user.
+func WithTargetAuth(username string, password string) func(xrepl *XRepl) {
+	return func(xrepl *XRepl) {
+		xrepl.username = username
+		xrepl.password = password
+	}
+}
+
+// WithDatabases limits replication to the given databases. All databases are replicated by default.
+func WithDatabases(databases []int) func(xrepl *XRepl) {
+	return func(xrepl *XRepl) {
+		xrepl.databases = databases
+	}
+}
+
+// WithKeyPatterns limits replication to the keys that match one of the glob patterns.
+// All keys are replicated by default.
+func WithKeyPatterns(patterns []string) func(xrepl *XRepl) {
+	return func(xrepl *XRepl) {
+		for _, pattern := range patterns {
+			g, err := glob.Compile(pattern)
+			if err != nil {
+				xrepl.logger.Error("invalid xrepl key pattern", "pattern", pattern, logger.ErrorKey, err)
+				continue
+			}
+			xrepl.keyPatterns = append(xrepl.keyPatterns, g)
+		}
+	}
+}
+
+// WithIsActiveFunc sets the function that reports whether this server should run the agent.
+// Only one server of the cluster (the raft leader) should replicate the cluster.
+func WithIsActiveFunc(f func() bool) func(xrepl *XRepl) {
+	return func(xrepl *XRepl) {
+		xrepl.isActiveFunc = f
+	}
+}
+
+// WithGetKeysFunc sets the function that lists the keys of every database for a full sync.
+func WithGetKeysFunc(f func() map[int][]string) func(xrepl *XRepl) {
+	return func(xrepl *XRepl) {
+		xrepl.getKeysFunc = f
+	}
+}
+
+// WithGetKeyFunc sets the function that returns the current state of a key along with its latest write.
+// It returns false when the key does not exist.
+func WithGetKeyFunc(f func(database int, key string) (internal.KeyData, internal.WriteOrigin, bool)) func(xrepl *XRepl) {
+	return func(xrepl *XRepl) {
+		xrepl.getKeyFunc = f
+	}
+}
+
+func NewXRepl(options ...func(xrepl *XRepl)) *XRepl {
+	xrepl := &XRepl{
+		writes:           make(map[int]map[string]internal.WriteOrigin),
+		flushes:          make(map[int]internal.WriteOrigin),
+		checkpoints:      make(map[string]Checkpoint),
+		tombstoneTTL:     defaultTombstoneTTL,
+		retryInterval:    time.Second,
+		checkpointEvents: 100,
+		isActiveFunc: func() bool {
+			return true
+		},
+		getKeysFunc: func() map[int][]string {
+			return map[int][]string{}
+		},
+		getKeyFunc: func(database int, key string) (internal.KeyData, internal.WriteOrigin, bool) {
+			return internal.KeyData{}, internal.WriteOrigin{}, false
+		},
+		logger: logger.Default(),
+		state:  stateNone,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+
+	for _, option := range options {
+		option(xrepl)
+	}
+
+	if xrepl.feed == nil {
+		xrepl.feed = cdc.NewFeed()
+	}
+
+	return xrepl
+}
+
+// Enabled returns true when cross-cluster replication is enabled on this server.
+func (xrepl *XRepl) Enabled() bool {
+	return xrepl.enabled
+}
+
+// SourceID returns the source ID of this cluster.
+func (xrepl *XRepl) SourceID() string {
+	return xrepl.sourceID
+}
+
+// newer returns true when write a happened after write b.
+func newer(a, b internal.WriteOrigin) bool {
+	if !a.Timestamp.Equal(b.Timestamp) {
+		return a.Timestamp.After(b.Timestamp)
+	}
+	return a.Source > b.Source
+}
+
+// lastWrite returns the latest write of the key, taking flushes of its database into account.
+// The caller must hold xrepl.mut.
+func (xrepl *XRepl) lastWrite(database int, key string) (internal.WriteOrigin, bool) {
+	latest, found := xrepl.writes[database][key]
+	for _, db := range []int{database, -1} {
+		if flush, ok := xrepl.flushes[db]; ok && (!found || newer(flush, latest)) {
+			latest, found = flush, true
+		}
+	}
+	return latest, found
+}
+
+// LastWrite returns the latest write of the key. It returns false when the time of the latest write is unknown.
+func (xrepl *XRepl) LastWrite(database int, key string) (internal.WriteOrigin, bool) {
+	xrepl.mut.Lock()
+	defer xrepl.mut.Unlock()
+	return xrepl.lastWrite(database, key)
+}
+
+// Accepts returns true when the replicated write is not older than the latest write of the key.
+// Writes to keys without a known write time, and every write when conflict resolution is disabled, are accepted.
+func (xrepl *XRepl) Accepts(database int, key string, write internal.WriteOrigin) bool {
+	if !xrepl.enabled {
+		return true
+	}
+	xrepl.mut.Lock()
+	defer xrepl.mut.Unlock()
+	latest, ok := xrepl.lastWrite(database, key)
+	return !ok || !newer(latest, write)
+}
+
+// Record records the change as the latest write of the key. It must be called with every published
+// change data capture event, in order.
+func (xrepl *XRepl) Record(event internal.MutationEvent) {
+	if !xrepl.enabled {
+		return
+	}
+
+	write := internal.WriteOrigin{Source: event.Origin, Timestamp: event.Timestamp}
+	if write.Source == "" {
+		write.Source = xrepl.sourceID
+	}
+
+	xrepl.mut.Lock()
+	defer xrepl.mut.Unlock()
+
+	xrepl.pruneTombstones()
+
+	switch event.Type {
+	case cdc.EventEvict:
+		// Evictions free memory on this server only, they don't change the replicated dataset.
+		// The latest write of the key is kept as a tombstone.
+		if latest, ok := xrepl.writes[event.Database][event.Key]; ok {
+			xrepl.addTombstone(event.Database, event.Key, latest)
+		}
+	case cdc.EventFlush:
+		if event.Database == -1 {
+			clear(xrepl.writes)
+		} else {
+			delete(xrepl.writes, event.Database)
+		}
+		xrepl.flushes[event.Database] = write
+	default:
+		if xrepl.writes[event.Database] == nil {
+			xrepl.writes[event.Database] = make(map[string]internal.WriteOrigin)
+		}
+		xrepl.writes[event.Database][event.Key] = write
+		// Deleted keys keep their latest write so that older writes replicated later don't recreate them.
+		if event.Type == cdc.EventDel || event.Type == cdc.EventExpire {
+			xrepl.addTombstone(event.Database, event.Key, write)
+		}
+	}
+}
+
+// addTombstone schedules the write of the deleted key to be forgotten after the tombstone TTL.
+// The caller must hold xrepl.mut.
+func (xrepl *XRepl) addTombstone(database int, key string, write internal.WriteOrigin) {
+	xrepl.tombstones = append(xrepl.tombstones, tombstone{
+		database:  database,
+		key:       key,
+		write:     write,
+		expiresAt: time.Now().Add(xrepl.tombstoneTTL),
+	})
+}
+
+// pruneTombstones forgets the writes of the deleted keys whose tombstone TTL has elapsed. A write is only forgotten
+// when it's still the latest write of the key, so the keys written again since they were deleted are kept.
+// The caller must hold xrepl.mut.
+func (xrepl *XRepl) pruneTombstones() {
+	now := time.Now()
+	i := 0
+	for ; i < len(xrepl.tombstones) && !xrepl.tombstones[i].expiresAt.After(now); i++ {
+		t := xrepl.tombstones[i]
+		if latest, ok := xrepl.writes[t.database][t.key]; ok && latest == t.write {
+			delete(xrepl.writes[t.database], t.key)
+		}
+	}
+	xrepl.tombstones = xrepl.tombstones[i:]
+}
+
+// RecordWrite records the replicated write as the latest write of the key when it's newer.
+// It's used for the replicated deletion of a key that doesn't exist, so the write is kept as a tombstone.
+func (xrepl *XRepl) RecordWrite(database int, key string, write internal.WriteOrigin) {
+	if !xrepl.enabled {
+		return
+	}
+	xrepl.mut.Lock()
+	defer xrepl.mut.Unlock()
+	xrepl.pruneTombstones()
+	if latest, ok := xrepl.lastWrite(database, key); ok && newer(latest, write) {
+		return
+	}
+	if xrepl.writes[database] == nil {
+		xrepl.writes[database] = make(map[string]internal.WriteOrigin)
+	}
+	xrepl.writes[database][key] = write
+	xrepl.addTombstone(database, key, write)
+}
+
+// Checkpoint returns the checkpoint of the source.
+func (xrepl *XRepl) Checkpoint(source string) (Checkpoint, bool) {
+	xrepl.mut.Lock()
+	defer xrepl.mut.Unlock()
+	checkpoint, ok := xrepl.checkpoints[source]
+	return checkpoint, ok
+}
+
+// SetCheckpoint sets the checkpoint of the source and saves it in the data directory.
+func (xrepl *XRepl) SetCheckpoint(source string, checkpoint Checkpoint) error {
+	xrepl.mut.Lock()
+	defer xrepl.mut.Unlock()
+	xrepl.checkpoints[source] = checkpoint
+	return xrepl.saveLocked(false)
+}
+
+// matches returns true when the key in the database passes the database and key pattern filters.
+func (xrepl *XRepl) matches(database int, key string) bool {
+	if len(xrepl.databases) > 0 && !slices.Contains(xrepl.databases, database) {
+		return false
+	}
+	if len(xrepl.keyPatterns) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(xrepl.keyPatterns, func(g glob.Glob) bool {
+		return g.Match(key)
+	})
+}
@@ -0,0 +1,111 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xrepl_test
+
+import (
+	"context"
+	"github.com/echovault/echovault/internal"
+	"github.com/echovault/echovault/internal/modules/cdc"
+	"github.com/echovault/echovault/internal/modules/xrepl"
+	"testing"
+	"time"
+)
+
+func Test_Tombstones(t *testing.T) {
+	x := xrepl.NewXRepl(
+		xrepl.WithEnabled(true),
+		xrepl.WithSourceID("region-b"),
+		xrepl.WithTombstoneTTL(50*time.Millisecond),
+	)
+
+	now := time.Now()
+	x.Record(internal.MutationEvent{Type: cdc.EventSet, Database: 0, Key: "Kept", Timestamp: now})
+	x.Record(internal.MutationEvent{Type: cdc.EventSet, Database: 0, Key: "Deleted", Timestamp: now})
+	x.Record(internal.MutationEvent{Type: cdc.EventDel, Database: 0, Key: "Deleted", Timestamp: now})
+	x.Record(internal.MutationEvent{Type: cdc.EventDel, Database: 0, Key: "Recreated", Timestamp: now})
+	x.Record(internal.MutationEvent{Type: cdc.EventSet, Database: 0, Key: "Recreated", Timestamp: now.Add(time.Millisecond)})
+	x.RecordWrite(0, "Missing", internal.WriteOrigin{Source: "region-a", Timestamp: now})
+
+	// Older writes are rejected while the tombstones are kept.
+	older := internal.WriteOrigin{Source: "region-a", Timestamp: now.Add(-time.Second)}
+	for _, key := range []string{"Deleted", "Missing"} {
+		if x.Accepts(0, key, older) {
+			t.Errorf("expected older write of %s to be rejected", key)
+		}
+	}
+
+	// The tombstones are forgotten once the TTL elapses, but not the writes of the keys that exist.
+	time.Sleep(100 * time.Millisecond)
+	x.Record(internal.MutationEvent{Type: cdc.EventSet, Database: 1, Key: "Other", Timestamp: time.Now()})
+	for key, want := range map[string]bool{"Kept": true, "Recreated": true, "Deleted": false, "Missing": false} {
+		if _, ok := x.LastWrite(0, key); ok != want {
+			t.Errorf("expected last write of %s to be known: %v, got %v", key, want, ok)
+		}
+	}
+}
+
+func Test_State(t *testing.T) {
+	directory := t.TempDir()
+
+	feed := cdc.NewFeed()
+	x := xrepl.NewXRepl(xrepl.WithEnabled(true), xrepl.WithFeed(feed), xrepl.WithDirectory(directory))
+	feed.Publish(internal.MutationEvent{Type: cdc.EventSet, Database: 0, Key: "Key1"})
+	feed.Publish(internal.MutationEvent{Type: cdc.EventSet, Database: 0, Key: "Key2"})
+	if err := x.SetCheckpoint("region-a", xrepl.Checkpoint{FeedID: "feed-a", Offset: 42}); err != nil {
+		t.Fatal(err)
+	}
+	if err := x.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The feed and the checkpoints are restored after a restart.
+	state, err := xrepl.ReadState(directory)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.FeedID != feed.ID() || state.FeedOffset != 2 {
+		t.Errorf("expected feed %s at offset 2, got %s at offset %d", feed.ID(), state.FeedID, state.FeedOffset)
+	}
+	if checkpoint := state.Checkpoints["region-a"]; checkpoint.FeedID != "feed-a" || checkpoint.Offset != 42 {
+		t.Errorf("expected checkpoint {feed-a 42}, got %v", checkpoint)
+	}
+
+	resumed := cdc.NewFeed(cdc.WithResume(state.FeedID, state.FeedOffset))
+	if resumed.ID() != feed.ID() || resumed.Offset() != 2 {
+		t.Errorf("expected the feed to resume at offset 2, got %s at offset %d", resumed.ID(), resumed.Offset())
+	}
+	// Only a subscriber that received every event can resume, as the backlog is lost.
+	if _, err = resumed.Subscribe(context.Background(), 2); err == nil {
+		t.Error("expected error when subscribing from an offset of the previous backlog")
+	}
+	if _, err = resumed.Subscribe(context.Background(), 3); err != nil {
+		t.Errorf("expected the subscriber to resume after the last offset, got %v", err)
+	}
+
+	// The feed is removed from the state once the server starts, so that it's not resumed after a crash.
+	x = xrepl.NewXRepl(xrepl.WithEnabled(true), xrepl.WithFeed(resumed), xrepl.WithDirectory(directory),
+		xrepl.WithCheckpoints(state.Checkpoints))
+	x.Start()
+	defer x.Close()
+	if state, err = xrepl.ReadState(directory); err != nil {
+		t.Fatal(err)
+	}
+	if state.FeedID != "" || len(state.Checkpoints) != 1 {
+		t.Errorf("expected only the checkpoints to be saved, got %+v", state)
+	}
+	if checkpoint, ok := x.Checkpoint("region-a"); !ok || checkpoint.Offset != 42 {
+		t.Errorf("expected checkpoint of region-a to be restored, got %v", checkpoint)
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package monitor streams the commands processed by the server to the connections running MONITOR.
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"github.com/echovault/echovault/internal"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// The sources of the commands streamed to the monitors.
+const (
+	SourceEmbedded    = "embedded"    // The command was called via the embedded API.
+	SourceTCP         = "tcp"         // The command was sent by a TCP client.
+	SourceAOF         = "aof"         // The command was replayed from the append-only log.
+	SourceReplication = "replication" // The command was streamed by the primary to this replica.
+	SourceRaft        = "raft"        // The command was applied from the raft log.
+)
+
+// Redacted replaces the sensitive arguments of the streamed commands.
+const Redacted = "(redacted)"
+
+// Monitor fans the processed commands out to the subscribed monitors. Each monitor has a bounded buffer.
+// Entries are dropped for monitors whose buffer is full, so that slow monitors never block the server.
+type Monitor struct {
+	mut         sync.RWMutex
+	bufferSize  int
+	subscribers map[*subscriber]struct{}
+	active      atomic.Int64 // The number of subscribers. Lets Feed return early without taking the lock.
+}
+
+type subscriber struct {
+	entries chan internal.MonitorEntry
+	dropped atomic.Uint64 // The number of entries dropped since the last entry that was queued.
+}
+
+// WithBufferSize sets the number of entries buffered for each monitor.
+func WithBufferSize(size int) func(monitor *Monitor) {
+	return func(monitor *Monitor) {
+		if size > 0 {
+			monitor.bufferSize = size
+		}
+	}
+}
+
+func NewMonitor(options ...func(monitor *Monitor)) *Monitor {
+	monitor := &Monitor{
+		bufferSize:  1024,
+		subscribers: make(map[*subscriber]struct{}),
+	}
+	for _, option := range options {
+		option(monitor)
+	}
+	return monitor
+}
+
+// Active returns true when at least one monitor is subscribed.
+func (monitor *Monitor) Active() bool {
+	return monitor.active.Load() > 0
+}
+
+// Subscribe returns a channel that receives every entry fed from now on.
+// The channel is closed when the context is cancelled.
+func (monitor *Monitor) Subscribe(ctx context.Context) <-chan internal.MonitorEntry {
+	sub := &subscriber{entries: make(chan internal.MonitorEntry, monitor.bufferSize)}
+
+	monitor.mut.Lock()
+	monitor.subscribers[sub] = struct{}{}
+	monitor.active.Add(1)
+	monitor.mut.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		monitor.mut.Lock()
+		delete(monitor.subscribers, sub)
+		monitor.active.Add(-1)
+		monitor.mut.Unlock()
+		close(sub.entries)
+	}()
+
+	return sub.entries
+}
+
+// NumSubscribers returns the number of active monitors.
+func (monitor *Monitor) NumSubscribers() int {
+	return int(monitor.active.Load())
+}
+
+// Feed redacts the sensitive arguments of the entry's command and queues the entry for every monitor.
+// The entry is dropped for the monitors whose buffer is full.
+func (monitor *Monitor) Feed(entry internal.MonitorEntry) {
+	if !monitor.Active() {
+		return
+	}
+
+	entry.Command = Redact(entry.Command)
+
+	monitor.mut.RLock()
+	defer monitor.mut.RUnlock()
+
+	for sub := range monitor.subscribers {
+		e := entry
+		e.Dropped = sub.dropped.Swap(0)
+		select {
+		case sub.entries <- e:
+		default:
+			sub.dropped.Add(e.Dropped + 1)
+		}
+	}
+}
+
+// Redact returns the command with its passwords replaced. The command is returned as is when it holds no passwords.
+//
+// The passwords are the arguments of AUTH, the password following AUTH in HELLO, the password rules (>, <, # and !)
+// of ACL SETUSER, and the value of the password parameter of CONFIG SET.
+func Redact(command []string) []string {
+	if len(command) == 0 {
+		return command
+	}
+
+	var redact func(i int, arg string) bool
+	switch {
+	case strings.EqualFold(command[0], "auth"):
+		redact = func(i int, _ string) bool { return i > 0 }
+	case strings.EqualFold(command[0], "hello"):
+		redact = func(i int, _ string) bool { return i > 2 && strings.EqualFold(command[i-2], "auth") }
+	case strings.EqualFold(command[0], "acl") && len(command) > 1 && strings.EqualFold(command[1], "setuser"):
+		redact = func(i int, arg string) bool { return i > 2 && arg != "" && strings.ContainsAny(arg[:1], "><#!") }
+	case strings.EqualFold(command[0], "config") && len(command) > 1 && strings.EqualFold(command[1], "set"):
+		redact = func(i int, _ string) bool { return i > 2 && i%2 == 1 && strings.EqualFold(command[i-1], "password") }
+	default:
+		return command
+	}
+
+	res := make([]string, len(command))
+	for i, arg := range command {
+		if redact(i, arg) {
+			res[i] = Redacted
+			continue
+		}
+		res[i] = arg
+	}
+	return res
+}
+
+// Format formats the entry as the status reply sent to MONITOR connections, e.g.
+//
+//	+1700000000.123456 [0 tcp 127.0.0.1:50000 client-name] "SET" "key" "value"
+//
+// The client address and name are omitted when empty. When entries were dropped before this one,
+// a line reporting the number of dropped entries comes first.
+func Format(entry internal.MonitorEntry) []byte {
+	var b strings.Builder
+
+	if entry.Dropped > 0 {
+		b.WriteString(fmt.Sprintf("+(%d entries dropped)\r\n", entry.Dropped))
+	}
+
+	b.WriteString(fmt.Sprintf("+%d.%06d [%d %s",
+		entry.Timestamp.Unix(), entry.Timestamp.Nanosecond()/1000, entry.Database, entry.Source))
+	if entry.ClientAddr != "" {
+		b.WriteString(" " + entry.ClientAddr)
+	}
+	if entry.ClientName != "" {
+		b.WriteString(" " + entry.ClientName)
+	}
+	b.WriteString("]")
+
+	for _, arg := range entry.Command {
+		// Escape the argument so that it's printable and never breaks the status reply.
+		b.WriteString(" " + strconv.Quote(arg))
+	}
+	b.WriteString("\r\n")
+
+	return []byte(b.String())
+}
@@ -0,0 +1,136 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitor_test
+
+import (
+	"context"
+	"github.com/echovault/echovault/internal"
+	"github.com/echovault/echovault/internal/monitor"
+	"slices"
+	"testing"
+	"time"
+)
+
+func Test_Monitor(t *testing.T) {
+	m := monitor.NewMonitor(monitor.WithBufferSize(2))
+	entry := internal.MonitorEntry{Source: monitor.SourceTCP, Command: []string{"GET", "key"}}
+
+	// Entries are only queued while a monitor is subscribed.
+	m.Feed(entry)
+	if m.Active() {
+		t.Fatal("expected no active monitor")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	entries := m.Subscribe(ctx)
+	if !m.Active() || m.NumSubscribers() != 1 {
+		t.Fatalf("expected 1 active monitor, got %d", m.NumSubscribers())
+	}
+
+	// The buffer holds 2 entries, so the next 2 are dropped.
+	for i := 0; i < 4; i++ {
+		m.Feed(entry)
+	}
+	for i := 0; i < 2; i++ {
+		if e := <-entries; e.Dropped != 0 || !slices.Equal(e.Command, entry.Command) {
+			t.Errorf("unexpected entry %+v", e)
+		}
+	}
+
+	// The next entry reports the dropped entries.
+	m.Feed(entry)
+	if e := <-entries; e.Dropped != 2 {
+		t.Errorf("expected 2 dropped entries, got %d", e.Dropped)
+	}
+
+	cancel()
+	select {
+	case _, ok := <-entries:
+		if ok {
+			t.Error("expected the channel to be closed without further entries")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the channel to close")
+	}
+	if m.Active() {
+		t.Error("expected no active monitor after the context is cancelled")
+	}
+}
+
+func Test_Redact(t *testing.T) {
+	tests := []struct {
+		name     string
+		command  []string
+		expected []string
+	}{
+		{
+			name:     "1. Redact the arguments of AUTH",
+			command:  []string{"AUTH", "user", "password"},
+			expected: []string{"AUTH", monitor.Redacted, monitor.Redacted},
+		},
+		{
+			name:     "2. Redact the password of HELLO",
+			command:  []string{"hello", "3", "auth", "user", "password", "SETNAME", "client"},
+			expected: []string{"hello", "3", "auth", "user", monitor.Redacted, "SETNAME", "client"},
+		},
+		{
+			name:     "3. Redact the password rules of ACL SETUSER",
+			command:  []string{"ACL", "SETUSER", "user", "on", ">password", "#hash", "<old", "+@all"},
+			expected: []string{"ACL", "SETUSER", "user", "on", monitor.Redacted, monitor.Redacted, monitor.Redacted, "+@all"},
+		},
+		{
+			name:     "4. Redact the password parameter of CONFIG SET",
+			command:  []string{"CONFIG", "SET", "require-pass", "yes", "PASSWORD", "password"},
+			expected: []string{"CONFIG", "SET", "require-pass", "yes", "PASSWORD", monitor.Redacted},
+		},
+		{
+			name:     "5. Keep commands without passwords",
+			command:  []string{"SET", "auth", ">password"},
+			expected: []string{"SET", "auth", ">password"},
+		},
+	}
+
+	for _, test := range tests {
+		if got := monitor.Redact(test.command); !slices.Equal(got, test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, got)
+		}
+	}
+}
+
+func Test_Format(t *testing.T) {
+	entry := internal.MonitorEntry{
+		Timestamp:  time.Unix(1700000000, 123456000),
+		Database:   1,
+		Source:     monitor.SourceTCP,
+		ClientAddr: "127.0.0.1:50000",
+		ClientName: "client",
+		Command:    []string{"SET", "key", "two words\r\n"},
+	}
+	expected := "+1700000000.123456 [1 tcp 127.0.0.1:50000 client] \"SET\" \"key\" \"two words\\r\\n\"\r\n"
+	if got := string(monitor.Format(entry)); got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+
+	entry = internal.MonitorEntry{
+		Timestamp: time.Unix(1700000000, 0),
+		Source:    monitor.SourceEmbedded,
+		Command:   []string{"PING"},
+		Dropped:   3,
+	}
+	expected = "+(3 entries dropped)\r\n+1700000000.000000 [0 embedded] \"PING\"\r\n"
+	if got := string(monitor.Format(entry)); got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package raft
+
+import (
+	"fmt"
+	"github.com/echovault/echovault/internal"
+	"github.com/hashicorp/raft"
+	"sync"
+	"time"
+)
+
+const (
+	// maxBatchSize is the maximum number of requests in a single batch log entry.
+	maxBatchSize = 256
+	// maxInflightBatches is the number of batches that can be committing at the same time.
+	// Requests that arrive while the batches are committing are collected into the next batch.
+	maxInflightBatches = 2
+)
+
+type pendingApply struct {
+	request internal.ApplyRequest
+	timeout time.Duration
+	done    chan internal.ApplyResponse
+}
+
+func (p *pendingApply) fail(err error) {
+	p.done <- internal.ApplyResponse{Error: err}
+}
+
+// batcher implements group commit. Concurrent requests are collected into a single log entry, so that the
+// cost of replicating and persisting the entry is shared by every request in the batch.
+type batcher struct {
+	raft     *raft.Raft
+	pending  chan *pendingApply
+	inflight chan struct{}
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+func newBatcher(r *raft.Raft) *batcher {
+	b := &batcher{
+		raft:     r,
+		pending:  make(chan *pendingApply, maxBatchSize),
+		inflight: make(chan struct{}, maxInflightBatches),
+		stop:     make(chan struct{}),
+	}
+	go b.run()
+	return b
+}
+
+// apply queues the request and returns the response of its handler once the batch is applied by the FSM.
+// The timeout bounds how long the request can wait to be queued, in the same way as raft.Apply.
+func (b *batcher) apply(request internal.ApplyRequest, timeout time.Duration) ([]byte, error) {
+	p := &pendingApply{
+		request: request,
+		timeout: timeout,
+		done:    make(chan internal.ApplyResponse, 1),
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case b.pending <- p:
+	case <-timer.C:
+		return nil, raft.ErrEnqueueTimeout
+	case <-b.stop:
+		return nil, raft.ErrRaftShutdown
+	}
+
+	select {
+	case res := <-p.done:
+		return res.Response, res.Error
+	case <-b.stop:
+		return nil, raft.ErrRaftShutdown
+	}
+}
+
+func (b *batcher) run() {
+	for {
+		// Wait for a commit slot before collecting the batch, so that requests keep accumulating while the
+		// previous batches are committing.
+		select {
+		case b.inflight <- struct{}{}:
+		case <-b.stop:
+			return
+		}
+
+		var first *pendingApply
+		select {
+		case first = <-b.pending:
+		case <-b.stop:
+			return
+		}
+
+		batch := []*pendingApply{first}
+	collect:
+		for len(batch) < maxBatchSize {
+			select {
+			case p := <-b.pending:
+				batch = append(batch, p)
+			default:
+				break collect
+			}
+		}
+
+		requests := make([]internal.ApplyRequest, len(batch))
+		timeout := time.Duration(0)
+		for i, p := range batch {
+			requests[i] = p.request
+			timeout = max(timeout, p.timeout)
+		}
+
+		future := b.raft.Apply(EncodeApplyRequests(requests), timeout)
+		go func() {
+			defer func() { <-b.inflight }()
+			b.respond(batch, future)
+		}()
+	}
+}
+
+// respond waits for the batch to be applied and hands each caller the response to its own request.
+func (b *batcher) respond(batch []*pendingApply, future raft.ApplyFuture) {
+	if err := future.Error(); err != nil {
+		for _, p := range batch {
+			p.fail(err)
+		}
+		return
+	}
+
+	responses, ok := future.Response().([]internal.ApplyResponse)
+	if !ok || len(responses) != len(batch) {
+		err := fmt.Errorf("unprocessable entity %v", future.Response())
+		if res, ok := future.Response().(internal.ApplyResponse); ok && res.Error != nil {
+			err = res.Error
+		}
+		for _, p := range batch {
+			p.fail(err)
+		}
+		return
+	}
+
+	for i, p := range batch {
+		p.done <- responses[i]
+	}
+}
+
+// close stops collecting batches. Callers waiting for a response return raft.ErrRaftShutdown.
+func (b *batcher) close() {
+	b.stopOnce.Do(func() {
+		close(b.stop)
+	})
+}
@@ -0,0 +1,173 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package raft
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/echovault/echovault/internal"
+)
+
+// batchFormatVersion is the first byte of a binary encoded batch log entry.
+// Log entries written before batching are JSON encoded and start with '{'.
+const batchFormatVersion byte = 1
+
+// EncodeApplyRequests encodes a batch of apply requests into a single log entry.
+//
+// The entry is made up of the format version, the number of requests, and the fields of each request in order.
+// Strings and byte slices are prefixed with their length, and integers are varint encoded.
+func EncodeApplyRequests(requests []internal.ApplyRequest) []byte {
+	b := []byte{batchFormatVersion}
+	b = binary.AppendUvarint(b, uint64(len(requests)))
+	for _, request := range requests {
+		b = appendString(b, request.Type)
+		b = appendString(b, request.ServerID)
+		b = appendString(b, request.ConnectionID)
+		b = binary.AppendVarint(b, int64(request.Protocol))
+		b = binary.AppendVarint(b, int64(request.Database))
+		b = binary.AppendUvarint(b, uint64(len(request.CMD)))
+		for _, arg := range request.CMD {
+			b = appendString(b, arg)
+		}
+		b = appendString(b, request.Key)
+		b = appendBytes(b, request.ACLState)
+		b = appendString(b, request.Reason)
+	}
+	return b
+}
+
+// DecodeApplyRequests decodes a log entry into the batch of apply requests it holds.
+// JSON encoded entries from before batching are decoded into a batch of one request.
+func DecodeApplyRequests(data []byte) ([]internal.ApplyRequest, error) {
+	if len(data) == 0 {
+		return nil, errors.New("empty log entry")
+	}
+
+	if data[0] == '{' {
+		var request internal.ApplyRequest
+		if err := json.Unmarshal(data, &request); err != nil {
+			return nil, err
+		}
+		return []internal.ApplyRequest{request}, nil
+	}
+
+	if data[0] != batchFormatVersion {
+		return nil, fmt.Errorf("unsupported log entry format %d", data[0])
+	}
+
+	d := decoder{data: data[1:]}
+	count := d.uvarint()
+	// Every request takes at least one byte, which bounds the allocation for corrupted entries.
+	if d.err == nil && count > uint64(len(d.data)) {
+		return nil, errors.New("malformed log entry")
+	}
+
+	requests := make([]internal.ApplyRequest, count)
+	for i := range requests {
+		requests[i].Type = d.string()
+		requests[i].ServerID = d.string()
+		requests[i].ConnectionID = d.string()
+		requests[i].Protocol = int(d.varint())
+		requests[i].Database = int(d.varint())
+		if args := d.uvarint(); args > 0 && d.err == nil {
+			if args > uint64(len(d.data)) {
+				return nil, errors.New("malformed log entry")
+			}
+			requests[i].CMD = make([]string, args)
+			for j := range requests[i].CMD {
+				requests[i].CMD[j] = d.string()
+			}
+		}
+		requests[i].Key = d.string()
+		requests[i].ACLState = d.bytes()
+		requests[i].Reason = d.string()
+	}
+
+	if d.err != nil {
+		return nil, d.err
+	}
+	if len(d.data) > 0 {
+		return nil, errors.New("malformed log entry: unexpected trailing bytes")
+	}
+
+	return requests, nil
+}
+
+func appendString(b []byte, s string) []byte {
+	b = binary.AppendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+func appendBytes(b []byte, p []byte) []byte {
+	b = binary.AppendUvarint(b, uint64(len(p)))
+	return append(b, p...)
+}
+
+// decoder reads the fields of a binary encoded log entry.
+// The first error is kept, and every subsequent read returns the zero value.
+type decoder struct {
+	data []byte
+	err  error
+}
+
+func (d *decoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.err = errors.New("malformed log entry")
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+func (d *decoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(d.data)
+	if n <= 0 {
+		d.err = errors.New("malformed log entry")
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+func (d *decoder) bytes() []byte {
+	l := d.uvarint()
+	if d.err != nil {
+		return nil
+	}
+	if l > uint64(len(d.data)) {
+		d.err = errors.New("malformed log entry")
+		return nil
+	}
+	if l == 0 {
+		return nil
+	}
+	b := make([]byte, l)
+	copy(b, d.data[:l])
+	d.data = d.data[l:]
+	return b
+}
+
+func (d *decoder) string() string {
+	return string(d.bytes())
+}
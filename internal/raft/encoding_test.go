@@ -0,0 +1,84 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package raft_test
+
+import (
+	"encoding/json"
+	"github.com/echovault/echovault/internal"
+	"github.com/echovault/echovault/internal/raft"
+	"reflect"
+	"testing"
+)
+
+func Test_ApplyRequestEncoding(t *testing.T) {
+	requests := []internal.ApplyRequest{
+		{
+			Type:         "command",
+			ServerID:     "SERVER-1",
+			ConnectionID: "conn-1",
+			Protocol:     3,
+			Database:     2,
+			CMD:          []string{"SET", "key1", "value with spaces", ""},
+		},
+		{
+			Type:         "delete-key",
+			ServerID:     "SERVER-1",
+			ConnectionID: "nil",
+			Protocol:     2,
+			Database:     0,
+			Key:          "key2",
+			Reason:       "expire",
+		},
+		{
+			Type:         "acl-state",
+			ServerID:     "SERVER-2",
+			ConnectionID: "nil",
+			ACLState:     []byte(`[{"Username":"default"}]`),
+		},
+	}
+
+	t.Run("1. Decode a batch of binary encoded requests", func(t *testing.T) {
+		decoded, err := raft.DecodeApplyRequests(raft.EncodeApplyRequests(requests))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(decoded, requests) {
+			t.Errorf("expected %+v, got %+v", requests, decoded)
+		}
+	})
+
+	t.Run("2. Decode a JSON encoded request from before batching", func(t *testing.T) {
+		b, err := json.Marshal(requests[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded, err := raft.DecodeApplyRequests(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(decoded, requests[:1]) {
+			t.Errorf("expected %+v, got %+v", requests[:1], decoded)
+		}
+	})
+
+	t.Run("3. Return an error for malformed entries", func(t *testing.T) {
+		b := raft.EncodeApplyRequests(requests)
+		for _, data := range [][]byte{nil, {0xff}, b[:len(b)-1], append(b, 0)} {
+			if _, err := raft.DecodeApplyRequests(data); err == nil {
+				t.Errorf("expected error when decoding %v", data)
+			}
+		}
+	})
+}
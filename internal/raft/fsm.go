@@ -15,14 +15,16 @@
 package raft
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/echovault/echovault/internal"
 	"github.com/echovault/echovault/internal/config"
+	"github.com/echovault/echovault/internal/metrics"
 	"github.com/hashicorp/raft"
 	"io"
-	"log"
 	"net"
 	"strings"
 	"time"
@@ -32,13 +34,18 @@ type FSMOpts struct {
 	Config                config.Config
 	GetState              func() map[int]map[string]internal.KeyData
 	GetCommand            func(command string) (internal.Command, error)
-	SetValues             func(ctx context.Context, entries map[string]interface{}) error
-	SetExpiry             func(ctx context.Context, key string, expire time.Time, touch bool)
+	SetState              func(state map[int]map[string]internal.KeyData) // Replaces the whole keyspace.
 	DeleteKey             func(ctx context.Context, key string) error
 	StartSnapshot         func()
 	FinishSnapshot        func()
+	StartApply            func() // Called before a batch is applied, to keep the readers out until it's applied. Optional.
+	FinishApply           func() // Called once the batch is applied. Optional.
 	SetLatestSnapshotTime func(msec int64)
 	GetHandlerFuncParams  func(ctx context.Context, cmd []string, conn *net.Conn) internal.HandlerFuncParams
+	GetACLState           func() ([]byte, error)
+	SetACLState           func(state []byte) error
+	SnapshotDuration      *metrics.Histogram                      // Observes the time taken to persist each snapshot. Optional.
+	MonitorCommand        func(ctx context.Context, cmd []string) // Called with each command applied from the log. Optional.
 }
 
 type FSM struct {
@@ -52,128 +59,211 @@ func NewFSM(opts FSMOpts) raft.FSM {
 }
 
 // Apply Implements raft.FSM interface
+//
+// Each log entry holds a batch of requests. The requests are applied in order within a single call, so no other
+// log entry is applied in the middle of a batch. The response is a slice with the response to each request.
 func (fsm *FSM) Apply(log *raft.Log) interface{} {
 	switch log.Type {
 	default:
 		// No-Op
 	case raft.LogCommand:
-		var request internal.ApplyRequest
-
-		if err := json.Unmarshal(log.Data, &request); err != nil {
+		requests, err := DecodeApplyRequests(log.Data)
+		if err != nil {
 			return internal.ApplyResponse{
 				Error:    err,
 				Response: nil,
 			}
 		}
 
-		ctx := context.WithValue(context.Background(), internal.ContextServerID("ServerID"), request.ServerID)
-		ctx = context.WithValue(ctx, internal.ContextConnID("ConnectionID"), request.ConnectionID)
-		ctx = context.WithValue(ctx, "Protocol", request.Protocol)
-		ctx = context.WithValue(ctx, "Database", request.Database)
+		// The batch is applied atomically, so the read commands see either none or all of its writes.
+		if fsm.options.StartApply != nil {
+			fsm.options.StartApply()
+			defer fsm.options.FinishApply()
+		}
+
+		responses := make([]internal.ApplyResponse, len(requests))
+		for i, request := range requests {
+			responses[i] = fsm.applyRequest(request)
+		}
+		return responses
+	}
+
+	return nil
+}
+
+func (fsm *FSM) applyRequest(request internal.ApplyRequest) internal.ApplyResponse {
+	ctx := context.WithValue(context.Background(), internal.ContextServerID("ServerID"), request.ServerID)
+	ctx = context.WithValue(ctx, internal.ContextConnID("ConnectionID"), request.ConnectionID)
+	ctx = context.WithValue(ctx, "Protocol", request.Protocol)
+	ctx = context.WithValue(ctx, "Database", request.Database)
+
+	switch strings.ToLower(request.Type) {
+	default:
+		return internal.ApplyResponse{
+			Error:    fmt.Errorf("unsupported raft command type %s", request.Type),
+			Response: nil,
+		}
 
-		switch strings.ToLower(request.Type) {
-		default:
+	case "delete-key":
+		if request.Reason != "" {
+			ctx = context.WithValue(ctx, internal.ContextDeleteReason("Reason"), request.Reason)
+		}
+		if err := fsm.options.DeleteKey(ctx, request.Key); err != nil {
 			return internal.ApplyResponse{
-				Error:    fmt.Errorf("unsupported raft command type %s", request.Type),
+				Error:    err,
 				Response: nil,
 			}
+		}
+		return internal.ApplyResponse{
+			Error:    nil,
+			Response: []byte("OK"),
+		}
 
-		case "delete-key":
-			if err := fsm.options.DeleteKey(ctx, request.Key); err != nil {
-				return internal.ApplyResponse{
-					Error:    err,
-					Response: nil,
-				}
+	case "acl-state":
+		if err := fsm.options.SetACLState(request.ACLState); err != nil {
+			return internal.ApplyResponse{
+				Error:    err,
+				Response: nil,
 			}
+		}
+		return internal.ApplyResponse{
+			Error:    nil,
+			Response: []byte("OK"),
+		}
+
+	case "command":
+		// Handle command
+		if len(request.CMD) == 0 {
 			return internal.ApplyResponse{
-				Error:    nil,
-				Response: []byte("OK"),
+				Error:    errors.New("empty command"),
+				Response: nil,
 			}
+		}
 
-		case "command":
-			// Handle command
-			command, err := fsm.options.GetCommand(request.CMD[0])
-			if err != nil {
-				return internal.ApplyResponse{
-					Error:    err,
-					Response: nil,
-				}
+		command, err := fsm.options.GetCommand(request.CMD[0])
+		if err != nil {
+			return internal.ApplyResponse{
+				Error:    err,
+				Response: nil,
 			}
+		}
 
-			handler := command.HandlerFunc
+		handler := command.HandlerFunc
+		ctx = context.WithValue(ctx, internal.ContextCommand("Command"), request.CMD)
 
-			sc, err := internal.GetSubCommand(command, request.CMD)
-			if err != nil {
-				return internal.ApplyResponse{
-					Error:    err,
-					Response: nil,
-				}
-			}
-			subCommand, ok := sc.(internal.SubCommand)
-			if ok {
-				handler = subCommand.HandlerFunc
+		if fsm.options.MonitorCommand != nil {
+			fsm.options.MonitorCommand(ctx, request.CMD)
+		}
+
+		sc, err := internal.GetSubCommand(command, request.CMD)
+		if err != nil {
+			return internal.ApplyResponse{
+				Error:    err,
+				Response: nil,
 			}
+		}
+		subCommand, ok := sc.(internal.SubCommand)
+		if ok {
+			handler = subCommand.HandlerFunc
+		}
 
-			if res, err := handler(fsm.options.GetHandlerFuncParams(ctx, request.CMD, nil)); err != nil {
-				return internal.ApplyResponse{
-					Error:    err,
-					Response: nil,
-				}
-			} else {
-				return internal.ApplyResponse{
-					Error:    nil,
-					Response: res,
-				}
+		if res, err := handler(fsm.options.GetHandlerFuncParams(ctx, request.CMD, nil)); err != nil {
+			return internal.ApplyResponse{
+				Error:    err,
+				Response: nil,
+			}
+		} else {
+			return internal.ApplyResponse{
+				Error:    nil,
+				Response: res,
 			}
 		}
 	}
-
-	return nil
 }
 
 // Snapshot implements raft.FSM interface
 func (fsm *FSM) Snapshot() (raft.FSMSnapshot, error) {
+	aclState, err := fsm.options.GetACLState()
+	if err != nil {
+		return nil, err
+	}
 	return NewFSMSnapshot(SnapshotOpts{
 		config:                fsm.options.Config,
 		startSnapshot:         fsm.options.StartSnapshot,
 		finishSnapshot:        fsm.options.FinishSnapshot,
 		setLatestSnapshotTime: fsm.options.SetLatestSnapshotTime,
 		data:                  fsm.options.GetState(),
+		aclState:              aclState,
+		duration:              fsm.options.SnapshotDuration,
 	}), nil
 }
 
 // Restore implements raft.FSM interface
+//
+// The snapshot is decoded into a new keyspace, which replaces the current one only once the whole snapshot is
+// read and its checksum is verified. Snapshots taken before the binary format are JSON encoded and are restored
+// with the JSON decoder.
 func (fsm *FSM) Restore(snapshot io.ReadCloser) error {
-	b, err := io.ReadAll(snapshot)
+	defer func() {
+		_ = snapshot.Close()
+	}()
+
+	r := bufio.NewReader(snapshot)
+	if !IsBinarySnapshot(r) {
+		return fsm.restoreJSON(r)
+	}
 
+	decoder, err := NewSnapshotDecoder(r)
 	if err != nil {
-		log.Fatal(err)
 		return err
 	}
 
+	state := make(map[int]map[string]internal.KeyData)
+	for {
+		database, key, keyData, err := decoder.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if state[database] == nil {
+			state[database] = make(map[string]internal.KeyData)
+		}
+		state[database][key] = keyData
+	}
+
+	return fsm.restoreState(state, decoder.ACLState, decoder.LatestSnapshotMilliseconds)
+}
+
+// restoreJSON restores a JSON encoded snapshot.
+func (fsm *FSM) restoreJSON(r io.Reader) error {
 	data := internal.SnapshotObject{
 		State:                      make(map[int]map[string]internal.KeyData),
 		LatestSnapshotMilliseconds: 0,
 	}
 
-	if err = json.Unmarshal(b, &data); err != nil {
-		log.Fatal(err)
-		return err
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return fmt.Errorf("snapshot: %v", err)
 	}
 
-	// Set state
-	for database, data := range internal.FilterExpiredKeys(time.Now(), data.State) {
-		ctx := context.WithValue(context.Background(), "Database", database)
-		for key, keyData := range data {
-			if err = fsm.options.SetValues(ctx, map[string]interface{}{key: keyData.Value}); err != nil {
-				log.Fatal(err)
-			}
-			fsm.options.SetExpiry(ctx, key, keyData.ExpireAt, false)
+	return fsm.restoreState(data.State, data.ACLState, data.LatestSnapshotMilliseconds)
+}
+
+// restoreState replaces the keyspace with the keys of the snapshot that are not expired yet. The ACL state is
+// restored first, as it's the only step that can fail, so that a failed restore leaves the keyspace unchanged.
+func (fsm *FSM) restoreState(state map[int]map[string]internal.KeyData, aclState []byte, latestSnapshotMilliseconds int64) error {
+	// Set ACL state. Snapshots taken before the ACL was replicated do not have an ACL state.
+	if len(aclState) > 0 {
+		if err := fsm.options.SetACLState(aclState); err != nil {
+			return err
 		}
 	}
 
+	fsm.options.SetState(internal.FilterExpiredKeys(time.Now(), state))
+
 	// Set latest snapshot milliseconds.
-	fsm.options.SetLatestSnapshotTime(data.LatestSnapshotMilliseconds)
+	fsm.options.SetLatestSnapshotTime(latestSnapshotMilliseconds)
 
 	return nil
 }
@@ -15,10 +15,11 @@
 package raft
 
 import (
-	"encoding/json"
 	"github.com/echovault/echovault/internal"
 	"github.com/echovault/echovault/internal/config"
+	"github.com/echovault/echovault/internal/metrics"
 	"github.com/hashicorp/raft"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -27,9 +28,11 @@ import (
 type SnapshotOpts struct {
 	config                config.Config
 	data                  map[int]map[string]internal.KeyData
+	aclState              []byte
 	startSnapshot         func()
 	finishSnapshot        func()
 	setLatestSnapshotTime func(msec int64)
+	duration              *metrics.Histogram
 }
 
 type Snapshot struct {
@@ -43,7 +46,16 @@ func NewFSMSnapshot(opts SnapshotOpts) *Snapshot {
 }
 
 // Persist implements FSMSnapshot interface
+//
+// The snapshot is streamed to the sink one database at a time, with the concrete type of each value.
 func (s *Snapshot) Persist(sink raft.SnapshotSink) error {
+	if s.options.duration != nil {
+		start := time.Now()
+		defer func() {
+			s.options.duration.Observe(time.Since(start))
+		}()
+	}
+
 	s.options.startSnapshot()
 
 	msec, err := strconv.Atoi(strings.Split(sink.ID(), "-")[2])
@@ -52,20 +64,12 @@ func (s *Snapshot) Persist(sink raft.SnapshotSink) error {
 		return err
 	}
 
-	snapshotObject := internal.SnapshotObject{
-		State:                      internal.FilterExpiredKeys(time.Now(), s.options.data),
-		LatestSnapshotMilliseconds: int64(msec),
-	}
-
-	o, err := json.Marshal(snapshotObject)
-
-	if err != nil {
+	if err = s.write(sink, int64(msec)); err != nil {
 		_ = sink.Cancel()
 		return err
 	}
 
-	if _, err = sink.Write(o); err != nil {
-		_ = sink.Cancel()
+	if err = sink.Close(); err != nil {
 		return err
 	}
 
@@ -74,6 +78,28 @@ func (s *Snapshot) Persist(sink raft.SnapshotSink) error {
 	return nil
 }
 
+func (s *Snapshot) write(sink raft.SnapshotSink, msec int64) error {
+	encoder, err := NewSnapshotEncoder(sink, msec, s.options.aclState)
+	if err != nil {
+		return err
+	}
+
+	state := internal.FilterExpiredKeys(time.Now(), s.options.data)
+	databases := make([]int, 0, len(state))
+	for database := range state {
+		databases = append(databases, database)
+	}
+	slices.Sort(databases)
+
+	for _, database := range databases {
+		if err = encoder.WriteDatabase(database, state[database]); err != nil {
+			return err
+		}
+	}
+
+	return encoder.Close()
+}
+
 // Release implements FSMSnapshot interface
 func (s *Snapshot) Release() {
 	s.options.finishSnapshot()
@@ -0,0 +1,230 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package raft_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/echovault/echovault/internal"
+	"github.com/echovault/echovault/internal/config"
+	"github.com/echovault/echovault/internal/modules/set"
+	"github.com/echovault/echovault/internal/modules/sorted_set"
+	"github.com/echovault/echovault/internal/raft"
+	hraft "github.com/hashicorp/raft"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// testFSM holds the state restored by an FSM created with newTestFSM.
+type testFSM struct {
+	fsm                        hraft.FSM
+	state                      map[int]map[string]internal.KeyData
+	aclState                   []byte
+	latestSnapshotMilliseconds int64
+}
+
+func newTestFSM(state map[int]map[string]internal.KeyData, aclState []byte) *testFSM {
+	f := &testFSM{state: make(map[int]map[string]internal.KeyData)}
+	f.fsm = raft.NewFSM(raft.FSMOpts{
+		Config: config.DefaultConfig(),
+		GetState: func() map[int]map[string]internal.KeyData {
+			return state
+		},
+		SetState: func(state map[int]map[string]internal.KeyData) {
+			f.state = state
+		},
+		StartSnapshot:  func() {},
+		FinishSnapshot: func() {},
+		SetLatestSnapshotTime: func(msec int64) {
+			f.latestSnapshotMilliseconds = msec
+		},
+		GetACLState: func() ([]byte, error) {
+			return aclState, nil
+		},
+		SetACLState: func(state []byte) error {
+			f.aclState = state
+			return nil
+		},
+	})
+	return f
+}
+
+// persist takes a snapshot of the FSM and returns its contents.
+func persist(t *testing.T, fsm hraft.FSM) []byte {
+	store := hraft.NewInmemSnapshotStore()
+	sink, err := store.Create(1, 10, 1, hraft.Configuration{}, 1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshot, err := fsm.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer snapshot.Release()
+	if err = snapshot.Persist(sink); err != nil {
+		t.Fatal(err)
+	}
+	_, rc, err := store.Open(sink.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func Test_FSMSnapshot(t *testing.T) {
+	expireAt := time.Now().Add(time.Hour).Round(0)
+	state := map[int]map[string]internal.KeyData{
+		0: {
+			"string":    {Value: "value1"},
+			"int":       {Value: 10},
+			"float":     {Value: 3.142},
+			"volatile":  {Value: "value2", ExpireAt: expireAt},
+			"expired":   {Value: "value3", ExpireAt: time.Now().Add(-time.Hour)},
+			"hash":      {Value: map[string]interface{}{"field1": "value1", "field2": 2, "field3": 1.5}},
+			"list":      {Value: []string{"elem1", "elem2", "elem3"}},
+			"set":       {Value: set.NewSet([]string{"member1", "member2"})},
+			"sortedSet": {Value: sorted_set.NewSortedSet([]sorted_set.MemberParam{{Value: "member1", Score: 1.5}})},
+		},
+		3: {
+			"string": {Value: "value4"},
+		},
+	}
+	aclState := []byte(`[{"Username":"default"}]`)
+
+	source := newTestFSM(state, aclState)
+	b := persist(t, source.fsm)
+
+	t.Run("1. Restore the snapshot with the concrete value types", func(t *testing.T) {
+		target := newTestFSM(nil, nil)
+		target.state[0] = map[string]internal.KeyData{"existing": {Value: "value"}}
+		if err := target.fsm.Restore(io.NopCloser(bytes.NewReader(b))); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, ok := target.state[0]["existing"]; ok {
+			t.Error("expected the keys that are not in the snapshot to be removed")
+		}
+		if _, ok := target.state[0]["expired"]; ok {
+			t.Error("expected expired key not to be restored")
+		}
+		for database, data := range state {
+			for key, keyData := range data {
+				if key == "expired" {
+					continue
+				}
+				restored, ok := target.state[database][key]
+				if !ok {
+					t.Errorf("expected key %s in database %d to be restored", key, database)
+					continue
+				}
+				if !reflect.DeepEqual(restored.Value, keyData.Value) {
+					t.Errorf("expected key %s to be %#v, got %#v", key, keyData.Value, restored.Value)
+				}
+				if !restored.ExpireAt.Equal(keyData.ExpireAt) {
+					t.Errorf("expected key %s to expire at %v, got %v", key, keyData.ExpireAt, restored.ExpireAt)
+				}
+			}
+		}
+		if !bytes.Equal(target.aclState, aclState) {
+			t.Errorf("expected ACL state %s, got %s", aclState, target.aclState)
+		}
+		if target.latestSnapshotMilliseconds == 0 {
+			t.Error("expected latest snapshot time to be restored")
+		}
+	})
+
+	t.Run("2. Return an error for corrupt snapshots", func(t *testing.T) {
+		corrupted := bytes.Clone(b)
+		corrupted[len(corrupted)/2] ^= 0xff
+		for _, data := range [][]byte{corrupted, b[:len(b)-5], b[:10], []byte("not a snapshot")} {
+			target := newTestFSM(nil, nil)
+			target.state[0] = map[string]internal.KeyData{"existing": {Value: "value"}}
+			if err := target.fsm.Restore(io.NopCloser(bytes.NewReader(data))); err == nil {
+				t.Error("expected error when restoring a corrupt snapshot")
+			}
+			// The keyspace is left as it was.
+			if len(target.state) != 1 || len(target.state[0]) != 1 || target.state[0]["existing"].Value != "value" {
+				t.Errorf("expected the keyspace to be unchanged, got %v", target.state)
+			}
+		}
+	})
+
+	t.Run("3. Restore a JSON snapshot taken before the binary format", func(t *testing.T) {
+		b, err := json.Marshal(internal.SnapshotObject{
+			State: map[int]map[string]internal.KeyData{
+				1: {"key": {Value: "value"}},
+			},
+			LatestSnapshotMilliseconds: 1000,
+			ACLState:                   aclState,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		target := newTestFSM(nil, nil)
+		if err = target.fsm.Restore(io.NopCloser(bytes.NewReader(b))); err != nil {
+			t.Fatal(err)
+		}
+		if target.state[1]["key"].Value != "value" {
+			t.Errorf("expected key to be \"value\", got %v", target.state[1]["key"].Value)
+		}
+		if target.latestSnapshotMilliseconds != 1000 {
+			t.Errorf("expected latest snapshot time 1000, got %d", target.latestSnapshotMilliseconds)
+		}
+	})
+}
+
+func Test_FSMApply(t *testing.T) {
+	// The events are recorded by the FSM callbacks, which the FSM calls from a single goroutine.
+	var events []string
+	fsm := raft.NewFSM(raft.FSMOpts{
+		Config: config.DefaultConfig(),
+		GetCommand: func(command string) (internal.Command, error) {
+			return internal.Command{
+				Command: command,
+				HandlerFunc: func(params internal.HandlerFuncParams) ([]byte, error) {
+					events = append(events, params.Command[1])
+					return []byte("+OK\r\n"), nil
+				},
+			}, nil
+		},
+		GetHandlerFuncParams: func(ctx context.Context, cmd []string, conn *net.Conn) internal.HandlerFuncParams {
+			return internal.HandlerFuncParams{Context: ctx, Command: cmd}
+		},
+		StartApply:  func() { events = append(events, "start") },
+		FinishApply: func() { events = append(events, "finish") },
+	})
+
+	requests := []internal.ApplyRequest{
+		{Type: "command", CMD: []string{"SET", "key1", "value1"}},
+		{Type: "command", CMD: []string{"SET", "key2", "value2"}},
+	}
+	responses, ok := fsm.Apply(&hraft.Log{Type: hraft.LogCommand, Data: raft.EncodeApplyRequests(requests)}).([]internal.ApplyResponse)
+	if !ok || len(responses) != len(requests) {
+		t.Fatalf("expected %d responses, got %v", len(requests), responses)
+	}
+
+	// The whole batch is applied between StartApply and FinishApply, so that readers never see it half-applied.
+	if want := []string{"start", "key1", "key2", "finish"}; !reflect.DeepEqual(events, want) {
+		t.Errorf("expected events %v, got %v", want, events)
+	}
+}
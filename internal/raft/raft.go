@@ -20,45 +20,70 @@ import (
 	"fmt"
 	"github.com/echovault/echovault/internal"
 	"github.com/echovault/echovault/internal/config"
+	"github.com/echovault/echovault/internal/logger"
 	"github.com/echovault/echovault/internal/memberlist"
-	"log"
+	"github.com/echovault/echovault/internal/metrics"
 	"net"
-	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/raft"
 	raftboltdb "github.com/hashicorp/raft-boltdb"
 )
 
 type Opts struct {
 	Config                config.Config
-	SetValues             func(ctx context.Context, entries map[string]interface{}) error
-	SetExpiry             func(ctx context.Context, key string, expire time.Time, touch bool)
+	SetState              func(state map[int]map[string]internal.KeyData) // Replaces the whole keyspace.
 	GetState              func() map[int]map[string]internal.KeyData
 	GetCommand            func(command string) (internal.Command, error)
 	DeleteKey             func(ctx context.Context, key string) error
 	StartSnapshot         func()
 	FinishSnapshot        func()
+	StartApply            func() // Called before a batch is applied, to keep the readers out until it's applied. Optional.
+	FinishApply           func() // Called once the batch is applied. Optional.
 	SetLatestSnapshotTime func(msec int64)
 	GetHandlerFuncParams  func(ctx context.Context, cmd []string, conn *net.Conn) internal.HandlerFuncParams
+	GetACLState           func() ([]byte, error)
+	SetACLState           func(state []byte) error
+	MonitorCommand        func(ctx context.Context, cmd []string) // Called with each command applied from the log. Optional.
+	// Logger for the raft library, the transport and the snapshot store. logger.Default() is used when it's nil.
+	Logger logger.Logger
 }
 
 type Raft struct {
-	options Opts
-	raft    *raft.Raft
+	options          Opts
+	raft             *raft.Raft
+	batcher          *batcher
+	snapshotDuration *metrics.Histogram // The time taken to persist each snapshot.
 }
 
 func NewRaft(opts Opts) *Raft {
+	if opts.Logger == nil {
+		opts.Logger = logger.Default()
+	}
 	return &Raft{
-		options: opts,
+		options:          opts,
+		snapshotDuration: metrics.NewHistogram(),
 	}
 }
 
-func (r *Raft) RaftInit(ctx context.Context) {
+func (r *Raft) RaftInit(ctx context.Context) error {
 	conf := r.options.Config
 
+	// The raft library logs through hclog, forward its entries to our logger. The level is filtered by our logger.
+	raftLogger := hclog.New(&hclog.LoggerOptions{
+		Name:        "raft",
+		Level:       hclog.Debug,
+		Output:      logger.Writer(r.options.Logger),
+		DisableTime: true,
+	})
+
 	raftConfig := raft.DefaultConfig()
+	raftConfig.Logger = raftLogger
 	raftConfig.LocalID = raft.ServerID(conf.ServerID)
 	raftConfig.SnapshotThreshold = conf.SnapShotThreshold
 	raftConfig.SnapshotInterval = conf.SnapshotInterval
@@ -75,38 +100,47 @@ func (r *Raft) RaftInit(ctx context.Context) {
 	} else {
 		boltdb, err := raftboltdb.NewBoltStore(filepath.Join(conf.DataDir, "logs.db"))
 		if err != nil {
-			log.Fatal(err)
+			return fmt.Errorf("open raft log store: %w", err)
 		}
 
 		logStore, err = raft.NewLogCache(512, boltdb)
 		if err != nil {
-			log.Fatal(err)
+			return fmt.Errorf("create raft log cache: %w", err)
 		}
 
 		stableStore = raft.StableStore(boltdb)
 
-		snapshotStore, err = raft.NewFileSnapshotStore(conf.DataDir, 2, os.Stdout)
+		snapshotStore, err = raft.NewFileSnapshotStoreWithLogger(conf.DataDir, 2, raftLogger.Named("snapshot"))
 		if err != nil {
-			log.Fatal(err)
+			return fmt.Errorf("create raft snapshot store: %w", err)
 		}
 	}
 
 	bindAddr := fmt.Sprintf("%s:%d", conf.RaftBindAddr, conf.RaftBindPort)
 	advertiseAddr, err := net.ResolveTCPAddr("tcp", bindAddr)
 	if err != nil {
-		log.Fatal(err)
+		return fmt.Errorf("resolve raft address: %w", err)
 	}
 
-	raftTransport, err := raft.NewTCPTransport(
-		bindAddr,
-		advertiseAddr,
-		10,
-		5*time.Second,
-		os.Stdout,
-	)
-
-	if err != nil {
-		log.Fatal(err)
+	var raftTransport raft.Transport
+	if conf.RaftTLS {
+		// Secure the transport with mTLS so that only nodes with a trusted certificate can join the cluster.
+		streamLayer, err := NewTLSStreamLayer(bindAddr, advertiseAddr, conf)
+		if err != nil {
+			return fmt.Errorf("create raft TLS transport: %w", err)
+		}
+		raftTransport = raft.NewNetworkTransportWithLogger(streamLayer, 10, 5*time.Second, raftLogger.Named("transport"))
+	} else {
+		raftTransport, err = raft.NewTCPTransportWithLogger(
+			bindAddr,
+			advertiseAddr,
+			10,
+			5*time.Second,
+			raftLogger.Named("transport"),
+		)
+		if err != nil {
+			return fmt.Errorf("create raft transport: %w", err)
+		}
 	}
 
 	// Start raft echovault
@@ -116,13 +150,18 @@ func (r *Raft) RaftInit(ctx context.Context) {
 			Config:                r.options.Config,
 			GetState:              r.options.GetState,
 			GetCommand:            r.options.GetCommand,
-			SetValues:             r.options.SetValues,
-			SetExpiry:             r.options.SetExpiry,
+			SetState:              r.options.SetState,
 			DeleteKey:             r.options.DeleteKey,
 			StartSnapshot:         r.options.StartSnapshot,
 			FinishSnapshot:        r.options.FinishSnapshot,
+			StartApply:            r.options.StartApply,
+			FinishApply:           r.options.FinishApply,
 			SetLatestSnapshotTime: r.options.SetLatestSnapshotTime,
 			GetHandlerFuncParams:  r.options.GetHandlerFuncParams,
+			GetACLState:           r.options.GetACLState,
+			SetACLState:           r.options.SetACLState,
+			MonitorCommand:        r.options.MonitorCommand,
+			SnapshotDuration:      r.snapshotDuration,
 		}),
 		logStore,
 		stableStore,
@@ -131,7 +170,7 @@ func (r *Raft) RaftInit(ctx context.Context) {
 	)
 
 	if err != nil {
-		log.Fatalf("could not start node with error; %s", err)
+		return fmt.Errorf("could not start node with error; %w", err)
 	}
 
 	if conf.BootstrapCluster {
@@ -148,16 +187,55 @@ func (r *Raft) RaftInit(ctx context.Context) {
 	}
 
 	r.raft = raftServer
+	r.batcher = newBatcher(raftServer)
+	return nil
 }
 
-func (r *Raft) Apply(cmd []byte, timeout time.Duration) raft.ApplyFuture {
-	return r.raft.Apply(cmd, timeout)
+// Apply replicates the request through the raft log and returns the response from the FSM.
+// Concurrent requests are batched into a single log entry.
+func (r *Raft) Apply(request internal.ApplyRequest, timeout time.Duration) ([]byte, error) {
+	return r.batcher.apply(request, timeout)
 }
 
 func (r *Raft) IsRaftLeader() bool {
 	return r.raft.State() == raft.Leader
 }
 
+// State returns the raft state of the node in lower case (leader, follower, candidate or shutdown).
+func (r *Raft) State() string {
+	return strings.ToLower(r.raft.State().String())
+}
+
+// LeaderID returns the server ID of the current leader, or an empty string if there's no leader.
+func (r *Raft) LeaderID() string {
+	_, id := r.raft.LeaderWithID()
+	return string(id)
+}
+
+// CommitIndex returns the index of the latest committed log entry.
+func (r *Raft) CommitIndex() uint64 {
+	index, _ := strconv.ParseUint(r.raft.Stats()["commit_index"], 10, 64)
+	return index
+}
+
+// AppliedIndex returns the index of the latest log entry applied to the FSM.
+func (r *Raft) AppliedIndex() uint64 {
+	return r.raft.AppliedIndex()
+}
+
+// SetSnapshotConfig changes the number of log entries and the interval that trigger a snapshot.
+func (r *Raft) SetSnapshotConfig(threshold uint64, interval time.Duration) error {
+	rc := r.raft.ReloadableConfig()
+	rc.SnapshotThreshold = threshold
+	rc.SnapshotInterval = interval
+	return r.raft.ReloadConfig(rc)
+}
+
+// SnapshotDuration returns the histogram of the time taken to persist snapshots.
+func (r *Raft) SnapshotDuration() metrics.HistogramSnapshot {
+	return r.snapshotDuration.Snapshot()
+}
+
 func (r *Raft) isRaftFollower() bool {
 	return r.raft.State() == raft.Follower
 }
@@ -200,29 +278,128 @@ func (r *Raft) AddVoter(
 }
 
 func (r *Raft) RemoveServer(meta memberlist.NodeMeta) error {
+	return r.RemoveServerByID(string(meta.ServerID))
+}
+
+// RemoveServerByID removes the server from the raft configuration. Only the leader can remove servers.
+func (r *Raft) RemoveServerByID(id string) error {
 	if !r.IsRaftLeader() {
 		return errors.New("not leader, could not remove node")
 	}
 
-	if err := r.raft.RemoveServer(meta.ServerID, 0, 0).Error(); err != nil {
+	if err := r.raft.RemoveServer(raft.ServerID(id), 0, 0).Error(); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (r *Raft) TakeSnapshot() error {
-	return r.raft.Snapshot().Error()
+// Barrier blocks until all the log entries issued before it are committed and applied, or the timeout elapses.
+// It returns the number of other voters that are guaranteed to have persisted those entries.
+func (r *Raft) Barrier(timeout time.Duration) (int, error) {
+	if !r.IsRaftLeader() {
+		return 0, errors.New("not leader, could not wait for commit")
+	}
+
+	if err := r.raft.Barrier(timeout).Error(); err != nil {
+		return 0, err
+	}
+
+	raftConfig := r.raft.GetConfiguration()
+	if err := raftConfig.Error(); err != nil {
+		return 0, errors.New("could not retrieve raft config")
+	}
+
+	voters := 0
+	for _, s := range raftConfig.Configuration().Servers {
+		if s.Suffrage == raft.Voter {
+			voters += 1
+		}
+	}
+
+	// An entry is committed once a quorum of voters, including the leader, has persisted it.
+	return voters / 2, nil
 }
 
-func (r *Raft) RaftShutdown() {
-	// Leadership transfer if current node is the leader.
-	if r.IsRaftLeader() {
-		err := r.raft.LeadershipTransfer().Error()
-		if err != nil {
-			log.Printf("raft shutdown: %v\n", err)
-			return
+// Voters returns the server IDs of the voters in the latest raft configuration.
+func (r *Raft) Voters() ([]string, error) {
+	raftConfig := r.raft.GetConfiguration()
+	if err := raftConfig.Error(); err != nil {
+		return nil, errors.New("could not retrieve raft config")
+	}
+	var voters []string
+	for _, s := range raftConfig.Configuration().Servers {
+		if s.Suffrage == raft.Voter {
+			voters = append(voters, string(s.ID))
+		}
+	}
+	return voters, nil
+}
+
+// HasServer returns true if the server is part of the latest raft configuration.
+func (r *Raft) HasServer(id string) bool {
+	raftConfig := r.raft.GetConfiguration()
+	if err := raftConfig.Error(); err != nil {
+		return false
+	}
+	for _, s := range raftConfig.Configuration().Servers {
+		if string(s.ID) == id {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckQuorumAfterRemoval returns an error if removing the server would leave fewer live voters than the quorum
+// of the remaining configuration. isAlive reports whether a voter is currently reachable.
+func (r *Raft) CheckQuorumAfterRemoval(id string, isAlive func(id string) bool) error {
+	voters, err := r.Voters()
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(voters, id) {
+		return fmt.Errorf("server %s is not a voter in the cluster", id)
+	}
+	if len(voters) == 1 {
+		return fmt.Errorf("server %s is the last voter in the cluster", id)
+	}
+
+	alive := 0
+	for _, voter := range voters {
+		if voter != id && isAlive(voter) {
+			alive += 1
 		}
-		log.Println("leadership transfer successful.")
 	}
+	if quorum := (len(voters)-1)/2 + 1; alive < quorum {
+		return fmt.Errorf("removing server %s would leave %d live voters, but a quorum of %d is required",
+			id, alive, quorum)
+	}
+
+	return nil
+}
+
+// TransferLeadership hands leadership over to another voter if the node is the leader.
+func (r *Raft) TransferLeadership() error {
+	if !r.IsRaftLeader() {
+		return nil
+	}
+	voters, err := r.Voters()
+	if err != nil {
+		return err
+	}
+	if len(voters) < 2 {
+		// There's no other voter to transfer leadership to.
+		return nil
+	}
+	return r.raft.LeadershipTransfer().Error()
+}
+
+// Shutdown stops the raft node.
+func (r *Raft) Shutdown() error {
+	r.batcher.close()
+	return r.raft.Shutdown().Error()
+}
+
+func (r *Raft) TakeSnapshot() error {
+	return r.raft.Snapshot().Error()
 }
@@ -0,0 +1,429 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package raft
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/echovault/echovault/internal"
+	"github.com/echovault/echovault/internal/modules/set"
+	"github.com/echovault/echovault/internal/modules/sorted_set"
+	"hash"
+	"hash/crc32"
+	"io"
+	"math"
+	"time"
+)
+
+// snapshotMagic is the start of a binary snapshot. Snapshots taken before the binary format are JSON encoded.
+var snapshotMagic = []byte("EVSNAP")
+
+const snapshotFormatVersion byte = 1
+
+// maxSnapshotFieldSize is the largest string accepted when decoding a snapshot. It guards against
+// allocating huge buffers when a length prefix is corrupted.
+const maxSnapshotFieldSize = 512 * 1024 * 1024
+
+// Record types in a snapshot.
+const (
+	recordDatabase byte = 1
+	recordKey      byte = 2
+	recordEOF      byte = 0xff
+)
+
+// Value types in a snapshot.
+const (
+	valueString    byte = 1
+	valueInt       byte = 2
+	valueFloat     byte = 3
+	valueHash      byte = 4
+	valueList      byte = 5
+	valueSet       byte = 6
+	valueSortedSet byte = 7
+)
+
+// SnapshotEncoder writes a snapshot to a stream, one database at a time.
+//
+// The snapshot starts with a header holding the latest snapshot time and the ACL state. It's followed by
+// a record for each database and a record for each key in the database, and ends with a CRC32 checksum
+// of everything before it.
+type SnapshotEncoder struct {
+	w   *bufio.Writer
+	out io.Writer
+	crc hash.Hash32
+	buf []byte
+}
+
+// NewSnapshotEncoder writes the snapshot header to w and returns the encoder for the rest of the snapshot.
+func NewSnapshotEncoder(w io.Writer, latestSnapshotMilliseconds int64, aclState []byte) (*SnapshotEncoder, error) {
+	bw := bufio.NewWriter(w)
+	crc := crc32.NewIEEE()
+	e := &SnapshotEncoder{
+		w:   bw,
+		out: io.MultiWriter(bw, crc),
+		crc: crc,
+	}
+
+	e.buf = append(e.buf[:0], snapshotMagic...)
+	e.buf = append(e.buf, snapshotFormatVersion)
+	e.buf = binary.AppendVarint(e.buf, latestSnapshotMilliseconds)
+	e.buf = appendBytes(e.buf, aclState)
+	if err := e.flush(); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// WriteDatabase writes the keys in the database to the snapshot.
+func (e *SnapshotEncoder) WriteDatabase(database int, data map[string]internal.KeyData) error {
+	e.buf = append(e.buf[:0], recordDatabase)
+	e.buf = binary.AppendVarint(e.buf, int64(database))
+	if err := e.flush(); err != nil {
+		return err
+	}
+
+	for key, keyData := range data {
+		e.buf = append(e.buf[:0], recordKey)
+		e.buf = appendString(e.buf, key)
+		var expireAt int64
+		if !keyData.ExpireAt.IsZero() {
+			expireAt = keyData.ExpireAt.UnixNano()
+		}
+		e.buf = binary.AppendVarint(e.buf, expireAt)
+
+		var err error
+		if e.buf, err = appendValue(e.buf, keyData.Value); err != nil {
+			return fmt.Errorf("snapshot key %s: %v", key, err)
+		}
+		if err = e.flush(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Close ends the snapshot with the checksum and flushes it to the underlying writer.
+// It does not close the underlying writer.
+func (e *SnapshotEncoder) Close() error {
+	e.buf = append(e.buf[:0], recordEOF)
+	if err := e.flush(); err != nil {
+		return err
+	}
+	if _, err := e.w.Write(binary.BigEndian.AppendUint32(nil, e.crc.Sum32())); err != nil {
+		return err
+	}
+	return e.w.Flush()
+}
+
+func (e *SnapshotEncoder) flush() error {
+	_, err := e.out.Write(e.buf)
+	return err
+}
+
+func appendValue(b []byte, value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case string:
+		b = append(b, valueString)
+		return appendString(b, v), nil
+	case int:
+		b = append(b, valueInt)
+		return binary.AppendVarint(b, int64(v)), nil
+	case float64:
+		b = append(b, valueFloat)
+		return binary.BigEndian.AppendUint64(b, math.Float64bits(v)), nil
+	case map[string]interface{}:
+		b = append(b, valueHash)
+		b = binary.AppendUvarint(b, uint64(len(v)))
+		for field, fieldValue := range v {
+			b = appendString(b, field)
+			var err error
+			switch fieldValue.(type) {
+			case string, int, float64:
+				if b, err = appendValue(b, fieldValue); err != nil {
+					return nil, err
+				}
+			default:
+				return nil, fmt.Errorf("unsupported hash field type %T", fieldValue)
+			}
+		}
+		return b, nil
+	case []string:
+		b = append(b, valueList)
+		b = binary.AppendUvarint(b, uint64(len(v)))
+		for _, elem := range v {
+			b = appendString(b, elem)
+		}
+		return b, nil
+	case *set.Set:
+		members := v.GetAll()
+		b = append(b, valueSet)
+		b = binary.AppendUvarint(b, uint64(len(members)))
+		for _, member := range members {
+			b = appendString(b, member)
+		}
+		return b, nil
+	case *sorted_set.SortedSet:
+		members := v.GetAll()
+		b = append(b, valueSortedSet)
+		b = binary.AppendUvarint(b, uint64(len(members)))
+		for _, member := range members {
+			b = binary.BigEndian.AppendUint64(b, math.Float64bits(float64(member.Score)))
+			b = appendString(b, string(member.Value))
+		}
+		return b, nil
+	default:
+		return nil, fmt.Errorf("unsupported value type %T", value)
+	}
+}
+
+// SnapshotDecoder reads a snapshot written by SnapshotEncoder from a stream, one key at a time.
+type SnapshotDecoder struct {
+	r        *bufio.Reader
+	crc      hash.Hash32
+	database int
+	// LatestSnapshotMilliseconds is the time the snapshot was taken.
+	LatestSnapshotMilliseconds int64
+	// ACLState is the serialized ACL users in the snapshot.
+	ACLState []byte
+}
+
+// IsBinarySnapshot returns true if the stream starts with a binary snapshot header.
+// Snapshots taken before the binary format are JSON encoded.
+func IsBinarySnapshot(r *bufio.Reader) bool {
+	header, err := r.Peek(len(snapshotMagic))
+	return err == nil && string(header) == string(snapshotMagic)
+}
+
+// NewSnapshotDecoder reads the snapshot header from r and returns the decoder for the rest of the snapshot.
+func NewSnapshotDecoder(r *bufio.Reader) (*SnapshotDecoder, error) {
+	d := &SnapshotDecoder{
+		r:   r,
+		crc: crc32.NewIEEE(),
+	}
+
+	header := make([]byte, len(snapshotMagic)+1)
+	if err := d.read(header); err != nil {
+		return nil, fmt.Errorf("snapshot header: %v", err)
+	}
+	if string(header[:len(snapshotMagic)]) != string(snapshotMagic) {
+		return nil, errors.New("snapshot header: not a binary snapshot")
+	}
+	if version := header[len(snapshotMagic)]; version != snapshotFormatVersion {
+		return nil, fmt.Errorf("snapshot header: unsupported format version %d", version)
+	}
+
+	var err error
+	if d.LatestSnapshotMilliseconds, err = binary.ReadVarint(d); err != nil {
+		return nil, fmt.Errorf("snapshot header: %v", err)
+	}
+	if d.ACLState, err = d.bytes(); err != nil {
+		return nil, fmt.Errorf("snapshot header: %v", err)
+	}
+
+	return d, nil
+}
+
+// Next returns the next key in the snapshot along with its database.
+// It returns io.EOF once every key is read and the checksum matches.
+func (d *SnapshotDecoder) Next() (int, string, internal.KeyData, error) {
+	for {
+		recordType, err := d.ReadByte()
+		if err != nil {
+			return 0, "", internal.KeyData{}, unexpectedEOF(err)
+		}
+
+		switch recordType {
+		default:
+			return 0, "", internal.KeyData{}, fmt.Errorf("snapshot: unknown record type %d", recordType)
+
+		case recordEOF:
+			expected := d.crc.Sum32()
+			checksum := make([]byte, 4)
+			if _, err = io.ReadFull(d.r, checksum); err != nil {
+				return 0, "", internal.KeyData{}, fmt.Errorf("snapshot checksum: %v", unexpectedEOF(err))
+			}
+			if binary.BigEndian.Uint32(checksum) != expected {
+				return 0, "", internal.KeyData{}, errors.New("snapshot checksum mismatch")
+			}
+			return 0, "", internal.KeyData{}, io.EOF
+
+		case recordDatabase:
+			database, err := binary.ReadVarint(d)
+			if err != nil {
+				return 0, "", internal.KeyData{}, unexpectedEOF(err)
+			}
+			d.database = int(database)
+
+		case recordKey:
+			key, err := d.string()
+			if err != nil {
+				return 0, "", internal.KeyData{}, unexpectedEOF(err)
+			}
+			expireAt, err := binary.ReadVarint(d)
+			if err != nil {
+				return 0, "", internal.KeyData{}, unexpectedEOF(err)
+			}
+			value, err := d.value(true)
+			if err != nil {
+				return 0, "", internal.KeyData{}, fmt.Errorf("snapshot key %s: %v", key, unexpectedEOF(err))
+			}
+			keyData := internal.KeyData{Value: value}
+			if expireAt != 0 {
+				keyData.ExpireAt = time.Unix(0, expireAt)
+			}
+			return d.database, key, keyData, nil
+		}
+	}
+}
+
+func (d *SnapshotDecoder) value(nested bool) (interface{}, error) {
+	valueType, err := d.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	switch valueType {
+	case valueString:
+		return d.string()
+	case valueInt:
+		v, err := binary.ReadVarint(d)
+		return int(v), err
+	case valueFloat:
+		return d.float()
+	}
+
+	if !nested {
+		return nil, fmt.Errorf("unsupported hash field type %d", valueType)
+	}
+
+	count, err := binary.ReadUvarint(d)
+	if err != nil {
+		return nil, err
+	}
+	// Every element takes at least one byte, so a corrupted count fails on the next read instead of
+	// allocating a huge collection up front.
+	capacity := min(count, 1024)
+
+	switch valueType {
+	default:
+		return nil, fmt.Errorf("unknown value type %d", valueType)
+
+	case valueHash:
+		entries := make(map[string]interface{}, capacity)
+		for i := uint64(0); i < count; i++ {
+			field, err := d.string()
+			if err != nil {
+				return nil, err
+			}
+			if entries[field], err = d.value(false); err != nil {
+				return nil, err
+			}
+		}
+		return entries, nil
+
+	case valueList, valueSet:
+		elems := make([]string, 0, capacity)
+		for i := uint64(0); i < count; i++ {
+			elem, err := d.string()
+			if err != nil {
+				return nil, err
+			}
+			elems = append(elems, elem)
+		}
+		if valueType == valueSet {
+			return set.NewSet(elems), nil
+		}
+		return elems, nil
+
+	case valueSortedSet:
+		members := make([]sorted_set.MemberParam, 0, capacity)
+		for i := uint64(0); i < count; i++ {
+			score, err := d.float()
+			if err != nil {
+				return nil, err
+			}
+			member, err := d.string()
+			if err != nil {
+				return nil, err
+			}
+			members = append(members, sorted_set.MemberParam{
+				Value: sorted_set.Value(member),
+				Score: sorted_set.Score(score),
+			})
+		}
+		return sorted_set.NewSortedSet(members), nil
+	}
+}
+
+// ReadByte implements io.ByteReader and adds the byte to the checksum.
+func (d *SnapshotDecoder) ReadByte() (byte, error) {
+	b, err := d.r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	_, _ = d.crc.Write([]byte{b})
+	return b, nil
+}
+
+func (d *SnapshotDecoder) read(p []byte) error {
+	if _, err := io.ReadFull(d.r, p); err != nil {
+		return err
+	}
+	_, _ = d.crc.Write(p)
+	return nil
+}
+
+func (d *SnapshotDecoder) bytes() ([]byte, error) {
+	l, err := binary.ReadUvarint(d)
+	if err != nil {
+		return nil, err
+	}
+	if l > maxSnapshotFieldSize {
+		return nil, fmt.Errorf("field length %d exceeds the maximum of %d", l, maxSnapshotFieldSize)
+	}
+	if l == 0 {
+		return nil, nil
+	}
+	b := make([]byte, l)
+	if err = d.read(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (d *SnapshotDecoder) string() (string, error) {
+	b, err := d.bytes()
+	return string(b), err
+}
+
+func (d *SnapshotDecoder) float() (float64, error) {
+	b := make([]byte, 8)
+	if err := d.read(b); err != nil {
+		return 0, err
+	}
+	return math.Float64frombits(binary.BigEndian.Uint64(b)), nil
+}
+
+// unexpectedEOF reports a snapshot that ends before the EOF record as corrupted.
+func unexpectedEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,156 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package raft
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"github.com/echovault/echovault/internal/config"
+	"github.com/hashicorp/raft"
+	"net"
+	"os"
+	"time"
+)
+
+// TLSStreamLayer implements raft.StreamLayer over mutually authenticated TLS connections.
+// Every node presents its certificate when accepting and dialing connections, and peers whose
+// certificates are not signed by one of the configured certificate authorities are rejected.
+type TLSStreamLayer struct {
+	listener  net.Listener
+	advertise net.Addr
+	config    *tls.Config
+}
+
+// NewTLSStreamLayer listens on bindAddr for raft connections secured with the conf.CertKeyPairs
+// certificates and verified against conf.ClientCAs.
+func NewTLSStreamLayer(bindAddr string, advertise net.Addr, conf config.Config) (*TLSStreamLayer, error) {
+	tlsConfig, err := NewTLSConfig(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := tls.Listen("tcp", bindAddr, tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TLSStreamLayer{
+		listener:  listener,
+		advertise: advertise,
+		config:    tlsConfig,
+	}, nil
+}
+
+// NewTLSConfig builds the mTLS configuration shared by the accepting and dialing sides of the raft transport.
+// Nodes dial each other by IP address, so peers are verified against the certificate authorities without
+// checking the host name.
+func NewTLSConfig(conf config.Config) (*tls.Config, error) {
+	if len(conf.CertKeyPairs) == 0 {
+		return nil, errors.New("raft tls: must provide certificate and key file paths")
+	}
+	if len(conf.ClientCAs) == 0 {
+		return nil, errors.New("raft tls: must provide certificate authorities to verify peers")
+	}
+
+	var certificates []tls.Certificate
+	for _, certKeyPair := range conf.CertKeyPairs {
+		c, err := tls.LoadX509KeyPair(certKeyPair[0], certKeyPair[1])
+		if err != nil {
+			return nil, fmt.Errorf("raft tls: load cert key pair: %v", err)
+		}
+		certificates = append(certificates, c)
+	}
+
+	pool := x509.NewCertPool()
+	for _, ca := range conf.ClientCAs {
+		certBytes, err := os.ReadFile(ca)
+		if err != nil {
+			return nil, fmt.Errorf("raft tls: read certificate authority: %v", err)
+		}
+		if ok := pool.AppendCertsFromPEM(certBytes); !ok {
+			return nil, fmt.Errorf("raft tls: no certificates found in %s", ca)
+		}
+	}
+
+	return &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: certificates,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    pool,
+		// The server certificate is verified in VerifyPeerCertificate instead.
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			return verifyPeer(rawCerts, pool)
+		},
+	}, nil
+}
+
+// verifyPeer verifies the certificate chain presented by a peer against the certificate authorities.
+func verifyPeer(rawCerts [][]byte, pool *x509.CertPool) error {
+	if len(rawCerts) == 0 {
+		return errors.New("raft tls: peer did not present a certificate")
+	}
+
+	certs := make([]*x509.Certificate, len(rawCerts))
+	for i, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return fmt.Errorf("raft tls: parse peer certificate: %v", err)
+		}
+		certs[i] = cert
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	if _, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         pool,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}); err != nil {
+		return fmt.Errorf("raft tls: verify peer certificate: %v", err)
+	}
+
+	return nil
+}
+
+// Dial implements raft.StreamLayer. The TLS handshake is completed before the connection is returned,
+// so peers with an untrusted certificate are rejected immediately.
+func (layer *TLSStreamLayer) Dial(address raft.ServerAddress, timeout time.Duration) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: timeout}
+	return tls.DialWithDialer(dialer, "tcp", string(address), layer.config)
+}
+
+// Accept implements net.Listener.
+func (layer *TLSStreamLayer) Accept() (net.Conn, error) {
+	return layer.listener.Accept()
+}
+
+// Close implements net.Listener.
+func (layer *TLSStreamLayer) Close() error {
+	return layer.listener.Close()
+}
+
+// Addr implements net.Listener.
+func (layer *TLSStreamLayer) Addr() net.Addr {
+	if layer.advertise != nil {
+		return layer.advertise
+	}
+	return layer.listener.Addr()
+}
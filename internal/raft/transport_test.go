@@ -0,0 +1,130 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package raft_test
+
+import (
+	"github.com/echovault/echovault/internal/config"
+	"github.com/echovault/echovault/internal/raft"
+	hraft "github.com/hashicorp/raft"
+	"io"
+	"path"
+	"testing"
+	"time"
+)
+
+func tlsConfig(certDir string, cert string, caDir string) config.Config {
+	conf := config.DefaultConfig()
+	conf.RaftTLS = true
+	conf.CertKeyPairs = [][]string{{
+		path.Join("..", "..", "openssl", certDir, cert+".crt"),
+		path.Join("..", "..", "openssl", certDir, cert+".key"),
+	}}
+	conf.ClientCAs = []string{path.Join("..", "..", "openssl", caDir, "rootCA.crt")}
+	return conf
+}
+
+func Test_TLSStreamLayer(t *testing.T) {
+	server, err := raft.NewTLSStreamLayer("127.0.0.1:0", nil, tlsConfig("server", "server1", "server"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = server.Close()
+	}()
+
+	// Echo the first message back on every accepted connection.
+	go func() {
+		for {
+			conn, err := server.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer func() {
+					_ = conn.Close()
+				}()
+				buf := make([]byte, 5)
+				if _, err := io.ReadFull(conn, buf); err != nil {
+					return
+				}
+				_, _ = conn.Write(buf)
+			}()
+		}
+	}()
+
+	tests := []struct {
+		name    string
+		conf    config.Config
+		wantErr bool
+	}{
+		{
+			name: "1. Peer with a certificate signed by a trusted authority can connect",
+			conf: tlsConfig("server", "server2", "server"),
+		},
+		{
+			name:    "2. Peer with a certificate signed by an untrusted authority is rejected",
+			conf:    tlsConfig("client", "client1", "server"),
+			wantErr: true,
+		},
+		{
+			name:    "3. Peer that does not trust the server certificate is rejected",
+			conf:    tlsConfig("server", "server2", "client"),
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, err := raft.NewTLSStreamLayer("127.0.0.1:0", nil, test.conf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() {
+				_ = client.Close()
+			}()
+
+			conn, err := client.Dial(hraft.ServerAddress(server.Addr().String()), time.Second)
+			if err == nil {
+				defer func() {
+					_ = conn.Close()
+				}()
+				// The server verifies the client certificate after the handshake, so the rejection
+				// surfaces on the first exchange.
+				_ = conn.SetDeadline(time.Now().Add(time.Second))
+				if _, err = conn.Write([]byte("hello")); err == nil {
+					buf := make([]byte, 5)
+					_, err = io.ReadFull(conn, buf)
+					if err == nil && string(buf) != "hello" {
+						t.Errorf("expected echo \"hello\", got \"%s\"", string(buf))
+					}
+				}
+			}
+			if test.wantErr && err == nil {
+				t.Error("expected connection to be rejected")
+			}
+			if !test.wantErr && err != nil {
+				t.Errorf("expected connection to succeed, got %v", err)
+			}
+		})
+	}
+
+	t.Run("4. NewTLSConfig returns error when no certificate authorities are provided", func(t *testing.T) {
+		conf := tlsConfig("server", "server1", "server")
+		conf.ClientCAs = []string{}
+		if _, err := raft.NewTLSConfig(conf); err == nil {
+			t.Error("expected error when no certificate authorities are provided")
+		}
+	})
+}
@@ -0,0 +1,162 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package slowlog keeps a bounded log of the commands that took longer than a threshold to execute.
+package slowlog
+
+import (
+	"fmt"
+	"github.com/echovault/echovault/internal"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// MaxArgs is the number of arguments of a command kept in an entry. The remaining arguments are summarised.
+	MaxArgs = 32
+	// MaxArgLen is the number of bytes of an argument kept in an entry. The remaining bytes are summarised.
+	MaxArgLen = 128
+)
+
+type SlowLog struct {
+	mutex     sync.Mutex
+	threshold atomic.Int64 // Commands that take at least this long are logged. A negative threshold disables the log.
+	maxLen    int          // The maximum number of entries kept.
+	nextID    uint64
+	entries   []internal.SlowlogEntry // The entries, oldest first.
+}
+
+func WithThreshold(threshold time.Duration) func(sl *SlowLog) {
+	return func(sl *SlowLog) {
+		sl.threshold.Store(int64(threshold))
+	}
+}
+
+func WithMaxLen(maxLen int) func(sl *SlowLog) {
+	return func(sl *SlowLog) {
+		sl.maxLen = maxLen
+	}
+}
+
+func NewSlowLog(options ...func(sl *SlowLog)) *SlowLog {
+	sl := &SlowLog{
+		maxLen: 128,
+	}
+	sl.threshold.Store(int64(10 * time.Millisecond))
+	for _, option := range options {
+		option(sl)
+	}
+	return sl
+}
+
+// Exceeds returns true when a command that took the given time reaches the threshold. It doesn't lock the log,
+// so that the callers can skip preparing the entry of the commands that are not logged.
+func (sl *SlowLog) Exceeds(duration time.Duration) bool {
+	threshold := time.Duration(sl.threshold.Load())
+	return threshold >= 0 && duration >= threshold
+}
+
+// Record logs the command if its execution time reached the threshold.
+// When the log is full, the oldest entry is removed.
+func (sl *SlowLog) Record(timestamp time.Time, duration time.Duration, command []string, clientAddr string, clientName string) {
+	if !sl.Exceeds(duration) {
+		return
+	}
+
+	sl.mutex.Lock()
+	defer sl.mutex.Unlock()
+
+	if sl.maxLen <= 0 {
+		return
+	}
+
+	sl.entries = append(sl.entries, internal.SlowlogEntry{
+		ID:         sl.nextID,
+		Timestamp:  timestamp,
+		Duration:   duration,
+		Command:    truncate(command),
+		ClientAddr: clientAddr,
+		ClientName: clientName,
+	})
+	sl.nextID++
+
+	if len(sl.entries) > sl.maxLen {
+		sl.entries = append([]internal.SlowlogEntry(nil), sl.entries[len(sl.entries)-sl.maxLen:]...)
+	}
+}
+
+// SetThreshold changes the execution time from which commands are logged. A negative threshold disables the log.
+func (sl *SlowLog) SetThreshold(threshold time.Duration) {
+	sl.threshold.Store(int64(threshold))
+}
+
+// SetMaxLen changes the maximum number of entries kept. The oldest entries are removed when the log is too long.
+func (sl *SlowLog) SetMaxLen(maxLen int) {
+	sl.mutex.Lock()
+	defer sl.mutex.Unlock()
+	sl.maxLen = maxLen
+	if len(sl.entries) > max(maxLen, 0) {
+		sl.entries = append([]internal.SlowlogEntry(nil), sl.entries[len(sl.entries)-max(maxLen, 0):]...)
+	}
+}
+
+// Get returns the latest count entries, newest first. A negative count returns every entry.
+func (sl *SlowLog) Get(count int) []internal.SlowlogEntry {
+	sl.mutex.Lock()
+	defer sl.mutex.Unlock()
+
+	if count < 0 || count > len(sl.entries) {
+		count = len(sl.entries)
+	}
+	res := make([]internal.SlowlogEntry, count)
+	for i := 0; i < count; i++ {
+		res[i] = sl.entries[len(sl.entries)-1-i]
+	}
+	return res
+}
+
+// Len returns the number of entries in the log.
+func (sl *SlowLog) Len() int {
+	sl.mutex.Lock()
+	defer sl.mutex.Unlock()
+	return len(sl.entries)
+}
+
+// Reset removes every entry from the log. IDs keep increasing after a reset.
+func (sl *SlowLog) Reset() {
+	sl.mutex.Lock()
+	defer sl.mutex.Unlock()
+	sl.entries = nil
+}
+
+// truncate copies the command, keeping at most MaxArgs arguments of at most MaxArgLen bytes each.
+func truncate(command []string) []string {
+	n := min(len(command), MaxArgs)
+	if len(command) > MaxArgs {
+		// The last kept argument summarises the remaining arguments.
+		n = MaxArgs - 1
+	}
+	res := make([]string, n, n+1)
+	for i := 0; i < n; i++ {
+		res[i] = command[i]
+		if len(command[i]) > MaxArgLen {
+			res[i] = fmt.Sprintf("%s... (%d more bytes)", command[i][:MaxArgLen], len(command[i])-MaxArgLen)
+		}
+	}
+	if len(command) > MaxArgs {
+		res = append(res, fmt.Sprintf("... (%d more arguments)", len(command)-n))
+	}
+	return res
+}
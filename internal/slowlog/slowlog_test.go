@@ -0,0 +1,98 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slowlog_test
+
+import (
+	"fmt"
+	"github.com/echovault/echovault/internal/slowlog"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_SlowLog(t *testing.T) {
+	log := slowlog.NewSlowLog(slowlog.WithThreshold(time.Millisecond), slowlog.WithMaxLen(3))
+	now := time.Now()
+
+	log.Record(now, 500*time.Microsecond, []string{"GET", "fast"}, "127.0.0.1:1234", "")
+	for i := 0; i < 5; i++ {
+		log.Record(now, time.Duration(i+1)*time.Millisecond, []string{"GET", fmt.Sprintf("key%d", i)}, "127.0.0.1:1234", "client")
+	}
+
+	if log.Len() != 3 {
+		t.Errorf("expected the log to hold 3 entries, got %d", log.Len())
+	}
+
+	entries := log.Get(2)
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].ID != 4 || entries[1].ID != 3 {
+		t.Errorf("expected the newest entries 4 and 3, got %d and %d", entries[0].ID, entries[1].ID)
+	}
+	if !slices.Equal(entries[0].Command, []string{"GET", "key4"}) || entries[0].Duration != 5*time.Millisecond ||
+		entries[0].ClientAddr != "127.0.0.1:1234" || entries[0].ClientName != "client" {
+		t.Errorf("unexpected entry %+v", entries[0])
+	}
+	if len(log.Get(-1)) != 3 {
+		t.Errorf("expected a negative count to return every entry")
+	}
+
+	if log.Exceeds(500*time.Microsecond) || !log.Exceeds(time.Millisecond) {
+		t.Error("expected only the commands that take at least the threshold to be logged")
+	}
+	log.SetThreshold(-1)
+	if log.Exceeds(time.Hour) {
+		t.Error("expected a negative threshold to disable the log")
+	}
+	log.SetThreshold(time.Millisecond)
+
+	log.Reset()
+	if log.Len() != 0 {
+		t.Errorf("expected the log to be empty after a reset, got %d entries", log.Len())
+	}
+	log.Record(now, time.Second, []string{"GET", "key"}, "", "")
+	if entries = log.Get(1); entries[0].ID != 5 {
+		t.Errorf("expected IDs to keep increasing after a reset, got %d", entries[0].ID)
+	}
+}
+
+func Test_SlowLogTruncate(t *testing.T) {
+	log := slowlog.NewSlowLog(slowlog.WithThreshold(0))
+
+	command := []string{"SADD", strings.Repeat("a", slowlog.MaxArgLen+10)}
+	for i := 0; i < 40; i++ {
+		command = append(command, "member")
+	}
+	log.Record(time.Now(), 0, command, "", "")
+
+	entry := log.Get(1)[0]
+	if len(entry.Command) != slowlog.MaxArgs {
+		t.Errorf("expected %d arguments, got %d", slowlog.MaxArgs, len(entry.Command))
+	}
+	if expected := strings.Repeat("a", slowlog.MaxArgLen) + "... (10 more bytes)"; entry.Command[1] != expected {
+		t.Errorf("expected long argument to be truncated to %q, got %q", expected, entry.Command[1])
+	}
+	if last := entry.Command[len(entry.Command)-1]; last != "... (11 more arguments)" {
+		t.Errorf("expected the remaining arguments to be summarised, got %q", last)
+	}
+
+	disabled := slowlog.NewSlowLog(slowlog.WithThreshold(-1))
+	disabled.Record(time.Now(), time.Hour, []string{"GET", "key"}, "", "")
+	if disabled.Len() != 0 {
+		t.Errorf("expected a negative threshold to disable the log")
+	}
+}
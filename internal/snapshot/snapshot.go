@@ -21,9 +21,10 @@ import (
 	"fmt"
 	"github.com/echovault/echovault/internal"
 	"github.com/echovault/echovault/internal/clock"
+	"github.com/echovault/echovault/internal/logger"
+	"github.com/echovault/echovault/internal/metrics"
 	"io"
 	"io/fs"
-	"log"
 	"os"
 	"path"
 	"sync/atomic"
@@ -42,14 +43,17 @@ type Engine struct {
 	clock                     clock.Clock
 	changeCount               atomic.Uint64
 	directory                 string
-	snapshotInterval          time.Duration
-	snapshotThreshold         uint64
+	snapshotInterval          atomic.Int64  // The interval in nanoseconds between the checks of the change count.
+	snapshotThreshold         atomic.Uint64 // The number of changes from which a snapshot is taken.
+	intervalChanged           chan struct{}
 	startSnapshotFunc         func()
 	finishSnapshotFunc        func()
 	getStateFunc              func() map[int]map[string]internal.KeyData
 	setLatestSnapshotTimeFunc func(msec int64)
 	getLatestSnapshotTimeFunc func() int64
 	setKeyDataFunc            func(database int, key string, data internal.KeyData)
+	duration                  *metrics.Histogram
+	logger                    logger.Logger
 }
 
 func WithClock(clock clock.Clock) func(engine *Engine) {
@@ -58,6 +62,12 @@ func WithClock(clock clock.Clock) func(engine *Engine) {
 	}
 }
 
+func WithLogger(logger logger.Logger) func(engine *Engine) {
+	return func(engine *Engine) {
+		engine.logger = logger
+	}
+}
+
 func WithDirectory(directory string) func(engine *Engine) {
 	return func(engine *Engine) {
 		engine.directory = directory
@@ -66,13 +76,13 @@ func WithDirectory(directory string) func(engine *Engine) {
 
 func WithInterval(interval time.Duration) func(engine *Engine) {
 	return func(engine *Engine) {
-		engine.snapshotInterval = interval
+		engine.snapshotInterval.Store(int64(interval))
 	}
 }
 
 func WithThreshold(threshold uint64) func(engine *Engine) {
 	return func(engine *Engine) {
-		engine.snapshotThreshold = threshold
+		engine.snapshotThreshold.Store(threshold)
 	}
 }
 
@@ -117,8 +127,7 @@ func NewSnapshotEngine(options ...func(engine *Engine)) *Engine {
 		clock:              clock.NewClock(),
 		changeCount:        atomic.Uint64{},
 		directory:          "",
-		snapshotInterval:   5 * time.Minute,
-		snapshotThreshold:  1000,
+		intervalChanged:    make(chan struct{}, 1),
 		startSnapshotFunc:  func() {},
 		finishSnapshotFunc: func() {},
 		getStateFunc: func() map[int]map[string]internal.KeyData {
@@ -129,33 +138,73 @@ func NewSnapshotEngine(options ...func(engine *Engine)) *Engine {
 		getLatestSnapshotTimeFunc: func() int64 {
 			return 0
 		},
+		duration: metrics.NewHistogram(),
+		logger:   logger.Default(),
 	}
 
+	engine.snapshotInterval.Store(int64(5 * time.Minute))
+	engine.snapshotThreshold.Store(1000)
+
 	for _, option := range options {
 		option(engine)
 	}
 
-	if engine.snapshotInterval != 0 {
-		go func() {
-			ticker := time.NewTicker(engine.snapshotInterval)
-			defer func() {
-				ticker.Stop()
-			}()
-			for {
-				<-ticker.C
-				if engine.changeCount.Load() == engine.snapshotThreshold {
-					if err := engine.TakeSnapshot(); err != nil {
-						log.Println(err)
-					}
+	go engine.checkChanges()
+
+	return engine
+}
+
+// checkChanges takes a snapshot every interval if the change count reached the threshold.
+// The ticker is reset when the interval changes. An interval of 0 disables the checks.
+func (engine *Engine) checkChanges() {
+	var ticker *time.Ticker
+	var tick <-chan time.Time
+	reset := func() {
+		if ticker != nil {
+			ticker.Stop()
+			ticker, tick = nil, nil
+		}
+		if interval := time.Duration(engine.snapshotInterval.Load()); interval > 0 {
+			ticker = time.NewTicker(interval)
+			tick = ticker.C
+		}
+	}
+	reset()
+
+	for {
+		select {
+		case <-engine.intervalChanged:
+			reset()
+		case <-tick:
+			if engine.changeCount.Load() >= engine.snapshotThreshold.Load() {
+				if err := engine.TakeSnapshot(); err != nil {
+					engine.logger.Error("take snapshot", logger.ErrorKey, err)
 				}
 			}
-		}()
+		}
 	}
+}
 
-	return engine
+// SetInterval changes the interval between the checks of the change count. 0 disables the checks.
+func (engine *Engine) SetInterval(interval time.Duration) {
+	engine.snapshotInterval.Store(int64(interval))
+	select {
+	case engine.intervalChanged <- struct{}{}:
+	default:
+	}
+}
+
+// SetThreshold changes the number of changes from which a snapshot is taken.
+func (engine *Engine) SetThreshold(threshold uint64) {
+	engine.snapshotThreshold.Store(threshold)
 }
 
 func (engine *Engine) TakeSnapshot() error {
+	start := time.Now()
+	defer func() {
+		engine.duration.Observe(time.Since(start))
+	}()
+
 	engine.startSnapshotFunc()
 	defer engine.finishSnapshotFunc()
 
@@ -175,7 +224,6 @@ func (engine *Engine) TakeSnapshot() error {
 
 	dirname := path.Join(engine.directory, "snapshots")
 	if err := os.MkdirAll(dirname, os.ModePerm); err != nil {
-		log.Println(err)
 		return err
 	}
 
@@ -187,23 +235,19 @@ func (engine *Engine) TakeSnapshot() error {
 			// Create file if it does not exist
 			mf, err = os.Create(path.Join(dirname, "manifest.bin"))
 			if err != nil {
-				log.Println(err)
 				return err
 			}
 			firstSnapshot = true
 		} else {
-			log.Println(err)
 			return err
 		}
 	}
 
 	md, err := io.ReadAll(mf)
 	if err != nil {
-		log.Println(err)
 		return err
 	}
 	if err := mf.Close(); err != nil {
-		log.Println(err)
 		return err
 	}
 
@@ -211,7 +255,6 @@ func (engine *Engine) TakeSnapshot() error {
 
 	if !firstSnapshot {
 		if err = json.Unmarshal(md, manifest); err != nil {
-			log.Println(err)
 			return err
 		}
 	}
@@ -223,7 +266,6 @@ func (engine *Engine) TakeSnapshot() error {
 	}
 	out, err := json.Marshal(snapshotObject)
 	if err != nil {
-		log.Println(err)
 		return err
 	}
 
@@ -237,14 +279,12 @@ func (engine *Engine) TakeSnapshot() error {
 	// Marshal the updated snapshotObject
 	out, err = json.Marshal(snapshotObject)
 	if err != nil {
-		log.Println(err)
 		return err
 	}
 
 	// os.Create will replace the old manifest file
 	mf, err = os.Create(path.Join(dirname, "manifest.bin"))
 	if err != nil {
-		log.Println(err)
 		return err
 	}
 
@@ -255,18 +295,15 @@ func (engine *Engine) TakeSnapshot() error {
 	}
 	mo, err := json.Marshal(manifest)
 	if err != nil {
-		log.Println(err)
 		return err
 	}
 	if _, err = mf.Write(mo); err != nil {
-		log.Println(err)
 		return err
 	}
 	if err = mf.Sync(); err != nil {
-		log.Println(err)
+		engine.logger.Error("sync snapshot manifest", logger.ErrorKey, err)
 	}
 	if err = mf.Close(); err != nil {
-		log.Println(err)
 		return err
 	}
 
@@ -279,12 +316,11 @@ func (engine *Engine) TakeSnapshot() error {
 	// Create snapshot file
 	f, err := os.OpenFile(path.Join(dirname, "state.bin"), os.O_WRONLY|os.O_CREATE, os.ModePerm)
 	if err != nil {
-		log.Println(err)
 		return err
 	}
 	defer func() {
 		if err := f.Close(); err != nil {
-			log.Println(err)
+			engine.logger.Error("close snapshot file", logger.ErrorKey, err)
 		}
 	}()
 
@@ -293,7 +329,7 @@ func (engine *Engine) TakeSnapshot() error {
 		return err
 	}
 	if err = f.Sync(); err != nil {
-		log.Println(err)
+		engine.logger.Error("sync snapshot file", logger.ErrorKey, err)
 	}
 
 	// Set the latest snapshot in unix milliseconds
@@ -315,7 +351,7 @@ func (engine *Engine) Restore() error {
 	}
 	defer func() {
 		if err := mf.Close(); err != nil {
-			log.Println(err)
+			engine.logger.Error("close snapshot manifest", logger.ErrorKey, err)
 		}
 	}()
 
@@ -347,7 +383,7 @@ func (engine *Engine) Restore() error {
 	}
 	defer func() {
 		if err := sf.Close(); err != nil {
-			log.Println(err)
+			engine.logger.Error("close snapshot file", logger.ErrorKey, err)
 		}
 	}()
 
@@ -369,7 +405,7 @@ func (engine *Engine) Restore() error {
 		}
 	}
 
-	log.Println("successfully restored latest snapshot")
+	engine.logger.Info("restored latest snapshot", "time", snapshotObject.LatestSnapshotMilliseconds)
 
 	return nil
 }
@@ -378,6 +414,16 @@ func (engine *Engine) IncrementChangeCount() {
 	engine.changeCount.Add(1)
 }
 
+// Duration returns the histogram of the time taken by snapshots.
+func (engine *Engine) Duration() metrics.HistogramSnapshot {
+	return engine.duration.Snapshot()
+}
+
+// ChangeCount returns the number of changes since the latest snapshot.
+func (engine *Engine) ChangeCount() uint64 {
+	return engine.changeCount.Load()
+}
+
 func (engine *Engine) resetChangeCount() {
 	engine.changeCount.Store(0)
 }
@@ -0,0 +1,400 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tracking implements server-assisted client-side caching. It remembers the keys read by the clients
+// that enabled CLIENT TRACKING, and sends them invalidation messages when the keys are modified.
+package tracking
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// InvalidateChannel is the pub/sub channel of the invalidation messages sent to a REDIRECT connection.
+const InvalidateChannel = "__redis__:invalidate"
+
+// Options are the options of CLIENT TRACKING ON.
+type Options struct {
+	Redirect uint64   // The id of the connection that receives the invalidation messages. 0 sends them to the client.
+	BCast    bool     // Invalidate every key that matches the prefixes, instead of the keys read by the client.
+	Prefixes []string // The key prefixes of BCAST mode. Without prefixes, every key is invalidated.
+	OptIn    bool     // Track the keys read by a command only when it follows CLIENT CACHING YES.
+	OptOut   bool     // Track the keys read by every command, except when it follows CLIENT CACHING NO.
+	NoLoop   bool     // Don't send the invalidation of the keys modified by the client itself.
+}
+
+type client struct {
+	id           uint64              // The id of the connection.
+	connectionID string              // The connection id used to recognise the writes of the client for NOLOOP.
+	options      Options             // The tracking options of the client.
+	caching      string              // The argument of the CLIENT CACHING sent before the current command.
+	keys         map[string]struct{} // The keys tracked for the client in default mode.
+}
+
+// Tracking holds the tracking state of the clients.
+type Tracking struct {
+	mutex    sync.Mutex
+	active   atomic.Int64                   // The number of clients with tracking enabled.
+	clients  map[uint64]*client             // The clients with tracking enabled, by connection id.
+	keys     map[string]map[uint64]struct{} // The clients that read each key in default mode.
+	prefixes map[string]map[uint64]struct{} // The clients that track each prefix in BCAST mode.
+
+	// send writes a message to the connection with the id. The message is built for the RESP protocol of the
+	// connection, and is nil when it can't be delivered with the protocol.
+	send       func(id uint64, message func(protocol int) []byte) bool
+	queueMutex sync.Mutex
+	queues     map[uint64][]func(protocol int) []byte // The messages waiting to be written to each connection.
+}
+
+// WithSendFunc sets the function that writes a message to the connection with the id.
+// It returns false when the connection does not exist.
+func WithSendFunc(f func(id uint64, message func(protocol int) []byte) bool) func(tracking *Tracking) {
+	return func(tracking *Tracking) {
+		tracking.send = f
+	}
+}
+
+func NewTracking(options ...func(tracking *Tracking)) *Tracking {
+	tracking := &Tracking{
+		clients:  make(map[uint64]*client),
+		keys:     make(map[string]map[uint64]struct{}),
+		prefixes: make(map[string]map[uint64]struct{}),
+		send: func(id uint64, message func(protocol int) []byte) bool {
+			return false
+		},
+		queues: make(map[uint64][]func(protocol int) []byte),
+	}
+	for _, option := range options {
+		option(tracking)
+	}
+	return tracking
+}
+
+// Enable turns tracking on for the client with the id. When tracking is already on, the new prefixes are added,
+// but the mode of the client can't be changed.
+func (tracking *Tracking) Enable(id uint64, connectionID string, options Options) error {
+	if len(options.Prefixes) > 0 && !options.BCast {
+		return errors.New("PREFIX option requires BCAST mode to be enabled")
+	}
+	if options.OptIn && options.OptOut {
+		return errors.New("you can't use both OPTIN and OPTOUT")
+	}
+	if options.BCast && (options.OptIn || options.OptOut) {
+		return errors.New("OPTIN and OPTOUT are not compatible with BCAST")
+	}
+
+	tracking.mutex.Lock()
+	defer tracking.mutex.Unlock()
+
+	c, ok := tracking.clients[id]
+	if ok {
+		if c.options.BCast != options.BCast || c.options.OptIn != options.OptIn || c.options.OptOut != options.OptOut {
+			return errors.New("you can't switch BCAST, OPTIN or OPTOUT mode before turning tracking off")
+		}
+		c.options.Redirect = options.Redirect
+		c.options.NoLoop = options.NoLoop
+	} else {
+		c = &client{
+			id:           id,
+			connectionID: connectionID,
+			options:      options,
+			keys:         make(map[string]struct{}),
+		}
+		// The prefixes are added below.
+		c.options.Prefixes = nil
+		tracking.clients[id] = c
+		tracking.active.Add(1)
+	}
+
+	if !options.BCast {
+		return nil
+	}
+	prefixes := options.Prefixes
+	if len(prefixes) == 0 {
+		// No prefix matches every key.
+		prefixes = []string{""}
+	}
+	for _, prefix := range prefixes {
+		if !slices.Contains(c.options.Prefixes, prefix) {
+			c.options.Prefixes = append(c.options.Prefixes, prefix)
+		}
+		if tracking.prefixes[prefix] == nil {
+			tracking.prefixes[prefix] = make(map[uint64]struct{})
+		}
+		tracking.prefixes[prefix][id] = struct{}{}
+	}
+	return nil
+}
+
+// Disable turns tracking off for the client with the id, and forgets the keys tracked for it.
+func (tracking *Tracking) Disable(id uint64) {
+	tracking.mutex.Lock()
+	defer tracking.mutex.Unlock()
+
+	c, ok := tracking.clients[id]
+	if !ok {
+		return
+	}
+	for key := range c.keys {
+		delete(tracking.keys[key], id)
+		if len(tracking.keys[key]) == 0 {
+			delete(tracking.keys, key)
+		}
+	}
+	for _, prefix := range c.options.Prefixes {
+		delete(tracking.prefixes[prefix], id)
+		if len(tracking.prefixes[prefix]) == 0 {
+			delete(tracking.prefixes, prefix)
+		}
+	}
+	delete(tracking.clients, id)
+	tracking.active.Add(-1)
+}
+
+// Enabled returns true when tracking is on for the client with the id.
+func (tracking *Tracking) Enabled(id uint64) bool {
+	if tracking.active.Load() == 0 {
+		return false
+	}
+	tracking.mutex.Lock()
+	defer tracking.mutex.Unlock()
+	_, ok := tracking.clients[id]
+	return ok
+}
+
+// Options returns the tracking options of the client with the id, and false when tracking is off.
+func (tracking *Tracking) Options(id uint64) (Options, bool) {
+	tracking.mutex.Lock()
+	defer tracking.mutex.Unlock()
+	c, ok := tracking.clients[id]
+	if !ok {
+		return Options{}, false
+	}
+	options := c.options
+	options.Prefixes = slices.Clone(c.options.Prefixes)
+	return options, true
+}
+
+// SetCaching handles CLIENT CACHING YES|NO, which decides whether the keys read by the next command of the client
+// are tracked.
+func (tracking *Tracking) SetCaching(id uint64, yes bool) error {
+	tracking.mutex.Lock()
+	defer tracking.mutex.Unlock()
+
+	c, ok := tracking.clients[id]
+	if !ok || (!c.options.OptIn && !c.options.OptOut) {
+		return errors.New("CLIENT CACHING can be called only when the client is in tracking mode with OPTIN or OPTOUT mode enabled")
+	}
+	if yes && !c.options.OptIn {
+		return errors.New("CLIENT CACHING YES is only valid when tracking is enabled in OPTIN mode")
+	}
+	if !yes && !c.options.OptOut {
+		return errors.New("CLIENT CACHING NO is only valid when tracking is enabled in OPTOUT mode")
+	}
+	c.caching = "no"
+	if yes {
+		c.caching = "yes"
+	}
+	return nil
+}
+
+// Track records the keys read by a command of the client with the id. It must be called for every command of
+// the client, except CLIENT CACHING, so that CLIENT CACHING only applies to the command that follows it.
+// It returns the keys that the client was not tracking yet.
+func (tracking *Tracking) Track(id uint64, keys []string) []string {
+	if tracking.active.Load() == 0 {
+		return nil
+	}
+	tracking.mutex.Lock()
+	defer tracking.mutex.Unlock()
+
+	c, ok := tracking.clients[id]
+	if !ok {
+		return nil
+	}
+	caching := c.caching
+	c.caching = ""
+
+	// In BCAST mode, the keys are invalidated by prefix.
+	if c.options.BCast {
+		return nil
+	}
+	if (c.options.OptIn && caching != "yes") || (c.options.OptOut && caching == "no") {
+		return nil
+	}
+	var tracked []string
+	for _, key := range keys {
+		if _, ok = c.keys[key]; ok {
+			continue
+		}
+		if tracking.keys[key] == nil {
+			tracking.keys[key] = make(map[uint64]struct{})
+		}
+		tracking.keys[key][id] = struct{}{}
+		c.keys[key] = struct{}{}
+		tracked = append(tracked, key)
+	}
+	return tracked
+}
+
+// Untrack forgets the keys recorded by Track for the client with the id, e.g. when the command that read them
+// was not executed.
+func (tracking *Tracking) Untrack(id uint64, keys []string) {
+	tracking.mutex.Lock()
+	defer tracking.mutex.Unlock()
+
+	c, ok := tracking.clients[id]
+	if !ok {
+		return
+	}
+	for _, key := range keys {
+		delete(c.keys, key)
+		if clients, ok := tracking.keys[key]; ok {
+			delete(clients, id)
+			if len(clients) == 0 {
+				delete(tracking.keys, key)
+			}
+		}
+	}
+}
+
+// Invalidate sends the invalidation of the modified key to the clients tracking it. The sender is the connection
+// id of the client that modified the key, and is used by NOLOOP. The key is forgotten by the clients in default mode
+// until they read it again.
+func (tracking *Tracking) Invalidate(key string, sender string) {
+	if tracking.active.Load() == 0 {
+		return
+	}
+	tracking.mutex.Lock()
+	defer tracking.mutex.Unlock()
+
+	notify := func(id uint64) {
+		c, ok := tracking.clients[id]
+		if !ok || (c.options.NoLoop && sender != "" && c.connectionID == sender) {
+			return
+		}
+		tracking.enqueue(c, []string{key})
+	}
+
+	for id := range tracking.keys[key] {
+		notify(id)
+		delete(tracking.clients[id].keys, key)
+	}
+	delete(tracking.keys, key)
+
+	for prefix, ids := range tracking.prefixes {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		for id := range ids {
+			notify(id)
+		}
+	}
+}
+
+// InvalidateAll sends a null invalidation, which invalidates every key, to all the tracking clients.
+// It's used when the databases are flushed or swapped.
+func (tracking *Tracking) InvalidateAll() {
+	if tracking.active.Load() == 0 {
+		return
+	}
+	tracking.mutex.Lock()
+	defer tracking.mutex.Unlock()
+
+	for _, c := range tracking.clients {
+		tracking.enqueue(c, nil)
+		clear(c.keys)
+	}
+	clear(tracking.keys)
+}
+
+// enqueue queues the invalidation message for the client. The messages of each connection are written in order
+// by their own goroutine, so that the writes to the keyspace are never blocked by a slow connection.
+// The caller must hold tracking.mutex.
+func (tracking *Tracking) enqueue(c *client, keys []string) {
+	target := c.id
+	message := func(protocol int) []byte {
+		return InvalidateMessage(keys, protocol, false)
+	}
+	if c.options.Redirect != 0 {
+		target = c.options.Redirect
+		message = func(protocol int) []byte {
+			return InvalidateMessage(keys, protocol, true)
+		}
+	}
+
+	tracking.queueMutex.Lock()
+	defer tracking.queueMutex.Unlock()
+	queue, draining := tracking.queues[target]
+	tracking.queues[target] = append(queue, message)
+	if !draining {
+		go tracking.drain(target)
+	}
+}
+
+// drain writes the queued messages to the connection until its queue is empty.
+func (tracking *Tracking) drain(target uint64) {
+	for {
+		tracking.queueMutex.Lock()
+		queue := tracking.queues[target]
+		if len(queue) == 0 {
+			delete(tracking.queues, target)
+			tracking.queueMutex.Unlock()
+			return
+		}
+		tracking.queues[target] = queue[:0:0]
+		tracking.queueMutex.Unlock()
+
+		for _, message := range queue {
+			if !tracking.send(target, message) {
+				// The connection is gone, so the remaining messages can't be delivered.
+				break
+			}
+		}
+	}
+}
+
+// InvalidateMessage returns the invalidation message of the keys for the RESP protocol. A nil slice of keys
+// invalidates every key. The message is a RESP3 push message, or a pub/sub message of InvalidateChannel when
+// redirect is true. Without redirect, RESP2 connections can't receive the message, so nil is returned.
+func InvalidateMessage(keys []string, protocol int, redirect bool) []byte {
+	var message strings.Builder
+	switch {
+	case redirect && protocol == 3:
+		message.WriteString(fmt.Sprintf(">3\r\n$7\r\nmessage\r\n$%d\r\n%s\r\n", len(InvalidateChannel), InvalidateChannel))
+	case redirect:
+		message.WriteString(fmt.Sprintf("*3\r\n$7\r\nmessage\r\n$%d\r\n%s\r\n", len(InvalidateChannel), InvalidateChannel))
+	case protocol == 3:
+		message.WriteString(">2\r\n$10\r\ninvalidate\r\n")
+	default:
+		return nil
+	}
+
+	switch {
+	case keys == nil && protocol == 3:
+		message.WriteString("_\r\n")
+	case keys == nil:
+		message.WriteString("*-1\r\n")
+	default:
+		message.WriteString(fmt.Sprintf("*%d\r\n", len(keys)))
+		for _, key := range keys {
+			message.WriteString(fmt.Sprintf("$%d\r\n%s\r\n", len(key), key))
+		}
+	}
+	return []byte(message.String())
+}
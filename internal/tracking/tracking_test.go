@@ -0,0 +1,228 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracking_test
+
+import (
+	"github.com/echovault/echovault/internal/tracking"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder records the messages sent to each connection.
+type recorder struct {
+	mutex     sync.Mutex
+	protocols map[uint64]int
+	messages  map[uint64][]string
+}
+
+func newRecorder(protocols map[uint64]int) *recorder {
+	return &recorder{protocols: protocols, messages: make(map[uint64][]string)}
+}
+
+func (r *recorder) send(id uint64, message func(protocol int) []byte) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	protocol, ok := r.protocols[id]
+	if !ok {
+		return false
+	}
+	if m := message(protocol); m != nil {
+		r.messages[id] = append(r.messages[id], string(m))
+	}
+	return true
+}
+
+// wait returns the messages sent to the connection once there are n of them, or after 200ms.
+func (r *recorder) wait(id uint64, n int) []string {
+	deadline := time.Now().Add(200 * time.Millisecond)
+	for {
+		r.mutex.Lock()
+		messages := append([]string{}, r.messages[id]...)
+		r.mutex.Unlock()
+		if len(messages) >= n || time.Now().After(deadline) {
+			return messages
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func Test_Tracking(t *testing.T) {
+	t.Run("Test default mode", func(t *testing.T) {
+		r := newRecorder(map[uint64]int{1: 3, 2: 3})
+		tr := tracking.NewTracking(tracking.WithSendFunc(r.send))
+		if err := tr.Enable(1, "conn-1", tracking.Options{}); err != nil {
+			t.Fatal(err)
+		}
+
+		// Only the keys read by the client are invalidated, once until they're read again.
+		tr.Track(1, []string{"key1"})
+		tr.Invalidate("key2", "conn-2")
+		tr.Invalidate("key1", "conn-2")
+		tr.Invalidate("key1", "conn-2")
+		tr.Track(1, []string{"key1"})
+		tr.Invalidate("key1", "conn-1")
+
+		want := string(tracking.InvalidateMessage([]string{"key1"}, 3, false))
+		messages := r.wait(1, 2)
+		if len(messages) != 2 || messages[0] != want || messages[1] != want {
+			t.Errorf("expected 2 invalidations of key1, got %q", messages)
+		}
+	})
+
+	t.Run("Test BCAST mode with prefixes", func(t *testing.T) {
+		r := newRecorder(map[uint64]int{1: 3})
+		tr := tracking.NewTracking(tracking.WithSendFunc(r.send))
+		if err := tr.Enable(1, "conn-1", tracking.Options{BCast: true, Prefixes: []string{"user:"}}); err != nil {
+			t.Fatal(err)
+		}
+
+		tr.Invalidate("order:1", "")
+		tr.Invalidate("user:1", "")
+
+		messages := r.wait(1, 1)
+		if len(messages) != 1 || messages[0] != string(tracking.InvalidateMessage([]string{"user:1"}, 3, false)) {
+			t.Errorf("expected the invalidation of user:1, got %q", messages)
+		}
+	})
+
+	t.Run("Test OPTIN and OPTOUT", func(t *testing.T) {
+		r := newRecorder(map[uint64]int{1: 3, 2: 3})
+		tr := tracking.NewTracking(tracking.WithSendFunc(r.send))
+		if err := tr.Enable(1, "conn-1", tracking.Options{OptIn: true}); err != nil {
+			t.Fatal(err)
+		}
+		if err := tr.Enable(2, "conn-2", tracking.Options{OptOut: true}); err != nil {
+			t.Fatal(err)
+		}
+		if err := tr.SetCaching(1, false); err == nil {
+			t.Error("expected error for CLIENT CACHING NO in OPTIN mode")
+		}
+
+		// The OPTIN client only tracks the command after CLIENT CACHING YES.
+		tr.Track(1, []string{"key1"})
+		if err := tr.SetCaching(1, true); err != nil {
+			t.Fatal(err)
+		}
+		tr.Track(1, []string{"key2"})
+		tr.Track(1, []string{"key3"})
+
+		// The OPTOUT client tracks every command except the one after CLIENT CACHING NO.
+		if err := tr.SetCaching(2, false); err != nil {
+			t.Fatal(err)
+		}
+		tr.Track(2, []string{"key1"})
+		tr.Track(2, []string{"key2"})
+
+		for _, key := range []string{"key1", "key2", "key3"} {
+			tr.Invalidate(key, "")
+		}
+
+		want := string(tracking.InvalidateMessage([]string{"key2"}, 3, false))
+		for _, id := range []uint64{1, 2} {
+			if messages := r.wait(id, 2); len(messages) != 1 || messages[0] != want {
+				t.Errorf("expected client %d to only receive the invalidation of key2, got %q", id, messages)
+			}
+		}
+	})
+
+	t.Run("Test NOLOOP and REDIRECT", func(t *testing.T) {
+		r := newRecorder(map[uint64]int{1: 2, 2: 2})
+		tr := tracking.NewTracking(tracking.WithSendFunc(r.send))
+		if err := tr.Enable(1, "conn-1", tracking.Options{Redirect: 2, NoLoop: true}); err != nil {
+			t.Fatal(err)
+		}
+
+		tr.Track(1, []string{"key1", "key2"})
+		tr.Invalidate("key1", "conn-1")
+		tr.Invalidate("key2", "conn-3")
+
+		want := "*3\r\n$7\r\nmessage\r\n$20\r\n__redis__:invalidate\r\n*1\r\n$4\r\nkey2\r\n"
+		if messages := r.wait(2, 2); len(messages) != 1 || messages[0] != want {
+			t.Errorf("expected the redirect connection to receive the invalidation of key2, got %q", messages)
+		}
+		if messages := r.wait(1, 1); len(messages) != 0 {
+			t.Errorf("expected no message on the tracking connection, got %q", messages)
+		}
+	})
+
+	t.Run("Test Untrack", func(t *testing.T) {
+		r := newRecorder(map[uint64]int{1: 3})
+		tr := tracking.NewTracking(tracking.WithSendFunc(r.send))
+		if err := tr.Enable(1, "conn-1", tracking.Options{}); err != nil {
+			t.Fatal(err)
+		}
+
+		// Only the keys that weren't tracked yet are returned, so untracking them keeps key1.
+		tr.Track(1, []string{"key1"})
+		added := tr.Track(1, []string{"key1", "key2"})
+		if len(added) != 1 || added[0] != "key2" {
+			t.Errorf("expected only key2 to be newly tracked, got %q", added)
+		}
+		tr.Untrack(1, added)
+		tr.Invalidate("key2", "")
+		tr.Invalidate("key1", "")
+
+		want := string(tracking.InvalidateMessage([]string{"key1"}, 3, false))
+		if messages := r.wait(1, 2); len(messages) != 1 || messages[0] != want {
+			t.Errorf("expected only the invalidation of key1, got %q", messages)
+		}
+	})
+
+	t.Run("Test InvalidateAll and Disable", func(t *testing.T) {
+		r := newRecorder(map[uint64]int{1: 3, 2: 3})
+		tr := tracking.NewTracking(tracking.WithSendFunc(r.send))
+		for _, id := range []uint64{1, 2} {
+			if err := tr.Enable(id, "", tracking.Options{}); err != nil {
+				t.Fatal(err)
+			}
+			tr.Track(id, []string{"key1"})
+		}
+
+		tr.Disable(2)
+		if tr.Enabled(2) {
+			t.Error("expected tracking to be off for client 2")
+		}
+		tr.InvalidateAll()
+		tr.Invalidate("key1", "")
+
+		if messages := r.wait(1, 2); len(messages) != 1 || messages[0] != ">2\r\n$10\r\ninvalidate\r\n_\r\n" {
+			t.Errorf("expected a null invalidation, got %q", messages)
+		}
+		if messages := r.wait(2, 1); len(messages) != 0 {
+			t.Errorf("expected no message for client 2, got %q", messages)
+		}
+	})
+
+	t.Run("Test invalid options", func(t *testing.T) {
+		tr := tracking.NewTracking()
+		for _, options := range []tracking.Options{
+			{Prefixes: []string{"user:"}},
+			{OptIn: true, OptOut: true},
+			{BCast: true, OptIn: true},
+		} {
+			if err := tr.Enable(1, "", options); err == nil {
+				t.Errorf("expected error for options %+v", options)
+			}
+		}
+
+		if err := tr.Enable(1, "", tracking.Options{}); err != nil {
+			t.Fatal(err)
+		}
+		if err := tr.Enable(1, "", tracking.Options{BCast: true}); err == nil {
+			t.Error("expected error when switching to BCAST mode")
+		}
+	})
+}
@@ -17,6 +17,7 @@ package internal
 import (
 	"context"
 	"github.com/echovault/echovault/internal/clock"
+	"github.com/echovault/echovault/internal/logger"
 	"net"
 	"time"
 )
@@ -24,19 +25,33 @@ import (
 type KeyData struct {
 	Value    interface{}
 	ExpireAt time.Time
+	Size     int64  `json:"-"` // The estimated number of bytes used by the key and its value.
+	Access   uint32 `json:"-"` // The LRU clock or LFU counter of the key's last access, used to evict keys.
 }
 
 type ContextServerID string
 type ContextConnID string
+type ContextCommand string      // The command that's being executed. Recorded in change data capture events.
+type ContextDeleteReason string // Why a key is deleted (expire | evict). Keys deleted by commands have no reason.
+type ContextWriteOrigin string  // The origin of a write replicated from another cluster.
+type ContextSource string       // Where a replayed command comes from (aof | replication). Shown by MONITOR.
+
+// WriteOrigin identifies a write for last-writer-wins conflict resolution between clusters.
+type WriteOrigin struct {
+	Source    string    // The source ID of the cluster that made the write.
+	Timestamp time.Time // The time of the write on that cluster.
+}
 
 type ApplyRequest struct {
-	Type         string   `json:"Type"` // command | delete-key
+	Type         string   `json:"Type"` // command | delete-key | acl-state
 	ServerID     string   `json:"ServerID"`
 	ConnectionID string   `json:"ConnectionID"`
 	Protocol     int      `json:"Protocol"`
 	Database     int      `json:"Database"`
 	CMD          []string `json:"CMD"`
-	Key          string   `json:"Key"` // Optional: Used with delete-key type to specify which key to delete.
+	Key          string   `json:"Key"`      // Optional: Used with delete-key type to specify which key to delete.
+	ACLState     []byte   `json:"ACLState"` // Optional: Used with acl-state type to replace the ACL users on every node.
+	Reason       string   `json:"Reason"`   // Optional: Used with delete-key type to specify why the key is deleted.
 }
 
 type ApplyResponse struct {
@@ -44,9 +59,32 @@ type ApplyResponse struct {
 	Response []byte
 }
 
+// MutationEvent is a change data capture event. It describes a single change to the keyspace.
+type MutationEvent struct {
+	Offset    uint64    // The position of the event in the feed. Offsets start at 1 and increase by 1 per event.
+	Type      string    // set | del | expire | evict | flush
+	Database  int       // The database of the key. -1 when every database is flushed.
+	Key       string    // The key that changed. Empty for flush events.
+	Command   []string  // The command that caused the change.
+	Timestamp time.Time // The time of the change. Changes replicated from another cluster keep the original time.
+	Origin    string    // The source ID of the cluster that made the change. Empty for local changes.
+}
+
+// MonitorEntry is a command streamed to the connections running MONITOR.
+type MonitorEntry struct {
+	Timestamp  time.Time // The time the command was processed.
+	Database   int       // The database the command was executed against.
+	Source     string    // embedded | tcp | aof | replication | raft
+	ClientAddr string    // The remote address of the TCP client. Empty for other sources.
+	ClientName string    // The name of the client connection, if set.
+	Command    []string  // The command and its arguments, with passwords redacted.
+	Dropped    uint64    // The number of entries dropped before this one because the monitor fell behind.
+}
+
 type SnapshotObject struct {
 	State                      map[int]map[string]KeyData
 	LatestSnapshotMilliseconds int64
+	ACLState                   []byte // Serialized ACL users. Only populated in raft snapshots.
 }
 
 // ServerInfo holds information about the server/node.
@@ -59,12 +97,241 @@ type ServerInfo struct {
 	Modules []string
 }
 
+// ClusterInfo holds the cluster membership state of the server.
+type ClusterInfo struct {
+	Enabled      bool     // Whether the server runs in cluster mode.
+	JoinState    string   // "none" when bootstrapping without seeds, "joining" while retrying, or "joined".
+	JoinAttempts int      // The number of failed attempts to join the cluster.
+	JoinError    string   // The error from the latest failed attempt to join the cluster.
+	Seeds        []string // The static seed addresses.
+	Members      int      // The number of live memberlist members, including the server.
+	RaftState    string   // The raft state of the server (leader, follower, candidate or shutdown).
+	Leader       string   // The server ID of the raft leader.
+	Followers    []string // The server IDs of the raft voters other than the leader.
+	Ready        bool     // Whether the server is ready to serve requests.
+}
+
+// ExpiryInfo holds statistics of the deletion of expired keys.
+type ExpiryInfo struct {
+	VolatileKeys     int           // The number of keys that have an expiry time, in every database.
+	ExpiredKeys      uint64        // The number of keys deleted because they expired.
+	Cycles           uint64        // The number of active expiry cycles.
+	LastCycleExpired uint64        // The number of keys deleted by the latest active expiry cycle.
+	TimeCapReached   uint64        // The number of active expiry cycles that stopped at their time limit.
+	CycleTime        time.Duration // The total time spent in active expiry cycles.
+}
+
+// MemoryStats holds the memory usage of the dataset.
+type MemoryStats struct {
+	PeakDataset    int64                  // The highest number of bytes used by the dataset.
+	Dataset        int64                  // The number of bytes used by the dataset.
+	Keys           int                    // The number of keys in every database.
+	MaxMemory      uint64                 // The configured maximum number of bytes used by the dataset. 0 means no limit.
+	EvictionPolicy string                 // The configured eviction policy.
+	EvictedKeys    uint64                 // The number of keys evicted because the dataset reached max memory.
+	HeapAlloc      uint64                 // The number of bytes of allocated heap objects, as reported by the runtime.
+	Databases      map[int]DatabaseMemory // The memory usage of each database.
+}
+
+// DatabaseMemory holds the memory usage of a database.
+type DatabaseMemory struct {
+	Keys    int   // The number of keys in the database.
+	Dataset int64 // The number of bytes used by the keys of the database.
+}
+
+// Info holds the sections reported by the INFO command.
+type Info struct {
+	Server       ServerSection
+	Clients      ClientsInfo
+	Memory       MemoryStats
+	Persistence  PersistenceInfo
+	Stats        StatsInfo
+	Replication  ReplicationInfo
+	CPU          CPUInfo
+	Cluster      ClusterInfo
+	CommandStats map[string]CommandStats // The statistics of each command that has been called, by command name (e.g. get, config|set).
+	ErrorStats   map[string]uint64       // The number of errors returned to clients, by error prefix (e.g. ERR).
+	Keyspace     map[int]KeyspaceInfo    // The number of keys in each database that holds keys.
+}
+
+// ServerSection holds the information about the server reported by the server section of the INFO command.
+type ServerSection struct {
+	ServerInfo
+	ProcessID int           // The process ID of the server.
+	TCPPort   int           // The port the server listens on for TCP connections.
+	Uptime    time.Duration // The time since the server started.
+	GoVersion string        // The Go version the server was built with.
+	OS        string        // The operating system and architecture of the server.
+}
+
+// ClientsInfo holds information about the client connections.
+type ClientsInfo struct {
+	ConnectedClients int // The number of open TCP connections.
+}
+
+// PersistenceInfo holds the state of the snapshots and the append-only log.
+type PersistenceInfo struct {
+	SnapshotInProgress   bool   // Whether a snapshot is being taken.
+	LastSnapshot         int64  // The unix epoch in milliseconds of the latest snapshot. 0 if no snapshot was taken.
+	ChangesSinceSnapshot uint64 // The number of writes since the latest snapshot.
+	AOFEnabled           bool   // Whether commands are persisted to an append-only log.
+	AOFRewriteInProgress bool   // Whether the append-only log is being rewritten.
+	AOFOffset            uint64 // The number of bytes written to the append-only log since startup.
+	AOFSyncedOffset      uint64 // The offset up to which the append-only log is fsynced.
+}
+
+// StatsInfo holds general statistics of the server.
+type StatsInfo struct {
+	TotalConnections uint64 // The number of connections accepted by the server.
+	TotalCommands    uint64 // The number of commands processed by the server.
+	TotalErrors      uint64 // The number of errors returned to clients.
+	KeyspaceHits     uint64 // The number of successful lookups of keys.
+	KeyspaceMisses   uint64 // The number of failed lookups of keys.
+	EvictedKeys      uint64 // The number of keys evicted because the dataset reached max memory.
+	ExpiryInfo
+}
+
+// CPUInfo holds the CPU time consumed by the server process.
+type CPUInfo struct {
+	UsedSys    time.Duration // The system CPU time consumed by the server.
+	UsedUser   time.Duration // The user CPU time consumed by the server.
+	Goroutines int           // The number of goroutines that currently exist.
+}
+
+// CommandStats holds the statistics of a command or subcommand.
+type CommandStats struct {
+	Calls         uint64        // The number of calls to the command, including the failed calls.
+	RejectedCalls uint64        // The number of calls rejected before execution, by the ACL layer or because of invalid arguments.
+	FailedCalls   uint64        // The number of calls that were executed and returned an error.
+	Time          time.Duration // The total time spent executing the command.
+}
+
+// KeyspaceInfo holds the number of keys in a database.
+type KeyspaceInfo struct {
+	Keys    int // The number of keys in the database.
+	Expires int // The number of keys in the database with an expiry time.
+}
+
+// SlowlogEntry is a command that took at least the slow log threshold to execute.
+type SlowlogEntry struct {
+	ID         uint64        // The unique, incrementing ID of the entry.
+	Timestamp  time.Time     // The time the command was executed.
+	Duration   time.Duration // The execution time of the command.
+	Command    []string      // The command and its arguments, truncated when they're too long.
+	ClientAddr string        // The address of the client. Empty for the embedded API.
+	ClientName string        // The name of the client connection.
+}
+
+// LatencyEvent holds the latest and highest latency of an event recorded by the latency monitor.
+type LatencyEvent struct {
+	Name      string        // The name of the event (e.g. aof-fsync, snapshot, eviction-cycle).
+	Timestamp time.Time     // The time of the latest recorded latency.
+	Latest    time.Duration // The latest recorded latency.
+	Max       time.Duration // The highest recorded latency.
+}
+
+// LatencySample is a latency of an event recorded by the latency monitor.
+type LatencySample struct {
+	Timestamp time.Time
+	Latency   time.Duration
+}
+
+// LatencyHistogram holds the distribution of the execution time of a command.
+type LatencyHistogram struct {
+	Calls   uint64          // The number of calls to the command.
+	Buckets []LatencyBucket // The buckets holding at least one call, in ascending order.
+}
+
+// LatencyBucket is a bucket of a latency histogram.
+type LatencyBucket struct {
+	UpperBound time.Duration // The upper bound of the bucket.
+	Count      uint64        // The number of calls that took at most the upper bound.
+}
+
 // ConnectionInfo holds information about the connection
 type ConnectionInfo struct {
-	Id       uint64 // Connection id.
-	Name     string // Alias name for this connection.
-	Protocol int    // The RESP protocol used by the client. Can be either 2 or 3.
-	Database int    // Database index currently being used by the connection.
+	Id                   uint64    // Connection id.
+	Name                 string    // Alias name for this connection.
+	Protocol             int       // The RESP protocol used by the client. Can be either 2 or 3.
+	Database             int       // Database index currently being used by the connection.
+	Addr                 string    // The address of the client.
+	LocalAddr            string    // The address of the server end of the connection.
+	User                 string    // The ACL user the connection is authenticated as.
+	CreatedAt            time.Time // The time the connection was accepted.
+	LastInteraction      time.Time // The time the connection sent its last command.
+	LastCommand          string    // The last command sent by the connection, e.g. "get" or "client|list".
+	QueryBuffer          int       // The size in bytes of the last command sent by the connection.
+	OutputBuffer         int       // The size in bytes of the last reply written to the connection.
+	Subscriptions        int       // The number of channels the connection is subscribed to.
+	PatternSubscriptions int       // The number of patterns the connection is subscribed to.
+	Replica              bool      // Whether the connection is a replica streaming from this server.
+	Monitor              bool      // Whether the connection is running MONITOR.
+	Tracking             bool      // Whether CLIENT TRACKING is on for the connection.
+	NoEvict              bool      // Whether CLIENT NO-EVICT is on for the connection.
+}
+
+// Type returns the type of the connection used by CLIENT LIST and CLIENT KILL: replica, pubsub or normal.
+func (info ConnectionInfo) Type() string {
+	switch {
+	case info.Replica:
+		return "replica"
+	case info.Subscriptions > 0 || info.PatternSubscriptions > 0:
+		return "pubsub"
+	default:
+		return "normal"
+	}
+}
+
+// Flags returns the flags of the connection in the format of CLIENT LIST.
+// S is a replica, O is running MONITOR, P is a pubsub subscriber, t is tracking keys, e is not evicted and
+// N means no flags.
+func (info ConnectionInfo) Flags() string {
+	var flags string
+	if info.Replica {
+		flags += "S"
+	}
+	if info.Monitor {
+		flags += "O"
+	}
+	if info.Subscriptions > 0 || info.PatternSubscriptions > 0 {
+		flags += "P"
+	}
+	if info.Tracking {
+		flags += "t"
+	}
+	if info.NoEvict {
+		flags += "e"
+	}
+	if flags == "" {
+		return "N"
+	}
+	return flags
+}
+
+// ReplicationInfo holds the primary/replica replication state of the server.
+type ReplicationInfo struct {
+	Role                   string        // Either "master" or "slave".
+	ReplID                 string        // The replication ID of the dataset served by this server.
+	Offset                 int64         // The current replication offset.
+	BacklogSize            int           // The capacity of the replication backlog in bytes.
+	BacklogFirstByteOffset int64         // The replication offset of the oldest byte held in the backlog.
+	BacklogHistLen         int64         // The number of bytes currently held in the backlog.
+	PrimaryHost            string        // The host of the primary. Only set on replicas.
+	PrimaryPort            int           // The port of the primary. Only set on replicas.
+	LinkStatus             string        // The state of the link to the primary (connect, connecting, sync, connected).
+	SyncFull               uint64        // The number of full resynchronizations served.
+	SyncPartialOK          uint64        // The number of accepted partial resynchronizations.
+	SyncPartialErr         uint64        // The number of partial resynchronizations that fell back to a full resync.
+	Replicas               []ReplicaInfo // The replicas currently attached to this server.
+}
+
+// ReplicaInfo holds information about a replica attached to the server.
+type ReplicaInfo struct {
+	Addr   string        // The IP address of the replica.
+	Port   int           // The listening port announced by the replica.
+	State  string        // The state of the replica (wait_bgsave, online).
+	Offset int64         // The latest replication offset acknowledged by the replica.
+	Lag    time.Duration // The time since the last acknowledgement from the replica.
 }
 
 // KeyExtractionFuncResult is the return type of the KeyExtractionFunc for the command/subcommand.
@@ -102,12 +369,18 @@ type HandlerFuncParams struct {
 	GetValues func(ctx context.Context, keys []string) map[string]interface{}
 	// SetValues sets each of the keys with their corresponding values in the provided map.
 	SetValues func(ctx context.Context, entries map[string]interface{}) error
+	// UpdateValues sets the keys like SetValues for the hashes and lists mutated by the command. The estimated size
+	// of each key changes by its growth in bytes instead of being estimated again from sampled elements.
+	UpdateValues func(ctx context.Context, entries map[string]interface{}, growth map[string]int64) error
 	// Set expiry sets the expiry time of the key.
 	SetExpiry func(ctx context.Context, key string, expire time.Time, touch bool)
 	// GetClock gets the clock used by the server.
 	// Use this when making use of time methods like .Now and .After.
 	// This inversion of control is a helper for testing as the clock is automatically mocked in tests.
 	GetClock func() clock.Clock
+	// GetLogger returns the server's logger with the connection ID, database and command of the
+	// handler attached to every entry.
+	GetLogger func() logger.Logger
 	// GetAllCommands returns all the commands loaded in the EchoVault instance.
 	GetAllCommands func() []Command
 	// GetACL returns the EchoVault instance's ACL engine.
@@ -118,6 +391,82 @@ type HandlerFuncParams struct {
 	// GetPubSub returns the EchoVault instance's PubSub engine.
 	// There's no need to use this outside of the pubsub package.
 	GetPubSub func() interface{}
+	// GetTracking returns the EchoVault instance's client-side caching engine (*tracking.Tracking).
+	// There's no need to use this outside of the connection package.
+	GetTracking func() interface{}
+	// GetConfig returns the EchoVault instance's configuration (config.Config).
+	// There's no need to use this outside of the admin package.
+	GetConfig func() interface{}
+	// SetConfig changes the values of the mutable configuration parameters while the server is running.
+	// Either all the parameters are changed or none of them.
+	SetConfig func(values map[string]string) error
+	// RewriteConfig persists the mutable configuration parameters to the config file the server was started with.
+	RewriteConfig func() error
+	// ResetStats resets the command, error and keyspace statistics reported by the INFO command.
+	ResetStats func()
+	// GetReplication returns the EchoVault instance's replication engine.
+	// There's no need to use this outside of the replication package.
+	GetReplication func() interface{}
+	// GetReplicationInfo returns the primary/replica replication state of the server.
+	GetReplicationInfo func() ReplicationInfo
+	// GetCDC returns the EchoVault instance's change data capture feed.
+	// There's no need to use this outside of the cdc package.
+	GetCDC func() interface{}
+	// GetXRepl returns the EchoVault instance's cross-cluster replication engine.
+	// There's no need to use this outside of the xrepl package.
+	GetXRepl func() interface{}
+	// GetClusterInfo returns the cluster membership state of the server.
+	GetClusterInfo func() ClusterInfo
+	// GetExpiryInfo returns the statistics of the deletion of expired keys.
+	GetExpiryInfo func() ExpiryInfo
+	// GetInfo returns the sections reported by the INFO command.
+	GetInfo func() Info
+	// GetSlowlog returns the latest count entries of the slow log, newest first. A negative count returns every entry.
+	GetSlowlog func(count int) []SlowlogEntry
+	// GetSlowlogLen returns the number of entries in the slow log.
+	GetSlowlogLen func() int
+	// ResetSlowlog removes every entry from the slow log.
+	ResetSlowlog func()
+	// GetLatestLatency returns the latest and highest latency of each event recorded by the latency monitor.
+	GetLatestLatency func() []LatencyEvent
+	// GetLatencyHistory returns the latencies recorded for the event, oldest first.
+	GetLatencyHistory func(event string) []LatencySample
+	// ResetLatency removes the latencies recorded for the given events, or every event when none is given.
+	// It returns the number of events reset.
+	ResetLatency func(events []string) int
+	// GetLatencyHistogram returns the distribution of the execution time of the given commands,
+	// or of every command that has been called when none is given.
+	GetLatencyHistogram func(commands []string) map[string]LatencyHistogram
+	// SubscribeMonitor returns a channel that receives every command processed from now on,
+	// until the context is cancelled.
+	SubscribeMonitor func(ctx context.Context) <-chan MonitorEntry
+	// GetMemoryUsage returns the estimated number of bytes used by the key and its value, extrapolated from the
+	// given number of sampled elements (0 samples every element). It returns false when the key does not exist.
+	GetMemoryUsage func(ctx context.Context, key string, samples int) (int64, bool)
+	// GetMemoryStats returns the memory usage of the dataset.
+	GetMemoryStats func() MemoryStats
+	// GetObjectFreq returns the logarithmic access counter of the key. It returns false when the key does not exist,
+	// and an error when the eviction policy is not an lfu policy, as the counter is only tracked by lfu policies.
+	GetObjectFreq func(ctx context.Context, key string) (int, bool, error)
+	// GetObjectIdleTime returns the time since the key was last accessed. It returns false when the key does not
+	// exist, and an error when the eviction policy is an lfu policy, as the access time is not tracked by lfu policies.
+	GetObjectIdleTime func(ctx context.Context, key string) (time.Duration, bool, error)
+	// PinKeys adds glob patterns of keys that are never evicted, and returns the number of new patterns.
+	PinKeys func(patterns []string) (int, error)
+	// UnpinKeys removes glob patterns of keys that are never evicted, and returns the number of removed patterns.
+	UnpinKeys func(patterns []string) int
+	// GetPinnedKeys returns the glob patterns of keys that are never evicted.
+	GetPinnedKeys func() []string
+	// ForgetNode removes another node from the cluster's raft configuration. Only the leader can forget nodes.
+	ForgetNode func(serverId string) error
+	// DecommissionNode gracefully removes the current node from the cluster.
+	DecommissionNode func() error
+	// Wait blocks until the writes issued before it are acknowledged by numReplicas replicas or the timeout
+	// elapses, and returns the number of replicas that acknowledged them. A timeout of 0 blocks indefinitely.
+	Wait func(numReplicas int, timeout time.Duration) (int, error)
+	// WaitAOF blocks until the writes issued before it are fsynced to the local append-only log (when numLocal is 1)
+	// and by numReplicas replicas, or the timeout elapses. It returns the number of local and replica fsyncs.
+	WaitAOF func(numLocal int, numReplicas int, timeout time.Duration) (int, int, error)
 	// TakeSnapshot triggers a snapshot by the EchoVault instance.
 	TakeSnapshot func() error
 	// RewriteAOF triggers a compaction of the commands logs by the EchoVault instance.
@@ -136,6 +485,18 @@ type HandlerFuncParams struct {
 	SetConnectionInfo func(conn *net.Conn, clientname string, protocol int, database int)
 	// GetConnectionInfo returns information about the current connection.
 	GetConnectionInfo func(conn *net.Conn) ConnectionInfo
+	// UpdateConnectionInfo applies the update to the information of the connection.
+	UpdateConnectionInfo func(conn *net.Conn, update func(info *ConnectionInfo))
+	// ListConnections returns the information of the TCP connections, sorted by id.
+	ListConnections func() []ConnectionInfo
+	// KillConnections closes the TCP connections with the given ids and returns the number of connections closed.
+	// When the current connection is one of them, it's closed after the reply is written.
+	KillConnections func(ids []uint64) int
+	// PauseClients suspends the commands of the clients for the timeout. When writeOnly is true,
+	// only the write commands are suspended.
+	PauseClients func(timeout time.Duration, writeOnly bool)
+	// UnpauseClients resumes the commands suspended by PauseClients.
+	UnpauseClients func()
 	// GetServerInfo returns information about the server when requested by commands such as HELLO.
 	GetServerInfo func() ServerInfo
 	// SwapDBs swaps two databases,
@@ -155,6 +516,14 @@ type HandlerFuncParams struct {
 // In embedded mode, the response is parsed and a native Go type is returned to the caller.
 type HandlerFunc func(params HandlerFuncParams) ([]byte, error)
 
+// KeySpec describes a range of key arguments of a command and how the command uses those keys.
+type KeySpec struct {
+	Flags       []string // The key spec flags (e.g. RO, RW, access, update). All the available flags are in the `constants` package.
+	BeginSearch int      // The index of the first key in the command.
+	LastKey     int      // The index of the last key relative to BeginSearch. Negative values count back from the last argument.
+	KeyStep     int      // The number of arguments from one key to the next.
+}
+
 type Command struct {
 	Command     string       // The command keyword (e.g. "set", "get", "hset").
 	Module      string       // The module this command belongs to. All the available modules are in the `constants` package.
@@ -162,16 +531,32 @@ type Command struct {
 	Description string       // The description of the command. Includes the command syntax.
 	SubCommands []SubCommand // The list of subcommands for this command. Empty if the command has no subcommands.
 	Sync        bool         // Specifies if command should be synced across replication cluster
+	Arity       int          // The number of arguments including the command name. A negative arity -N means N or more arguments.
+	Flags       []string     // The command flags (e.g. write, readonly, denyoom). All the available flags are in the `constants` package.
+	FirstKey    int          // The index of the first key in the command. 0 if the command doesn't take keys.
+	LastKey     int          // The index of the last key in the command. Negative values count back from the last argument.
+	KeyStep     int          // The number of arguments from one key to the next.
+	KeySpecs    []KeySpec    // The key specs of the command. When empty, a single spec is derived from FirstKey, LastKey and KeyStep.
+	Complexity  string       // The time complexity of the command.
+	Since       string       // The EchoVault version that introduced the command.
 	KeyExtractionFunc
 	HandlerFunc
 }
 
 type SubCommand struct {
-	Command     string   // The keyword for this subcommand. (Check the acl module for an example of subcommands within a command).
-	Module      string   // The module this subcommand belongs to. Should be the same as the parent command.
-	Categories  []string // The ACL categories the subcommand belongs to.
-	Description string   // The description of the subcommand. Includes syntax.
-	Sync        bool     // Specifies if sub-command should be synced across replication cluster
+	Command     string    // The keyword for this subcommand. (Check the acl module for an example of subcommands within a command).
+	Module      string    // The module this subcommand belongs to. Should be the same as the parent command.
+	Categories  []string  // The ACL categories the subcommand belongs to.
+	Description string    // The description of the subcommand. Includes syntax.
+	Sync        bool      // Specifies if sub-command should be synced across replication cluster
+	Arity       int       // The number of arguments including the command and subcommand names. Negative means at least -Arity.
+	Flags       []string  // The subcommand flags (e.g. write, readonly, denyoom).
+	FirstKey    int       // The index of the first key in the command. 0 if the subcommand doesn't take keys.
+	LastKey     int       // The index of the last key in the command. Negative values count back from the last argument.
+	KeyStep     int       // The number of arguments from one key to the next.
+	KeySpecs    []KeySpec // The key specs of the subcommand. When empty, a single spec is derived from the key positions.
+	Complexity  string    // The time complexity of the subcommand.
+	Since       string    // The EchoVault version that introduced the subcommand.
 	KeyExtractionFunc
 	HandlerFunc
 }
@@ -23,11 +23,9 @@ import (
 	"fmt"
 	"github.com/echovault/echovault/internal/constants"
 	"io"
-	"log"
 	"math/big"
 	"net"
 	"reflect"
-	"runtime"
 	"slices"
 	"strconv"
 	"strings"
@@ -120,9 +118,7 @@ func GetIPAddress() (string, error) {
 		return "", err
 	}
 	defer func() {
-		if err = conn.Close(); err != nil {
-			log.Println(err)
-		}
+		_ = conn.Close()
 	}()
 
 	localAddr := strings.Split(conn.LocalAddr().String(), ":")[0]
@@ -151,6 +147,11 @@ func IsWriteCommand(command Command, subCommand SubCommand) bool {
 	return slices.Contains(append(command.Categories, subCommand.Categories...), constants.WriteCategory)
 }
 
+// IsReadCommand returns true when the command reads keys. Read commands never block.
+func IsReadCommand(command Command, subCommand SubCommand) bool {
+	return slices.Contains(append(command.Categories, subCommand.Categories...), constants.ReadCategory)
+}
+
 func AbsInt(n int) int {
 	if n < 0 {
 		return -n
@@ -186,30 +187,6 @@ func ParseMemory(memory string) (uint64, error) {
 	return uint64(bytesInt), nil
 }
 
-// IsMaxMemoryExceeded checks whether we have exceeded the current maximum memory limit.
-func IsMaxMemoryExceeded(maxMemory uint64) bool {
-	if maxMemory == 0 {
-		return false
-	}
-
-	var memStats runtime.MemStats
-	runtime.ReadMemStats(&memStats)
-
-	// If we're currently using less than the configured max memory, return false.
-	if memStats.HeapInuse < maxMemory {
-		return false
-	}
-
-	// If we're currently using more than max memory, force a garbage collection before we start deleting keys.
-	// This measure is to prevent deleting keys that may be important when some memory can be reclaimed
-	// by just collecting garbage.
-	runtime.GC()
-	runtime.ReadMemStats(&memStats)
-
-	// Return true when whe are above or equal to max memory.
-	return memStats.HeapInuse >= maxMemory
-}
-
 // FilterExpiredKeys filters out keys that are already expired, so they are not persisted.
 func FilterExpiredKeys(now time.Time, state map[int]map[string]KeyData) map[int]map[string]KeyData {
 	for database, data := range state {